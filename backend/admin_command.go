@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"evoconnect/backend/config"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/web"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+func runAdminCommand(ctx context.Context, cfg config.Config, db *sql.DB, logger *slog.Logger, args []string) int {
+	if len(args) == 0 || args[0] != "create" {
+		return usageError("usage: evoconnect admin create -email EMAIL -name NAME [-role ROLE] [-password-stdin]")
+	}
+
+	flags := flag.NewFlagSet("admin create", flag.ContinueOnError)
+	email := flags.String("email", "", "email address the admin signs in with")
+	name := flags.String("name", "", "display name")
+	role := flags.String("role", "super_admin", "super_admin, moderator, company_reviewer or support")
+	passwordStdin := flags.Bool("password-stdin", false, "read the password from the first line of stdin instead of generating one")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	// A password on the command line would end up in the shell history and the process list
+	password := ""
+	generated := false
+	if *passwordStdin {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			fmt.Fprintln(os.Stderr, "admin create: failed to read the password from stdin:", err)
+			return 1
+		}
+		password = strings.TrimRight(line, "\r\n")
+	} else {
+		password = helper.GenerateSecureToken(18)
+		generated = true
+	}
+
+	application, err := newApplication(ctx, cfg, db, logger)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "admin create:", err)
+		return 1
+	}
+
+	admin := application.adminManagementService.Create(operatorContext(ctx), web.AdminCreateRequest{
+		Name:     *name,
+		Email:    *email,
+		Role:     *role,
+		Password: password,
+	})
+
+	fmt.Printf("admin created: %s (%s, %s)\n", admin.Email, admin.Role, admin.ID)
+	if generated {
+		fmt.Printf("password: %s\n", password)
+	}
+	return 0
+}
@@ -3,6 +3,8 @@ package app
 import (
 	"evoconnect/backend/controller"
 	"evoconnect/backend/middleware"
+	"evoconnect/backend/model/domain"
+	"evoconnect/backend/service"
 
 	"github.com/julienschmidt/httprouter"
 )
@@ -14,37 +16,67 @@ func setupAdminRoutes(
 	adminCompanyEditController controller.AdminCompanyEditController,
 	adminReportController controller.AdminReportController,
 	adminNotificationController controller.AdminNotificationController,
+	twoFactorController controller.TwoFactorController,
+	adminManagementController controller.AdminManagementController,
+	auditLogController controller.AuditLogController,
+	adminAuthService service.AdminAuthService,
 ) {
 	// Create admin middleware
-	adminAuth := middleware.NewAdminAuthMiddleware()
+	adminAuth := middleware.NewAdminAuthMiddleware(adminAuthService)
+
+	// can wraps a handler so only admins whose role grants the permission reach it
+	can := func(permission domain.AdminPermission, handle httprouter.Handle) httprouter.Handle {
+		return adminAuth(middleware.RequireAdminPermission(permission)(handle))
+	}
 
 	// Public admin auth routes (no middleware needed)
 	router.POST("/api/admin/auth/login", adminAuthController.Login)
-	// router.POST("/api/admin/auth/register", adminAuthController.Register)
+	router.POST("/api/admin/auth/2fa/enroll", adminAuthController.EnrollTwoFactor)
+	router.POST("/api/admin/auth/2fa/verify", adminAuthController.VerifyTwoFactor)
+	router.POST("/api/admin/auth/accept-invitation", adminAuthController.AcceptInvitation)
 
 	// Protected admin routes (require admin authentication)
+	// Two-factor authentication for the signed-in admin and the admin-wide policy
+	router.GET("/api/admin/2fa", adminAuth(twoFactorController.GetStatus))
+	router.POST("/api/admin/2fa/setup", adminAuth(twoFactorController.Setup))
+	router.POST("/api/admin/2fa/enable", adminAuth(twoFactorController.Enable))
+	router.POST("/api/admin/2fa/disable", adminAuth(twoFactorController.Disable))
+	router.POST("/api/admin/2fa/recovery-codes", adminAuth(twoFactorController.RegenerateRecoveryCodes))
+	router.GET("/api/admin/settings/two-factor", can(domain.AdminPermissionManageSettings, twoFactorController.GetAdminPolicy))
+	router.PUT("/api/admin/settings/two-factor", can(domain.AdminPermissionManageSettings, twoFactorController.UpdateAdminPolicy))
+
+	// Admin account management (super admins only)
+	router.GET("/api/admin/admins", can(domain.AdminPermissionManageAdmins, adminManagementController.FindAll))
+	router.GET("/api/admin/admins/invitations", can(domain.AdminPermissionManageAdmins, adminManagementController.FindPendingInvitations))
+	router.POST("/api/admin/admins/invitations", can(domain.AdminPermissionManageAdmins, adminManagementController.Invite))
+	router.PUT("/api/admin/admins/:adminId/role", can(domain.AdminPermissionManageAdmins, adminManagementController.UpdateRole))
+	router.PUT("/api/admin/admins/:adminId/status", can(domain.AdminPermissionManageAdmins, adminManagementController.UpdateStatus))
+
+	// Audit log of admin and moderation actions
+	router.GET("/api/admin/audit-logs", can(domain.AdminPermissionViewAuditLogs, auditLogController.FindAll))
+	router.GET("/api/admin/audit-logs/export", can(domain.AdminPermissionViewAuditLogs, auditLogController.ExportCSV))
+
 	// Company submission management routes - IMPORTANT: More specific routes first!
-	router.GET("/api/admin/company-submissions/stats", adminAuth(companySubmissionController.GetStats))
-	router.GET("/api/admin/company-submissions/status/:status", adminAuth(companySubmissionController.FindByStatus))
-	router.GET("/api/admin/company-submissions", adminAuth(companySubmissionController.FindAll))
-	router.GET("/api/admin/company-submissions/view/:submissionId", adminAuth(companySubmissionController.FindById))
-	router.PUT("/api/admin/company-submissions/review/:submissionId", adminAuth(companySubmissionController.Review))
+	router.GET("/api/admin/company-submissions/stats", can(domain.AdminPermissionViewCompanies, companySubmissionController.GetStats))
+	router.GET("/api/admin/company-submissions/status/:status", can(domain.AdminPermissionViewCompanies, companySubmissionController.FindByStatus))
+	router.GET("/api/admin/company-submissions", can(domain.AdminPermissionViewCompanies, companySubmissionController.FindAll))
+	router.GET("/api/admin/company-submissions/view/:submissionId", can(domain.AdminPermissionViewCompanies, companySubmissionController.FindById))
+	router.PUT("/api/admin/company-submissions/review/:submissionId", can(domain.AdminPermissionReviewCompanies, companySubmissionController.Review))
 
 	// Company Edit Request Management Routes - Fixed route structure
-	router.GET("/api/admin/company-edit-requests/stats", adminAuth(adminCompanyEditController.GetEditRequestStats))
-	router.GET("/api/admin/company-edit-requests/status/:status", adminAuth(adminCompanyEditController.GetEditRequestsByStatus))
-	router.GET("/api/admin/company-edit-requests", adminAuth(adminCompanyEditController.GetAllEditRequests))
-	router.GET("/api/admin/company-edit-requests/view/:requestId", adminAuth(adminCompanyEditController.GetEditRequestDetail))
-	router.POST("/api/admin/company-edit-requests/review/:requestId", adminAuth(adminCompanyEditController.ReviewEditRequest))
-
-		router.GET("/api/admin/reports", adminReportController.GetAllReports)
-	router.GET("/api/admin/reports/:reportId", adminReportController.GetReportDetail)
-	router.POST("/api/admin/reports/:reportId/action", adminReportController.TakeAction)
+	router.GET("/api/admin/company-edit-requests/stats", can(domain.AdminPermissionViewCompanies, adminCompanyEditController.GetEditRequestStats))
+	router.GET("/api/admin/company-edit-requests/status/:status", can(domain.AdminPermissionViewCompanies, adminCompanyEditController.GetEditRequestsByStatus))
+	router.GET("/api/admin/company-edit-requests", can(domain.AdminPermissionViewCompanies, adminCompanyEditController.GetAllEditRequests))
+	router.GET("/api/admin/company-edit-requests/view/:requestId", can(domain.AdminPermissionViewCompanies, adminCompanyEditController.GetEditRequestDetail))
+	router.POST("/api/admin/company-edit-requests/review/:requestId", can(domain.AdminPermissionReviewCompanies, adminCompanyEditController.ReviewEditRequest))
+
+	// Report moderation routes
+	router.GET("/api/admin/reports", can(domain.AdminPermissionViewReports, adminReportController.GetAllReports))
+	router.GET("/api/admin/reports/:reportId", can(domain.AdminPermissionViewReports, adminReportController.GetReportDetail))
+	router.POST("/api/admin/reports/:reportId/action", can(domain.AdminPermissionManageReports, adminReportController.TakeAction))
 	// Add more admin routes here as needed
 	// Examples:
 	// router.GET("/api/admin/users", adminAuth(adminUserController.GetAllUsers))
 	// router.PUT("/api/admin/users/:userId/status", adminAuth(adminUserController.UpdateUserStatus))
-	// router.GET("/api/admin/reports", adminAuth(adminReportController.GetAllReports))
-	// router.PUT("/api/admin/reports/:reportId/resolve", adminAuth(adminReportController.ResolveReport))
 	// router.GET("/api/admin/analytics", adminAuth(adminAnalyticsController.GetAnalytics))
 }
@@ -2,53 +2,36 @@ package app
 
 import (
 	"database/sql"
+	"evoconnect/backend/config"
 	"evoconnect/backend/helper"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 )
 
-type DatabaseConfig struct {
-	Host     string
-	Port     int
-	User     string
-	Password string
-	DbName   string
-	SSLMode  string
-}
-
-func GetDatabaseConfig() DatabaseConfig {
-	return DatabaseConfig{
-		Host:     helper.GetEnv("DB_HOST", "localhost"),
-		Port:     helper.GetEnvInt("DB_PORT", 5432),
-		User:     helper.GetEnv("DB_USERNAME", "postgres"),
-		Password: helper.GetEnv("DB_PASSWORD", "root"),
-		DbName:   helper.GetEnv("DB_NAME", "go_database"),
-		SSLMode:  helper.GetEnv("DB_SSLMODE", "disable"),
-	}
-}
-
-func NewDB() *sql.DB {
-	config := GetDatabaseConfig()
+func NewDB(databaseConfig config.DatabaseConfig) *sql.DB {
 	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		config.Host, config.Port, config.User, config.Password, config.DbName, config.SSLMode)
-	log.Println("Connecting to database...")
+		databaseConfig.Host, databaseConfig.Port, databaseConfig.User, databaseConfig.Password, databaseConfig.Name, databaseConfig.SSLMode)
+	slog.Info("connecting to database", "host", databaseConfig.Host, "port", databaseConfig.Port, "database", databaseConfig.Name)
 	db, err := sql.Open("postgres", dsn)
 	helper.PanicIfError(err)
 
-	db.SetMaxOpenConns(25)                 // Batasi jumlah koneksi total
-	db.SetMaxIdleConns(10)                 // Jumlah koneksi idle yang dipertahankan
-	db.SetConnMaxLifetime(5 * time.Minute) // Waktu maksimum penggunaan koneksi
+	db.SetMaxOpenConns(databaseConfig.MaxOpenConns) // Batasi jumlah koneksi total
+	db.SetMaxIdleConns(databaseConfig.MaxIdleConns) // Jumlah koneksi idle yang dipertahankan
+	db.SetConnMaxLifetime(5 * time.Minute)          // Waktu maksimum penggunaan koneksi
 	db.SetConnMaxIdleTime(3 * time.Minute)
 
 	err = db.Ping()
 	if err != nil {
-		log.Println("Error connecting to database:", err)
+		slog.Error("database connection failed", "error", err)
 		return nil
 	}
-	log.Println("Connected to database successfully")
+	slog.Info("connected to database")
 	helper.PanicIfError(err)
 
+	// Pool saturation is exposed on /metrics
+	helper.RegisterDBStats(db, databaseConfig.Name)
+
 	return db
 }
 
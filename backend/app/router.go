@@ -1,10 +1,15 @@
 package app
 
 import (
+	"errors"
 	"evoconnect/backend/controller"
 	"evoconnect/backend/exception"
 	"evoconnect/backend/helper"
+	"evoconnect/backend/middleware"
 	"evoconnect/backend/model/web"
+	"evoconnect/backend/service"
+	"evoconnect/backend/storage"
+	"mime"
 	"net/http"
 
 	"github.com/julienschmidt/httprouter"
@@ -42,6 +47,22 @@ func NewRouter(
 	jobApplicationController controller.JobApplicationController,
 	userCvStorageController controller.UserCvStorageController,
 	savedJobController controller.SavedJobController,
+	userSessionController controller.UserSessionController,
+	twoFactorController controller.TwoFactorController,
+	accountDeletionController controller.AccountDeletionController,
+	dataExportController controller.DataExportController,
+	oauthController controller.OAuthController,
+	apiTokenController controller.APITokenController,
+	emailChangeController controller.EmailChangeController,
+	adminManagementController controller.AdminManagementController,
+	auditLogController controller.AuditLogController,
+	healthController controller.HealthController,
+	accountStatusService service.AccountStatusService,
+	userSessionService service.UserSessionService,
+	apiTokenService service.APITokenService,
+	adminAuthService service.AdminAuthService,
+	rateLimiter service.RateLimiter,
+	metricsToken string,
 ) *httprouter.Router {
 	router := httprouter.New()
 
@@ -75,6 +96,17 @@ func NewRouter(
 		jobApplicationController,
 		userCvStorageController,
 		savedJobController,
+		userSessionController,
+		twoFactorController,
+		accountDeletionController,
+		dataExportController,
+		oauthController,
+		apiTokenController,
+		emailChangeController,
+		accountStatusService,
+		userSessionService,
+		apiTokenService,
+		rateLimiter,
 	)
 
 	// Setup admin routes
@@ -85,11 +117,22 @@ func NewRouter(
 		adminCompanyEditController,
 		adminReportController,
 		adminNotificationController,
+		twoFactorController,
+		adminManagementController,
+		auditLogController,
+		adminAuthService,
 	)
 
 	// Static file servers
 	setupStaticRoutes(router)
 
+	// Prometheus scrape endpoint
+	setupMetricsRoutes(router, metricsToken)
+
+	// Liveness and readiness probes
+	router.GET("/healthz", healthController.Liveness)
+	router.GET("/readyz", healthController.Readiness)
+
 	// Setup error handlers
 	setupErrorHandlers(router)
 
@@ -97,20 +140,53 @@ func NewRouter(
 }
 
 func setupStaticRoutes(router *httprouter.Router) {
-	uploadFS := http.FileServer(http.Dir("uploads"))
 	publicFS := http.FileServer(http.Dir("public"))
  
 	// Add custom file server handler to serve static files
 	router.GET("/uploads/*filepath", func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-		// Remove /uploads prefix from path
-		r.URL.Path = ps.ByName("filepath")
+		// CVs and chat files are only served through signed links, see /api/files
+		if helper.IsPrivateFile("uploads" + ps.ByName("filepath")) {
+			router.NotFound.ServeHTTP(w, r)
+			return
+		}
 
 		// Set headers for browser caching
 		w.Header().Set("Cache-Control", "public, max-age=31536000")
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 
-		// Serve the file
-		uploadFS.ServeHTTP(w, r)
+		// Serve the file from the configured storage, the request path is its key
+		err := helper.ServeStoredFile(w, r, "uploads"+ps.ByName("filepath"))
+		if errors.Is(err, storage.ErrNotFound) {
+			w.Header().Del("Cache-Control")
+			router.NotFound.ServeHTTP(w, r)
+			return
+		}
+		helper.PanicIfError(err)
+	})
+
+	// Private files, the signature in the query grants access for a few minutes
+	router.GET("/api/files", func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		file, err := helper.VerifyFileURL(r.URL.Query())
+		if err != nil {
+			panic(exception.NewForbiddenError(err.Error()))
+		}
+
+		disposition := "attachment"
+		if file.Inline {
+			disposition = "inline"
+		}
+		w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": file.Filename}))
+		w.Header().Set("Cache-Control", "private, no-store")
+		// A file opened in the browser cannot run scripts against the site
+		w.Header().Set("Content-Security-Policy", "sandbox")
+
+		err = helper.ServeStoredFile(w, r, file.Key)
+		if errors.Is(err, storage.ErrNotFound) {
+			w.Header().Del("Content-Disposition")
+			router.NotFound.ServeHTTP(w, r)
+			return
+		}
+		helper.PanicIfError(err)
 	})
 
 	router.GET("/public/*filepath", func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
@@ -126,6 +202,11 @@ func setupStaticRoutes(router *httprouter.Router) {
 	})
 }
 
+func setupMetricsRoutes(router *httprouter.Router, metricsToken string) {
+	metricsAuth := middleware.NewMetricsAuthMiddleware(metricsToken)
+	router.Handler(http.MethodGet, "/metrics", metricsAuth(helper.MetricsHandler()))
+}
+
 func setupErrorHandlers(router *httprouter.Router) {
 	// Add custom NotFound handler
 	router.NotFound = http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
@@ -3,6 +3,8 @@ package app
 import (
 	"evoconnect/backend/controller"
 	"evoconnect/backend/middleware"
+	"evoconnect/backend/model/domain"
+	"evoconnect/backend/service"
 
 	"github.com/julienschmidt/httprouter"
 )
@@ -36,18 +38,47 @@ func setupUserRoutes(
 	jobApplicationController controller.JobApplicationController,
 	userCvStorageController controller.UserCvStorageController,
 	savedJobController controller.SavedJobController,
+	userSessionController controller.UserSessionController,
+	twoFactorController controller.TwoFactorController,
+	accountDeletionController controller.AccountDeletionController,
+	dataExportController controller.DataExportController,
+	oauthController controller.OAuthController,
+	apiTokenController controller.APITokenController,
+	emailChangeController controller.EmailChangeController,
+	accountStatusService service.AccountStatusService,
+	userSessionService service.UserSessionService,
+	apiTokenService service.APITokenService,
+	rateLimiter service.RateLimiter,
 ) {
 	// Create user middleware
-	userAuth := middleware.NewUserAuthMiddleware()
+	userAuth := middleware.NewUserAuthMiddleware(accountStatusService, userSessionService)
+	// Routes integrations may call with an API token carrying the scope
+	tokenAuth := middleware.NewScopedUserAuthMiddleware(accountStatusService, userSessionService, apiTokenService)
+	// Routes acting on the caller's own data, personal tokens only
+	personalTokenAuth := middleware.NewPersonalScopedUserAuthMiddleware(accountStatusService, userSessionService, apiTokenService)
+	registerLimit := middleware.NewIPRateLimitMiddleware(rateLimiter, service.RateLimitRegister)
+	refreshLimit := middleware.NewIPRateLimitMiddleware(rateLimiter, service.RateLimitRefreshToken)
 
 	// ========== PUBLIC AUTH ROUTES ==========
 	router.POST("/api/auth/google", authController.GoogleAuth)
 	router.POST("/api/auth/login", authController.Login)
-	router.POST("/api/auth/register", authController.Register)
+	router.POST("/api/auth/register", registerLimit(authController.Register))
 	router.POST("/api/auth/verify/send", authController.SendVerificationEmail)
 	router.POST("/api/auth/verify", authController.VerifyEmail)
 	router.POST("/api/auth/forgot-password", authController.ForgotPassword)
 	router.POST("/api/auth/reset-password", authController.ResetPassword)
+	router.POST("/api/auth/refresh", refreshLimit(authController.RefreshToken))
+	router.POST("/api/auth/logout", authController.Logout)
+	router.POST("/api/auth/2fa/verify", authController.VerifyTwoFactor)
+	router.POST("/api/auth/magic-link", authController.SendMagicLink)
+	router.POST("/api/auth/magic-link/login", authController.MagicLinkLogin)
+	router.POST("/api/auth/email-change/confirm", emailChangeController.Confirm)
+	router.POST("/api/auth/email-change/cancel", emailChangeController.CancelByToken)
+
+	// Sign-in with external identity providers (OIDC and GitHub)
+	router.GET("/api/auth/providers", oauthController.ListProviders)
+	router.GET("/api/auth/providers/:provider/authorize", oauthController.Authorize)
+	router.POST("/api/auth/providers/:provider/callback", oauthController.Callback)
 
 	// ========== SAVED JOBS ROUTES ==========
 	router.GET("/api/saved-jobs", userAuth(savedJobController.FindSavedJobs))
@@ -66,13 +97,52 @@ func setupUserRoutes(
 	router.GET("/api/job-details/:vacancyId", jobVacancyController.GetPublicJobDetail)
 
 	// ========== PROTECTED USER PROFILE ROUTES ==========
-	router.GET("/api/user/profile", userAuth(userController.GetProfile))
+	router.GET("/api/user/profile", personalTokenAuth(domain.APIScopeProfileRead, userController.GetProfile))
 	router.PUT("/api/user/profile", userAuth(userController.UpdateProfile))
 	router.GET("/api/user-profile/:username", userAuth(userController.GetByUsername))
 	router.POST("/api/user/photo", userAuth(userController.UploadPhotoProfile))
 	router.DELETE("/api/user/photo", userAuth(userController.DeletePhotoProfile))
 	router.GET("/api/user-peoples", userAuth(userController.GetPeoples))
 
+	// ========== USER SESSION ROUTES ==========
+	router.GET("/api/user/sessions", userAuth(userSessionController.FindActiveSessions))
+	router.DELETE("/api/user/sessions", userAuth(userSessionController.RevokeOtherSessions))
+	router.DELETE("/api/user/sessions/:sessionId", userAuth(userSessionController.RevokeSession))
+
+	// ========== TWO-FACTOR AUTHENTICATION ROUTES ==========
+	router.GET("/api/user/2fa", userAuth(twoFactorController.GetStatus))
+	router.POST("/api/user/2fa/setup", userAuth(twoFactorController.Setup))
+	router.POST("/api/user/2fa/enable", userAuth(twoFactorController.Enable))
+	router.POST("/api/user/2fa/disable", userAuth(twoFactorController.Disable))
+	router.POST("/api/user/2fa/recovery-codes", userAuth(twoFactorController.RegenerateRecoveryCodes))
+
+	router.PUT("/api/user/password", userAuth(authController.ChangePassword))
+
+	// Email change, swapped in once the new address confirms it
+	router.GET("/api/user/email-change", userAuth(emailChangeController.FindPending))
+	router.POST("/api/user/email-change", userAuth(emailChangeController.RequestChange))
+	router.DELETE("/api/user/email-change", userAuth(emailChangeController.CancelPending))
+
+	// Account deletion, cancelled by logging in again during the grace period
+	router.DELETE("/api/user/account", userAuth(accountDeletionController.RequestDeletion))
+
+	// Personal data export, built in the background and downloadable until it expires
+	router.POST("/api/user/export", userAuth(dataExportController.RequestExport))
+	router.GET("/api/user/export", userAuth(dataExportController.FindAll))
+	router.GET("/api/user/export/:exportId", userAuth(dataExportController.FindById))
+	router.GET("/api/user/export/:exportId/download", userAuth(dataExportController.Download))
+
+	// Connected sign-in providers
+	router.GET("/api/user/identities", userAuth(oauthController.FindIdentities))
+	router.POST("/api/user/identities/:provider/authorize", userAuth(oauthController.AuthorizeConnect))
+	router.POST("/api/user/identities/:provider/callback", userAuth(oauthController.Connect))
+	router.DELETE("/api/user/identities/:provider", userAuth(oauthController.Disconnect))
+
+	// Personal access tokens, the token is only shown in the create response
+	router.GET("/api/user/api-tokens", userAuth(apiTokenController.FindPersonalTokens))
+	router.POST("/api/user/api-tokens", userAuth(apiTokenController.CreatePersonalToken))
+	router.DELETE("/api/user/api-tokens/:tokenId", userAuth(apiTokenController.RevokePersonalToken))
+
 	// ========== BLOG ROUTES ==========
 	router.POST("/api/blogs", userAuth(blogController.Create))
 	router.GET("/api/blogs", userAuth(blogController.FindAll))
@@ -200,6 +270,7 @@ func setupUserRoutes(
 	router.GET("/api/conversations/:conversationId/messages", userAuth(chatController.GetMessages))
 	router.PUT("/api/messages/:messageId", userAuth(chatController.UpdateMessage))
 	router.DELETE("/api/messages/:messageId", userAuth(chatController.DeleteMessage))
+	router.GET("/api/messages/:messageId/file", userAuth(chatController.GetMessageFile))
 
 	// Pusher authentication
 	router.POST("/api/pusher/auth", userAuth(chatController.AuthPusher))
@@ -240,6 +311,11 @@ func setupUserRoutes(
 	router.DELETE("/api/companies/:companyId/request-edit", userAuth(companyManagementController.DeleteCompanyEditRequest))
 	router.GET("/api/companies/:companyId/stats", userAuth(companyManagementController.GetCompanyStats))
 
+	// Partner API keys, managed by company admins
+	router.GET("/api/companies/:companyId/api-keys", userAuth(apiTokenController.FindPartnerKeys))
+	router.POST("/api/companies/:companyId/api-keys", userAuth(apiTokenController.CreatePartnerKey))
+	router.DELETE("/api/companies/:companyId/api-keys/:keyId", userAuth(apiTokenController.RevokePartnerKey))
+
 	// ========== MEMBER COMPANY ROUTES ==========
 	router.GET("/api/member-companies/:memberCompanyId", userAuth(memberCompanyController.GetMemberByID))
 	router.PUT("/api/member-companies/:memberCompanyId/role", userAuth(memberCompanyController.UpdateMemberRole))
@@ -303,41 +379,42 @@ func setupUserRoutes(
 
 	// ========== PROTECTED JOB VACANCY ROUTES ==========
 	// User's job vacancy management
-	router.GET("/api/user/job-vacancies", userAuth(jobVacancyController.FindByCreatorId))
+	router.GET("/api/user/job-vacancies", personalTokenAuth(domain.APIScopeJobsRead, jobVacancyController.FindByCreatorId))
 
 	// Company job vacancy management
-	router.POST("/api/companies/:companyId/jobs", userAuth(jobVacancyController.Create))
-	router.GET("/api/companies/:companyId/jobs", userAuth(jobVacancyController.FindByCompanyId))
+	router.POST("/api/companies/:companyId/jobs", tokenAuth(domain.APIScopeJobsWrite, jobVacancyController.Create))
+	router.GET("/api/companies/:companyId/jobs", tokenAuth(domain.APIScopeJobsRead, jobVacancyController.FindByCompanyId))
 
 	// Job vacancy operations - specific routes first
-	router.PUT("/api/job-vacancies/:jobVacancyId/status", userAuth(jobVacancyController.UpdateStatus))
-	router.PUT("/api/job-vacancies/:jobVacancyId", userAuth(jobVacancyController.Update))
-	router.DELETE("/api/job-vacancies/:jobVacancyId", userAuth(jobVacancyController.Delete))
-	router.GET("/api/job-vacancies/:jobVacancyId", userAuth(jobVacancyController.FindById))
+	router.PUT("/api/job-vacancies/:jobVacancyId/status", tokenAuth(domain.APIScopeJobsWrite, jobVacancyController.UpdateStatus))
+	router.PUT("/api/job-vacancies/:jobVacancyId", tokenAuth(domain.APIScopeJobsWrite, jobVacancyController.Update))
+	router.DELETE("/api/job-vacancies/:jobVacancyId", tokenAuth(domain.APIScopeJobsWrite, jobVacancyController.Delete))
+	router.GET("/api/job-vacancies/:jobVacancyId", tokenAuth(domain.APIScopeJobsRead, jobVacancyController.FindById))
 
 	// ========== JOB APPLICATION ROUTES ==========
 	// IMPORTANT: Use separate namespaces to avoid ALL conflicts
 
 	// Application search and stats
-	router.GET("/api/job-app-search", userAuth(jobApplicationController.FindWithFilters))
-	router.GET("/api/job-app-stats", userAuth(jobApplicationController.GetStats))
+	router.GET("/api/job-app-search", tokenAuth(domain.APIScopeApplicationsRead, jobApplicationController.FindWithFilters))
+	router.GET("/api/job-app-stats", tokenAuth(domain.APIScopeApplicationsRead, jobApplicationController.GetStats))
 
 	// User's job applications
-	router.GET("/api/my-applications", userAuth(jobApplicationController.FindByApplicant))
+	router.GET("/api/my-applications", personalTokenAuth(domain.APIScopeApplicationsRead, jobApplicationController.FindByApplicant))
 	router.GET("/api/users/:userId/job-applications", userAuth(jobApplicationController.FindByApplicant))
 
 	// Company job application management
-	router.GET("/api/companies/:companyId/job-applications", userAuth(jobApplicationController.FindByCompany))
-	router.GET("/api/companies/:companyId/app-stats", userAuth(jobApplicationController.GetStats))
+	router.GET("/api/companies/:companyId/job-applications", tokenAuth(domain.APIScopeApplicationsRead, jobApplicationController.FindByCompany))
+	router.GET("/api/companies/:companyId/app-stats", tokenAuth(domain.APIScopeApplicationsRead, jobApplicationController.GetStats))
 
 	// Job vacancy specific routes
 	router.GET("/api/job-vacancies/:jobVacancyId/my-app-status", userAuth(jobApplicationController.CheckApplicationStatus))
-	router.GET("/api/job-vacancies/:jobVacancyId/applicants", userAuth(jobApplicationController.FindByJobVacancy))
-	router.POST("/api/job-applications/:jobVacancyId/apply", userAuth(jobApplicationController.Create))
+	router.GET("/api/job-vacancies/:jobVacancyId/applicants", tokenAuth(domain.APIScopeApplicationsRead, jobApplicationController.FindByJobVacancy))
+	router.POST("/api/job-applications/:jobVacancyId/apply", personalTokenAuth(domain.APIScopeApplicationsWrite, jobApplicationController.Create))
 
 	// Individual job application operations - Use separate namespace
-	router.GET("/api/job-applications/:applicationId", userAuth(jobApplicationController.FindById))
-	router.PUT("/api/job-applications/:applicationId", userAuth(jobApplicationController.Update))
-	router.PUT("/api/job-app/:applicationId/review", userAuth(jobApplicationController.ReviewApplication))
-	router.DELETE("/api/job-app/:applicationId", userAuth(jobApplicationController.Delete))
+	router.GET("/api/job-applications/:applicationId", tokenAuth(domain.APIScopeApplicationsRead, jobApplicationController.FindById))
+	router.PUT("/api/job-applications/:applicationId", personalTokenAuth(domain.APIScopeApplicationsWrite, jobApplicationController.Update))
+	router.PUT("/api/job-app/:applicationId/review", tokenAuth(domain.APIScopeApplicationsWrite, jobApplicationController.ReviewApplication))
+	router.GET("/api/job-app/:applicationId/cv", tokenAuth(domain.APIScopeApplicationsRead, jobApplicationController.ViewCv))
+	router.DELETE("/api/job-app/:applicationId", personalTokenAuth(domain.APIScopeApplicationsWrite, jobApplicationController.Delete))
 }
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/app"
+	"evoconnect/backend/config"
+	"evoconnect/backend/exception"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"os/user"
+	"syscall"
+	"text/tabwriter"
+)
+
+// command is a subcommand of the binary. run returns the process exit code:
+// 0 on success, 1 when the command failed and 2 for invalid usage.
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(ctx context.Context, cfg config.Config, db *sql.DB, logger *slog.Logger, args []string) int
+}
+
+var commands = []command{
+	{"serve", "serve", "start the HTTP server and background workers (default)", runServeCommand},
+	{"migrate", "migrate up|down [-to VERSION]|status|redo", "manage the database schema", runMigrateCommand},
+	{"seed", "seed [-admin=false] [-demo]", "create the default admin and, in debug mode, demo data", runSeedCommand},
+	{"admin", "admin create -email EMAIL -name NAME [-role ROLE] [-password-stdin]", "create an active admin account", runAdminCommand},
+	{"user", "user suspend [-days N]|unsuspend|verify USER", "change a user's account status, USER is an email or id", runUserCommand},
+	{"reindex-search", "reindex-search", "rebuild the indexes of the searched tables", runReindexSearchCommand},
+	{"purge-deleted", "purge-deleted", "erase accounts whose deletion grace period is over", runPurgeDeletedCommand},
+	{"storage", "storage copy [-from-root DIR] [-prefix PREFIX] [-dry-run]", "copy local uploads into the configured storage", runStorageCommand},
+}
+
+// runCommand dispatches to the subcommand named by the first argument and returns the exit code
+func runCommand(args []string, logger *slog.Logger) (code int) {
+	name := "serve"
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage(os.Stdout)
+		return 0
+	}
+
+	var selected *command
+	for i := range commands {
+		if commands[i].name == name {
+			selected = &commands[i]
+		}
+	}
+	if selected == nil {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		printUsage(os.Stderr)
+		return 2
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	db := app.NewDB(cfg.Database)
+	if db == nil {
+		fmt.Fprintln(os.Stderr, "failed to connect to the database")
+		return 1
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			logger.Error("failed to close database", "error", err)
+		}
+	}()
+
+	// Services report failures by panicking, a command prints them instead of a stack trace
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", name, panicMessage(recovered))
+			code = 1
+		}
+	}()
+
+	// SIGINT or SIGTERM cancels ctx, a long command stops at its next checkpoint
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return selected.run(ctx, cfg, db, logger, args)
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: evoconnect <command> [arguments]")
+	fmt.Fprintln(w)
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, command := range commands {
+		fmt.Fprintf(table, "  %s\t%s\n", command.name, command.summary)
+		fmt.Fprintf(table, "  \tevoconnect %s\n", command.usage)
+	}
+	table.Flush()
+}
+
+func usageError(message string) int {
+	fmt.Fprintln(os.Stderr, message)
+	return 2
+}
+
+func panicMessage(recovered interface{}) string {
+	switch err := recovered.(type) {
+	case exception.BadRequestError:
+		return err.Error
+	case exception.NotFoundError:
+		return err.Error
+	case exception.ForbiddenError:
+		return err.Error
+	case exception.UnauthorizedError:
+		return err.Error
+	case exception.InternalServerError:
+		return err.Error
+	case exception.PasswordPolicyError:
+		message := err.Message
+		for _, violation := range err.Violations {
+			message += "\n  - " + violation.Message
+		}
+		return message
+	case error:
+		return err.Error()
+	default:
+		return fmt.Sprint(recovered)
+	}
+}
+
+// operatorContext marks the changes a command makes as done from the command line in the audit log
+func operatorContext(ctx context.Context) context.Context {
+	operator := "unknown"
+	if current, err := user.Current(); err == nil {
+		operator = current.Username
+	}
+	ctx = context.WithValue(ctx, "audit_actor_type", "cli")
+	return context.WithValue(ctx, "audit_actor", operator)
+}
@@ -0,0 +1,275 @@
+package config
+
+// Environments, production requires every secret and refuses placeholder values. APP_ENV has no
+// default, a production deploy that forgets it must not start with the development rules.
+const (
+	EnvironmentDevelopment = "development"
+	EnvironmentProduction  = "production"
+)
+
+// Storage drivers for uploaded files
+const (
+	StorageDriverLocal = "local"
+	StorageDriverS3    = "s3"
+)
+
+// Stores for rate limit hits. Memory only suits a single instance.
+const (
+	RateLimitStorePostgres = "postgres"
+	RateLimitStoreMemory   = "memory"
+)
+
+// Protocols of OAuth providers
+const (
+	OAuthProviderTypeOIDC   = "oidc"
+	OAuthProviderTypeGitHub = "github"
+)
+
+// Malware scanners for uploaded documents
+const (
+	ScannerDriverNone  = "none"
+	ScannerDriverClamd = "clamd"
+)
+
+// Config is the configuration of the server and the commands. It is loaded and validated once by
+// Load, the values are handed to the code that uses them.
+type Config struct {
+	Environment string           `yaml:"environment" toml:"environment"`
+	Debug       bool             `yaml:"debug" toml:"debug"`
+	ClientURL   string           `yaml:"client_url" toml:"client_url"`
+	Server      ServerConfig     `yaml:"server" toml:"server"`
+	Database    DatabaseConfig   `yaml:"database" toml:"database"`
+	JWT         JWTConfig        `yaml:"jwt" toml:"jwt"`
+	Auth        AuthConfig       `yaml:"auth" toml:"auth"`
+	OAuth       OAuthConfig      `yaml:"oauth" toml:"oauth"`
+	RateLimit   RateLimitConfig  `yaml:"rate_limit" toml:"rate_limit"`
+	Email       EmailConfig      `yaml:"email" toml:"email"`
+	Pusher      PusherConfig     `yaml:"pusher" toml:"pusher"`
+	CORS        CORSConfig       `yaml:"cors" toml:"cors"`
+	Metrics     MetricsConfig    `yaml:"metrics" toml:"metrics"`
+	Storage     StorageConfig    `yaml:"storage" toml:"storage"`
+	Scanner     ScannerConfig    `yaml:"scanner" toml:"scanner"`
+	Accounts    AccountsConfig   `yaml:"accounts" toml:"accounts"`
+	DataExport  DataExportConfig `yaml:"data_export" toml:"data_export"`
+	Migrations  MigrationsConfig `yaml:"migrations" toml:"migrations"`
+}
+
+type ServerConfig struct {
+	Address                   string `yaml:"address" toml:"address"`
+	ReadHeaderTimeoutSeconds  int    `yaml:"read_header_timeout_seconds" toml:"read_header_timeout_seconds"`
+	ReadTimeoutSeconds        int    `yaml:"read_timeout_seconds" toml:"read_timeout_seconds"`
+	WriteTimeoutSeconds       int    `yaml:"write_timeout_seconds" toml:"write_timeout_seconds"`
+	IdleTimeoutSeconds        int    `yaml:"idle_timeout_seconds" toml:"idle_timeout_seconds"`
+	ShutdownTimeoutSeconds    int    `yaml:"shutdown_timeout_seconds" toml:"shutdown_timeout_seconds"`
+	ShutdownDrainDelaySeconds int    `yaml:"shutdown_drain_delay_seconds" toml:"shutdown_drain_delay_seconds"`
+}
+
+type DatabaseConfig struct {
+	Host         string `yaml:"host" toml:"host"`
+	Port         int    `yaml:"port" toml:"port"`
+	User         string `yaml:"user" toml:"user"`
+	Password     string `yaml:"password" toml:"password"`
+	Name         string `yaml:"name" toml:"name"`
+	SSLMode      string `yaml:"sslmode" toml:"sslmode"`
+	MaxOpenConns int    `yaml:"max_open_conns" toml:"max_open_conns"`
+	MaxIdleConns int    `yaml:"max_idle_conns" toml:"max_idle_conns"`
+}
+
+// JWTConfig holds the shared secret and the keyrings of user and admin tokens. A keyring is a
+// comma separated list of kid=ALG:material entries, see utils.LoadJWTKeyring.
+type JWTConfig struct {
+	Secret string `yaml:"secret" toml:"secret"`
+	// Signs admin tokens when AdminKeys is empty, defaults to Secret
+	AdminSecret string `yaml:"admin_secret" toml:"admin_secret"`
+	UserKeys    string `yaml:"user_keys" toml:"user_keys"`
+	AdminKeys   string `yaml:"admin_keys" toml:"admin_keys"`
+	// Accepts tokens signed with Secret before key ids existed
+	AcceptLegacyTokens bool `yaml:"accept_legacy_tokens" toml:"accept_legacy_tokens"`
+}
+
+// AuthConfig covers sessions, passwords and login lockouts
+type AuthConfig struct {
+	AccessTokenMinutes      int    `yaml:"access_token_minutes" toml:"access_token_minutes"`
+	RefreshTokenDays        int    `yaml:"refresh_token_days" toml:"refresh_token_days"`
+	PasswordMinLength       int    `yaml:"password_min_length" toml:"password_min_length"`
+	PasswordBlocklistFile   string `yaml:"password_blocklist_file" toml:"password_blocklist_file"`
+	LoginLockoutThreshold   int    `yaml:"login_lockout_threshold" toml:"login_lockout_threshold"`
+	LoginLockoutBaseMinutes int    `yaml:"login_lockout_base_minutes" toml:"login_lockout_base_minutes"`
+}
+
+// OAuthConfig holds the sign-in providers by name. Well-known providers only need a client id,
+// Google is always enabled for the existing sign-in button.
+type OAuthConfig struct {
+	Providers map[string]OAuthProviderConfig `yaml:"providers" toml:"providers"`
+	// Defaults to ClientURL + "/oauth/callback", the provider name is appended
+	RedirectBaseURL string `yaml:"redirect_base_url" toml:"redirect_base_url"`
+}
+
+// OAuthProviderConfig overrides the defaults of a provider, empty fields keep them
+type OAuthProviderConfig struct {
+	DisplayName  string   `yaml:"display_name" toml:"display_name"`
+	Type         string   `yaml:"type" toml:"type"`
+	ClientID     string   `yaml:"client_id" toml:"client_id"`
+	ClientSecret string   `yaml:"client_secret" toml:"client_secret"`
+	Issuer       string   `yaml:"issuer" toml:"issuer"`
+	AuthURL      string   `yaml:"auth_url" toml:"auth_url"`
+	TokenURL     string   `yaml:"token_url" toml:"token_url"`
+	APIURL       string   `yaml:"api_url" toml:"api_url"`
+	Scopes       []string `yaml:"scopes" toml:"scopes"`
+	RedirectURL  string   `yaml:"redirect_url" toml:"redirect_url"`
+}
+
+type RateLimitConfig struct {
+	Store string `yaml:"store" toml:"store"`
+}
+
+type EmailConfig struct {
+	Host     string `yaml:"host" toml:"host"`
+	Port     int    `yaml:"port" toml:"port"`
+	Username string `yaml:"username" toml:"username"`
+	Password string `yaml:"password" toml:"password"`
+	From     string `yaml:"from" toml:"from"`
+}
+
+type MetricsConfig struct {
+	// Bearer token for /metrics, without it the endpoint is disabled
+	Token string `yaml:"token" toml:"token"`
+}
+
+type PusherConfig struct {
+	AppID   string `yaml:"app_id" toml:"app_id"`
+	Key     string `yaml:"key" toml:"key"`
+	Secret  string `yaml:"secret" toml:"secret"`
+	Cluster string `yaml:"cluster" toml:"cluster"`
+}
+
+type CORSConfig struct {
+	// "*" allows any origin
+	AllowedOrigins []string `yaml:"allowed_origins" toml:"allowed_origins"`
+}
+
+// StorageConfig selects where uploads are kept. The local driver only suits a single instance.
+type StorageConfig struct {
+	Driver    string   `yaml:"driver" toml:"driver"`
+	LocalRoot string   `yaml:"local_root" toml:"local_root"`
+	S3        S3Config `yaml:"s3" toml:"s3"`
+
+	// Signs the download URLs of CVs and chat files, shared by every instance
+	SigningKey          string `yaml:"signing_key" toml:"signing_key"`
+	SignedURLTTLSeconds int    `yaml:"signed_url_ttl_seconds" toml:"signed_url_ttl_seconds"`
+}
+
+// S3Config also covers S3 compatible services, Endpoint is a host with an optional port
+type S3Config struct {
+	Endpoint        string `yaml:"endpoint" toml:"endpoint"`
+	Region          string `yaml:"region" toml:"region"`
+	Bucket          string `yaml:"bucket" toml:"bucket"`
+	AccessKeyID     string `yaml:"access_key_id" toml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key" toml:"secret_access_key"`
+	UseSSL          bool   `yaml:"use_ssl" toml:"use_ssl"`
+}
+
+// ScannerConfig selects the malware scanner for CVs and chat files. With none every file passes,
+// after going through the same quarantine as with a real scanner.
+type ScannerConfig struct {
+	Driver         string `yaml:"driver" toml:"driver"`
+	ClamdAddress   string `yaml:"clamd_address" toml:"clamd_address"`
+	TimeoutSeconds int    `yaml:"timeout_seconds" toml:"timeout_seconds"`
+	// A file whose scan keeps failing stays in quarantine after this many attempts
+	MaxAttempts           int `yaml:"max_attempts" toml:"max_attempts"`
+	WorkerIntervalSeconds int `yaml:"worker_interval_seconds" toml:"worker_interval_seconds"`
+}
+
+type AccountsConfig struct {
+	DeletionGraceDays    int `yaml:"deletion_grace_days" toml:"deletion_grace_days"`
+	PurgeIntervalMinutes int `yaml:"purge_interval_minutes" toml:"purge_interval_minutes"`
+}
+
+// DataExportConfig sets how long archives are kept, they are stored with the uploads under exports/
+type DataExportConfig struct {
+	TTLHours              int `yaml:"ttl_hours" toml:"ttl_hours"`
+	CooldownHours         int `yaml:"cooldown_hours" toml:"cooldown_hours"`
+	WorkerIntervalSeconds int `yaml:"worker_interval_seconds" toml:"worker_interval_seconds"`
+}
+
+type MigrationsConfig struct {
+	AllowPending bool `yaml:"allow_pending" toml:"allow_pending"`
+	// The goose version table, existing databases may use another name
+	Table string `yaml:"table" toml:"table"`
+}
+
+// Default holds the values used when neither the file nor the environment sets them.
+// Secrets and the environment have no default.
+func Default() Config {
+	return Config{
+		ClientURL: "http://localhost:3000",
+		Server: ServerConfig{
+			Address:                  "localhost:3000",
+			ReadHeaderTimeoutSeconds: 10,
+			ReadTimeoutSeconds:       60,
+			WriteTimeoutSeconds:      120,
+			IdleTimeoutSeconds:       120,
+			ShutdownTimeoutSeconds:   30,
+		},
+		Database: DatabaseConfig{
+			Host:         "localhost",
+			Port:         5432,
+			User:         "postgres",
+			Name:         "go_database",
+			SSLMode:      "disable",
+			MaxOpenConns: 25,
+			MaxIdleConns: 10,
+		},
+		JWT: JWTConfig{
+			AcceptLegacyTokens: true,
+		},
+		Auth: AuthConfig{
+			AccessTokenMinutes:      15,
+			RefreshTokenDays:        30,
+			PasswordMinLength:       8,
+			LoginLockoutThreshold:   5,
+			LoginLockoutBaseMinutes: 5,
+		},
+		RateLimit: RateLimitConfig{
+			Store: RateLimitStorePostgres,
+		},
+		Email: EmailConfig{
+			Port: 587,
+			From: "EvoConnect <noreply@evoconnect.com>",
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: []string{"*"},
+		},
+		Storage: StorageConfig{
+			Driver:    StorageDriverLocal,
+			LocalRoot: ".",
+			S3: S3Config{
+				UseSSL: true,
+			},
+			SignedURLTTLSeconds: 300,
+		},
+		Scanner: ScannerConfig{
+			Driver:                ScannerDriverNone,
+			ClamdAddress:          "localhost:3310",
+			TimeoutSeconds:        60,
+			MaxAttempts:           10,
+			WorkerIntervalSeconds: 30,
+		},
+		Accounts: AccountsConfig{
+			DeletionGraceDays:    30,
+			PurgeIntervalMinutes: 60,
+		},
+		DataExport: DataExportConfig{
+			TTLHours:              72,
+			CooldownHours:         24,
+			WorkerIntervalSeconds: 60,
+		},
+		Migrations: MigrationsConfig{
+			Table: "goose_db_version",
+		},
+	}
+}
+
+func (cfg Config) IsProduction() bool {
+	return cfg.Environment == EnvironmentProduction
+}
@@ -0,0 +1,245 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Load builds the configuration from the defaults, the optional YAML or TOML file named by
+// CONFIG_FILE and the environment, in increasing priority, and validates the result
+func Load() (Config, error) {
+	cfg := Default()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := loadFile(path, &cfg); err != nil {
+			return cfg, err
+		}
+	}
+
+	if err := applyEnv(&cfg); err != nil {
+		return cfg, err
+	}
+
+	return cfg, cfg.Validate()
+}
+
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	// Unknown keys are rejected, a typo would otherwise silently fall back to the default
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(cfg); err != nil {
+			return fmt.Errorf("parse config file %s: %w", path, err)
+		}
+	case ".toml":
+		metadata, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return fmt.Errorf("parse config file %s: %w", path, err)
+		}
+		if undecoded := metadata.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("parse config file %s: unknown key %s", path, undecoded[0])
+		}
+	default:
+		return fmt.Errorf("config file %s must end in .yaml, .yml or .toml", path)
+	}
+	return nil
+}
+
+// envReader overrides configuration values with the environment variables that are set,
+// collecting the ones that cannot be parsed
+type envReader struct {
+	problems []string
+}
+
+func (reader *envReader) lookup(key string) (string, bool) {
+	value, exists := os.LookupEnv(key)
+	return value, exists && value != ""
+}
+
+func (reader *envReader) string(target *string, key string) {
+	if value, ok := reader.lookup(key); ok {
+		*target = value
+	}
+}
+
+func (reader *envReader) int(target *int, key string) {
+	if value, ok := reader.lookup(key); ok {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			reader.problems = append(reader.problems, fmt.Sprintf("%s must be a whole number, got %q", key, value))
+			return
+		}
+		*target = parsed
+	}
+}
+
+func (reader *envReader) bool(target *bool, key string) {
+	if value, ok := reader.lookup(key); ok {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			reader.problems = append(reader.problems, fmt.Sprintf("%s must be true or false, got %q", key, value))
+			return
+		}
+		*target = parsed
+	}
+}
+
+func (reader *envReader) list(target *[]string, key string) {
+	if value, ok := reader.lookup(key); ok {
+		items := []string{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		*target = items
+	}
+}
+
+// words is list for values that may also be separated by spaces, like OAuth scopes
+func (reader *envReader) words(target *[]string, key string) {
+	if value, ok := reader.lookup(key); ok {
+		*target = strings.FieldsFunc(value, func(r rune) bool { return r == ',' || unicode.IsSpace(r) })
+	}
+}
+
+// oauth adds the providers named in OAUTH_PROVIDERS and reads their OAUTH_<NAME>_* variables.
+// GOOGLE_CLIENT_ID and GOOGLE_CLIENT_SECRET are still read for Google.
+func (reader *envReader) oauth(target *OAuthConfig) {
+	reader.string(&target.RedirectBaseURL, "OAUTH_REDIRECT_BASE_URL")
+
+	names := []string{"google"}
+	var listed []string
+	reader.words(&listed, "OAUTH_PROVIDERS")
+	names = append(names, listed...)
+
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if target.Providers == nil {
+			target.Providers = make(map[string]OAuthProviderConfig)
+		}
+		provider := target.Providers[name]
+
+		prefix := "OAUTH_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		if name == "google" {
+			reader.string(&provider.ClientID, "GOOGLE_CLIENT_ID")
+			reader.string(&provider.ClientSecret, "GOOGLE_CLIENT_SECRET")
+		}
+		reader.string(&provider.DisplayName, prefix+"DISPLAY_NAME")
+		reader.string(&provider.Type, prefix+"TYPE")
+		reader.string(&provider.ClientID, prefix+"CLIENT_ID")
+		reader.string(&provider.ClientSecret, prefix+"CLIENT_SECRET")
+		reader.string(&provider.Issuer, prefix+"ISSUER")
+		reader.string(&provider.AuthURL, prefix+"AUTH_URL")
+		reader.string(&provider.TokenURL, prefix+"TOKEN_URL")
+		reader.string(&provider.APIURL, prefix+"API_URL")
+		reader.words(&provider.Scopes, prefix+"SCOPES")
+		reader.string(&provider.RedirectURL, prefix+"REDIRECT_URL")
+
+		target.Providers[name] = provider
+	}
+}
+
+func applyEnv(cfg *Config) error {
+	reader := &envReader{}
+
+	reader.string(&cfg.Environment, "APP_ENV")
+	reader.bool(&cfg.Debug, "DEBUG_MODE")
+	reader.string(&cfg.ClientURL, "CLIENT_URL")
+
+	reader.string(&cfg.Server.Address, "APP_SERVER")
+	reader.int(&cfg.Server.ReadHeaderTimeoutSeconds, "HTTP_READ_HEADER_TIMEOUT_SECONDS")
+	reader.int(&cfg.Server.ReadTimeoutSeconds, "HTTP_READ_TIMEOUT_SECONDS")
+	reader.int(&cfg.Server.WriteTimeoutSeconds, "HTTP_WRITE_TIMEOUT_SECONDS")
+	reader.int(&cfg.Server.IdleTimeoutSeconds, "HTTP_IDLE_TIMEOUT_SECONDS")
+	reader.int(&cfg.Server.ShutdownTimeoutSeconds, "SHUTDOWN_TIMEOUT_SECONDS")
+	reader.int(&cfg.Server.ShutdownDrainDelaySeconds, "SHUTDOWN_DRAIN_DELAY_SECONDS")
+
+	reader.string(&cfg.Database.Host, "DB_HOST")
+	reader.int(&cfg.Database.Port, "DB_PORT")
+	reader.string(&cfg.Database.User, "DB_USERNAME")
+	reader.string(&cfg.Database.Password, "DB_PASSWORD")
+	reader.string(&cfg.Database.Name, "DB_NAME")
+	reader.string(&cfg.Database.SSLMode, "DB_SSLMODE")
+	reader.int(&cfg.Database.MaxOpenConns, "DB_MAX_OPEN_CONNS")
+	reader.int(&cfg.Database.MaxIdleConns, "DB_MAX_IDLE_CONNS")
+
+	reader.string(&cfg.JWT.Secret, "JWT_SECRET_KEY")
+	reader.string(&cfg.JWT.AdminSecret, "ADMIN_JWT_SECRET_KEY")
+	reader.string(&cfg.JWT.UserKeys, "JWT_USER_KEYS")
+	reader.string(&cfg.JWT.AdminKeys, "JWT_ADMIN_KEYS")
+	reader.bool(&cfg.JWT.AcceptLegacyTokens, "JWT_ACCEPT_LEGACY_TOKENS")
+
+	reader.int(&cfg.Auth.AccessTokenMinutes, "ACCESS_TOKEN_EXPIRES_IN_MINUTES")
+	reader.int(&cfg.Auth.RefreshTokenDays, "REFRESH_TOKEN_EXPIRES_IN_DAYS")
+	reader.int(&cfg.Auth.PasswordMinLength, "PASSWORD_MIN_LENGTH")
+	reader.string(&cfg.Auth.PasswordBlocklistFile, "PASSWORD_BLOCKLIST_FILE")
+	reader.int(&cfg.Auth.LoginLockoutThreshold, "LOGIN_LOCKOUT_THRESHOLD")
+	reader.int(&cfg.Auth.LoginLockoutBaseMinutes, "LOGIN_LOCKOUT_BASE_MINUTES")
+
+	reader.oauth(&cfg.OAuth)
+
+	reader.string(&cfg.RateLimit.Store, "RATE_LIMIT_STORE")
+
+	reader.string(&cfg.Email.Host, "EMAIL_HOST")
+	reader.int(&cfg.Email.Port, "EMAIL_PORT")
+	reader.string(&cfg.Email.Username, "EMAIL_USERNAME")
+	reader.string(&cfg.Email.Password, "EMAIL_PASSWORD")
+	reader.string(&cfg.Email.From, "EMAIL_FROM")
+
+	reader.string(&cfg.Pusher.AppID, "PUSHER_APP_ID")
+	reader.string(&cfg.Pusher.Key, "PUSHER_KEY")
+	reader.string(&cfg.Pusher.Secret, "PUSHER_SECRET")
+	reader.string(&cfg.Pusher.Cluster, "PUSHER_CLUSTER")
+
+	reader.list(&cfg.CORS.AllowedOrigins, "CORS_ALLOWED_ORIGINS")
+
+	reader.string(&cfg.Metrics.Token, "METRICS_TOKEN")
+
+	reader.string(&cfg.Storage.Driver, "STORAGE_DRIVER")
+	reader.string(&cfg.Storage.LocalRoot, "STORAGE_LOCAL_ROOT")
+	reader.string(&cfg.Storage.S3.Endpoint, "S3_ENDPOINT")
+	reader.string(&cfg.Storage.S3.Region, "S3_REGION")
+	reader.string(&cfg.Storage.S3.Bucket, "S3_BUCKET")
+	reader.string(&cfg.Storage.S3.AccessKeyID, "S3_ACCESS_KEY_ID")
+	reader.string(&cfg.Storage.S3.SecretAccessKey, "S3_SECRET_ACCESS_KEY")
+	reader.bool(&cfg.Storage.S3.UseSSL, "S3_USE_SSL")
+	reader.string(&cfg.Storage.SigningKey, "FILE_URL_SIGNING_KEY")
+	reader.int(&cfg.Storage.SignedURLTTLSeconds, "FILE_URL_TTL_SECONDS")
+
+	reader.string(&cfg.Scanner.Driver, "MALWARE_SCANNER")
+	reader.string(&cfg.Scanner.ClamdAddress, "CLAMD_ADDRESS")
+	reader.int(&cfg.Scanner.TimeoutSeconds, "CLAMD_TIMEOUT_SECONDS")
+	reader.int(&cfg.Scanner.MaxAttempts, "FILE_SCAN_MAX_ATTEMPTS")
+	reader.int(&cfg.Scanner.WorkerIntervalSeconds, "FILE_SCAN_WORKER_INTERVAL_SECONDS")
+
+	reader.int(&cfg.Accounts.DeletionGraceDays, "ACCOUNT_DELETION_GRACE_DAYS")
+	reader.int(&cfg.Accounts.PurgeIntervalMinutes, "ACCOUNT_PURGE_INTERVAL_MINUTES")
+
+	reader.int(&cfg.DataExport.TTLHours, "DATA_EXPORT_TTL_HOURS")
+	reader.int(&cfg.DataExport.CooldownHours, "DATA_EXPORT_COOLDOWN_HOURS")
+	reader.int(&cfg.DataExport.WorkerIntervalSeconds, "DATA_EXPORT_WORKER_INTERVAL_SECONDS")
+
+	reader.bool(&cfg.Migrations.AllowPending, "ALLOW_PENDING_MIGRATIONS")
+	reader.string(&cfg.Migrations.Table, "GOOSE_TABLE")
+
+	if len(reader.problems) > 0 {
+		return errors.New("invalid configuration:\n  - " + strings.Join(reader.problems, "\n  - "))
+	}
+	return nil
+}
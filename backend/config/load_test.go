@@ -0,0 +1,88 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadRequiresEnvironment(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("APP_ENV", "")
+
+	_, err := Load()
+	if err == nil || !strings.Contains(err.Error(), "APP_ENV is required") {
+		t.Fatalf("Load() error = %v, want APP_ENV to be required", err)
+	}
+}
+
+func TestLoadReadsEnvironment(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("APP_ENV", EnvironmentDevelopment)
+	t.Setenv("CLIENT_URL", "https://evoconnect.example.com")
+	t.Setenv("ACCESS_TOKEN_EXPIRES_IN_MINUTES", "5")
+	t.Setenv("FILE_SCAN_MAX_ATTEMPTS", "3")
+	t.Setenv("GOOSE_TABLE", "custom.goose_migrations")
+	t.Setenv("OAUTH_PROVIDERS", "github, linkedin")
+	t.Setenv("OAUTH_GITHUB_CLIENT_ID", "github-client")
+	t.Setenv("OAUTH_LINKEDIN_CLIENT_ID", "linkedin-client")
+	t.Setenv("OAUTH_LINKEDIN_SCOPES", "openid email")
+	t.Setenv("GOOGLE_CLIENT_ID", "google-client")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.ClientURL != "https://evoconnect.example.com" || cfg.Auth.AccessTokenMinutes != 5 ||
+		cfg.Scanner.MaxAttempts != 3 || cfg.Migrations.Table != "custom.goose_migrations" {
+		t.Errorf("environment not applied: %+v", cfg)
+	}
+	if cfg.Auth.RefreshTokenDays != 30 || !cfg.JWT.AcceptLegacyTokens {
+		t.Errorf("defaults lost: %+v", cfg)
+	}
+
+	providers := cfg.OAuth.Providers
+	if providers["github"].ClientID != "github-client" || providers["google"].ClientID != "google-client" {
+		t.Errorf("OAuth providers = %+v", providers)
+	}
+	if scopes := providers["linkedin"].Scopes; len(scopes) != 2 || scopes[0] != "openid" || scopes[1] != "email" {
+		t.Errorf("linkedin scopes = %v", scopes)
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	cfg := Default()
+	cfg.Environment = EnvironmentDevelopment
+	cfg.ClientURL = "localhost:3000"
+	cfg.RateLimit.Store = "redis"
+	cfg.Auth.PasswordMinLength = 100
+	cfg.OAuth.Providers = map[string]OAuthProviderConfig{"gitlab": {Type: "saml"}}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate() accepted an invalid configuration")
+	}
+	for _, want := range []string{"CLIENT_URL", "RATE_LIMIT_STORE", "PASSWORD_MIN_LENGTH", "gitlab: type", "gitlab: client id"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() error does not mention %s:\n%v", want, err)
+		}
+	}
+}
+
+func TestValidateProductionSecrets(t *testing.T) {
+	cfg := Default()
+	cfg.Environment = EnvironmentProduction
+	cfg.Database.Password = "database-password"
+	cfg.Email = EmailConfig{Host: "smtp.example.com", Port: 587, Username: "mailer", Password: "mail-password"}
+	cfg.Pusher = PusherConfig{AppID: "1", Key: "key", Secret: "secret", Cluster: "ap1"}
+	cfg.Storage.SigningKey = "signing-key"
+
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "JWT_SECRET_KEY is required") {
+		t.Fatalf("Validate() error = %v, want the JWT secret to be required", err)
+	}
+
+	// A user keyring replaces the shared secret
+	cfg.JWT.UserKeys = "2025=HS256:0123456789abcdef0123456789abcdef"
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Validate reports every problem at once, so a deploy is not fixed one setting at a time
+func (cfg Config) Validate() error {
+	var problems []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	// Fails closed: without APP_ENV a production deploy would skip the production checks below
+	if cfg.Environment == "" {
+		check(false, "APP_ENV is required, set it to %s or %s", EnvironmentDevelopment, EnvironmentProduction)
+	} else {
+		check(cfg.Environment == EnvironmentDevelopment || cfg.Environment == EnvironmentProduction,
+			"APP_ENV must be %s or %s, got %q", EnvironmentDevelopment, EnvironmentProduction, cfg.Environment)
+	}
+
+	clientURL, err := url.Parse(cfg.ClientURL)
+	check(err == nil && (clientURL.Scheme == "http" || clientURL.Scheme == "https") && clientURL.Host != "",
+		"CLIENT_URL must be an http or https URL, got %q", cfg.ClientURL)
+
+	check(cfg.Server.Address != "", "APP_SERVER is required")
+	for _, timeout := range []struct {
+		key     string
+		seconds int
+	}{
+		{"HTTP_READ_HEADER_TIMEOUT_SECONDS", cfg.Server.ReadHeaderTimeoutSeconds},
+		{"HTTP_READ_TIMEOUT_SECONDS", cfg.Server.ReadTimeoutSeconds},
+		{"HTTP_WRITE_TIMEOUT_SECONDS", cfg.Server.WriteTimeoutSeconds},
+		{"HTTP_IDLE_TIMEOUT_SECONDS", cfg.Server.IdleTimeoutSeconds},
+		{"SHUTDOWN_TIMEOUT_SECONDS", cfg.Server.ShutdownTimeoutSeconds},
+		{"SHUTDOWN_DRAIN_DELAY_SECONDS", cfg.Server.ShutdownDrainDelaySeconds},
+	} {
+		check(timeout.seconds >= 0, "%s cannot be negative", timeout.key)
+	}
+
+	check(cfg.Database.Host != "", "DB_HOST is required")
+	check(cfg.Database.Port > 0 && cfg.Database.Port < 65536, "DB_PORT must be a port number, got %d", cfg.Database.Port)
+	check(cfg.Database.User != "", "DB_USERNAME is required")
+	check(cfg.Database.Name != "", "DB_NAME is required")
+	check(cfg.Database.MaxOpenConns > 0, "DB_MAX_OPEN_CONNS must be positive")
+	check(cfg.Database.MaxIdleConns >= 0, "DB_MAX_IDLE_CONNS cannot be negative")
+
+	for _, setting := range []struct {
+		key   string
+		value int
+	}{
+		{"ACCESS_TOKEN_EXPIRES_IN_MINUTES", cfg.Auth.AccessTokenMinutes},
+		{"REFRESH_TOKEN_EXPIRES_IN_DAYS", cfg.Auth.RefreshTokenDays},
+		{"LOGIN_LOCKOUT_THRESHOLD", cfg.Auth.LoginLockoutThreshold},
+		{"LOGIN_LOCKOUT_BASE_MINUTES", cfg.Auth.LoginLockoutBaseMinutes},
+		{"FILE_SCAN_MAX_ATTEMPTS", cfg.Scanner.MaxAttempts},
+		{"FILE_SCAN_WORKER_INTERVAL_SECONDS", cfg.Scanner.WorkerIntervalSeconds},
+		{"ACCOUNT_DELETION_GRACE_DAYS", cfg.Accounts.DeletionGraceDays},
+		{"ACCOUNT_PURGE_INTERVAL_MINUTES", cfg.Accounts.PurgeIntervalMinutes},
+		{"DATA_EXPORT_TTL_HOURS", cfg.DataExport.TTLHours},
+		{"DATA_EXPORT_COOLDOWN_HOURS", cfg.DataExport.CooldownHours},
+		{"DATA_EXPORT_WORKER_INTERVAL_SECONDS", cfg.DataExport.WorkerIntervalSeconds},
+	} {
+		check(setting.value > 0, "%s must be positive", setting.key)
+	}
+	// bcrypt ignores everything after 72 bytes
+	check(cfg.Auth.PasswordMinLength >= 1 && cfg.Auth.PasswordMinLength <= 72,
+		"PASSWORD_MIN_LENGTH must be between 1 and 72, got %d", cfg.Auth.PasswordMinLength)
+	check(cfg.RateLimit.Store == RateLimitStorePostgres || cfg.RateLimit.Store == RateLimitStoreMemory,
+		"RATE_LIMIT_STORE must be %s or %s, got %q", RateLimitStorePostgres, RateLimitStoreMemory, cfg.RateLimit.Store)
+	check(cfg.Migrations.Table != "", "GOOSE_TABLE cannot be empty")
+
+	for name, provider := range cfg.OAuth.Providers {
+		check(provider.Type == "" || provider.Type == OAuthProviderTypeOIDC || provider.Type == OAuthProviderTypeGitHub,
+			"OAuth provider %s: type must be %s or %s, got %q", name, OAuthProviderTypeOIDC, OAuthProviderTypeGitHub, provider.Type)
+		// Google falls back to the client id of the existing sign-in button
+		check(name == "google" || provider.ClientID != "", "OAuth provider %s: client id is required", name)
+	}
+
+	check(cfg.Email.Port > 0 && cfg.Email.Port < 65536, "EMAIL_PORT must be a port number, got %d", cfg.Email.Port)
+	check(len(cfg.CORS.AllowedOrigins) > 0, "CORS_ALLOWED_ORIGINS needs at least one origin, or * for any")
+
+	switch cfg.Storage.Driver {
+	case StorageDriverLocal:
+		check(cfg.Storage.LocalRoot != "", "STORAGE_LOCAL_ROOT is required")
+	case StorageDriverS3:
+		check(cfg.Storage.S3.Endpoint != "", "S3_ENDPOINT is required for the s3 storage driver")
+		check(cfg.Storage.S3.Bucket != "", "S3_BUCKET is required for the s3 storage driver")
+		check(cfg.Storage.S3.AccessKeyID != "", "S3_ACCESS_KEY_ID is required for the s3 storage driver")
+		check(cfg.Storage.S3.SecretAccessKey != "", "S3_SECRET_ACCESS_KEY is required for the s3 storage driver")
+	default:
+		check(false, "STORAGE_DRIVER must be %s or %s, got %q", StorageDriverLocal, StorageDriverS3, cfg.Storage.Driver)
+	}
+	check(cfg.Storage.SignedURLTTLSeconds > 0, "FILE_URL_TTL_SECONDS must be positive")
+
+	switch cfg.Scanner.Driver {
+	case ScannerDriverNone:
+	case ScannerDriverClamd:
+		check(cfg.Scanner.ClamdAddress != "", "CLAMD_ADDRESS is required for the clamd scanner")
+		check(cfg.Scanner.TimeoutSeconds > 0, "CLAMD_TIMEOUT_SECONDS must be positive")
+	default:
+		check(false, "MALWARE_SCANNER must be %s or %s, got %q", ScannerDriverNone, ScannerDriverClamd, cfg.Scanner.Driver)
+	}
+
+	if cfg.IsProduction() {
+		check(!cfg.Debug, "DEBUG_MODE cannot be enabled in production")
+
+		// The keyrings can replace the shared JWT secret, see utils.InitJWT
+		if cfg.JWT.UserKeys == "" {
+			requireSecret(check, "JWT_SECRET_KEY", cfg.JWT.Secret)
+		}
+		requireSecret(check, "DB_PASSWORD", cfg.Database.Password)
+		requireSecret(check, "EMAIL_HOST", cfg.Email.Host)
+		requireSecret(check, "EMAIL_USERNAME", cfg.Email.Username)
+		requireSecret(check, "EMAIL_PASSWORD", cfg.Email.Password)
+		requireSecret(check, "PUSHER_APP_ID", cfg.Pusher.AppID)
+		requireSecret(check, "PUSHER_KEY", cfg.Pusher.Key)
+		requireSecret(check, "PUSHER_SECRET", cfg.Pusher.Secret)
+		requireSecret(check, "PUSHER_CLUSTER", cfg.Pusher.Cluster)
+		requireSecret(check, "FILE_URL_SIGNING_KEY", cfg.Storage.SigningKey)
+	}
+
+	if len(problems) > 0 {
+		return errors.New("invalid configuration:\n  - " + strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+// requireSecret also refuses the "your_..." placeholders of .env.example
+func requireSecret(check func(bool, string, ...interface{}), key string, value string) {
+	check(value != "", "%s is required in production", key)
+	lower := strings.ToLower(value)
+	check(!strings.HasPrefix(lower, "your_") && !strings.HasPrefix(lower, "your-"),
+		"%s is still set to the placeholder %q", key, value)
+}
@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/web"
+	"evoconnect/backend/service"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type AccountDeletionControllerImpl struct {
+	AccountDeletionService service.AccountDeletionService
+}
+
+func NewAccountDeletionController(accountDeletionService service.AccountDeletionService) AccountDeletionController {
+	return &AccountDeletionControllerImpl{
+		AccountDeletionService: accountDeletionService,
+	}
+}
+
+func (controller *AccountDeletionControllerImpl) RequestDeletion(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, _ := currentUserSession(request)
+
+	deleteRequest := web.DeleteAccountRequest{}
+	helper.ReadFromRequestBody(request, &deleteRequest)
+
+	response := controller.AccountDeletionService.RequestDeletion(request.Context(), userId, deleteRequest)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   response,
+	})
+}
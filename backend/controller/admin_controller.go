@@ -7,5 +7,7 @@ import (
 
 type AdminAuthController interface {
     Login(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
-    Register(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+    AcceptInvitation(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+    EnrollTwoFactor(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+    VerifyTwoFactor(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 }
\ No newline at end of file
@@ -33,15 +33,43 @@ func (controller *AdminAuthControllerImpl) Login(writer http.ResponseWriter, req
 	helper.WriteToResponseBody(writer, webResponse)
 }
 
-func (controller *AdminAuthControllerImpl) Register(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
-	registerRequest := web.AdminRegisterRequest{}
-	helper.ReadFromRequestBody(request, &registerRequest)
+func (controller *AdminAuthControllerImpl) AcceptInvitation(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	acceptRequest := web.AdminAcceptInvitationRequest{}
+	helper.ReadFromRequestBody(request, &acceptRequest)
 
-	registerResponse := controller.AdminAuthService.Register(request.Context(), registerRequest)
+	adminResponse := controller.AdminAuthService.AcceptInvitation(request.Context(), acceptRequest)
 	webResponse := web.WebResponse{
 		Code:   201,
 		Status: "CREATED",
-		Data:   registerResponse,
+		Data:   adminResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *AdminAuthControllerImpl) EnrollTwoFactor(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	enrollRequest := web.TwoFactorEnrollRequest{}
+	helper.ReadFromRequestBody(request, &enrollRequest)
+
+	setupResponse := controller.AdminAuthService.EnrollTwoFactor(request.Context(), enrollRequest)
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   setupResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *AdminAuthControllerImpl) VerifyTwoFactor(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	twoFactorRequest := web.TwoFactorLoginRequest{}
+	helper.ReadFromRequestBody(request, &twoFactorRequest)
+
+	loginResponse := controller.AdminAuthService.VerifyTwoFactorLogin(request.Context(), twoFactorRequest)
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   loginResponse,
 	}
 
 	helper.WriteToResponseBody(writer, webResponse)
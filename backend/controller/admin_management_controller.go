@@ -0,0 +1,15 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type AdminManagementController interface {
+	FindAll(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindPendingInvitations(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Invite(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	UpdateRole(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	UpdateStatus(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"evoconnect/backend/exception"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/web"
+	"evoconnect/backend/service"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+)
+
+type AdminManagementControllerImpl struct {
+	AdminManagementService service.AdminManagementService
+}
+
+func NewAdminManagementController(adminManagementService service.AdminManagementService) AdminManagementController {
+	return &AdminManagementControllerImpl{
+		AdminManagementService: adminManagementService,
+	}
+}
+
+func (controller *AdminManagementControllerImpl) FindAll(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	adminResponses := controller.AdminManagementService.FindAll(request.Context())
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   adminResponses,
+	})
+}
+
+func (controller *AdminManagementControllerImpl) FindPendingInvitations(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	invitationResponses := controller.AdminManagementService.FindPendingInvitations(request.Context())
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   invitationResponses,
+	})
+}
+
+func (controller *AdminManagementControllerImpl) Invite(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	inviteRequest := web.AdminInviteRequest{}
+	helper.ReadFromRequestBody(request, &inviteRequest)
+
+	invitationResponse := controller.AdminManagementService.Invite(request.Context(), currentAdminId(request), inviteRequest)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   201,
+		Status: "CREATED",
+		Data:   invitationResponse,
+	})
+}
+
+func (controller *AdminManagementControllerImpl) UpdateRole(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	adminId, err := uuid.Parse(params.ByName("adminId"))
+	if err != nil {
+		panic(exception.NewBadRequestError("Invalid admin ID"))
+	}
+
+	roleRequest := web.AdminUpdateRoleRequest{}
+	helper.ReadFromRequestBody(request, &roleRequest)
+
+	adminResponse := controller.AdminManagementService.UpdateRole(request.Context(), currentAdminId(request), adminId, roleRequest)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   adminResponse,
+	})
+}
+
+func (controller *AdminManagementControllerImpl) UpdateStatus(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	adminId, err := uuid.Parse(params.ByName("adminId"))
+	if err != nil {
+		panic(exception.NewBadRequestError("Invalid admin ID"))
+	}
+
+	statusRequest := web.AdminUpdateStatusRequest{}
+	helper.ReadFromRequestBody(request, &statusRequest)
+
+	adminResponse := controller.AdminManagementService.UpdateStatus(request.Context(), currentAdminId(request), adminId, statusRequest)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   adminResponse,
+	})
+}
+
+// currentAdminId returns the admin set on the context by the admin auth middleware
+func currentAdminId(request *http.Request) uuid.UUID {
+	adminId, err := uuid.Parse(request.Context().Value("admin_id").(string))
+	if err != nil {
+		panic(exception.NewUnauthorizedError("Invalid admin token"))
+	}
+	return adminId
+}
@@ -0,0 +1,16 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type APITokenController interface {
+	CreatePersonalToken(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindPersonalTokens(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	RevokePersonalToken(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	CreatePartnerKey(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindPartnerKeys(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	RevokePartnerKey(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"evoconnect/backend/exception"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/web"
+	"evoconnect/backend/service"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+)
+
+type APITokenControllerImpl struct {
+	APITokenService service.APITokenService
+}
+
+func NewAPITokenController(apiTokenService service.APITokenService) APITokenController {
+	return &APITokenControllerImpl{
+		APITokenService: apiTokenService,
+	}
+}
+
+func (controller *APITokenControllerImpl) CreatePersonalToken(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, _ := currentUserSession(request)
+
+	createRequest := web.CreateAPITokenRequest{}
+	helper.ReadFromRequestBody(request, &createRequest)
+
+	response := controller.APITokenService.CreatePersonalToken(request.Context(), userId, createRequest)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   201,
+		Status: "CREATED",
+		Data:   response,
+	})
+}
+
+func (controller *APITokenControllerImpl) FindPersonalTokens(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, _ := currentUserSession(request)
+
+	response := controller.APITokenService.FindPersonalTokens(request.Context(), userId)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   response,
+	})
+}
+
+func (controller *APITokenControllerImpl) RevokePersonalToken(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, _ := currentUserSession(request)
+
+	response := controller.APITokenService.RevokePersonalToken(request.Context(), userId, parseUUIDParam(params, "tokenId", "Invalid token ID"))
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   response,
+	})
+}
+
+func (controller *APITokenControllerImpl) CreatePartnerKey(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, _ := currentUserSession(request)
+
+	createRequest := web.CreateAPITokenRequest{}
+	helper.ReadFromRequestBody(request, &createRequest)
+
+	companyId := parseUUIDParam(params, "companyId", "Invalid company ID")
+	response := controller.APITokenService.CreatePartnerKey(request.Context(), userId, companyId, createRequest)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   201,
+		Status: "CREATED",
+		Data:   response,
+	})
+}
+
+func (controller *APITokenControllerImpl) FindPartnerKeys(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, _ := currentUserSession(request)
+
+	companyId := parseUUIDParam(params, "companyId", "Invalid company ID")
+	response := controller.APITokenService.FindPartnerKeys(request.Context(), userId, companyId)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   response,
+	})
+}
+
+func (controller *APITokenControllerImpl) RevokePartnerKey(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, _ := currentUserSession(request)
+
+	companyId := parseUUIDParam(params, "companyId", "Invalid company ID")
+	keyId := parseUUIDParam(params, "keyId", "Invalid API key ID")
+	response := controller.APITokenService.RevokePartnerKey(request.Context(), userId, companyId, keyId)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   response,
+	})
+}
+
+func parseUUIDParam(params httprouter.Params, name string, message string) uuid.UUID {
+	id, err := uuid.Parse(params.ByName(name))
+	if err != nil {
+		panic(exception.NewBadRequestError(message))
+	}
+	return id
+}
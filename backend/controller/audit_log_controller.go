@@ -0,0 +1,12 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type AuditLogController interface {
+	FindAll(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	ExportCSV(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
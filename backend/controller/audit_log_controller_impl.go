@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"evoconnect/backend/exception"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/domain"
+	"evoconnect/backend/model/web"
+	"evoconnect/backend/service"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+)
+
+type AuditLogControllerImpl struct {
+	AuditLogService service.AuditLogService
+}
+
+func NewAuditLogController(auditLogService service.AuditLogService) AuditLogController {
+	return &AuditLogControllerImpl{
+		AuditLogService: auditLogService,
+	}
+}
+
+func (controller *AuditLogControllerImpl) FindAll(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	filter := parseAuditLogFilter(request)
+
+	limit, offset, err := helper.GetPaginationParams(request)
+	if err != nil {
+		panic(exception.NewBadRequestError(err.Error()))
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	auditLogResponse := controller.AuditLogService.FindAll(request.Context(), filter, limit, offset)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   auditLogResponse,
+	})
+}
+
+func (controller *AuditLogControllerImpl) ExportCSV(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	filter := parseAuditLogFilter(request)
+
+	filename := fmt.Sprintf("audit-logs-%s.csv", time.Now().Format("20060102-150405"))
+	writer.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+
+	controller.AuditLogService.ExportCSV(request.Context(), filter, writer)
+}
+
+// parseAuditLogFilter reads actor_id, action, target_type, target_id, from and to. Dates
+// accept RFC 3339 or YYYY-MM-DD, a plain "to" date includes the whole day.
+func parseAuditLogFilter(request *http.Request) domain.AuditLogFilter {
+	query := request.URL.Query()
+	filter := domain.AuditLogFilter{
+		Action:     query.Get("action"),
+		TargetType: query.Get("target_type"),
+		TargetId:   query.Get("target_id"),
+	}
+
+	if actorIdStr := query.Get("actor_id"); actorIdStr != "" {
+		actorId, err := uuid.Parse(actorIdStr)
+		if err != nil {
+			panic(exception.NewBadRequestError("Invalid actor_id"))
+		}
+		filter.ActorId = &actorId
+	}
+
+	if fromStr := query.Get("from"); fromStr != "" {
+		from, _, err := parseAuditLogDate(fromStr)
+		if err != nil {
+			panic(exception.NewBadRequestError("Invalid from date"))
+		}
+		filter.From = &from
+	}
+
+	if toStr := query.Get("to"); toStr != "" {
+		to, dateOnly, err := parseAuditLogDate(toStr)
+		if err != nil {
+			panic(exception.NewBadRequestError("Invalid to date"))
+		}
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		filter.To = &to
+	}
+
+	return filter
+}
+
+func parseAuditLogDate(value string) (time.Time, bool, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, true, nil
+	}
+	date, err := time.Parse(time.RFC3339, value)
+	return date, false, err
+}
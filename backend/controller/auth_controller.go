@@ -14,4 +14,10 @@ type AuthController interface {
 	ForgotPassword(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	ResetPassword(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	GoogleAuth(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	RefreshToken(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Logout(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	VerifyTwoFactor(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	SendMagicLink(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	MagicLinkLogin(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	ChangePassword(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 }
@@ -148,3 +148,89 @@ func (controller *AuthControllerImpl) ResetPassword(writer http.ResponseWriter,
 
 	helper.WriteToResponseBody(writer, webResponse)
 }
+
+func (controller *AuthControllerImpl) RefreshToken(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	refreshRequest := web.RefreshTokenRequest{}
+	helper.ReadFromRequestBody(request, &refreshRequest)
+
+	tokenResponse := controller.AuthService.RefreshToken(request.Context(), refreshRequest)
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   tokenResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *AuthControllerImpl) Logout(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	logoutRequest := web.RefreshTokenRequest{}
+	helper.ReadFromRequestBody(request, &logoutRequest)
+
+	messageResponse := controller.AuthService.Logout(request.Context(), logoutRequest)
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   messageResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *AuthControllerImpl) VerifyTwoFactor(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	twoFactorRequest := web.TwoFactorLoginRequest{}
+	helper.ReadFromRequestBody(request, &twoFactorRequest)
+
+	loginResponse := controller.AuthService.VerifyTwoFactorLogin(request.Context(), twoFactorRequest)
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   loginResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *AuthControllerImpl) SendMagicLink(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	emailRequest := web.EmailRequest{}
+	helper.ReadFromRequestBody(request, &emailRequest)
+
+	response := controller.AuthService.SendMagicLink(request.Context(), emailRequest)
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   response,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *AuthControllerImpl) MagicLinkLogin(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	magicLinkRequest := web.MagicLinkLoginRequest{}
+	helper.ReadFromRequestBody(request, &magicLinkRequest)
+
+	loginResponse := controller.AuthService.MagicLinkLogin(request.Context(), magicLinkRequest)
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   loginResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *AuthControllerImpl) ChangePassword(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, sessionId := currentUserSession(request)
+
+	changePasswordRequest := web.ChangePasswordRequest{}
+	helper.ReadFromRequestBody(request, &changePasswordRequest)
+
+	response := controller.AuthService.ChangePassword(request.Context(), userId, sessionId, changePasswordRequest)
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   response,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
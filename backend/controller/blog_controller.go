@@ -8,6 +8,7 @@ import (
 	"evoconnect/backend/service"
 	"fmt"
 	"github.com/julienschmidt/httprouter"
+	"log/slog"
 	"net/http"
 )
 
@@ -116,6 +117,7 @@ func (c *BlogControllerImpl) Create(writer http.ResponseWriter, request *http.Re
 		defer file.Close()
 		savedPath, err = helper.SaveBlogImage(file, fileHeader, userID) // Tambahkan userID
 		if err != nil {
+			helper.PanicIfUploadRejected(err)
 			webResponse := web.WebResponse{
 				Code:   http.StatusInternalServerError,
 				Status: "INTERNAL SERVER ERROR",
@@ -198,11 +200,9 @@ func (c *BlogControllerImpl) Delete(writer http.ResponseWriter, request *http.Re
 
 func (c *BlogControllerImpl) GetBySlug(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
     slug := ps.ByName("slug")
-    fmt.Printf("DEBUG: Getting blog by slug: %s\n", slug)
     
     blog, err := c.BlogService.FindBySlug(r.Context(), slug)
     if err != nil {
-        fmt.Printf("DEBUG: Error getting blog: %v\n", err)
         helper.WriteJSON(w, http.StatusNotFound, web.APIResponse{
             Code:   http.StatusNotFound,
             Status: "NOT_FOUND",
@@ -211,8 +211,6 @@ func (c *BlogControllerImpl) GetBySlug(w http.ResponseWriter, r *http.Request, p
         return
     }
     
-    fmt.Printf("DEBUG: Blog found, warning: '%s'\n", blog.Warning)
-    
     helper.WriteJSON(w, http.StatusOK, web.APIResponse{
         Code:   http.StatusOK,
         Status: "OK",
@@ -312,13 +310,14 @@ func (c *BlogControllerImpl) Update(writer http.ResponseWriter, request *http.Re
 		if existingBlog.Photo != "" {
 			err = helper.DeleteBlogImage(existingBlog.Photo)
 			if err != nil {
-				fmt.Printf("Error deleting old image: %v\n", err)
+				slog.WarnContext(request.Context(), "failed to delete old blog image", "error", err)
 			}
 		}
 
 		// Simpan gambar baru dengan userID
 		savedPath, err = helper.SaveBlogImage(file, fileHeader, userID) // Tambahkan userID
 		if err != nil {
+			helper.PanicIfUploadRejected(err)
 			webResponse := web.WebResponse{
 				Code:   http.StatusInternalServerError,
 				Status: "INTERNAL SERVER ERROR",
@@ -373,6 +372,7 @@ func (c *BlogControllerImpl) UploadPhoto(writer http.ResponseWriter, request *ht
 	// Panggil service untuk menyimpan file
 	photoPath, err := c.BlogService.UploadPhoto(request.Context(), blogId, userId, file, fileHeader)
 	if err != nil {
+		helper.PanicIfUploadRejected(err)
 		webResponse := web.WebResponse{
 			Code:   http.StatusInternalServerError,
 			Status: "INTERNAL SERVER ERROR",
@@ -446,9 +446,10 @@ func (c *BlogControllerImpl) CreateWithImage(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// Upload gambar
-	imagePath, err := c.BlogService.UploadPhoto(r.Context(), "", userID, file, fileHeader)
+	// Upload gambar, blog belum ada sehingga disimpan langsung
+	imagePath, err := helper.SaveBlogImage(file, fileHeader, userID)
 	if err != nil {
+		helper.PanicIfUploadRejected(err)
 		http.Error(w, "Upload gambar gagal", http.StatusInternalServerError)
 		return
 	}
@@ -19,6 +19,7 @@ type ChatController interface {
 	GetMessages(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	UpdateMessage(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	DeleteMessage(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	GetMessageFile(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 
 	// Pusher Auth
 	AuthPusher(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
@@ -10,7 +10,7 @@ import (
 	"evoconnect/backend/service"
 	"evoconnect/backend/utils"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
@@ -238,6 +238,23 @@ func (controller *ChatControllerImpl) DeleteMessage(writer http.ResponseWriter,
 	helper.WriteToResponseBody(writer, webResponse)
 }
 
+func (controller *ChatControllerImpl) GetMessageFile(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, err := helper.GetUserIdFromToken(request)
+	helper.PanicIfError(err)
+
+	messageId, err := uuid.Parse(params.ByName("messageId"))
+	helper.PanicIfError(err)
+
+	fileResponse := controller.ChatService.GetMessageFileUrl(request.Context(), userId, messageId)
+
+	webResponse := web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   fileResponse,
+	}
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
 type AuthPusherRequest struct {
 	SocketId    string `json:"socket_id"`
 	ChannelName string `json:"channel_name"`
@@ -247,15 +264,13 @@ type AuthPusherRequest struct {
 func (c *ChatControllerImpl) AuthPusher(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	var socketId, channelName string
 
-	// Log the content type for debugging
 	contentType := r.Header.Get("Content-Type")
-	log.Printf("Request Content-Type: %s", contentType)
 
 	// Handle multipart form data (what your client is sending)
 	if strings.Contains(contentType, "multipart/form-data") {
 		// Parse multipart form with 10MB max memory
 		if err := r.ParseMultipartForm(10 << 20); err != nil {
-			log.Printf("Error parsing multipart form: %v", err)
+			slog.WarnContext(r.Context(), "failed to parse pusher auth form", "error", err)
 		} else {
 			// Get form values
 			if values, ok := r.MultipartForm.Value["socket_id"]; ok && len(values) > 0 {
@@ -264,27 +279,16 @@ func (c *ChatControllerImpl) AuthPusher(w http.ResponseWriter, r *http.Request,
 			if values, ok := r.MultipartForm.Value["channel_name"]; ok && len(values) > 0 {
 				channelName = values[0]
 			}
-			log.Printf("Multipart form data - socket_id: %s, channel_name: %s", socketId, channelName)
 		}
 	} else {
 		// Handle regular form data as fallback
 		if err := r.ParseForm(); err == nil {
 			socketId = r.FormValue("socket_id")
 			channelName = r.FormValue("channel_name")
-			log.Printf("Regular form data - socket_id: %s, channel_name: %s", socketId, channelName)
 		}
 	}
 
-	// Log the actual form data as received
-	log.Printf("Raw form data: %+v", r.Form)
-	if r.MultipartForm != nil {
-		log.Printf("Raw multipart form values: %+v", r.MultipartForm.Value)
-	}
-
-	log.Printf("Final values - Socket ID: %s, Channel: %s", socketId, channelName)
-
 	if socketId == "" || channelName == "" {
-		log.Printf("Missing required parameters - socketId: %s, channelName: %s", socketId, channelName)
 		http.Error(w, "Missing required parameters", http.StatusBadRequest)
 		return
 	}
@@ -310,6 +314,5 @@ func (c *ChatControllerImpl) AuthPusher(w http.ResponseWriter, r *http.Request,
 	// Return the response
 	w.Header().Set("Content-Type", "application/json")
 	jsonResponse, _ := json.Marshal(authResponse)
-	log.Printf("Sending auth response: %s", string(jsonResponse))
 	w.Write(jsonResponse)
 }
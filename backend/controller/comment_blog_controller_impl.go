@@ -5,7 +5,6 @@ import (
 	"evoconnect/backend/helper"
 	"evoconnect/backend/model/web"
 	"evoconnect/backend/service"
-	"fmt"
 	"net/http"
 	"strconv"
 
@@ -180,7 +179,6 @@ func (controller *CommentBlogControllerImpl) Reply(writer http.ResponseWriter, r
 		panic(exception.NewBadRequestError("Invalid user ID format"))
 	}
 
-	fmt.Printf("Replying to comment %s by user %s\n", commentId, userId)
 	replyResponse := controller.CommentBlogService.Reply(request.Context(), commentId, userId, replyRequest)
 
 	webResponse := web.WebResponse{
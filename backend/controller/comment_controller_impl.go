@@ -5,7 +5,6 @@ import (
 	"evoconnect/backend/helper"
 	"evoconnect/backend/model/web"
 	"evoconnect/backend/service"
-	"fmt"
 	"net/http"
 	"strconv"
 
@@ -182,7 +181,6 @@ func (controller *CommentControllerImpl) Reply(writer http.ResponseWriter, reque
 	helper.PanicIfError(err)
 
 	// Panggil service untuk membalas komentar
-	fmt.Printf("Replying to comment %s by user %s\n", commentId, userId)
 	replyResponse := controller.CommentService.Reply(request.Context(), commentId, userId, replyRequest)
 
 	// Buat response
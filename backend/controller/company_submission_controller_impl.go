@@ -6,7 +6,6 @@ import (
 	"evoconnect/backend/helper"
 	"evoconnect/backend/model/web"
 	"evoconnect/backend/service"
-	"fmt"
 	"mime/multipart"
 	"net/http"
 	"strconv"
@@ -137,7 +136,6 @@ func (controller *CompanySubmissionControllerImpl) FindAll(writer http.ResponseW
 	}
 
 	// Log untuk debugging
-	fmt.Printf("FindAll called with limit: %d, offset: %d\n", limit, offset)
 
 	responses := controller.CompanySubmissionService.FindAll(request.Context(), limit, offset)
 
@@ -192,7 +190,6 @@ func (controller *CompanySubmissionControllerImpl) FindByStatus(writer http.Resp
 	}
 
 	// Log untuk debugging
-	fmt.Printf("FindByStatus called with status: %s, limit: %d, offset: %d\n", status, limit, offset)
 
 	responses := controller.CompanySubmissionService.FindByStatus(request.Context(), status, limit, offset)
 
@@ -216,14 +213,12 @@ func (controller *CompanySubmissionControllerImpl) Review(writer http.ResponseWr
 		return
 	}
 
-	fmt.Printf("Review called for submission ID: %s\n", submissionId)
 	// Get reviewer ID from context (set by auth middleware)
 	reviewerId, err := helper.GetAdminIdFromToken(request)
 	if err != nil {
 		helper.PanicIfError(err)
 	}
 
-	fmt.Printf("Reviewer ID: %s\n", reviewerId)
 	var reviewRequest web.ReviewCompanySubmissionRequest
 	decoder := json.NewDecoder(request.Body)
 	err = decoder.Decode(&reviewRequest)
@@ -236,9 +231,7 @@ func (controller *CompanySubmissionControllerImpl) Review(writer http.ResponseWr
 		return
 	}
 
-	fmt.Printf("Review request: %+v\n", reviewRequest)
 	response := controller.CompanySubmissionService.Review(request.Context(), submissionId, reviewerId, reviewRequest)
-	fmt.Printf("Review response: %+v\n", response)
 
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
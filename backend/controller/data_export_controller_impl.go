@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"errors"
+	"evoconnect/backend/exception"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/web"
+	"evoconnect/backend/service"
+	"evoconnect/backend/storage"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+)
+
+type DataExportControllerImpl struct {
+	DataExportService service.DataExportService
+}
+
+func NewDataExportController(dataExportService service.DataExportService) DataExportController {
+	return &DataExportControllerImpl{
+		DataExportService: dataExportService,
+	}
+}
+
+func (controller *DataExportControllerImpl) RequestExport(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, _ := currentUserSession(request)
+
+	response := controller.DataExportService.RequestExport(request.Context(), userId)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   202,
+		Status: "ACCEPTED",
+		Data:   response,
+	})
+}
+
+func (controller *DataExportControllerImpl) FindAll(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, _ := currentUserSession(request)
+
+	response := controller.DataExportService.FindAll(request.Context(), userId)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   response,
+	})
+}
+
+func (controller *DataExportControllerImpl) FindById(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, _ := currentUserSession(request)
+
+	response := controller.DataExportService.FindById(request.Context(), userId, parseExportId(params))
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   response,
+	})
+}
+
+func (controller *DataExportControllerImpl) Download(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, _ := currentUserSession(request)
+
+	key, fileName := controller.DataExportService.FindDownload(request.Context(), userId, parseExportId(params))
+
+	writer.Header().Set("Content-Type", "application/zip")
+	writer.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+	writer.Header().Set("Cache-Control", "no-store")
+
+	err := helper.ServeStoredFile(writer, request, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		writer.Header().Del("Content-Disposition")
+		panic(exception.NewNotFoundError("Data export file not found"))
+	}
+	helper.PanicIfError(err)
+}
+
+func parseExportId(params httprouter.Params) uuid.UUID {
+	exportId, err := uuid.Parse(params.ByName("exportId"))
+	if err != nil {
+		panic(exception.NewBadRequestError("Invalid export ID"))
+	}
+	return exportId
+}
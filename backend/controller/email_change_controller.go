@@ -0,0 +1,15 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type EmailChangeController interface {
+	RequestChange(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindPending(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	CancelPending(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Confirm(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	CancelByToken(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/web"
+	"evoconnect/backend/service"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type EmailChangeControllerImpl struct {
+	EmailChangeService service.EmailChangeService
+}
+
+func NewEmailChangeController(emailChangeService service.EmailChangeService) EmailChangeController {
+	return &EmailChangeControllerImpl{
+		EmailChangeService: emailChangeService,
+	}
+}
+
+func (controller *EmailChangeControllerImpl) RequestChange(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, _ := currentUserSession(request)
+
+	changeRequest := web.ChangeEmailRequest{}
+	helper.ReadFromRequestBody(request, &changeRequest)
+
+	response := controller.EmailChangeService.RequestChange(request.Context(), userId, changeRequest)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   response,
+	})
+}
+
+func (controller *EmailChangeControllerImpl) FindPending(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, _ := currentUserSession(request)
+
+	response := controller.EmailChangeService.FindPending(request.Context(), userId)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   response,
+	})
+}
+
+func (controller *EmailChangeControllerImpl) CancelPending(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, _ := currentUserSession(request)
+
+	response := controller.EmailChangeService.CancelPending(request.Context(), userId)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   response,
+	})
+}
+
+func (controller *EmailChangeControllerImpl) Confirm(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	tokenRequest := web.EmailChangeTokenRequest{}
+	helper.ReadFromRequestBody(request, &tokenRequest)
+
+	response := controller.EmailChangeService.Confirm(request.Context(), tokenRequest)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   response,
+	})
+}
+
+func (controller *EmailChangeControllerImpl) CancelByToken(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	tokenRequest := web.EmailChangeTokenRequest{}
+	helper.ReadFromRequestBody(request, &tokenRequest)
+
+	response := controller.EmailChangeService.CancelByToken(request.Context(), tokenRequest)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   response,
+	})
+}
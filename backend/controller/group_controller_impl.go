@@ -6,7 +6,6 @@ import (
 	"evoconnect/backend/helper"
 	"evoconnect/backend/model/web"
 	"evoconnect/backend/service"
-	"fmt"
 	"github.com/google/uuid"
 	"github.com/julienschmidt/httprouter"
 	"mime/multipart"
@@ -218,8 +217,6 @@ func (controller *GroupControllerImpl) GetGroupPosts(writer http.ResponseWriter,
     groupId, err := uuid.Parse(params.ByName("groupId"))
     helper.PanicIfError(err)
     
-    fmt.Printf("DEBUG: Getting posts for group %s by user %s\n", groupId, userId)
-
     // Parse query params for pagination
     limit := 10 // Default
     offset := 0 // Default
@@ -243,8 +240,6 @@ func (controller *GroupControllerImpl) GetGroupPosts(writer http.ResponseWriter,
     // Get posts for group
     posts := controller.PostService.FindByGroupId(request.Context(), groupId, userId, limit, offset)
     
-    fmt.Printf("DEBUG: Found %d posts for group %s\n", len(posts), groupId)
-
     // Send response
     webResponse := web.WebResponse{
         Code:   http.StatusOK,
@@ -404,8 +399,6 @@ func (controller *GroupControllerImpl) JoinGroup(writer http.ResponseWriter, req
 		panic(exception.NewBadRequestError("invalid group ID format"))
 	}
 
-	fmt.Printf("DEBUG Controller: Joining group %s with user %s\n", groupId, userId)
-
 	// Perbaiki urutan parameter: userId dulu, baru groupId
 	response := controller.GroupService.JoinPublicGroup(request.Context(), userId, groupId)
 
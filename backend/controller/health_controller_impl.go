@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/web"
+	"evoconnect/backend/service"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type HealthControllerImpl struct {
+	HealthService service.HealthService
+}
+
+func NewHealthController(healthService service.HealthService) HealthController {
+	return &HealthControllerImpl{
+		HealthService: healthService,
+	}
+}
+
+func (controller *HealthControllerImpl) Liveness(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   controller.HealthService.Liveness(),
+	})
+}
+
+func (controller *HealthControllerImpl) Readiness(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	health, ready := controller.HealthService.Readiness(request.Context())
+
+	// Probes only look at the status code
+	if !ready {
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(http.StatusServiceUnavailable)
+		helper.WriteToResponseBody(writer, web.WebResponse{
+			Code:   http.StatusServiceUnavailable,
+			Status: "SERVICE_UNAVAILABLE",
+			Data:   health,
+		})
+		return
+	}
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   health,
+	})
+}
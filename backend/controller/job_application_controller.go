@@ -18,4 +18,5 @@ type JobApplicationController interface {
 	ReviewApplication(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	GetStats(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	CheckApplicationStatus(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	ViewCv(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 }
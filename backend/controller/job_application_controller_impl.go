@@ -602,3 +602,31 @@ func (controller *JobApplicationControllerImpl) CheckApplicationStatus(writer ht
 
 	helper.WriteToResponseBody(writer, webResponse)
 }
+
+func (controller *JobApplicationControllerImpl) ViewCv(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	// Get authenticated user
+	userId, err := helper.GetUserIdFromToken(request)
+	helper.PanicIfError(err)
+
+	// Parse application ID from URL
+	applicationIdStr := params.ByName("applicationId")
+	applicationId, err := uuid.Parse(applicationIdStr)
+	if err != nil {
+		helper.WriteToResponseBody(writer, web.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "BAD REQUEST",
+			Data:   "Invalid application ID format",
+		})
+		return
+	}
+
+	cvResponse := controller.JobApplicationService.ViewCv(request.Context(), applicationId, userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   cvResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
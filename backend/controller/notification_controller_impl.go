@@ -129,8 +129,6 @@ func (controller *NotificationControllerImpl) AuthPusher(writer http.ResponseWri
 	socketId := request.PostForm.Get("socket_id")
 	channelName := request.PostForm.Get("channel_name")
 
-	fmt.Printf("Pusher auth request: socketId=%s, channelName=%s\n", socketId, channelName)
-
 	// Get user ID from context
 	userIdStr, ok := request.Context().Value("user_id").(string)
 	if !ok {
@@ -145,8 +143,6 @@ func (controller *NotificationControllerImpl) AuthPusher(writer http.ResponseWri
 		// Extract user ID from channel name
 		channelUserId := strings.TrimPrefix(channelName, "private-user-")
 
-		fmt.Printf("Channel user ID: %s, Current user ID: %s\n", channelUserId, userId.String())
-
 		// Verify that the user is only subscribing to their own channel
 		if channelUserId != userId.String() {
 			panic(exception.NewForbiddenError("Cannot subscribe to another user's channel"))
@@ -164,8 +160,6 @@ func (controller *NotificationControllerImpl) AuthPusher(writer http.ResponseWri
 
 	authJson := fmt.Sprintf(`{"auth":"%s:%s"}`, utils.PusherClient.Key, signature)
 
-	fmt.Printf("Pusher auth successful\n")
-
 	writer.Header().Set("Content-Type", "application/json")
 	writer.Write([]byte(authJson))
 }
@@ -241,4 +235,3 @@ func (controller *NotificationControllerImpl) DeleteSelectedNotifications(writer
     
     helper.WriteToResponseBody(writer, webResponse)
 }
-
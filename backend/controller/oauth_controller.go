@@ -0,0 +1,17 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type OAuthController interface {
+	ListProviders(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Authorize(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Callback(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindIdentities(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	AuthorizeConnect(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Connect(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Disconnect(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
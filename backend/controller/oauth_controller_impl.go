@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/web"
+	"evoconnect/backend/service"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type OAuthControllerImpl struct {
+	OAuthService service.OAuthService
+}
+
+func NewOAuthController(oauthService service.OAuthService) OAuthController {
+	return &OAuthControllerImpl{
+		OAuthService: oauthService,
+	}
+}
+
+func (controller *OAuthControllerImpl) ListProviders(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	response := controller.OAuthService.ListProviders(request.Context())
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   response,
+	})
+}
+
+func (controller *OAuthControllerImpl) Authorize(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	response := controller.OAuthService.Authorize(request.Context(), params.ByName("provider"))
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   response,
+	})
+}
+
+func (controller *OAuthControllerImpl) Callback(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	callbackRequest := web.OAuthCallbackRequest{}
+	helper.ReadFromRequestBody(request, &callbackRequest)
+
+	response := controller.OAuthService.Login(request.Context(), params.ByName("provider"), callbackRequest)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   response,
+	})
+}
+
+func (controller *OAuthControllerImpl) FindIdentities(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, _ := currentUserSession(request)
+
+	response := controller.OAuthService.FindIdentities(request.Context(), userId)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   response,
+	})
+}
+
+func (controller *OAuthControllerImpl) AuthorizeConnect(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, _ := currentUserSession(request)
+
+	response := controller.OAuthService.AuthorizeConnect(request.Context(), userId, params.ByName("provider"))
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   response,
+	})
+}
+
+func (controller *OAuthControllerImpl) Connect(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, _ := currentUserSession(request)
+
+	callbackRequest := web.OAuthCallbackRequest{}
+	helper.ReadFromRequestBody(request, &callbackRequest)
+
+	response := controller.OAuthService.Connect(request.Context(), userId, params.ByName("provider"), callbackRequest)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   response,
+	})
+}
+
+func (controller *OAuthControllerImpl) Disconnect(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, _ := currentUserSession(request)
+
+	response := controller.OAuthService.Disconnect(request.Context(), userId, params.ByName("provider"))
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   response,
+	})
+}
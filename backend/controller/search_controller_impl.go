@@ -9,7 +9,6 @@ import (
     "github.com/julienschmidt/httprouter"
     "net/http"
     "strconv"
-    "fmt"
 )
 
 
@@ -54,7 +53,6 @@ func (controller *SearchControllerImpl) Search(writer http.ResponseWriter, reque
     query := request.URL.Query().Get("q")
    
     // Tambahkan log untuk debugging
-    fmt.Printf("Search query: %s\n", query)
    
     if query == "" {
         helper.WriteToResponseBody(writer, web.WebResponse{
@@ -95,8 +93,6 @@ func (controller *SearchControllerImpl) Search(writer http.ResponseWriter, reque
     searchResponse := controller.SearchService.Search(request.Context(), query, searchType, limit, offset, userId)
    
     // Log hasil pencarian untuk debugging
-    fmt.Printf("Search results: %+v\n", searchResponse)
-
 
     // Return response
     webResponse := web.WebResponse{
@@ -0,0 +1,17 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type TwoFactorController interface {
+	GetStatus(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Setup(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Enable(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Disable(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	RegenerateRecoveryCodes(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	GetAdminPolicy(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	UpdateAdminPolicy(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"evoconnect/backend/exception"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/domain"
+	"evoconnect/backend/model/web"
+	"evoconnect/backend/service"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+)
+
+// TwoFactorControllerImpl serves both /api/user/2fa and /api/admin/2fa. The owner is
+// taken from whichever auth middleware ran before it.
+type TwoFactorControllerImpl struct {
+	TwoFactorService service.TwoFactorService
+}
+
+func NewTwoFactorController(twoFactorService service.TwoFactorService) TwoFactorController {
+	return &TwoFactorControllerImpl{
+		TwoFactorService: twoFactorService,
+	}
+}
+
+func (controller *TwoFactorControllerImpl) GetStatus(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ownerType, ownerId, _ := twoFactorOwner(request)
+
+	statusResponse := controller.TwoFactorService.GetStatus(request.Context(), ownerType, ownerId)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   statusResponse,
+	})
+}
+
+func (controller *TwoFactorControllerImpl) Setup(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ownerType, ownerId, accountName := twoFactorOwner(request)
+
+	setupResponse := controller.TwoFactorService.Setup(request.Context(), ownerType, ownerId, accountName)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   setupResponse,
+	})
+}
+
+func (controller *TwoFactorControllerImpl) Enable(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ownerType, ownerId, _ := twoFactorOwner(request)
+
+	codeRequest := web.TwoFactorCodeRequest{}
+	helper.ReadFromRequestBody(request, &codeRequest)
+
+	recoveryCodesResponse := controller.TwoFactorService.Enable(request.Context(), ownerType, ownerId, codeRequest)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   recoveryCodesResponse,
+	})
+}
+
+func (controller *TwoFactorControllerImpl) Disable(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ownerType, ownerId, _ := twoFactorOwner(request)
+
+	codeRequest := web.TwoFactorCodeRequest{}
+	helper.ReadFromRequestBody(request, &codeRequest)
+
+	messageResponse := controller.TwoFactorService.Disable(request.Context(), ownerType, ownerId, codeRequest)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   messageResponse,
+	})
+}
+
+func (controller *TwoFactorControllerImpl) RegenerateRecoveryCodes(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ownerType, ownerId, _ := twoFactorOwner(request)
+
+	codeRequest := web.TwoFactorCodeRequest{}
+	helper.ReadFromRequestBody(request, &codeRequest)
+
+	recoveryCodesResponse := controller.TwoFactorService.RegenerateRecoveryCodes(request.Context(), ownerType, ownerId, codeRequest)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   recoveryCodesResponse,
+	})
+}
+
+func (controller *TwoFactorControllerImpl) GetAdminPolicy(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	required := controller.TwoFactorService.IsAdminTwoFactorRequired(request.Context())
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   web.TwoFactorPolicyResponse{AdminTwoFactorRequired: required},
+	})
+}
+
+func (controller *TwoFactorControllerImpl) UpdateAdminPolicy(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	policyRequest := web.TwoFactorPolicyRequest{}
+	helper.ReadFromRequestBody(request, &policyRequest)
+
+	policyResponse := controller.TwoFactorService.SetAdminTwoFactorRequired(request.Context(), policyRequest)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   policyResponse,
+	})
+}
+
+// twoFactorOwner returns the owner type, id and account name (email) of the caller
+func twoFactorOwner(request *http.Request) (domain.TwoFactorOwnerType, uuid.UUID, string) {
+	ctx := request.Context()
+
+	if adminIdStr, ok := ctx.Value("admin_id").(string); ok {
+		adminId, err := uuid.Parse(adminIdStr)
+		if err != nil {
+			panic(exception.NewUnauthorizedError("Invalid admin ID"))
+		}
+		email, _ := ctx.Value("admin_email").(string)
+		return domain.TwoFactorOwnerAdmin, adminId, email
+	}
+
+	if userIdStr, ok := ctx.Value("user_id").(string); ok {
+		userId, err := uuid.Parse(userIdStr)
+		if err != nil {
+			panic(exception.NewUnauthorizedError("Invalid user ID"))
+		}
+		email, _ := ctx.Value("user_email").(string)
+		return domain.TwoFactorOwnerUser, userId, email
+	}
+
+	panic(exception.NewUnauthorizedError("Unauthorized"))
+}
@@ -8,6 +8,7 @@ import (
 	"evoconnect/backend/model/web"
 	"evoconnect/backend/service"
 	"fmt"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
 	"strconv"
@@ -88,7 +89,7 @@ func (controller *UserControllerImpl) GetByUsername(writer http.ResponseWriter,
 		err := controller.ProfileViewService.RecordView(request.Context(), userResponse.ID, currentUserId)
 		if err != nil {
 			// Log error tapi jangan gagalkan request
-			fmt.Printf("Error recording profile view: %v\n", err)
+			slog.ErrorContext(request.Context(), "failed to record profile view", "error", err)
 		}
 		
 		// Kirim notifikasi ke pemilik profil
@@ -196,7 +197,6 @@ func (controller *UserControllerImpl) GetPeoples(writer http.ResponseWriter, req
     peopleResponses := controller.UserService.GetPeoples(request.Context(), limit, offset, currentUserIdStr)
 
     // Tambahkan log untuk debugging
-    fmt.Printf("People responses: %+v\n", peopleResponses)
 
     // Create web response
     webResponse := web.WebResponse{
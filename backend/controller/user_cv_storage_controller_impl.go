@@ -5,7 +5,6 @@ import (
 	"evoconnect/backend/model/web"
 	"evoconnect/backend/service"
 	"net/http"
-	"path/filepath"
 
 	"github.com/google/uuid"
 	"github.com/julienschmidt/httprouter"
@@ -213,24 +212,12 @@ func (controller *UserCvStorageControllerImpl) DownloadCv(writer http.ResponseWr
 		return
 	}
 
-	// Get CV info
-	cvInfo := controller.UserCvStorageService.GetUserCv(request.Context(), userId)
-
-	// Set response headers for file download
-	writer.Header().Set("Content-Disposition", "attachment; filename=\""+cvInfo.OriginalFilename+"\"")
-	writer.Header().Set("Content-Type", "application/octet-stream")
-
-	// Get file extension for content type
-	ext := filepath.Ext(cvInfo.OriginalFilename)
-	switch ext {
-	case ".pdf":
-		writer.Header().Set("Content-Type", "application/pdf")
-	case ".doc":
-		writer.Header().Set("Content-Type", "application/msword")
-	case ".docx":
-		writer.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.wordprocessingml.document")
-	}
+	// The file itself is served through a short-lived signed link
+	downloadResponse := controller.UserCvStorageService.DownloadCv(request.Context(), paramUserId, userId)
 
-	// Serve file
-	http.ServeFile(writer, request, cvInfo.CvFilePath)
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   downloadResponse,
+	})
 }
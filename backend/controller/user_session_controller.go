@@ -0,0 +1,13 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type UserSessionController interface {
+	FindActiveSessions(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	RevokeSession(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	RevokeOtherSessions(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"evoconnect/backend/exception"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/web"
+	"evoconnect/backend/service"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+)
+
+type UserSessionControllerImpl struct {
+	UserSessionService service.UserSessionService
+}
+
+func NewUserSessionController(userSessionService service.UserSessionService) UserSessionController {
+	return &UserSessionControllerImpl{
+		UserSessionService: userSessionService,
+	}
+}
+
+func (controller *UserSessionControllerImpl) FindActiveSessions(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, sessionId := currentUserSession(request)
+
+	sessions := controller.UserSessionService.FindActiveSessions(request.Context(), userId, sessionId)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   sessions,
+	})
+}
+
+func (controller *UserSessionControllerImpl) RevokeSession(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, _ := currentUserSession(request)
+
+	sessionId, err := uuid.Parse(params.ByName("sessionId"))
+	if err != nil {
+		panic(exception.NewBadRequestError("Invalid session ID"))
+	}
+
+	response := controller.UserSessionService.RevokeSession(request.Context(), userId, sessionId)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   response,
+	})
+}
+
+func (controller *UserSessionControllerImpl) RevokeOtherSessions(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, sessionId := currentUserSession(request)
+
+	response := controller.UserSessionService.RevokeOtherSessions(request.Context(), userId, sessionId)
+
+	helper.WriteToResponseBody(writer, web.WebResponse{
+		Code:   200,
+		Status: "OK",
+		Data:   response,
+	})
+}
+
+// currentUserSession reads the user and session ids set by the user auth middleware
+func currentUserSession(request *http.Request) (uuid.UUID, uuid.UUID) {
+	userIdStr, ok := request.Context().Value("user_id").(string)
+	if !ok {
+		panic(exception.NewUnauthorizedError("Unauthorized"))
+	}
+	userId, err := uuid.Parse(userIdStr)
+	if err != nil {
+		panic(exception.NewUnauthorizedError("Invalid user ID"))
+	}
+
+	sessionIdStr, _ := request.Context().Value("session_id").(string)
+	sessionId, err := uuid.Parse(sessionIdStr)
+	if err != nil {
+		panic(exception.NewUnauthorizedError("Invalid session"))
+	}
+
+	return userId, sessionId
+}
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+-- owner_type is either 'user' or 'admin', so owner_id has no foreign key
+CREATE TABLE IF NOT EXISTS two_factor_credentials (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_type VARCHAR(10) NOT NULL CHECK (owner_type IN ('user', 'admin')),
+    owner_id UUID NOT NULL,
+    secret VARCHAR(64) NOT NULL,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    enabled_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE (owner_type, owner_id)
+);
+
+CREATE TABLE IF NOT EXISTS two_factor_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    credential_id UUID NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (credential_id) REFERENCES two_factor_credentials(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_two_factor_recovery_codes_credential_id ON two_factor_recovery_codes(credential_id);
+
+CREATE TABLE IF NOT EXISTS system_settings (
+    key VARCHAR(100) PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS system_settings;
+DROP TABLE IF EXISTS two_factor_recovery_codes;
+DROP TABLE IF EXISTS two_factor_credentials;
+-- +goose StatementEnd
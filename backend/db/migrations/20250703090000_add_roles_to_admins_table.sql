@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Existing admins keep full access, new admins always get an explicit role
+ALTER TABLE admins ADD COLUMN IF NOT EXISTS role VARCHAR(30) NOT NULL DEFAULT 'super_admin'
+    CHECK (role IN ('super_admin', 'moderator', 'company_reviewer', 'support'));
+ALTER TABLE admins ALTER COLUMN role DROP DEFAULT;
+ALTER TABLE admins ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'disabled'));
+ALTER TABLE admins ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMP NULL;
+
+CREATE TABLE IF NOT EXISTS admin_invitations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email VARCHAR(255) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    role VARCHAR(30) NOT NULL CHECK (role IN ('super_admin', 'moderator', 'company_reviewer', 'support')),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    invited_by UUID NULL,
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (invited_by) REFERENCES admins(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_invitations_email ON admin_invitations(email);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS admin_invitations;
+ALTER TABLE admins DROP COLUMN IF EXISTS disabled_at;
+ALTER TABLE admins DROP COLUMN IF EXISTS status;
+ALTER TABLE admins DROP COLUMN IF EXISTS role;
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+-- One row per counted request; a key combines the rule name with an IP, email or user id
+CREATE TABLE IF NOT EXISTS rate_limit_hits (
+    id BIGSERIAL PRIMARY KEY,
+    bucket_key VARCHAR(320) NOT NULL,
+    hit_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_hits_key_time ON rate_limit_hits(bucket_key, hit_at);
+CREATE INDEX IF NOT EXISTS idx_rate_limit_hits_time ON rate_limit_hits(hit_at);
+
+-- Failed password logins per account; lockout_count makes every new lockout longer
+CREATE TABLE IF NOT EXISTS login_lockouts (
+    owner_type VARCHAR(10) NOT NULL CHECK (owner_type IN ('user', 'admin')),
+    owner_id UUID NOT NULL,
+    failed_count INT NOT NULL DEFAULT 0,
+    lockout_count INT NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMP NULL,
+    locked_until TIMESTAMP NULL,
+
+    PRIMARY KEY (owner_type, owner_id)
+);
+
+-- Replaced by rate_limit_hits
+DROP TABLE IF EXISTS failed_attempts;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS failed_attempts (
+    id SERIAL PRIMARY KEY,
+    ip_address VARCHAR(255) NOT NULL,
+    action_type VARCHAR(50) NOT NULL,
+    token VARCHAR(255),
+    attempt_time TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_failed_attempts_ip_action ON failed_attempts(ip_address, action_type, attempt_time);
+
+DROP TABLE IF EXISTS login_lockouts;
+DROP TABLE IF EXISTS rate_limit_hits;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- External accounts (Google, LinkedIn, GitHub, ...) a user can sign in with
+CREATE TABLE IF NOT EXISTS user_identities (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_login_at TIMESTAMP NULL,
+
+    CONSTRAINT uq_user_identities_subject UNIQUE (provider, subject),
+    CONSTRAINT uq_user_identities_user_provider UNIQUE (user_id, provider)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user ON user_identities(user_id);
+
+-- Pending authorization requests; a state is consumed once by the callback
+CREATE TABLE IF NOT EXISTS oauth_states (
+    id UUID PRIMARY KEY,
+    state_hash VARCHAR(64) NOT NULL UNIQUE,
+    provider VARCHAR(50) NOT NULL,
+    purpose VARCHAR(20) NOT NULL CHECK (purpose IN ('login', 'connect')),
+    user_id UUID NULL REFERENCES users(id) ON DELETE CASCADE,
+    nonce VARCHAR(128) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_oauth_states_expires ON oauth_states(expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS oauth_states;
+DROP TABLE IF EXISTS user_identities;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- pending_email waits for confirmation through the link in verification_token, the old address
+-- gets email_change_cancel_token to stop the change
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pending_email VARCHAR(255) NULL;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_change_cancel_token VARCHAR(255) NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_email_change_cancel_token ON users(email_change_cancel_token) WHERE email_change_cancel_token IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_users_email_change_cancel_token;
+ALTER TABLE users DROP COLUMN IF EXISTS email_change_cancel_token;
+ALTER TABLE users DROP COLUMN IF EXISTS pending_email;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Passwordless sign-in links, only the hash is stored and it is cleared on first use
+ALTER TABLE users ADD COLUMN IF NOT EXISTS magic_link_token VARCHAR(255) NULL;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS magic_link_expires TIMESTAMP NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_magic_link_token ON users(magic_link_token) WHERE magic_link_token IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_users_magic_link_token;
+ALTER TABLE users DROP COLUMN IF EXISTS magic_link_expires;
+ALTER TABLE users DROP COLUMN IF EXISTS magic_link_token;
+-- +goose StatementEnd
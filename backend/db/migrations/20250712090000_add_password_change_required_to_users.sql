@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Set at login when the stored password no longer meets the password policy, cleared when it is changed
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_change_required BOOLEAN NOT NULL DEFAULT false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS password_change_required;
+-- +goose StatementEnd
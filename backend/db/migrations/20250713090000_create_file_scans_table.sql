@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Malware scans of uploaded CVs and chat files, the file stays in quarantine until its scan is clean
+CREATE TABLE IF NOT EXISTS file_scans (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    file_key TEXT NOT NULL UNIQUE,
+    file_name VARCHAR(255) NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('cv', 'chat')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'scanning', 'clean', 'infected', 'failed')),
+    signature TEXT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    started_at TIMESTAMP NULL,
+    scanned_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_file_scans_status ON file_scans(status, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS file_scans;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every signed URL issued for a CV or chat file, with who asked for it and why
+CREATE TABLE IF NOT EXISTS file_access_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    file_key TEXT NOT NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('download', 'view')),
+    reference_type VARCHAR(30) NOT NULL,
+    reference_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_file_access_logs_file_key ON file_access_logs(file_key, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_file_access_logs_reference ON file_access_logs(reference_type, reference_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS file_access_logs;
+-- +goose StatementEnd
@@ -1,7 +1,8 @@
 #!/bin/bash
+# Run from the backend directory
 
 # Reset database to version 0 (down all migrations)
-goose down-to 0
+go run . migrate down -to 0
 
 # Apply all migrations (up)
-goose up
\ No newline at end of file
+go run . migrate up
@@ -0,0 +1,432 @@
+package migrations
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The SQL files are compiled into the binary, deployments do not need the source tree or goose
+//
+//go:embed *.sql
+var migrationFiles embed.FS
+
+// Migrations of all instances are serialised on this Postgres advisory lock
+const migrationLockId = 7301202507
+
+var (
+	migrationFilePattern = regexp.MustCompile(`^(\d+)_.+\.sql$`)
+	tableNamePattern     = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+)
+
+var ErrNoAppliedMigration = errors.New("no migration has been applied")
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+	// NoTransaction is set by "-- +goose NO TRANSACTION", for statements like CREATE INDEX CONCURRENTLY
+	NoTransaction bool
+}
+
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// Migrator applies the embedded migrations and records them in a goose compatible version table,
+// so databases that were migrated with the goose CLI carry on where they left off.
+type Migrator struct {
+	DB         *sql.DB
+	Table      string
+	Migrations []Migration
+	Logger     *slog.Logger
+}
+
+func NewMigrator(db *sql.DB, table string, logger *slog.Logger) (*Migrator, error) {
+	if !tableNamePattern.MatchString(table) {
+		return nil, fmt.Errorf("invalid migration table name %q", table)
+	}
+
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		DB:         db,
+		Table:      table,
+		Migrations: migrations,
+		Logger:     logger,
+	}, nil
+}
+
+// LoadMigrations parses the embedded files, ordered by version
+func LoadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []Migration
+	seen := make(map[int64]string)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse migration version of %s: %w", entry.Name(), err)
+		}
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", other, entry.Name(), version)
+		}
+		seen[version] = entry.Name()
+
+		content, err := migrationFiles.ReadFile(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		migration, err := parseMigration(version, entry.Name(), string(content))
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// parseMigration splits a goose file into its Up and Down sections. Each section is sent to Postgres
+// as one multi-statement query, so StatementBegin/StatementEnd markers are not needed to keep
+// function bodies together and are ignored.
+func parseMigration(version int64, name string, content string) (Migration, error) {
+	migration := Migration{Version: version, Name: name}
+
+	var up, down strings.Builder
+	var section *strings.Builder
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		annotation := strings.TrimSpace(line)
+		if strings.HasPrefix(annotation, "-- +goose ") {
+			switch strings.ToUpper(strings.TrimSpace(strings.TrimPrefix(annotation, "-- +goose "))) {
+			case "UP":
+				section = &up
+			case "DOWN":
+				section = &down
+			case "NO TRANSACTION":
+				migration.NoTransaction = true
+			}
+			continue
+		}
+		if section != nil {
+			section.WriteString(line)
+			section.WriteByte('\n')
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return migration, fmt.Errorf("read migration %s: %w", name, err)
+	}
+	if section == nil {
+		return migration, fmt.Errorf("migration %s has no -- +goose Up section", name)
+	}
+
+	migration.Up = up.String()
+	migration.Down = down.String()
+	return migration, nil
+}
+
+// Status lists every embedded migration and whether it has been applied
+func (migrator *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := migrator.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrator.Migrations))
+	for _, migration := range migrator.Migrations {
+		status := MigrationStatus{Migration: migration}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			if !appliedAt.IsZero() {
+				status.AppliedAt = &appliedAt
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Pending returns the migrations that have not been applied, oldest first
+func (migrator *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, status := range statuses {
+		if !status.Applied {
+			pending = append(pending, status.Migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up applies every pending migration and returns the ones it ran
+func (migrator *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	unlock, err := migrator.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, migration := range pending {
+		if err := migrator.apply(ctx, migration, true); err != nil {
+			return applied, err
+		}
+		applied = append(applied, migration)
+	}
+	return applied, nil
+}
+
+// DownTo rolls back applied migrations newer than version, newest first. Version 0 rolls back everything.
+func (migrator *Migrator) DownTo(ctx context.Context, version int64) ([]Migration, error) {
+	unlock, err := migrator.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var rolledBack []Migration
+	for i := len(statuses) - 1; i >= 0; i-- {
+		if !statuses[i].Applied || statuses[i].Version <= version {
+			continue
+		}
+		if err := migrator.apply(ctx, statuses[i].Migration, false); err != nil {
+			return rolledBack, err
+		}
+		rolledBack = append(rolledBack, statuses[i].Migration)
+	}
+	return rolledBack, nil
+}
+
+// Down rolls back the most recently applied migration
+func (migrator *Migrator) Down(ctx context.Context) (Migration, error) {
+	unlock, err := migrator.lock(ctx)
+	if err != nil {
+		return Migration{}, err
+	}
+	defer unlock()
+
+	latest, err := migrator.latestApplied(ctx)
+	if err != nil {
+		return Migration{}, err
+	}
+
+	return latest, migrator.apply(ctx, latest, false)
+}
+
+// Redo rolls back the most recently applied migration and applies it again
+func (migrator *Migrator) Redo(ctx context.Context) (Migration, error) {
+	unlock, err := migrator.lock(ctx)
+	if err != nil {
+		return Migration{}, err
+	}
+	defer unlock()
+
+	latest, err := migrator.latestApplied(ctx)
+	if err != nil {
+		return Migration{}, err
+	}
+
+	if err := migrator.apply(ctx, latest, false); err != nil {
+		return latest, err
+	}
+	return latest, migrator.apply(ctx, latest, true)
+}
+
+func (migrator *Migrator) latestApplied(ctx context.Context) (Migration, error) {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return Migration{}, err
+	}
+	for i := len(statuses) - 1; i >= 0; i-- {
+		if statuses[i].Applied {
+			return statuses[i].Migration, nil
+		}
+	}
+	return Migration{}, ErrNoAppliedMigration
+}
+
+// apply runs one direction of a migration and records it in the same transaction
+func (migrator *Migrator) apply(ctx context.Context, migration Migration, up bool) error {
+	direction, query := "up", migration.Up
+	record := "INSERT INTO " + migrator.quotedTable() + " (version_id, is_applied) VALUES ($1, true)"
+	if !up {
+		direction, query = "down", migration.Down
+		record = "DELETE FROM " + migrator.quotedTable() + " WHERE version_id = $1"
+	}
+
+	start := time.Now()
+	if migration.NoTransaction {
+		if err := execSection(ctx, migrator.DB, query); err != nil {
+			return fmt.Errorf("migrate %s %s: %w", direction, migration.Name, err)
+		}
+		if _, err := migrator.DB.ExecContext(ctx, record, migration.Version); err != nil {
+			return fmt.Errorf("record migration %s: %w", migration.Name, err)
+		}
+	} else {
+		tx, err := migrator.DB.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if err := execSection(ctx, tx, query); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migrate %s %s: %w", direction, migration.Name, err)
+		}
+		if _, err := tx.ExecContext(ctx, record, migration.Version); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("record migration %s: %w", migration.Name, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("commit migration %s: %w", migration.Name, err)
+		}
+	}
+
+	migrator.Logger.InfoContext(ctx, "migration applied", "direction", direction, "version", migration.Version,
+		"name", migration.Name, "duration", time.Since(start))
+	return nil
+}
+
+type sqlExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// execSection sends a whole section at once; without arguments lib/pq runs it as a simple
+// query, which accepts several statements and dollar-quoted bodies
+func execSection(ctx context.Context, executor sqlExecutor, query string) error {
+	if !hasStatements(query) {
+		return nil
+	}
+	_, err := executor.ExecContext(ctx, query)
+	return err
+}
+
+func hasStatements(query string) bool {
+	for _, line := range strings.Split(query, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "--") {
+			return true
+		}
+	}
+	return false
+}
+
+// appliedVersions replays the version log, the latest row of a version tells whether it is applied
+func (migrator *Migrator) appliedVersions(ctx context.Context) (map[int64]time.Time, error) {
+	// A database that was never migrated has no version table yet
+	var table sql.NullString
+	if err := migrator.DB.QueryRowContext(ctx, "SELECT to_regclass($1)::text", migrator.quotedTable()).Scan(&table); err != nil {
+		return nil, fmt.Errorf("look up migration table %s: %w", migrator.Table, err)
+	}
+	if !table.Valid {
+		return map[int64]time.Time{}, nil
+	}
+
+	rows, err := migrator.DB.QueryContext(ctx, "SELECT version_id, is_applied, tstamp FROM "+migrator.quotedTable()+" ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("read migration table %s: %w", migrator.Table, err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var isApplied bool
+		var appliedAt sql.NullTime
+		if err := rows.Scan(&version, &isApplied, &appliedAt); err != nil {
+			return nil, err
+		}
+		if isApplied {
+			applied[version] = appliedAt.Time
+		} else {
+			delete(applied, version)
+		}
+	}
+	return applied, rows.Err()
+}
+
+// ensureTable creates the version table in the layout goose uses
+func (migrator *Migrator) ensureTable(ctx context.Context) error {
+	if schema, _, ok := strings.Cut(migrator.Table, "."); ok {
+		if _, err := migrator.DB.ExecContext(ctx, `CREATE SCHEMA IF NOT EXISTS "`+schema+`"`); err != nil {
+			return fmt.Errorf("create migration schema: %w", err)
+		}
+	}
+
+	_, err := migrator.DB.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+migrator.quotedTable()+` (
+		id SERIAL PRIMARY KEY,
+		version_id BIGINT NOT NULL,
+		is_applied BOOLEAN NOT NULL,
+		tstamp TIMESTAMP NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return fmt.Errorf("create migration table: %w", err)
+	}
+	return nil
+}
+
+func (migrator *Migrator) quotedTable() string {
+	return `"` + strings.ReplaceAll(migrator.Table, ".", `"."`) + `"`
+}
+
+// lock holds the advisory lock on a dedicated connection until unlock is called, and makes sure
+// the version table exists before anything is recorded in it
+func (migrator *Migrator) lock(ctx context.Context) (func(), error) {
+	conn, err := migrator.DB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockId); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("acquire migration lock: %w", err)
+	}
+	if err := migrator.ensureTable(ctx); err != nil {
+		conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockId)
+		conn.Close()
+		return nil, err
+	}
+
+	return func() {
+		conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockId)
+		conn.Close()
+	}, nil
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+)
+
+// PendingVersions returns the embedded migrations that have not been applied to the database yet.
+// table is the goose version table, existing databases may use another name than the default.
+func PendingVersions(ctx context.Context, db *sql.DB, table string) ([]int64, error) {
+	migrator, err := NewMigrator(db, table, slog.Default())
+	if err != nil {
+		return nil, err
+	}
+	return migrator.PendingVersions(ctx)
+}
+
+// PendingVersions only reads the version table, the migrations were parsed when the migrator was built
+func (migrator *Migrator) PendingVersions(ctx context.Context) ([]int64, error) {
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]int64, 0, len(pending))
+	for _, migration := range pending {
+		versions = append(versions, migration.Version)
+	}
+	return versions, nil
+}
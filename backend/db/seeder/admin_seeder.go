@@ -3,8 +3,9 @@ package seeder
 import (
 	"database/sql"
 	"evoconnect/backend/helper"
+	"evoconnect/backend/model/domain"
 	"evoconnect/backend/utils"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,7 +21,7 @@ func SeedAdmin(db *sql.DB) {
 	helper.PanicIfError(err)
 
 	if count > 0 {
-		log.Println("Admin seeder: Default admin already exists")
+		slog.Debug("default admin already exists")
 		return
 	}
 
@@ -33,16 +34,15 @@ func SeedAdmin(db *sql.DB) {
 	now := time.Now()
 
 	// Use RETURNING to get the inserted ID (more consistent with repository pattern)
-	query := `INSERT INTO admins (id, email, password, name, created_at, updated_at) 
-              VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	query := `INSERT INTO admins (id, email, password, name, role, status, created_at, updated_at) 
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
 
+	// The default admin is the first super admin, it invites everyone else
 	var insertedId uuid.UUID
-	err = db.QueryRow(query, id, email, password, name, now, now).Scan(&insertedId)
+	err = db.QueryRow(query, id, email, password, name, domain.AdminRoleSuperAdmin, domain.AdminStatusActive, now, now).Scan(&insertedId)
 	helper.PanicIfError(err)
 
-	log.Println("Admin seeder: Default admin created successfully")
-	log.Printf("Default admin credentials - Email: %s, Password: admin123", email)
-	log.Printf("Admin ID: %s", insertedId.String())
+	slog.Warn("default admin created, change its password after the first login", "email", email, "admin_id", insertedId)
 }
 
 func createAdminTable(db *sql.DB) {
@@ -52,6 +52,9 @@ func createAdminTable(db *sql.DB) {
         email VARCHAR(255) NOT NULL UNIQUE,
         password VARCHAR(255) NOT NULL,
         name VARCHAR(100) NOT NULL,
+        role VARCHAR(30) NOT NULL DEFAULT 'super_admin',
+        status VARCHAR(20) NOT NULL DEFAULT 'active',
+        disabled_at TIMESTAMP NULL,
         created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
         updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
     );
@@ -62,6 +65,4 @@ func createAdminTable(db *sql.DB) {
 
 	_, err := db.Exec(query)
 	helper.PanicIfError(err)
-
-	log.Println("Admin seeder: Admin table created/verified successfully")
 }
@@ -0,0 +1,36 @@
+package exception
+
+import (
+	"fmt"
+	"time"
+)
+
+// AccountRestrictedError is returned when a suspended or banned user tries to authenticate
+type AccountRestrictedError struct {
+	Message string     `json:"message"`
+	Status  string     `json:"status"`
+	Until   *time.Time `json:"until,omitempty"`
+}
+
+func NewAccountRestrictedError(status string, until *time.Time) AccountRestrictedError {
+	message := "Your account has been banned"
+	if status == "deleted" {
+		message = "This account has been deleted"
+	} else if status == "suspended" {
+		if until != nil {
+			message = fmt.Sprintf("Your account is suspended until %s", until.Format(time.RFC3339))
+		} else {
+			message = "Your account is suspended"
+		}
+	}
+
+	return AccountRestrictedError{
+		Message: message,
+		Status:  status,
+		Until:   until,
+	}
+}
+
+func (e AccountRestrictedError) Error() string {
+	return e.Message
+}
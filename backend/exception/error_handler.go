@@ -4,7 +4,10 @@ import (
 	"evoconnect/backend/helper"
 	"evoconnect/backend/model/web"
 	"fmt"
+	"log/slog"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -19,6 +22,14 @@ func ErrorHandler(writer http.ResponseWriter, request *http.Request, err interfa
 		return
 	}
 
+	if passwordPolicyError(writer, request, err) {
+		return
+	}
+
+	if uploadRejectedError(writer, request, err) {
+		return
+	}
+
 	if badRequestError(writer, request, err) {
 		return
 	}
@@ -31,6 +42,10 @@ func ErrorHandler(writer http.ResponseWriter, request *http.Request, err interfa
 		return
 	}
 
+	if accountRestrictedError(writer, request, err) {
+		return
+	}
+
 	if tooManyRequestsError(writer, request, err) {
 		return
 	}
@@ -38,6 +53,8 @@ func ErrorHandler(writer http.ResponseWriter, request *http.Request, err interfa
 	if internalServerError(writer, request, err) {
 		return
 	}
+
+	unhandledPanic(writer, request, err)
 }
 
 func unauthorizedError(writer http.ResponseWriter, request *http.Request, err interface{}) bool {
@@ -121,6 +138,8 @@ func notFoundError(writer http.ResponseWriter, request *http.Request, err interf
 func internalServerError(writer http.ResponseWriter, request *http.Request, err interface{}) bool {
 	exception, ok := err.(InternalServerError)
 	if ok {
+		slog.ErrorContext(request.Context(), "internal server error", "error", exception.Error, "path", request.URL.Path)
+
 		writer.Header().Set("Content-Type", "application/json")
 		writer.WriteHeader(http.StatusInternalServerError)
 
@@ -160,6 +179,9 @@ func tooManyRequestsError(writer http.ResponseWriter, request *http.Request, err
 	exception, ok := err.(TooManyRequestsError)
 	if ok {
 		writer.Header().Set("Content-Type", "application/json")
+		if exception.RetryAfter > 0 {
+			writer.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(exception.RetryAfter.Seconds()))))
+		}
 		writer.WriteHeader(http.StatusTooManyRequests)
 
 		webResponse := web.WebResponse{
@@ -193,3 +215,82 @@ func forbiddenError(writer http.ResponseWriter, request *http.Request, err inter
 		return false
 	}
 }
+
+func accountRestrictedError(writer http.ResponseWriter, request *http.Request, err interface{}) bool {
+	exception, ok := err.(AccountRestrictedError)
+	if ok {
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(http.StatusForbidden)
+
+		webResponse := web.WebResponse{
+			Code:   http.StatusForbidden,
+			Status: "ACCOUNT_RESTRICTED",
+			Data:   exception,
+		}
+
+		helper.WriteToResponseBody(writer, webResponse)
+		return true
+	} else {
+		return false
+	}
+}
+
+func passwordPolicyError(writer http.ResponseWriter, request *http.Request, err interface{}) bool {
+	exception, ok := err.(PasswordPolicyError)
+	if ok {
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(http.StatusBadRequest)
+
+		webResponse := web.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "BAD REQUEST",
+			Data:   exception,
+		}
+
+		helper.WriteToResponseBody(writer, webResponse)
+		return true
+	} else {
+		return false
+	}
+}
+
+func uploadRejectedError(writer http.ResponseWriter, request *http.Request, err interface{}) bool {
+	exception, ok := err.(helper.UploadRejectedError)
+	if ok {
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(http.StatusBadRequest)
+
+		webResponse := web.WebResponse{
+			Code:   http.StatusBadRequest,
+			Status: "BAD REQUEST",
+			Data:   exception,
+		}
+
+		helper.WriteToResponseBody(writer, webResponse)
+		return true
+	} else {
+		return false
+	}
+}
+
+// unhandledPanic covers helper.PanicIfError and runtime panics, the details are only logged
+func unhandledPanic(writer http.ResponseWriter, request *http.Request, err interface{}) {
+	attrs := []any{"path", request.URL.Path}
+	if details, ok := err.(map[string]interface{}); ok {
+		attrs = append(attrs, "error", details["error"])
+	} else {
+		attrs = append(attrs, "error", fmt.Sprint(err), "type", fmt.Sprintf("%T", err))
+	}
+	slog.ErrorContext(request.Context(), "unhandled error", attrs...)
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusInternalServerError)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusInternalServerError,
+		Status: "INTERNAL SERVER ERROR",
+		Data:   "Internal server error occurred",
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
@@ -0,0 +1,33 @@
+package exception
+
+// Password policy violation codes, stable for clients to translate
+const (
+	PasswordTooShort             = "too_short"
+	PasswordTooLong              = "too_long"
+	PasswordContainsPersonalInfo = "contains_personal_info"
+	PasswordCommon               = "common_password"
+)
+
+type PasswordViolation struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// PasswordPolicyError is returned when a new password breaks the password policy
+type PasswordPolicyError struct {
+	Field      string              `json:"field"`
+	Message    string              `json:"message"`
+	Violations []PasswordViolation `json:"violations"`
+}
+
+func NewPasswordPolicyError(violations []PasswordViolation) PasswordPolicyError {
+	return PasswordPolicyError{
+		Field:      "password",
+		Message:    "Password does not meet the requirements",
+		Violations: violations,
+	}
+}
+
+func (e PasswordPolicyError) Error() string {
+	return e.Message
+}
@@ -1,13 +1,21 @@
 package exception
 
+import "time"
+
 type TooManyRequestsError struct {
 	Error string
+	// RetryAfter is sent back as the Retry-After header when set
+	RetryAfter time.Duration
 }
 
 func NewTooManyRequestsError(error string) TooManyRequestsError {
 	return TooManyRequestsError{Error: error}
 }
 
+func NewTooManyRequestsErrorWithRetry(error string, retryAfter time.Duration) TooManyRequestsError {
+	return TooManyRequestsError{Error: error, RetryAfter: retryAfter}
+}
+
 func (e TooManyRequestsError) GetError() string {
 	return e.Error
 }
@@ -5,10 +5,7 @@ import (
     "evoconnect/backend/model/web"
     "errors"
     "fmt"
-    "io"
-    "os"
     "mime/multipart"
-    "path/filepath"
     "time"  
 )
 
@@ -20,6 +17,7 @@ func ToBlogResponse(blog domain.Blog) web.BlogResponse {
         Category:  blog.Category,
         Content:   blog.Content,
         Photo:     blog.ImagePath,
+        PhotoVariants: ImageVariantsOf(blog.ImagePath),
         UserID:    blog.UserID,
         CreatedAt: blog.CreatedAt,
         UpdatedAt: blog.UpdatedAt,
@@ -41,6 +39,7 @@ func ToBlogResponseWithUser(blog domain.Blog, user domain.User, isConnected bool
         Category:  blog.Category,
         Content:   blog.Content,
         Photo:     blog.ImagePath,
+        PhotoVariants: ImageVariantsOf(blog.ImagePath),
         UserID:    blog.UserID,
         Warning:   warning,
         CreatedAt: blog.CreatedAt,
@@ -50,6 +49,7 @@ func ToBlogResponseWithUser(blog domain.Blog, user domain.User, isConnected bool
             Name:        user.Name,
             Username:    user.Username,
             Photo:       user.Photo,
+            PhotoVariants: ImageVariantsOf(user.Photo),
             IsConnected: isConnected,
         },
     }
@@ -89,45 +89,25 @@ func isValidCategory(category string) bool {
     return false
 }
 
+// SaveBlogImage menyimpan gambar blog tanpa metadata dalam setiap ukuran varian,
+// path yang dikembalikan adalah ukuran penuh
 func SaveBlogImage(file multipart.File, header *multipart.FileHeader, userID string) (string, error) {
-    // Buat direktori untuk user
-    uploadDir := fmt.Sprintf("uploads/blogs/%s", userID)
-    if err := os.MkdirAll(uploadDir, os.ModePerm); err != nil {
-        return "", fmt.Errorf("gagal membuat direktori upload: %w", err)
+    // Periksa isi file lalu encode ulang gambarnya
+    _, processed, err := processImageUpload(UploadPolicyPostImage, file, header)
+    if err != nil {
+        return "", err
     }
 
     // Generate unique filename
     timestamp := fmt.Sprintf("%d", time.Now().UnixNano())
-    ext := filepath.Ext(header.Filename)
-    fileName := fmt.Sprintf("blog-%s%s", timestamp, ext)
-    filePath := fmt.Sprintf("%s/%s", uploadDir, fileName)
+    name := fmt.Sprintf("uploads/blogs/%s/blog-%s", userID, timestamp)
 
     // Simpan file
-    out, err := os.Create(filePath)
-    if err != nil {
-        return "", err
-    }
-    defer out.Close()
-
-    _, err = io.Copy(out, file)
-    if err != nil {
-        return "", err
-    }
-
-    return filePath, nil
+    return storeImageVariants(processed, name)
 }
 
 // DeleteBlogImage menghapus file gambar blog jika ada
 func DeleteBlogImage(filePath string) error {
-    if filePath == "" {
-        return nil // Tidak ada file untuk dihapus
-    }
-
-    // Periksa apakah file ada
-    if _, err := os.Stat(filePath); os.IsNotExist(err) {
-        return nil // File sudah tidak ada, tidak perlu error
-    }
-
-    // Hapus file
-    return os.Remove(filePath)
+    // File yang sudah tidak ada tidak dianggap error
+    return DeleteFile(filePath)
 }
@@ -0,0 +1,52 @@
+package helper
+
+import (
+	"bytes"
+	"context"
+	"evoconnect/backend/storage"
+	"mime/multipart"
+	"strings"
+	"testing"
+)
+
+func TestSaveBlogImageStoresVariants(t *testing.T) {
+	previous := FileStorage()
+	InitStorage(storage.NewLocalStorage(t.TempDir()))
+	t.Cleanup(func() { InitStorage(previous) })
+
+	content := pngBytes(t)
+	file := memoryFile{bytes.NewReader(content)}
+	savedPath, err := SaveBlogImage(file, &multipart.FileHeader{Filename: "cover.png", Size: int64(len(content))}, "user-1")
+	if err != nil {
+		t.Fatalf("SaveBlogImage() error = %v", err)
+	}
+	if !strings.HasPrefix(savedPath, "uploads/blogs/user-1/blog-") || !strings.HasSuffix(savedPath, "_full.png") {
+		t.Fatalf("saved path = %q, want the full size variant", savedPath)
+	}
+
+	variants := ImageVariantsOf(savedPath)
+	for _, variant := range []string{variants.Thumbnail, variants.Medium, variants.Full} {
+		stored, _, err := OpenStoredFile(context.Background(), variant)
+		if err != nil {
+			t.Errorf("variant %s was not stored: %v", variant, err)
+			continue
+		}
+		stored.Close()
+	}
+
+	if err := DeleteBlogImage(savedPath); err != nil {
+		t.Fatalf("DeleteBlogImage() error = %v", err)
+	}
+	if _, _, err := OpenStoredFile(context.Background(), variants.Thumbnail); err == nil {
+		t.Error("thumbnail left behind after deleting the blog image")
+	}
+}
+
+func TestSaveBlogImageRejectsNonImages(t *testing.T) {
+	content := []byte("<html><script>alert(1)</script></html>")
+	file := memoryFile{bytes.NewReader(content)}
+	_, err := SaveBlogImage(file, &multipart.FileHeader{Filename: "cover.png", Size: int64(len(content))}, "user-1")
+	if rejectionCode(err) == "" {
+		t.Fatalf("SaveBlogImage() error = %v, want the upload rejected", err)
+	}
+}
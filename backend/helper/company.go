@@ -38,18 +38,19 @@ func ToCompanyEditRequestResponse(editRequest domain.CompanyEditRequest) web.Com
 
 	if editRequest.Company != nil {
 		response.Company = &web.CompanyResponse{
-			ID:          editRequest.Company.Id,
-			Name:        editRequest.Company.Name,
-			LinkedinUrl: editRequest.Company.LinkedinUrl,
-			Website:     editRequest.Company.Website,
-			Industry:    editRequest.Company.Industry,
-			Size:        editRequest.Company.Size,
-			Type:        editRequest.Company.Type,
-			Logo:        editRequest.Company.Logo,
-			Tagline:     editRequest.Company.Tagline,
-			IsVerified:  editRequest.Company.IsVerified,
-			CreatedAt:   editRequest.Company.CreatedAt,
-			UpdatedAt:   editRequest.Company.UpdatedAt,
+			ID:           editRequest.Company.Id,
+			Name:         editRequest.Company.Name,
+			LinkedinUrl:  editRequest.Company.LinkedinUrl,
+			Website:      editRequest.Company.Website,
+			Industry:     editRequest.Company.Industry,
+			Size:         editRequest.Company.Size,
+			Type:         editRequest.Company.Type,
+			Logo:         editRequest.Company.Logo,
+			LogoVariants: ImageVariantsOf(editRequest.Company.Logo),
+			Tagline:      editRequest.Company.Tagline,
+			IsVerified:   editRequest.Company.IsVerified,
+			CreatedAt:    editRequest.Company.CreatedAt,
+			UpdatedAt:    editRequest.Company.UpdatedAt,
 		}
 	}
 
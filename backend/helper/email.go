@@ -1,6 +1,7 @@
 package helper
 
 import (
+	"evoconnect/backend/config"
 	"fmt"
 	"net/smtp"
 )
@@ -10,29 +11,25 @@ var (
 	EmailSender = sendEmail
 )
 
-// EmailConfig holds email server configuration
-type EmailConfig struct {
-	Host     string
-	Port     int
-	Username string
-	Password string
-	From     string
+var emailConfig config.EmailConfig
+
+// InitEmail sets the SMTP server used by EmailSender - harus dipanggil saat startup
+func InitEmail(cfg config.EmailConfig) {
+	emailConfig = cfg
 }
 
-// GetEmailConfig reads email configuration from environment or uses defaults
-func GetEmailConfig() EmailConfig {
-	return EmailConfig{
-		Host:     GetEnv("EMAIL_HOST", "smtp.gmail.com"),
-		Port:     GetEnvInt("EMAIL_PORT", 587),
-		Username: GetEnv("EMAIL_USERNAME", "your-email@gmail.com"),
-		Password: GetEnv("EMAIL_PASSWORD", "your-password"),
-		From:     GetEnv("EMAIL_FROM", "EvoConnect <noreply@evoconnect.com>"),
-	}
+// GetEmailConfig returns the SMTP settings passed to InitEmail
+func GetEmailConfig() config.EmailConfig {
+	return emailConfig
 }
 
 // sendEmail sends an email using SMTP
 func sendEmail(to, subject, body string) error {
 	config := GetEmailConfig()
+	if config.Host == "" {
+		EmailsFailedTotal.Inc()
+		return fmt.Errorf("email is not configured, set EMAIL_HOST")
+	}
 
 	// More detailed logging
 	// fmt.Printf("Email configuration: Host=%s, Port=%d, Username=%s, From=%s\n",
@@ -48,5 +45,11 @@ func sendEmail(to, subject, body string) error {
 		body)
 
 	addr := fmt.Sprintf("%s:%d", config.Host, config.Port)
-	return smtp.SendMail(addr, auth, config.Username, []string{to}, msg)
+	err := smtp.SendMail(addr, auth, config.Username, []string{to}, msg)
+	if err != nil {
+		EmailsFailedTotal.Inc()
+		return err
+	}
+	EmailsSentTotal.Inc()
+	return nil
 }
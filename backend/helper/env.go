@@ -2,7 +2,7 @@ package helper
 
 import (
 	// "fmt"
-	"log"
+	"log/slog"
 	"os"
 	"strconv"
 
@@ -14,7 +14,7 @@ func LoadEnv() {
 	// Load .env file
 	err := godotenv.Load()
 	if err != nil {
-		log.Println("Warning: .env file not found or couldn't be loaded. Using default environment variables.")
+		slog.Warn(".env file not found or couldn't be loaded, using the process environment")
 	}
 
 	// jwtSecret := os.Getenv("JWT_SECRET_KEY")
@@ -1,13 +1,21 @@
 package helper
 
 import (
-	"log"
+	"log/slog"
+
+	"github.com/go-playground/validator/v10"
 )
 
+// PanicIfError aborts the request with an internal server error. The error is logged with the
+// request id by the router's panic handler.
 func PanicIfError(err error) {
+	// Validation errors keep their type so the panic handler answers 400 with the failing fields
+	if validationErrors, ok := err.(validator.ValidationErrors); ok {
+		panic(validationErrors)
+	}
+	// So do rejected uploads, answered with 400 and the reason
+	PanicIfUploadRejected(err)
 	if err != nil {
-		log.Printf("ERROR DETAILS: %v", err)
-		log.Printf("ERROR TYPE: %T", err)
 		panic(map[string]interface{}{
 			"error":   err.Error(),
 			"message": "Internal server error occurred",
@@ -18,7 +26,7 @@ func PanicIfError(err error) {
 
 func ValidationError(err error) error {
 	if err != nil {
-		log.Printf("Validation error: %v", err)
+		slog.Debug("validation failed", "error", err)
 		return err
 	}
 	return nil
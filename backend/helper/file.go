@@ -1,13 +1,14 @@
 package helper
 
 import (
-	"errors"
+	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"mime"
 	"mime/multipart"
 	"net/http"
-	"os"
-	"path/filepath"
+	"path"
 	"strings"
 	"time"
 
@@ -31,6 +32,7 @@ const (
 	DirBlogs        = "blogs"
 	DirCompanies    = "companies"
 	DirCompanyPosts = "company_posts"
+	DirCVStorage    = "cv_storage"
 )
 
 // FileUploadOptions provides configuration options for file uploads
@@ -50,26 +52,32 @@ type FileUploadOptions struct {
 	// Prefix for the filename
 	FilePrefix string
 
-	// Max file size in bytes (0 = unlimited)
-	MaxSize int64
-
-	// Allowed file types/extensions (nil = allow all)
-	AllowedTypes []string
+	// Allowed content types and max file size
+	Policy UploadPolicy
 
 	// Whether to include random UUID in filename for uniqueness
 	IncludeUUID bool
+
+	// Store the file in quarantine until its malware scan is clean, see ReleaseQuarantinedFile
+	Quarantine bool
 }
 
 // File upload result
 type UploadResult struct {
-	// Complete file path
+	// Storage key, saved in the database
 	FilePath string
 
-	// Relative URL path for serving
+	// Relative URL path for serving, the same as the key
 	RelativePath string
 
 	// Filename (without path)
 	Filename string
+
+	// Content type detected by the upload policy
+	ContentType string
+
+	// Sanitized client filename
+	OriginalFilename string
 }
 
 // UploadFile handles file upload with extensive options
@@ -84,55 +92,14 @@ func UploadFile(file multipart.File, fileHeader *multipart.FileHeader, options F
 		options.FilePrefix = "file"
 	}
 
-	// Check file size if max size is specified
-	if options.MaxSize > 0 && fileHeader.Size > options.MaxSize {
-		return nil, fmt.Errorf("file size exceeds the maximum allowed size of %d bytes", options.MaxSize)
-	}
-
-	// Validate file type if allowed types are specified
-	if len(options.AllowedTypes) > 0 {
-		valid := false
-		fileExt := strings.ToLower(filepath.Ext(fileHeader.Filename))
-
-		for _, allowedType := range options.AllowedTypes {
-			if strings.HasPrefix(fileExt, ".") && fileExt == allowedType {
-				valid = true
-				break
-			} else if !strings.HasPrefix(allowedType, ".") && fileExt == "."+allowedType {
-				valid = true
-				break
-			}
-		}
-
-		if !valid {
-			return nil, errors.New("file type not allowed")
-		}
-	}
-
-	// Build upload directory path
-	pathComponents := []string{options.BaseDir}
-
-	if options.EntityDir != "" {
-		pathComponents = append(pathComponents, options.EntityDir)
-	}
-
-	if options.EntityID != "" {
-		pathComponents = append(pathComponents, options.EntityID)
-	}
-
-	if options.SubDir != "" {
-		pathComponents = append(pathComponents, options.SubDir)
-	}
-
-	uploadDir := filepath.Join(pathComponents...)
-
-	// Create directory structure if it doesn't exist
-	if err := os.MkdirAll(uploadDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create upload directory: %w", err)
+	// Check size and content, the extension comes from the detected type
+	checked, err := options.Policy.Check(file, fileHeader)
+	if err != nil {
+		return nil, err
 	}
 
 	// Generate unique filename
-	fileExt := filepath.Ext(fileHeader.Filename)
+	fileExt := checked.Extension
 	timestamp := time.Now().Unix()
 	var filename string
 
@@ -143,75 +110,109 @@ func UploadFile(file multipart.File, fileHeader *multipart.FileHeader, options F
 		filename = fmt.Sprintf("%s-%d%s", options.FilePrefix, timestamp, fileExt)
 	}
 
-	filePath := filepath.Join(uploadDir, filename)
-
-	// Create destination file
-	dst, err := os.Create(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create file: %w", err)
+	key := uploadKey(options.BaseDir, options.EntityDir, options.EntityID, options.SubDir, filename)
+	storeKey := key
+	if options.Quarantine {
+		storeKey = QuarantineKey(key)
 	}
-	defer dst.Close()
 
-	// Copy uploaded file to destination
-	if _, err = io.Copy(dst, file); err != nil {
+	if err := StoreFile(storeKey, file, fileHeader.Size); err != nil {
 		return nil, fmt.Errorf("failed to save file: %w", err)
 	}
 
-	// Convert backslashes to forward slashes for URL consistency
-	relativePath := strings.ReplaceAll(filePath, "\\", "/")
-
 	return &UploadResult{
-		FilePath:     filePath,
-		RelativePath: relativePath,
-		Filename:     filename,
+		FilePath:         key,
+		RelativePath:     key,
+		Filename:         filename,
+		ContentType:      checked.ContentType,
+		OriginalFilename: checked.Filename,
 	}, nil
 }
 
-// ValidateImageFile checks if file is a valid image
-func ValidateImageFile(fileHeader *multipart.FileHeader) error {
-	ext := strings.ToLower(filepath.Ext(fileHeader.Filename))
-	allowedExts := []string{".jpg", ".jpeg", ".png", ".gif", ".webp"}
+// Helper function specifically for image uploads with default settings, see UploadImageWithPolicy
+func UploadImage(file multipart.File, fileHeader *multipart.FileHeader, entityDir string, entityID string, subDir string) (*UploadResult, error) {
+	return UploadImageWithPolicy(UploadPolicyPostImage, file, fileHeader, entityDir, entityID, subDir)
+}
 
-	for _, allowed := range allowedExts {
-		if ext == allowed {
-			return nil
-		}
+// UploadImageWithPolicy checks an image against the policy, re-encodes it without metadata and
+// stores it in every variant size. The returned path is the full size image, see ImageVariantsOf for the others.
+func UploadImageWithPolicy(policy UploadPolicy, file multipart.File, fileHeader *multipart.FileHeader, entityDir string, entityID string, subDir string) (*UploadResult, error) {
+	checked, processed, err := processImageUpload(policy, file, fileHeader)
+	if err != nil {
+		return nil, err
 	}
 
-	return errors.New("only JPG, JPEG, PNG, GIF and WEBP files are allowed")
-}
+	uuidStr := strings.ReplaceAll(uuid.New().String(), "-", "")[:8]
+	name := fmt.Sprintf("%s-%d-%s", subDir, time.Now().Unix(), uuidStr)
 
-// Helper function specifically for image uploads with default settings
-func UploadImage(file multipart.File, fileHeader *multipart.FileHeader, entityDir string, entityID string, subDir string) (*UploadResult, error) {
-	// Validate that it's an image file
-	if err := ValidateImageFile(fileHeader); err != nil {
+	fullKey, err := storeImageVariants(processed, uploadKey("uploads", entityDir, entityID, subDir, name))
+	if err != nil {
 		return nil, err
 	}
 
-	options := FileUploadOptions{
-		BaseDir:     "uploads",
-		EntityDir:   entityDir,
-		EntityID:    entityID,
-		SubDir:      subDir,
-		FilePrefix:  subDir,
-		MaxSize:     10 * 1024 * 1024, // 10 MB
-		IncludeUUID: true,
+	return &UploadResult{
+		FilePath:         fullKey,
+		RelativePath:     fullKey,
+		Filename:         path.Base(fullKey),
+		ContentType:      mime.TypeByExtension(processed.Extension),
+		OriginalFilename: checked.Filename,
+	}, nil
+}
+
+// processImageUpload checks an image against the policy and re-encodes it, see ProcessImage
+func processImageUpload(policy UploadPolicy, file multipart.File, fileHeader *multipart.FileHeader) (CheckedUpload, *ProcessedImage, error) {
+	checked, err := policy.Check(file, fileHeader)
+	if err != nil {
+		return checked, nil, err
 	}
 
-	return UploadFile(file, fileHeader, options)
+	processed, err := ProcessImage(io.LimitReader(file, policy.MaxSize))
+	if err != nil {
+		return checked, nil, err
+	}
+	return checked, processed, nil
 }
 
-// DeleteFile removes a file if it exists
+// storeImageVariants stores every size of a processed image as <name>_<size><extension> and
+// returns the key of the full size image
+func storeImageVariants(processed *ProcessedImage, name string) (string, error) {
+	// The full size image is stored last, once it exists its variants do too
+	var storedKeys []string
+	for _, size := range imageVariantSizes {
+		key := name + "_" + size.name + processed.Extension
+		variant := processed.Variants[size.name]
+
+		if err := StoreFile(key, bytes.NewReader(variant), int64(len(variant))); err != nil {
+			for _, storedKey := range storedKeys {
+				fileStorage.Delete(context.Background(), storedKey)
+			}
+			return "", fmt.Errorf("failed to save file: %w", err)
+		}
+		storedKeys = append(storedKeys, key)
+	}
+
+	return storedKeys[len(storedKeys)-1], nil
+}
+
+// DeleteFile removes a stored file if it exists, together with the other sizes of a processed
+// image and a copy still in quarantine. References that are not storage keys, like photo URLs
+// from sign-in providers, are left alone.
 func DeleteFile(filePath string) error {
-	if filePath == "" {
+	if filePath == "" || !IsStorageKey(filePath) {
 		return nil
 	}
 
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return nil // File doesn't exist, which is fine
+	for _, size := range imageVariantSizes {
+		if variantKey, ok := imageVariantKey(filePath, size.name); ok && size.name != ImageVariantFull {
+			if err := fileStorage.Delete(context.Background(), variantKey); err != nil {
+				return err
+			}
+		}
 	}
-
-	return os.Remove(filePath)
+	if err := DeleteQuarantinedFile(context.Background(), filePath); err != nil {
+		return err
+	}
+	return fileStorage.Delete(context.Background(), filePath)
 }
 
 func ParseMultipartForm(request *http.Request, maxMemory int64) error {
@@ -223,27 +224,31 @@ func ParseMultipartForm(request *http.Request, maxMemory int64) error {
 
 // ///////////////////////////////////////////
 func SaveUploadedFile(file multipart.File, category string, userId string, fileExt string) string {
-	// Create directory structure if it doesn't exist
-	uploadDir := filepath.Join("uploads", category, userId)
-	err := os.MkdirAll(uploadDir, 0755)
+	key := savedFileKey(category, userId, fileExt)
+
+	err := StoreFile(key, file, multipartFileSize(file))
 	PanicIfError(err)
 
-	// Generate unique filename using timestamp
-	timestamp := time.Now().Unix()
-	filename := fmt.Sprintf("%s-%d%s", category, timestamp, fileExt)
-	filepath := filepath.Join(uploadDir, filename)
+	return key
+}
 
-	// Create destination file
-	dst, err := os.Create(filepath)
-	PanicIfError(err)
-	defer dst.Close()
+// SaveQuarantinedFile is SaveUploadedFile for files that wait for a malware scan. The returned key
+// is where the file is served from once it is released.
+func SaveQuarantinedFile(file multipart.File, category string, userId string, fileExt string) string {
+	key := savedFileKey(category, userId, fileExt)
 
-	// Copy uploaded file to destination
-	_, err = io.Copy(dst, file)
+	err := StoreFile(QuarantineKey(key), file, multipartFileSize(file))
 	PanicIfError(err)
 
-	// Return relative path with forward slashes for consistency across platforms
-	return strings.ReplaceAll(filepath, "\\", "/")
+	return key
+}
+
+func savedFileKey(category string, userId string, fileExt string) string {
+	// Timestamp plus a random part, two uploads in the same second must not share a key
+	timestamp := time.Now().Unix()
+	uuidStr := strings.ReplaceAll(uuid.New().String(), "-", "")[:8]
+	filename := fmt.Sprintf("%s-%d-%s%s", category, timestamp, uuidStr, fileExt)
+	return uploadKey("uploads", category, userId, filename)
 }
 
 func GetFileHeaderFromForm(request *http.Request, fieldName string) (*multipart.FileHeader, error) {
@@ -0,0 +1,18 @@
+package helper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSavedFileKeyIsUniqueWithinASecond(t *testing.T) {
+	first := savedFileKey(DirCVStorage, "user-1", ".pdf")
+	second := savedFileKey(DirCVStorage, "user-1", ".pdf")
+
+	if first == second {
+		t.Fatalf("two uploads in the same second share the key %q", first)
+	}
+	if !strings.HasPrefix(first, "uploads/cv_storage/user-1/cv_storage-") || !strings.HasSuffix(first, ".pdf") {
+		t.Errorf("savedFileKey() = %q", first)
+	}
+}
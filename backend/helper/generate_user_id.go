@@ -6,53 +6,37 @@ import (
 	"net/http"
 	"strings"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/google/uuid"
 )
 
+// Token verification lives in utils, which imports helper. utils.InitJWT registers these so
+// tokens are checked against the same keyrings as in the auth middlewares.
+var (
+	userTokenParser  func(tokenString string) (string, error)
+	adminTokenParser func(tokenString string) (string, error)
+)
+
+// SetTokenParsers registers functions that verify a token and return the user or admin id
+func SetTokenParsers(userParser func(string) (string, error), adminParser func(string) (string, error)) {
+	userTokenParser = userParser
+	adminTokenParser = adminParser
+}
+
 // GetUserIdFromToken extracts the user ID from a JWT token
 func GetUserIdFromToken(request *http.Request) (uuid.UUID, error) {
-	// Extract the token from the request header
-	tokenString := request.Header.Get("Authorization")
-	if tokenString == "" {
-		return uuid.Nil, errors.New("authorization header is missing")
-	}
-
-	// Split the token string to get the actual token
-	tokenParts := strings.Split(tokenString, "Bearer ")
-	if len(tokenParts) != 2 {
-		return uuid.Nil, errors.New("invalid authorization format")
-	}
-	tokenString = tokenParts[1]
+	return idFromBearerToken(request, userTokenParser, "user_id")
+}
 
-	// Parse the token
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(GetEnv("JWT_SECRET_KEY", "your-secret-key")), nil
-	})
-	if err != nil {
-		return uuid.Nil, err
-	}
+func GetAdminIdFromToken(request *http.Request) (uuid.UUID, error) {
+	return idFromBearerToken(request, adminTokenParser, "admin_id")
+}
 
-	// Extract claims and get user ID
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		userId, ok := claims["user_id"].(string)
-		if !ok {
-			return uuid.Nil, errors.New("user_id not found in token claims")
-		}
-		parsedUUID, err := uuid.Parse(userId)
-		if err != nil {
-			return uuid.Nil, fmt.Errorf("invalid user_id format: %v", err)
-		}
-		return parsedUUID, nil
+func idFromBearerToken(request *http.Request, parser func(string) (string, error), claim string) (uuid.UUID, error) {
+	// Behind the auth middlewares the id is already known, this also covers API tokens
+	if id, ok := request.Context().Value(claim).(string); ok && id != "" {
+		return uuid.Parse(id)
 	}
 
-	return uuid.Nil, errors.New("invalid token")
-}
-
-func GetAdminIdFromToken(request *http.Request) (uuid.UUID, error) {
 	// Extract the token from the request header
 	tokenString := request.Header.Get("Authorization")
 	if tokenString == "" {
@@ -66,29 +50,18 @@ func GetAdminIdFromToken(request *http.Request) (uuid.UUID, error) {
 	}
 	tokenString = tokenParts[1]
 
-	// Parse the token
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(GetEnv("JWT_SECRET_KEY", "your-secret-key")), nil
-	})
+	if parser == nil {
+		return uuid.Nil, errors.New("token verification is not initialized")
+	}
+
+	id, err := parser(tokenString)
 	if err != nil {
 		return uuid.Nil, err
 	}
 
-	// Extract claims and get admin ID
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		adminId, ok := claims["admin_id"].(string)
-		if !ok {
-			return uuid.Nil, errors.New("admin_id not found in token claims")
-		}
-		parsedUUID, err := uuid.Parse(adminId)
-		if err != nil {
-			return uuid.Nil, fmt.Errorf("invalid admin_id format: %v", err)
-		}
-		return parsedUUID, nil
+	parsedUUID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid %s format: %v", claim, err)
 	}
-
-	return uuid.Nil, errors.New("invalid token")
+	return parsedUUID, nil
 }
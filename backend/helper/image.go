@@ -0,0 +1,296 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"evoconnect/backend/model/web"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path"
+	"strings"
+
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// Sizes every processed image is stored in
+	ImageVariantThumbnail = "thumb"
+	ImageVariantMedium    = "medium"
+	ImageVariantFull      = "full"
+
+	// A small file can declare a huge canvas, decoding is refused above this many pixels
+	maxImagePixels = 25_000_000
+	jpegQuality    = 85
+)
+
+// imageVariantSizes lists the variants with the limit of their longest side, full size last
+var imageVariantSizes = []struct {
+	name    string
+	maxSide int
+}{
+	{ImageVariantThumbnail, 320},
+	{ImageVariantMedium, 1080},
+	{ImageVariantFull, 2048},
+}
+
+// ErrInvalidImage is returned for uploads that cannot be decoded as a supported image
+var ErrInvalidImage = UploadRejectedError{Code: UploadInvalidImage, Message: "file is not a valid JPG, PNG, GIF or WEBP image"}
+
+// ProcessedImage holds the re-encoded variants of an upload
+type ProcessedImage struct {
+	// Extension of every variant, .jpg for opaque images and .png for transparent ones
+	Extension string
+
+	// Encoded image per variant name
+	Variants map[string][]byte
+}
+
+// ProcessImage decodes an upload, applies its EXIF orientation and re-encodes it in every
+// variant size. Re-encoding drops EXIF and all other metadata, such as the GPS position of
+// a phone photo. Animated GIFs keep their first frame only.
+func ProcessImage(reader io.Reader) (*ProcessedImage, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	imageConfig, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if imageConfig.Width <= 0 || imageConfig.Height <= 0 {
+		return nil, ErrInvalidImage
+	}
+	if imageConfig.Width*imageConfig.Height > maxImagePixels {
+		return nil, UploadRejectedError{
+			Code: UploadTooLarge,
+			Message: fmt.Sprintf("image of %dx%d pixels exceeds the maximum of %d megapixels",
+				imageConfig.Width, imageConfig.Height, maxImagePixels/1_000_000),
+		}
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	orientation := 1
+	if format == "jpeg" {
+		orientation = jpegOrientation(data)
+	}
+	oriented := orientImage(toRGBA(decoded), orientation)
+
+	processed := &ProcessedImage{
+		Extension: ".jpg",
+		Variants:  make(map[string][]byte, len(imageVariantSizes)),
+	}
+	if !oriented.Opaque() {
+		processed.Extension = ".png"
+	}
+
+	for _, size := range imageVariantSizes {
+		var encoded bytes.Buffer
+		variant := fitImage(oriented, size.maxSide)
+		if processed.Extension == ".png" {
+			err = png.Encode(&encoded, variant)
+		} else {
+			err = jpeg.Encode(&encoded, variant, &jpeg.Options{Quality: jpegQuality})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("encode %s image: %w", size.name, err)
+		}
+		processed.Variants[size.name] = encoded.Bytes()
+	}
+
+	return processed, nil
+}
+
+// ImageVariantsOf returns the paths of every size of a stored image. Images uploaded before
+// variants were generated, and photos hosted elsewhere, use the same path for every size.
+// It returns nil for an empty path.
+func ImageVariantsOf(imagePath string) *web.ImageVariants {
+	if imagePath == "" {
+		return nil
+	}
+
+	thumbnail, ok := imageVariantKey(imagePath, ImageVariantThumbnail)
+	if !ok || !IsStorageKey(imagePath) {
+		return &web.ImageVariants{Thumbnail: imagePath, Medium: imagePath, Full: imagePath}
+	}
+	medium, _ := imageVariantKey(imagePath, ImageVariantMedium)
+	return &web.ImageVariants{Thumbnail: thumbnail, Medium: medium, Full: imagePath}
+}
+
+// OptionalImageVariantsOf is ImageVariantsOf for images that may not be set
+func OptionalImageVariantsOf(imagePath *string) *web.ImageVariants {
+	if imagePath == nil {
+		return nil
+	}
+	return ImageVariantsOf(*imagePath)
+}
+
+// ImageVariantsOfAll returns the variants of each image, in the same order
+func ImageVariantsOfAll(imagePaths []string) []web.ImageVariants {
+	variants := make([]web.ImageVariants, 0, len(imagePaths))
+	for _, imagePath := range imagePaths {
+		if imageVariants := ImageVariantsOf(imagePath); imageVariants != nil {
+			variants = append(variants, *imageVariants)
+		}
+	}
+	return variants
+}
+
+// imageVariantKey derives the key of a variant from the key of the full size image,
+// "x_full.jpg" becomes "x_thumb.jpg". ok is false for images stored without variants.
+func imageVariantKey(fullKey string, variant string) (string, bool) {
+	ext := path.Ext(fullKey)
+	stem, found := strings.CutSuffix(strings.TrimSuffix(fullKey, ext), "_"+ImageVariantFull)
+	if !found {
+		return "", false
+	}
+	return stem + "_" + variant + ext, true
+}
+
+func toRGBA(src image.Image) *image.RGBA {
+	bounds := src.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+	return rgba
+}
+
+// fitImage scales an image down so its longest side is at most maxSide, smaller images are kept
+func fitImage(src *image.RGBA, maxSide int) image.Image {
+	width, height := src.Bounds().Dx(), src.Bounds().Dy()
+	if width <= maxSide && height <= maxSide {
+		return src
+	}
+
+	if width >= height {
+		width, height = maxSide, max(1, height*maxSide/width)
+	} else {
+		width, height = max(1, width*maxSide/height), maxSide
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), xdraw.Src, nil)
+	return dst
+}
+
+// orientImage rotates and flips an image the way its EXIF orientation (1-8) says it is displayed
+func orientImage(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+
+	width, height := src.Bounds().Dx(), src.Bounds().Dy()
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		// Orientations 5 to 8 turn the image by a quarter
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirrored
+				dx, dy = width-1-x, y
+			case 3: // upside down
+				dx, dy = width-1-x, height-1-y
+			case 4: // mirrored upside down
+				dx, dy = x, height-1-y
+			case 5: // transposed
+				dx, dy = y, x
+			case 6: // needs a clockwise turn
+				dx, dy = height-1-y, x
+			case 7: // transversed
+				dx, dy = height-1-y, width-1-x
+			case 8: // needs a counterclockwise turn
+				dx, dy = y, width-1-x
+			}
+			srcOffset, dstOffset := src.PixOffset(x, y), dst.PixOffset(dx, dy)
+			copy(dst.Pix[dstOffset:dstOffset+4], src.Pix[srcOffset:srcOffset+4])
+		}
+	}
+	return dst
+}
+
+// jpegOrientation reads the orientation tag from the EXIF segment of a JPEG file, 1 when it has none
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	offset := 2
+	for offset+4 <= len(data) {
+		if data[offset] != 0xFF {
+			return 1
+		}
+		marker := data[offset+1]
+		if marker == 0xFF {
+			// Fill byte before a marker
+			offset++
+			continue
+		}
+		if marker == 0xDA || marker == 0xD9 {
+			// Start of the image data, metadata segments come before it
+			return 1
+		}
+
+		length := int(binary.BigEndian.Uint16(data[offset+2:]))
+		if length < 2 || offset+2+length > len(data) {
+			return 1
+		}
+		segment := data[offset+4 : offset+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		offset += 2 + length
+	}
+	return 1
+}
+
+// exifOrientation finds tag 0x0112 in the first IFD of a TIFF structured EXIF block
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+
+	entries := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+	return 1
+}
@@ -0,0 +1,149 @@
+package helper
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+type requestIDKey struct{}
+
+// WithRequestID stores the request ID so every log line written with the context carries it
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// NewLogger builds the application logger from LOG_LEVEL (debug, info, warn, error) and
+// LOG_FORMAT (json or text). Debug mode defaults to debug level and text output. The logger is
+// built before the configuration is loaded so configuration errors are logged as well.
+func NewLogger(w io.Writer) (*slog.Logger, error) {
+	defaultLevel, defaultFormat := "info", "json"
+	if DebugMode() {
+		defaultLevel, defaultFormat = "debug", "text"
+	}
+
+	level, err := ParseLogLevel(GetEnv("LOG_LEVEL", defaultLevel))
+	if err != nil {
+		return nil, err
+	}
+
+	options := &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redactLogAttr,
+	}
+
+	var handler slog.Handler
+	switch format := strings.ToLower(GetEnv("LOG_FORMAT", defaultFormat)); format {
+	case "json":
+		handler = slog.NewJSONHandler(w, options)
+	case "text":
+		handler = slog.NewTextHandler(w, options)
+	default:
+		return nil, fmt.Errorf("LOG_FORMAT must be json or text, got %q", format)
+	}
+
+	return slog.New(&contextLogHandler{Handler: handler}), nil
+}
+
+func ParseLogLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return level, fmt.Errorf("LOG_LEVEL must be debug, info, warn or error, got %q", value)
+	}
+	return level, nil
+}
+
+// contextLogHandler adds the request ID of the context to every record
+type contextLogHandler struct {
+	slog.Handler
+}
+
+func (handler *contextLogHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+	return handler.Handler.Handle(ctx, record)
+}
+
+func (handler *contextLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextLogHandler{Handler: handler.Handler.WithAttrs(attrs)}
+}
+
+func (handler *contextLogHandler) WithGroup(name string) slog.Handler {
+	return &contextLogHandler{Handler: handler.Handler.WithGroup(name)}
+}
+
+const redactedLogValue = "[REDACTED]"
+
+// Attribute keys containing one of these never have their value logged
+var sensitiveLogKeys = []string{
+	"password",
+	"token",
+	"secret",
+	"authorization",
+	"cookie",
+	"api_key",
+	"apikey",
+	"otp",
+	"recovery_code",
+}
+
+// Credentials that end up inside messages and errors, like a header echoed back by a client
+var sensitiveLogPatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9\-._~+/]+=*`), "$1 " + redactedLogValue},
+	{regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), redactedLogValue},
+	{regexp.MustCompile(`\bevo_(pat|key)_[A-Za-z0-9_-]+`), "evo_${1}_" + redactedLogValue},
+	{regexp.MustCompile(`(?i)\b(token|password|secret|code)=[^&\s"]+`), "$1=" + redactedLogValue},
+}
+
+func redactLogAttr(groups []string, attr slog.Attr) slog.Attr {
+	if isSensitiveLogKey(attr.Key) {
+		return slog.String(attr.Key, redactedLogValue)
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, RedactLogText(attr.Value.String()))
+	case slog.KindAny:
+		if err, ok := attr.Value.Any().(error); ok {
+			return slog.String(attr.Key, RedactLogText(err.Error()))
+		}
+	}
+	return attr
+}
+
+func isSensitiveLogKey(key string) bool {
+	key = strings.ToLower(key)
+	// Ids of tokens and sessions are safe to log and needed to trace them
+	if strings.HasSuffix(key, "_id") {
+		return false
+	}
+	for _, sensitive := range sensitiveLogKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactLogText masks bearer tokens, JWTs, API tokens and credentials in query strings
+func RedactLogText(text string) string {
+	for _, sensitive := range sensitiveLogPatterns {
+		text = sensitive.pattern.ReplaceAllString(text, sensitive.replacement)
+	}
+	return text
+}
@@ -0,0 +1,83 @@
+package helper
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const metricsNamespace = "evoconnect"
+
+// metricsRegistry holds only our collectors, so tests and tools never see global state
+var metricsRegistry = prometheus.NewRegistry()
+
+var (
+	HTTPRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route pattern and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route pattern and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	NotificationsCreatedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "notifications_created_total",
+		Help:      "Notifications stored for users, by category.",
+	}, []string{"category"})
+
+	PusherTriggerFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "pusher_trigger_failures_total",
+		Help:      "Pusher events that could not be delivered, by event name.",
+	}, []string{"event"})
+
+	EmailsSentTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "emails_sent_total",
+		Help:      "Emails accepted by the SMTP server.",
+	})
+
+	EmailsFailedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "emails_failed_total",
+		Help:      "Emails the SMTP server did not accept.",
+	})
+)
+
+func init() {
+	metricsRegistry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestsTotal,
+		HTTPRequestDuration,
+		NotificationsCreatedTotal,
+		PusherTriggerFailuresTotal,
+		EmailsSentTotal,
+		EmailsFailedTotal,
+	)
+}
+
+// RegisterDBStats exposes the sql.DBStats of the connection pool. Only the first pool opened
+// under a name is reported, a command opening the database again must not panic.
+func RegisterDBStats(db *sql.DB, name string) {
+	err := metricsRegistry.Register(collectors.NewDBStatsCollector(db, name))
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if err != nil && !errors.As(err, &alreadyRegistered) {
+		panic(err)
+	}
+}
+
+// MetricsHandler serves every registered metric in the Prometheus text format
+func MetricsHandler() http.Handler {
+	return promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{})
+}
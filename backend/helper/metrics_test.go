@@ -0,0 +1,26 @@
+package helper
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+// unusedConnector satisfies sql.OpenDB, the stats collector never opens a connection
+type unusedConnector struct{}
+
+func (unusedConnector) Connect(context.Context) (driver.Conn, error) {
+	return nil, errors.New("not connected")
+}
+
+func (unusedConnector) Driver() driver.Driver { return nil }
+
+func TestRegisterDBStatsTwice(t *testing.T) {
+	for i := 0; i < 2; i++ {
+		db := sql.OpenDB(unusedConnector{})
+		defer db.Close()
+		RegisterDBStats(db, "metrics_test")
+	}
+}
@@ -85,6 +85,7 @@ func ToUserProfileResponse(user domain.User, isConnected ...bool) web.UserProfil
 		Skills:             skillsInterface,
 		Socials:            socialsInterface,
 		Photo:              user.Photo,
+		PhotoVariants:      ImageVariantsOf(user.Photo),
 		IsVerified:         user.IsVerified,
 		IsConnected:        connected,
 		IsConnectedRequest: connectedRequest,
@@ -94,10 +95,6 @@ func ToUserProfileResponse(user domain.User, isConnected ...bool) web.UserProfil
 }
 
 func ToUserShortResponse(user domain.User, isConnected bool, isConnectedRequest string) web.UserShort {
-	// Tambahkan log untuk debugging
-	fmt.Printf("ToUserShortResponse: user=%s, isConnected=%v, isConnectedRequest=%s\n",
-		user.Name, isConnected, isConnectedRequest)
-
 	// Jika isConnectedRequest kosong, berikan nilai default "none"
 	if isConnectedRequest == "" {
 		isConnectedRequest = "none"
@@ -108,6 +105,7 @@ func ToUserShortResponse(user domain.User, isConnected bool, isConnectedRequest
 		Name:               user.Name,
 		Username:           user.Username,
 		Photo:              &user.Photo,
+		PhotoVariants:      ImageVariantsOf(user.Photo),
 		Headline:           &user.Headline,
 		IsConnected:        isConnected,
 		IsConnectedRequest: isConnectedRequest,
@@ -120,6 +118,7 @@ func ToPostResponse(post domain.Post) web.PostResponse {
 		UserId:        post.UserId,
 		Content:       post.Content,
 		Images:        post.Images,
+		ImageVariants: ImageVariantsOfAll(post.Images),
 		Visibility:    post.Visibility,
 		CreatedAt:     post.CreatedAt,
 		UpdatedAt:     post.UpdatedAt,
@@ -143,6 +142,7 @@ func ToPostResponse(post domain.Post) web.PostResponse {
 			Name:               post.User.Name,
 			Username:           post.User.Username,
 			Photo:              &photo,
+			PhotoVariants:      ImageVariantsOf(photo),
 			Headline:           &headline,
 			IsConnected:        post.User.IsConnected,
 			IsConnectedRequest: "",
@@ -164,6 +164,7 @@ func ToPostResponse(post domain.Post) web.PostResponse {
 
 		if post.Group.Image != nil && *post.Group.Image != "" {
 			postResponse.Group.Image = post.Group.Image
+			postResponse.Group.ImageVariants = ImageVariantsOf(*post.Group.Image)
 		}
 	}
 
@@ -287,18 +288,19 @@ func ToExperienceResponses(experiences []domain.UserExperience) []web.Experience
 // Add this to helper/converter.go or wherever your helper functions are
 func ToGroupResponse(group domain.Group) web.GroupResponse {
 	groupResponse := web.GroupResponse{
-		Id:           group.Id,
-		Name:         group.Name,
-		Description:  group.Description,
-		Rule:         group.Rule,
-		Image:        group.Image,
-		PrivacyLevel: group.PrivacyLevel,
-		InvitePolicy: group.InvitePolicy,
-		PostApproval: group.PostApproval,
-		CreatorId:    group.CreatorId,
-		CreatedAt:    group.CreatedAt,
-		UpdatedAt:    group.UpdatedAt,
-		IsJoined:     false, // Default value
+		Id:            group.Id,
+		Name:          group.Name,
+		Description:   group.Description,
+		Rule:          group.Rule,
+		Image:         group.Image,
+		ImageVariants: OptionalImageVariantsOf(group.Image),
+		PrivacyLevel:  group.PrivacyLevel,
+		InvitePolicy:  group.InvitePolicy,
+		PostApproval:  group.PostApproval,
+		CreatorId:     group.CreatorId,
+		CreatedAt:     group.CreatedAt,
+		UpdatedAt:     group.UpdatedAt,
+		IsJoined:      false, // Default value
 	}
 
 	if group.Creator != nil {
@@ -393,6 +395,7 @@ func ToJoinRequestResponse(request domain.GroupJoinRequest) web.JoinRequestRespo
 		if request.User.Photo != "" {
 			photo := request.User.Photo
 			response.User.Photo = &photo
+			response.User.PhotoVariants = ImageVariantsOf(photo)
 		}
 
 		if request.User.Headline != "" {
@@ -443,9 +446,10 @@ func ToCompanyFollowerResponse(follower domain.CompanyFollower) web.CompanyFollo
 	// Set company info if available
 	if follower.Company != nil {
 		response.Company = &web.CompanyBasicInfo{
-			Id:   follower.Company.Id.String(),
-			Name: follower.Company.Name,
-			Logo: follower.Company.Logo,
+			Id:           follower.Company.Id.String(),
+			Name:         follower.Company.Name,
+			Logo:         follower.Company.Logo,
+			LogoVariants: ImageVariantsOf(follower.Company.Logo),
 		}
 	}
 
@@ -503,11 +507,12 @@ func ToJobApplicationResponse(jobApplication domain.JobApplication) web.JobAppli
 		}
 		if jobApplication.JobVacancy.Company != nil {
 			jobVacancyBrief.Company = &web.CompanyBriefResponse{
-				Id:       jobApplication.JobVacancy.Company.Id,
-				Name:     jobApplication.JobVacancy.Company.Name,
-				Logo:     &jobApplication.JobVacancy.Company.Logo,
-				Industry: jobApplication.JobVacancy.Company.Industry,
-				Website:  &jobApplication.JobVacancy.Company.Website,
+				Id:           jobApplication.JobVacancy.Company.Id,
+				Name:         jobApplication.JobVacancy.Company.Name,
+				Logo:         &jobApplication.JobVacancy.Company.Logo,
+				LogoVariants: ImageVariantsOf(jobApplication.JobVacancy.Company.Logo),
+				Industry:     jobApplication.JobVacancy.Company.Industry,
+				Website:      &jobApplication.JobVacancy.Company.Website,
 			}
 		}
 		response.JobVacancy = jobVacancyBrief
@@ -556,9 +561,10 @@ func ToJobVacancyResponse(jobVacancy domain.JobVacancy) web.JobVacancyResponse {
 	var companyInfo *web.CompanyBasicInfo
 	if jobVacancy.Company != nil {
 		companyInfo = &web.CompanyBasicInfo{
-			Id:   jobVacancy.Company.Id.String(),
-			Name: jobVacancy.Company.Name,
-			Logo: jobVacancy.Company.Logo,
+			Id:           jobVacancy.Company.Id.String(),
+			Name:         jobVacancy.Company.Name,
+			Logo:         jobVacancy.Company.Logo,
+			LogoVariants: ImageVariantsOf(jobVacancy.Company.Logo),
 		}
 	}
 
@@ -580,9 +586,10 @@ func ToJobVacancyResponse(jobVacancy domain.JobVacancy) web.JobVacancyResponse {
 	var companyResponse *web.CompanyBasicResponse
 	if companyInfo != nil {
 		companyResponse = &web.CompanyBasicResponse{
-			Id:   companyInfo.Id,
-			Name: companyInfo.Name,
-			Logo: &companyInfo.Logo,
+			Id:           companyInfo.Id,
+			Name:         companyInfo.Name,
+			Logo:         &companyInfo.Logo,
+			LogoVariants: ImageVariantsOf(companyInfo.Logo),
 		}
 	}
 
@@ -652,10 +659,27 @@ func ToSavedJobResponse(savedJob domain.SavedJob) web.SavedJobResponse {
 
 func ToAdminResponse(admin domain.Admin) web.AdminResponse {
 	return web.AdminResponse{
-		ID:        admin.Id,
-		Name:      admin.Name,
-		Email:     admin.Email,
-		CreatedAt: admin.CreatedAt,
+		ID:          admin.Id,
+		Name:        admin.Name,
+		Email:       admin.Email,
+		Role:        string(admin.Role),
+		Status:      string(admin.Status),
+		Permissions: admin.Role.Permissions(),
+		DisabledAt:  admin.DisabledAt,
+		CreatedAt:   admin.CreatedAt,
+		UpdatedAt:   admin.UpdatedAt,
+	}
+}
+
+func ToAdminInvitationResponse(invitation domain.AdminInvitation) web.AdminInvitationResponse {
+	return web.AdminInvitationResponse{
+		Id:        invitation.Id,
+		Email:     invitation.Email,
+		Name:      invitation.Name,
+		Role:      string(invitation.Role),
+		InvitedBy: invitation.InvitedBy,
+		ExpiresAt: invitation.ExpiresAt,
+		CreatedAt: invitation.CreatedAt,
 	}
 }
 
@@ -686,11 +710,12 @@ func ToCompanyJoinRequestResponse(request domain.CompanyJoinRequest) web.Company
 
 	if request.Company != nil {
 		response.Company = &web.CompanyBriefResponse{
-			Id:       request.Company.Id,
-			Name:     request.Company.Name,
-			Logo:     &request.Company.Logo,
-			Industry: request.Company.Industry,
-			Website:  &request.Company.Website,
+			Id:           request.Company.Id,
+			Name:         request.Company.Name,
+			Logo:         &request.Company.Logo,
+			LogoVariants: ImageVariantsOf(request.Company.Logo),
+			Industry:     request.Company.Industry,
+			Website:      &request.Company.Website,
 		}
 	}
 
@@ -725,6 +750,7 @@ func ToCompanySubmissionResponse(submission domain.CompanySubmission) web.Compan
 		Size:            submission.Size,
 		Type:            submission.Type,
 		Logo:            submission.Logo,
+		LogoVariants:    ImageVariantsOf(submission.Logo),
 		Tagline:         submission.Tagline,
 		Status:          string(submission.Status),
 		RejectionReason: submission.RejectionReason,
@@ -758,19 +784,20 @@ func ToAdminResponseBrief(admin domain.Admin) web.AdminResponse {
 
 func ToCompanyDetailResponse(company domain.Company) web.CompanyDetailResponse {
 	response := web.CompanyDetailResponse{
-		Id:          company.Id.String(),
-		Name:        company.Name,
-		LinkedinUrl: company.LinkedinUrl,
-		Website:     company.Website,
-		Industry:    company.Industry,
-		Size:        company.Size,
-		Type:        company.Type,
-		Logo:        company.Logo,
-		Tagline:     company.Tagline,
-		Location:    company.Location,
-		IsVerified:  company.IsVerified,
-		CreatedAt:   company.CreatedAt,
-		UpdatedAt:   company.UpdatedAt,
+		Id:           company.Id.String(),
+		Name:         company.Name,
+		LinkedinUrl:  company.LinkedinUrl,
+		Website:      company.Website,
+		Industry:     company.Industry,
+		Size:         company.Size,
+		Type:         company.Type,
+		Logo:         company.Logo,
+		LogoVariants: ImageVariantsOf(company.Logo),
+		Tagline:      company.Tagline,
+		Location:     company.Location,
+		IsVerified:   company.IsVerified,
+		CreatedAt:    company.CreatedAt,
+		UpdatedAt:    company.UpdatedAt,
 	}
 
 	// Set owner information if available
@@ -785,3 +812,70 @@ func ToCompanyDetailResponse(company domain.Company) web.CompanyDetailResponse {
 
 	return response
 }
+
+func ToAuditLogResponse(auditLog domain.AuditLog) web.AuditLogResponse {
+	return web.AuditLogResponse{
+		Id:         auditLog.Id,
+		ActorType:  auditLog.ActorType,
+		ActorId:    auditLog.ActorId,
+		ActorEmail: auditLog.ActorEmail,
+		Action:     auditLog.Action,
+		TargetType: auditLog.TargetType,
+		TargetId:   auditLog.TargetId,
+		Before:     auditLog.Before,
+		After:      auditLog.After,
+		IpAddress:  auditLog.IpAddress,
+		CreatedAt:  auditLog.CreatedAt,
+	}
+}
+
+func ToDataExportResponse(export domain.DataExport) web.DataExportResponse {
+	response := web.DataExportResponse{
+		Id:           export.Id.String(),
+		Status:       string(export.Status),
+		FileSize:     export.FileSize,
+		ErrorMessage: export.ErrorMessage,
+		CompletedAt:  export.CompletedAt,
+		ExpiresAt:    export.ExpiresAt,
+		CreatedAt:    export.CreatedAt,
+	}
+
+	if export.IsDownloadable() {
+		response.DownloadUrl = fmt.Sprintf("/api/user/export/%s/download", export.Id)
+	}
+
+	return response
+}
+
+func ToUserIdentityResponse(identity domain.UserIdentity, displayName string) web.UserIdentityResponse {
+	return web.UserIdentityResponse{
+		Provider:    identity.Provider,
+		DisplayName: displayName,
+		Email:       identity.Email,
+		CreatedAt:   identity.CreatedAt,
+		LastLoginAt: identity.LastLoginAt,
+	}
+}
+
+func ToAPITokenResponse(token domain.APIToken) web.APITokenResponse {
+	response := web.APITokenResponse{
+		Id:          token.Id.String(),
+		Name:        token.Name,
+		TokenPrefix: token.TokenPrefix,
+		Scopes:      []string{},
+		ExpiresAt:   token.ExpiresAt,
+		LastUsedAt:  token.LastUsedAt,
+		LastUsedIp:  token.LastUsedIp,
+		CreatedAt:   token.CreatedAt,
+	}
+
+	for _, scope := range token.Scopes {
+		response.Scopes = append(response.Scopes, string(scope))
+	}
+	if token.CompanyId != nil {
+		companyId := token.CompanyId.String()
+		response.CompanyId = &companyId
+	}
+
+	return response
+}
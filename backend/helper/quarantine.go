@@ -0,0 +1,40 @@
+package helper
+
+import (
+	"context"
+	"evoconnect/backend/storage"
+	"io"
+)
+
+// Uploads waiting for their malware scan are kept under this prefix, outside the publicly served
+// uploads/, and moved to their key once the scan is clean
+const quarantinePrefix = "quarantine/"
+
+// QuarantineKey is where the upload stored under key waits for its scan
+func QuarantineKey(key string) string {
+	return quarantinePrefix + key
+}
+
+// OpenQuarantinedFile opens an upload that is waiting for its scan
+func OpenQuarantinedFile(ctx context.Context, key string) (io.ReadSeekCloser, storage.ObjectInfo, error) {
+	return fileStorage.Open(ctx, QuarantineKey(key))
+}
+
+// ReleaseQuarantinedFile moves a scanned upload to its key, where it is served from
+func ReleaseQuarantinedFile(ctx context.Context, key string) error {
+	object, info, err := fileStorage.Open(ctx, QuarantineKey(key))
+	if err != nil {
+		return err
+	}
+	defer object.Close()
+
+	if err := fileStorage.Put(ctx, key, object, info.Size, info.ContentType); err != nil {
+		return err
+	}
+	return fileStorage.Delete(ctx, QuarantineKey(key))
+}
+
+// DeleteQuarantinedFile removes an upload that is waiting for its scan
+func DeleteQuarantinedFile(ctx context.Context, key string) error {
+	return fileStorage.Delete(ctx, QuarantineKey(key))
+}
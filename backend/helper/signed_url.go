@@ -0,0 +1,127 @@
+package helper
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"evoconnect/backend/storage"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Private uploads are never served from /uploads, only through URLs signed with SignFileURL
+var privateUploadPrefixes = []string{"uploads/cv_storage/", "uploads/chat/"}
+
+// Path of the handler serving signed URLs
+const signedFilePath = "/api/files"
+
+var (
+	ErrInvalidFileURL = errors.New("this file link is invalid")
+	ErrExpiredFileURL = errors.New("this file link has expired")
+)
+
+var (
+	fileURLKey []byte
+	fileURLTTL = 5 * time.Minute
+)
+
+// InitFileURLSigner sets the key and lifetime of signed file URLs - harus dipanggil saat startup
+func InitFileURLSigner(key []byte, ttl time.Duration) {
+	fileURLKey = key
+	fileURLTTL = ttl
+}
+
+// IsPrivateFile reports whether a stored file may only be downloaded through a signed URL
+func IsPrivateFile(reference string) bool {
+	key, err := storage.NormalizeKey(reference)
+	if err != nil {
+		return false
+	}
+	for _, prefix := range privateUploadPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// SignedFile is a download granted by a signed URL
+type SignedFile struct {
+	Key       string
+	Filename  string
+	Inline    bool
+	ExpiresAt time.Time
+}
+
+// SignFileURL returns the API path that serves a stored file until it expires. Inline files are
+// shown by the browser, the others are downloaded as filename.
+func SignFileURL(key string, filename string, inline bool) (string, time.Time) {
+	expiresAt := time.Now().Add(fileURLTTL).Truncate(time.Second)
+	file := SignedFile{Key: key, Filename: SanitizeFilename(filename), Inline: inline, ExpiresAt: expiresAt}
+
+	query := url.Values{}
+	query.Set("key", file.Key)
+	query.Set("name", file.Filename)
+	query.Set("disposition", file.disposition())
+	query.Set("expires", strconv.FormatInt(expiresAt.Unix(), 10))
+	query.Set("signature", file.signature())
+
+	return signedFilePath + "?" + query.Encode(), expiresAt
+}
+
+// VerifyFileURL checks the query of a signed URL and returns the file it grants
+func VerifyFileURL(query url.Values) (SignedFile, error) {
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil || query.Get("key") == "" {
+		return SignedFile{}, ErrInvalidFileURL
+	}
+
+	disposition := query.Get("disposition")
+	if disposition != "inline" && disposition != "attachment" {
+		return SignedFile{}, ErrInvalidFileURL
+	}
+
+	file := SignedFile{
+		Key:       query.Get("key"),
+		Filename:  query.Get("name"),
+		Inline:    disposition == "inline",
+		ExpiresAt: time.Unix(expires, 0),
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(query.Get("signature"))
+	if err != nil || len(fileURLKey) == 0 {
+		return SignedFile{}, ErrInvalidFileURL
+	}
+	expected, _ := base64.RawURLEncoding.DecodeString(file.signature())
+	if !hmac.Equal(signature, expected) {
+		return SignedFile{}, ErrInvalidFileURL
+	}
+
+	// Checked after the signature, so an expired message is only given for URLs we issued
+	if time.Now().After(file.ExpiresAt) {
+		return SignedFile{}, ErrExpiredFileURL
+	}
+	return file, nil
+}
+
+func (file SignedFile) disposition() string {
+	if file.Inline {
+		return "inline"
+	}
+	return "attachment"
+}
+
+// signature covers every parameter, a URL for one file cannot be turned into another
+func (file SignedFile) signature() string {
+	mac := hmac.New(sha256.New, fileURLKey)
+	mac.Write([]byte(strings.Join([]string{
+		file.Key,
+		file.Filename,
+		file.disposition(),
+		strconv.FormatInt(file.ExpiresAt.Unix(), 10),
+	}, "\n")))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,86 @@
+package helper
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// useFileURLSigner swaps the signing key and lifetime for one test
+func useFileURLSigner(t *testing.T, key string, ttl time.Duration) {
+	t.Helper()
+	previousKey, previousTTL := fileURLKey, fileURLTTL
+	InitFileURLSigner([]byte(key), ttl)
+	t.Cleanup(func() { InitFileURLSigner(previousKey, previousTTL) })
+}
+
+func signedQuery(t *testing.T, key string, filename string, inline bool) url.Values {
+	t.Helper()
+	signed, _ := SignFileURL(key, filename, inline)
+	path, rawQuery, _ := strings.Cut(signed, "?")
+	if path != signedFilePath {
+		t.Fatalf("signed URL path = %q, want %q", path, signedFilePath)
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return query
+}
+
+func TestVerifyFileURLAcceptsIssuedURL(t *testing.T) {
+	useFileURLSigner(t, "signed-url-test-key", time.Minute)
+
+	file, err := VerifyFileURL(signedQuery(t, "uploads/chat/photo.png", "photo.png", true))
+	if err != nil {
+		t.Fatalf("VerifyFileURL() error = %v", err)
+	}
+	if file.Key != "uploads/chat/photo.png" || file.Filename != "photo.png" || !file.Inline {
+		t.Errorf("VerifyFileURL() = %+v", file)
+	}
+}
+
+func TestVerifyFileURLRejectsTampering(t *testing.T) {
+	useFileURLSigner(t, "signed-url-test-key", time.Minute)
+
+	tests := []struct {
+		name   string
+		change func(query url.Values)
+	}{
+		{"other file", func(query url.Values) { query.Set("key", "uploads/cv_storage/cv.pdf") }},
+		{"other name", func(query url.Values) { query.Set("name", "invoice.pdf") }},
+		{"other disposition", func(query url.Values) { query.Set("disposition", "attachment") }},
+		{"later expiry", func(query url.Values) { query.Set("expires", "9999999999") }},
+		{"missing signature", func(query url.Values) { query.Del("signature") }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query := signedQuery(t, "uploads/chat/photo.png", "photo.png", true)
+			test.change(query)
+			if _, err := VerifyFileURL(query); !errors.Is(err, ErrInvalidFileURL) {
+				t.Fatalf("VerifyFileURL() error = %v, want ErrInvalidFileURL", err)
+			}
+		})
+	}
+}
+
+func TestVerifyFileURLRejectsOtherKey(t *testing.T) {
+	useFileURLSigner(t, "signed-url-test-key", time.Minute)
+	query := signedQuery(t, "uploads/chat/photo.png", "photo.png", true)
+
+	InitFileURLSigner([]byte("rotated-signing-key"), time.Minute)
+	if _, err := VerifyFileURL(query); !errors.Is(err, ErrInvalidFileURL) {
+		t.Fatalf("VerifyFileURL() error = %v, want ErrInvalidFileURL", err)
+	}
+}
+
+func TestVerifyFileURLExpires(t *testing.T) {
+	useFileURLSigner(t, "signed-url-test-key", -time.Minute)
+
+	_, err := VerifyFileURL(signedQuery(t, "uploads/chat/photo.png", "photo.png", true))
+	if !errors.Is(err, ErrExpiredFileURL) {
+		t.Fatalf("VerifyFileURL() error = %v, want ErrExpiredFileURL", err)
+	}
+}
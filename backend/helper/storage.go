@@ -0,0 +1,87 @@
+package helper
+
+import (
+	"context"
+	"evoconnect/backend/storage"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// fileStorage keeps every upload, local disk until InitStorage is called
+var fileStorage storage.Storage = storage.NewLocalStorage(".")
+
+// InitStorage sets the backend used by the upload helpers - harus dipanggil saat startup
+func InitStorage(backend storage.Storage) {
+	fileStorage = backend
+}
+
+// FileStorage returns the backend set with InitStorage
+func FileStorage() storage.Storage {
+	return fileStorage
+}
+
+// StoreFile saves an upload under key. size may be -1 when unknown.
+func StoreFile(key string, file io.Reader, size int64) error {
+	return fileStorage.Put(context.Background(), key, file, size, mime.TypeByExtension(path.Ext(key)))
+}
+
+// OpenStoredFile opens the object a stored reference points to
+func OpenStoredFile(ctx context.Context, reference string) (io.ReadSeekCloser, storage.ObjectInfo, error) {
+	return fileStorage.Open(ctx, reference)
+}
+
+// ServeStoredFile writes a stored object to the response with range and conditional request
+// support. It returns storage.ErrNotFound, without writing anything, when there is no such object.
+func ServeStoredFile(writer http.ResponseWriter, request *http.Request, reference string) error {
+	if !IsStorageKey(reference) {
+		return storage.ErrNotFound
+	}
+
+	object, info, err := fileStorage.Open(request.Context(), reference)
+	if err != nil {
+		return err
+	}
+	defer object.Close()
+
+	if writer.Header().Get("Content-Type") == "" && info.ContentType != "" {
+		writer.Header().Set("Content-Type", info.ContentType)
+	}
+	// Browsers must not second-guess the type, an upload is never run as HTML or script
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(writer, request, path.Base(info.Key), info.ModTime, object)
+	return nil
+}
+
+// IsStorageKey reports whether a stored reference is an upload rather than an external URL,
+// such as the photo of a sign-in provider
+func IsStorageKey(reference string) bool {
+	_, err := storage.NormalizeKey(reference)
+	return err == nil
+}
+
+// uploadKey joins key segments, skipping empty ones
+func uploadKey(segments ...string) string {
+	parts := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if segment = strings.Trim(segment, "/"); segment != "" {
+			parts = append(parts, segment)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// multipartFileSize returns the size of an upload and rewinds it, -1 when it cannot seek
+func multipartFileSize(file multipart.File) int64 {
+	size, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return -1
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return -1
+	}
+	return size
+}
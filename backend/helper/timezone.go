@@ -1,7 +1,7 @@
 package helper
 
 import (
-	"log"
+	"log/slog"
 
 	"time"
 )
@@ -12,7 +12,7 @@ func InitTimezone(locale string) {
 
 	location, err := time.LoadLocation(locale)
 	if err != nil {
-		log.Printf("Failed to load timezone location: %v", err)
+		slog.Warn("failed to load timezone, keeping the system default", "timezone", locale, "error", err)
 		return
 	}
 
@@ -2,24 +2,21 @@ package helper
 
 import (
 	"database/sql"
-	"fmt"
+	"log/slog"
 )
 
 func CommitOrRollback(tx *sql.Tx) {
 	err := recover()
 	if err != nil {
-		// Log error sebelum rollback
-		fmt.Printf("Transaction error, rolling back: %v\n", err)
-
+		// The panic itself is logged by the router's panic handler
 		errorRollback := tx.Rollback()
 		if errorRollback != nil {
-			fmt.Printf("Rollback error: %v\n", errorRollback)
+			slog.Error("transaction rollback failed", "error", errorRollback)
 		}
 		panic(err)
 	} else {
 		errorCommit := tx.Commit()
 		if errorCommit != nil {
-			fmt.Printf("Commit error: %v\n", errorCommit)
 			PanicIfError(errorCommit)
 		}
 	}
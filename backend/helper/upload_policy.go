@@ -0,0 +1,297 @@
+package helper
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// Upload rejection codes, stable for clients to translate
+const (
+	UploadEmpty          = "empty"
+	UploadTooLarge       = "too_large"
+	UploadTypeNotAllowed = "type_not_allowed"
+	UploadTypeMismatch   = "type_mismatch"
+	UploadPolyglot       = "polyglot"
+	UploadInvalidImage   = "invalid_image"
+)
+
+// UploadRejectedError is returned when an uploaded file breaks the policy of its upload kind.
+// The panic handler answers it with 400 and the code.
+type UploadRejectedError struct {
+	Code         string `json:"code"`
+	Message      string `json:"message"`
+	DetectedType string `json:"detected_type,omitempty"`
+}
+
+func (e UploadRejectedError) Error() string {
+	return e.Message
+}
+
+// PanicIfUploadRejected aborts the request with a 400 when err rejects an upload
+func PanicIfUploadRejected(err error) {
+	if rejected, ok := err.(UploadRejectedError); ok {
+		panic(rejected)
+	}
+}
+
+// UploadPolicy is what one kind of upload may contain. The type is detected from the content,
+// the client's filename only has to agree with it.
+type UploadPolicy struct {
+	// Name used in error messages, e.g. "profile photo"
+	Name string
+
+	// Max file size in bytes
+	MaxSize int64
+
+	// Allowed MIME types with the filename extensions accepted for each
+	AllowedTypes map[string][]string
+}
+
+var (
+	imageTypes = map[string][]string{
+		"image/jpeg": {".jpg", ".jpeg", ".jfif"},
+		"image/png":  {".png"},
+		"image/gif":  {".gif"},
+		// Animated PNG, decoded as its first frame
+		"image/vnd.mozilla.apng": {".png", ".apng"},
+		"image/webp":             {".webp"},
+	}
+
+	documentTypes = map[string][]string{
+		"application/pdf":    {".pdf"},
+		"application/msword": {".doc"},
+		"application/vnd.openxmlformats-officedocument.wordprocessingml.document": {".docx"},
+		"application/vnd.ms-excel": {".xls"},
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":         {".xlsx"},
+		"application/vnd.ms-powerpoint":                                             {".ppt"},
+		"application/vnd.openxmlformats-officedocument.presentationml.presentation": {".pptx"},
+		// Detection tells plain text and CSV apart by content, either may carry both names
+		"text/plain": {".txt", ".csv"},
+		"text/csv":   {".csv", ".txt"},
+	}
+
+	audioTypes = map[string][]string{
+		"audio/mpeg":  {".mp3"},
+		"audio/wav":   {".wav"},
+		"audio/ogg":   {".ogg", ".oga"},
+		"audio/x-m4a": {".m4a"},
+		"audio/mp4":   {".m4a", ".mp4"},
+		"audio/aac":   {".aac"},
+		// Browsers record voice notes as WebM
+		"video/webm": {".webm", ".weba"},
+	}
+)
+
+// Upload policies per kind of upload
+var (
+	UploadPolicyAvatar = UploadPolicy{Name: "profile photo", MaxSize: 5 * 1024 * 1024, AllowedTypes: imageTypes}
+
+	// Post images, also used for group images and company logos
+	UploadPolicyPostImage = UploadPolicy{Name: "image", MaxSize: 10 * 1024 * 1024, AllowedTypes: imageTypes}
+
+	UploadPolicyChatImage    = UploadPolicy{Name: "chat image", MaxSize: 4 * 1024 * 1024, AllowedTypes: imageTypes}
+	UploadPolicyChatDocument = UploadPolicy{Name: "chat document", MaxSize: 10 * 1024 * 1024, AllowedTypes: documentTypes}
+	UploadPolicyChatAudio    = UploadPolicy{Name: "chat audio", MaxSize: 10 * 1024 * 1024, AllowedTypes: audioTypes}
+
+	UploadPolicyCv = UploadPolicy{Name: "CV", MaxSize: 5 * 1024 * 1024, AllowedTypes: map[string][]string{
+		"application/pdf": {".pdf"},
+	}}
+)
+
+// CheckedUpload describes an upload that passed its policy
+type CheckedUpload struct {
+	// MIME type detected from the content
+	ContentType string
+
+	// Extension to store the file with, derived from the content
+	Extension string
+
+	// Client filename, safe to keep and to show
+	Filename string
+}
+
+// Check sniffs the content of an upload and applies the policy. The file is rewound afterwards.
+func (policy UploadPolicy) Check(file multipart.File, fileHeader *multipart.FileHeader) (CheckedUpload, error) {
+	if fileHeader.Size <= 0 {
+		return CheckedUpload{}, UploadRejectedError{Code: UploadEmpty, Message: fmt.Sprintf("%s is empty", policy.Name)}
+	}
+	if fileHeader.Size > policy.MaxSize {
+		return CheckedUpload{}, UploadRejectedError{
+			Code:    UploadTooLarge,
+			Message: fmt.Sprintf("%s must be at most %d MB", policy.Name, policy.MaxSize/(1024*1024)),
+		}
+	}
+
+	detected, err := mimetype.DetectReader(file)
+	if err != nil {
+		return CheckedUpload{}, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return CheckedUpload{}, err
+	}
+
+	contentType, extensions := policy.allowedType(detected)
+	if contentType == "" {
+		return CheckedUpload{}, UploadRejectedError{
+			Code:         UploadTypeNotAllowed,
+			Message:      fmt.Sprintf("%s must be %s", policy.Name, policy.describeTypes()),
+			DetectedType: detected.String(),
+		}
+	}
+
+	ext := strings.ToLower(filepath.Ext(fileHeader.Filename))
+	if !slices.Contains(extensions, ext) {
+		return CheckedUpload{}, UploadRejectedError{
+			Code:         UploadTypeMismatch,
+			Message:      fmt.Sprintf("%s content is %s but the file name ends in %q", policy.Name, contentType, ext),
+			DetectedType: contentType,
+		}
+	}
+
+	if reason := polyglotReason(file, fileHeader.Size, contentType); reason != "" {
+		return CheckedUpload{}, UploadRejectedError{
+			Code:         UploadPolyglot,
+			Message:      fmt.Sprintf("%s is not a plain %s file: %s", policy.Name, contentType, reason),
+			DetectedType: contentType,
+		}
+	}
+
+	return CheckedUpload{
+		ContentType: contentType,
+		Extension:   extensions[0],
+		Filename:    SanitizeFilename(fileHeader.Filename),
+	}, nil
+}
+
+// allowedType matches the detected type, or one of its aliases, against the allow-list
+func (policy UploadPolicy) allowedType(detected *mimetype.MIME) (string, []string) {
+	for contentType, extensions := range policy.AllowedTypes {
+		if detected.Is(contentType) {
+			return contentType, extensions
+		}
+	}
+	return "", nil
+}
+
+// describeTypes lists the allowed extensions for error messages, e.g. "a .gif, .jpg or .png file"
+func (policy UploadPolicy) describeTypes() string {
+	var names []string
+	for _, extensions := range policy.AllowedTypes {
+		names = append(names, extensions[0])
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+	if len(names) == 1 {
+		return "a " + names[0] + " file"
+	}
+	return "a " + strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1] + " file"
+}
+
+// Markers of content a browser or reader would act on, looked for where they are honoured
+var (
+	htmlMarkers = [][]byte{
+		[]byte("<!doctype html"), []byte("<html"), []byte("<head"), []byte("<body"),
+		[]byte("<script"), []byte("<iframe"), []byte("<svg"), []byte("<?php"),
+	}
+	pdfMarker         = []byte("%PDF-")
+	zipEndOfDirectory = []byte("PK\x05\x06")
+)
+
+// zipContainerTypes are formats that are ZIP archives themselves
+var zipContainerTypes = map[string]bool{
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document":   true,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":         true,
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": true,
+}
+
+// polyglotReason reports why a file that passed type detection is also valid as another format,
+// like an image that browsers sniff as HTML or a PDF with a ZIP archive appended to it
+func polyglotReason(file multipart.File, size int64, contentType string) string {
+	// Browsers sniff the first 512 bytes and PDF readers accept a header in the first 1024
+	head := make([]byte, min(size, 1024))
+	if _, err := file.ReadAt(head, 0); err != nil && err != io.EOF {
+		return "it could not be read"
+	}
+	lowerHead := bytes.ToLower(head)
+
+	if !strings.HasPrefix(contentType, "text/") {
+		for _, marker := range htmlMarkers {
+			if bytes.Contains(lowerHead, marker) {
+				return "it contains HTML or script markup"
+			}
+		}
+	}
+	if contentType != "application/pdf" && bytes.Contains(head, pdfMarker) {
+		return "it also contains a PDF header"
+	}
+
+	// ZIP readers look for the end of the central directory in the last 64 KB
+	if !zipContainerTypes[contentType] {
+		tailSize := min(size, 64*1024+22)
+		tail := make([]byte, tailSize)
+		if _, err := file.ReadAt(tail, size-tailSize); err != nil && err != io.EOF {
+			return "it could not be read"
+		}
+		if bytes.Contains(tail, zipEndOfDirectory) {
+			return "it has a ZIP archive appended"
+		}
+	}
+
+	return ""
+}
+
+// SanitizeFilename makes a client supplied filename safe to store, show and put in a
+// Content-Disposition header: no directories, control characters, quotes or reserved characters.
+func SanitizeFilename(filename string) string {
+	filename = strings.ToValidUTF8(filename, "")
+	if index := strings.LastIndexAny(filename, `/\`); index >= 0 {
+		filename = filename[index+1:]
+	}
+
+	var builder strings.Builder
+	lastSpace := false
+	for _, r := range filename {
+		switch {
+		case unicode.IsControl(r) || unicode.Is(unicode.Cf, r):
+			continue
+		case strings.ContainsRune(`<>:"|?*;`+"`", r):
+			r = '_'
+		case unicode.IsSpace(r):
+			if lastSpace {
+				continue
+			}
+			r = ' '
+		}
+		lastSpace = r == ' '
+		builder.WriteRune(r)
+	}
+
+	filename = strings.Trim(builder.String(), " .")
+	if filename == "" {
+		return "file"
+	}
+
+	// Keep the extension when shortening to 255 bytes
+	const maxLength = 255
+	if len(filename) > maxLength {
+		ext := filepath.Ext(filename)
+		if len(ext) > 16 {
+			ext = ""
+		}
+		stem := filename[:maxLength-len(ext)]
+		for !utf8.ValidString(stem) {
+			stem = stem[:len(stem)-1]
+		}
+		filename = stem + ext
+	}
+	return filename
+}
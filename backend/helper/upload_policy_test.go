@@ -0,0 +1,123 @@
+package helper
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"strings"
+	"testing"
+)
+
+// memoryFile is an in-memory multipart.File
+type memoryFile struct {
+	*bytes.Reader
+}
+
+func (memoryFile) Close() error { return nil }
+
+func checkUpload(t *testing.T, policy UploadPolicy, filename string, content []byte) (CheckedUpload, error) {
+	t.Helper()
+	file := memoryFile{bytes.NewReader(content)}
+	return policy.Check(file, &multipart.FileHeader{Filename: filename, Size: int64(len(content))})
+}
+
+func pngBytes(t *testing.T) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func rejectionCode(err error) string {
+	if rejected, ok := err.(UploadRejectedError); ok {
+		return rejected.Code
+	}
+	return ""
+}
+
+func TestUploadPolicyCheck(t *testing.T) {
+	pngImage := pngBytes(t)
+	pdf := []byte("%PDF-1.7\n1 0 obj << /Type /Catalog >> endobj\ntrailer << /Root 1 0 R >>\n%%EOF\n")
+
+	tests := []struct {
+		name     string
+		policy   UploadPolicy
+		filename string
+		content  []byte
+		wantCode string
+		wantExt  string
+	}{
+		{name: "image", policy: UploadPolicyPostImage, filename: "photo.png", content: pngImage, wantExt: ".png"},
+		{name: "extension is case insensitive", policy: UploadPolicyPostImage, filename: "photo.PNG", content: pngImage, wantExt: ".png"},
+		{name: "cv", policy: UploadPolicyCv, filename: "resume.pdf", content: pdf, wantExt: ".pdf"},
+		{name: "empty", policy: UploadPolicyPostImage, filename: "photo.png", content: nil, wantCode: UploadEmpty},
+		{name: "html named as an image", policy: UploadPolicyPostImage, filename: "photo.png", content: []byte("<!DOCTYPE html><html><script>alert(1)</script></html>"), wantCode: UploadTypeNotAllowed},
+		{name: "image as cv", policy: UploadPolicyCv, filename: "resume.pdf", content: pngImage, wantCode: UploadTypeNotAllowed},
+		{name: "extension does not match content", policy: UploadPolicyPostImage, filename: "photo.jpg", content: pngImage, wantCode: UploadTypeMismatch},
+		{name: "html extension", policy: UploadPolicyPostImage, filename: "photo.html", content: pngImage, wantCode: UploadTypeMismatch},
+		{name: "image with script markup", policy: UploadPolicyPostImage, filename: "photo.png", content: append(append([]byte{}, pngImage...), "<script>alert(1)</script>"...), wantCode: UploadPolyglot},
+		{name: "image with pdf header", policy: UploadPolicyPostImage, filename: "photo.png", content: append(append([]byte{}, pngImage...), pdf...), wantCode: UploadPolyglot},
+		{name: "pdf with zip appended", policy: UploadPolicyCv, filename: "resume.pdf", content: append(append([]byte{}, pdf...), "PK\x05\x06\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00"...), wantCode: UploadPolyglot},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checked, err := checkUpload(t, test.policy, test.filename, test.content)
+			if test.wantCode != "" {
+				if code := rejectionCode(err); code != test.wantCode {
+					t.Fatalf("Check() error = %v (code %q), want code %q", err, code, test.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if checked.Extension != test.wantExt {
+				t.Errorf("Extension = %q, want %q", checked.Extension, test.wantExt)
+			}
+		})
+	}
+}
+
+func TestUploadPolicyCheckSize(t *testing.T) {
+	policy := UploadPolicy{Name: "image", MaxSize: 1024, AllowedTypes: imageTypes}
+	file := memoryFile{bytes.NewReader(pngBytes(t))}
+
+	_, err := policy.Check(file, &multipart.FileHeader{Filename: "photo.png", Size: 2048})
+	if code := rejectionCode(err); code != UploadTooLarge {
+		t.Fatalf("Check() error = %v, want code %q", err, UploadTooLarge)
+	}
+}
+
+func TestUploadPolicyCheckRewinds(t *testing.T) {
+	content := pngBytes(t)
+	file := memoryFile{bytes.NewReader(content)}
+
+	if _, err := UploadPolicyPostImage.Check(file, &multipart.FileHeader{Filename: "photo.png", Size: int64(len(content))}); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if file.Len() != len(content) {
+		t.Errorf("file not rewound, %d of %d bytes left", file.Len(), len(content))
+	}
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := map[string]string{
+		"resume.pdf":                      "resume.pdf",
+		"../../etc/passwd":                "passwd",
+		`C:\Users\me\cv.pdf`:              "cv.pdf",
+		"my\r\n\"cv\".pdf":                "my_cv_.pdf",
+		"  spaced    out  name.pdf ":      "spaced out name.pdf",
+		"...":                             "file",
+		strings.Repeat("a", 300) + ".pdf": strings.Repeat("a", 251) + ".pdf",
+	}
+
+	for input, want := range tests {
+		if got := SanitizeFilename(input); got != want {
+			t.Errorf("SanitizeFilename(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
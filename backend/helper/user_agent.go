@@ -0,0 +1,63 @@
+package helper
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// GetUserAgent returns the User-Agent header of the request stored in the context
+func GetUserAgent(ctx context.Context) string {
+	if r, ok := ctx.Value("http_request").(*http.Request); ok {
+		return r.UserAgent()
+	}
+	return ""
+}
+
+// DescribeDevice turns a User-Agent string into a short label such as "Chrome on Windows"
+func DescribeDevice(userAgent string) string {
+	if userAgent == "" {
+		return "Unknown device"
+	}
+
+	ua := strings.ToLower(userAgent)
+
+	browser := "Unknown browser"
+	switch {
+	case strings.Contains(ua, "edg/"):
+		browser = "Edge"
+	case strings.Contains(ua, "opr/") || strings.Contains(ua, "opera"):
+		browser = "Opera"
+	case strings.Contains(ua, "firefox/"):
+		browser = "Firefox"
+	case strings.Contains(ua, "chrome/") || strings.Contains(ua, "crios/"):
+		browser = "Chrome"
+	case strings.Contains(ua, "safari/"):
+		browser = "Safari"
+	case strings.Contains(ua, "okhttp") || strings.Contains(ua, "dart"):
+		browser = "Mobile app"
+	case strings.Contains(ua, "postman"):
+		browser = "Postman"
+	case strings.Contains(ua, "curl"):
+		browser = "curl"
+	}
+
+	platform := ""
+	switch {
+	case strings.Contains(ua, "android"):
+		platform = "Android"
+	case strings.Contains(ua, "iphone") || strings.Contains(ua, "ipad"):
+		platform = "iOS"
+	case strings.Contains(ua, "windows"):
+		platform = "Windows"
+	case strings.Contains(ua, "mac os"):
+		platform = "macOS"
+	case strings.Contains(ua, "linux"):
+		platform = "Linux"
+	}
+
+	if platform == "" {
+		return browser
+	}
+	return browser + " on " + platform
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/config"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+func runReindexSearchCommand(ctx context.Context, cfg config.Config, db *sql.DB, logger *slog.Logger, args []string) int {
+	if len(args) > 0 {
+		return usageError("reindex-search takes no arguments")
+	}
+
+	application, err := newApplication(ctx, cfg, db, logger)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "reindex-search:", err)
+		return 1
+	}
+
+	tables := application.searchService.Reindex(ctx)
+	fmt.Printf("%d table(s) reindexed\n", len(tables))
+	return 0
+}
+
+// runPurgeDeletedCommand runs one pass of the purge worker, for when the server's worker is off or behind
+func runPurgeDeletedCommand(ctx context.Context, cfg config.Config, db *sql.DB, logger *slog.Logger, args []string) int {
+	if len(args) > 0 {
+		return usageError("purge-deleted takes no arguments")
+	}
+
+	application, err := newApplication(ctx, cfg, db, logger)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "purge-deleted:", err)
+		return 1
+	}
+
+	purged := application.accountDeletionService.PurgeDueAccounts(ctx)
+	fmt.Printf("%d account(s) purged\n", purged)
+	return 0
+}
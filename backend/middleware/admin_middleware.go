@@ -3,16 +3,19 @@ package middleware
 import (
 	"context"
 	"evoconnect/backend/helper"
+	"evoconnect/backend/model/domain"
 	"evoconnect/backend/model/web"
+	"evoconnect/backend/service"
 	"evoconnect/backend/utils"
-	"fmt"
+	"log/slog"
 	"net/http"
 	"strings"
 
+	"github.com/google/uuid"
 	"github.com/julienschmidt/httprouter"
 )
 
-func NewAdminAuthMiddleware() func(httprouter.Handle) httprouter.Handle {
+func NewAdminAuthMiddleware(adminAuthService service.AdminAuthService) func(httprouter.Handle) httprouter.Handle {
 	return func(next httprouter.Handle) httprouter.Handle {
 		return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 			// Get Authorization header
@@ -42,7 +45,28 @@ func NewAdminAuthMiddleware() func(httprouter.Handle) httprouter.Handle {
 			// Validate admin token using utility function
 			claims, err := utils.ValidateAdminToken(tokenString)
 			if err != nil {
-				fmt.Printf("Admin token validation error: %v\n", err)
+				slog.DebugContext(request.Context(), "admin token rejected", "error", err)
+				helper.WriteToResponseBody(writer, web.WebResponse{
+					Code:   http.StatusUnauthorized,
+					Status: "UNAUTHORIZED",
+					Data:   "Invalid admin token: " + err.Error(),
+				})
+				return
+			}
+
+			adminId, err := uuid.Parse(claims.ID)
+			if err != nil {
+				helper.WriteToResponseBody(writer, web.WebResponse{
+					Code:   http.StatusUnauthorized,
+					Status: "UNAUTHORIZED",
+					Data:   "Invalid admin token",
+				})
+				return
+			}
+
+			// The role is read from the database so role changes apply immediately
+			admin, err := adminAuthService.CheckAdminAccess(request.Context(), adminId)
+			if err != nil {
 				helper.WriteToResponseBody(writer, web.WebResponse{
 					Code:   http.StatusUnauthorized,
 					Status: "UNAUTHORIZED",
@@ -53,11 +77,32 @@ func NewAdminAuthMiddleware() func(httprouter.Handle) httprouter.Handle {
 
 			// Add admin info to context
 			ctx := context.WithValue(request.Context(), "admin_id", claims.ID)
-			ctx = context.WithValue(ctx, "admin_email", claims.Email)
-			ctx = context.WithValue(ctx, "admin_role", claims.Role)
+			ctx = context.WithValue(ctx, "admin_email", admin.Email)
+			ctx = context.WithValue(ctx, "admin_role", admin.Role)
 
 			// Continue to next handler
 			next(writer, request.WithContext(ctx), params)
 		}
 	}
 }
+
+// RequireAdminPermission must run after the admin auth middleware, which puts the admin role on the context
+func RequireAdminPermission(permission domain.AdminPermission) func(httprouter.Handle) httprouter.Handle {
+	return func(next httprouter.Handle) httprouter.Handle {
+		return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+			role, _ := request.Context().Value("admin_role").(string)
+			if !domain.AdminRole(role).HasPermission(permission) {
+				writer.Header().Set("Content-Type", "application/json")
+				writer.WriteHeader(http.StatusForbidden)
+				helper.WriteToResponseBody(writer, web.WebResponse{
+					Code:   http.StatusForbidden,
+					Status: "FORBIDDEN",
+					Data:   "You do not have permission to perform this action",
+				})
+				return
+			}
+
+			next(writer, request, params)
+		}
+	}
+}
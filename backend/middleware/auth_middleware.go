@@ -2,17 +2,23 @@ package middleware
 
 import (
 	"context"
+	"errors"
+	"evoconnect/backend/exception"
 	"evoconnect/backend/helper"
+	"evoconnect/backend/model/domain"
 	"evoconnect/backend/model/web"
+	"evoconnect/backend/service"
 	"evoconnect/backend/utils"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strings"
 
+	"github.com/google/uuid"
 	"github.com/julienschmidt/httprouter"
 )
 
-func NewUserAuthMiddleware() func(httprouter.Handle) httprouter.Handle {
+func NewUserAuthMiddleware(accountStatusService service.AccountStatusService, userSessionService service.UserSessionService) func(httprouter.Handle) httprouter.Handle {
 	return func(next httprouter.Handle) httprouter.Handle {
 		return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 
@@ -43,7 +49,62 @@ func NewUserAuthMiddleware() func(httprouter.Handle) httprouter.Handle {
 			// Validate user token using utility function
 			claims, err := utils.ValidateUserToken(tokenString)
 			if err != nil {
-				fmt.Printf("User token validation error: %v\n", err)
+				slog.DebugContext(request.Context(), "user token rejected", "error", err)
+				helper.WriteToResponseBody(writer, web.WebResponse{
+					Code:   http.StatusUnauthorized,
+					Status: "UNAUTHORIZED",
+					Data:   "Invalid user token: " + err.Error(),
+				})
+				return
+			}
+
+			// Reject banned and suspended accounts even when their token is still valid
+			userId, err := uuid.Parse(claims.ID)
+			if err != nil {
+				helper.WriteToResponseBody(writer, web.WebResponse{
+					Code:   http.StatusUnauthorized,
+					Status: "UNAUTHORIZED",
+					Data:   "Invalid user token: malformed user id",
+				})
+				return
+			}
+
+			// Access tokens are bound to a server-side session that can be revoked
+			sessionId, err := uuid.Parse(claims.SessionID)
+			if err != nil {
+				helper.WriteToResponseBody(writer, web.WebResponse{
+					Code:   http.StatusUnauthorized,
+					Status: "UNAUTHORIZED",
+					Data:   "Invalid user token: session is required, please log in again",
+				})
+				return
+			}
+
+			err = userSessionService.ValidateSession(request.Context(), userId, sessionId)
+			if err != nil {
+				helper.WriteToResponseBody(writer, web.WebResponse{
+					Code:   http.StatusUnauthorized,
+					Status: "UNAUTHORIZED",
+					Data:   "Invalid user token: " + err.Error(),
+				})
+				return
+			}
+
+			err = accountStatusService.CheckAccountStatus(request.Context(), userId)
+			if err != nil {
+				var restrictedErr exception.AccountRestrictedError
+				if errors.As(err, &restrictedErr) {
+					writer.Header().Set("Content-Type", "application/json")
+					writer.WriteHeader(http.StatusForbidden)
+					helper.WriteToResponseBody(writer, web.WebResponse{
+						Code:   http.StatusForbidden,
+						Status: "ACCOUNT_RESTRICTED",
+						Data:   restrictedErr,
+					})
+					return
+				}
+
+				slog.WarnContext(request.Context(), "account status check failed", "user_id", userId, "error", err)
 				helper.WriteToResponseBody(writer, web.WebResponse{
 					Code:   http.StatusUnauthorized,
 					Status: "UNAUTHORIZED",
@@ -56,9 +117,136 @@ func NewUserAuthMiddleware() func(httprouter.Handle) httprouter.Handle {
 			ctx := context.WithValue(request.Context(), "user_id", claims.ID)
 			ctx = context.WithValue(ctx, "user_email", claims.Email)
 			ctx = context.WithValue(ctx, "user_role", claims.Role)
+			ctx = context.WithValue(ctx, "session_id", claims.SessionID)
 
 			// Continue to next handler
 			next(writer, request.WithContext(ctx), params)
 		}
 	}
 }
+
+// Route parameters naming a resource a partner key must belong to
+var partnerKeyResourceParams = []struct {
+	param    string
+	resource domain.APIResource
+}{
+	{"companyId", domain.APIResourceCompany},
+	{"jobVacancyId", domain.APIResourceJobVacancy},
+	{"applicationId", domain.APIResourceJobApplication},
+}
+
+// NewScopedUserAuthMiddleware protects routes that integrations may call. Logged-in sessions are
+// handled like NewUserAuthMiddleware, API tokens are accepted when they carry the route's scope.
+// Partner keys are only accepted when the route names a resource of their company.
+func NewScopedUserAuthMiddleware(accountStatusService service.AccountStatusService, userSessionService service.UserSessionService, apiTokenService service.APITokenService) func(domain.APIScope, httprouter.Handle) httprouter.Handle {
+	return newScopedUserAuthMiddleware(accountStatusService, userSessionService, apiTokenService, true)
+}
+
+// NewPersonalScopedUserAuthMiddleware is NewScopedUserAuthMiddleware for routes acting on the
+// caller's own data, such as their applications. Partner keys are rejected there.
+func NewPersonalScopedUserAuthMiddleware(accountStatusService service.AccountStatusService, userSessionService service.UserSessionService, apiTokenService service.APITokenService) func(domain.APIScope, httprouter.Handle) httprouter.Handle {
+	return newScopedUserAuthMiddleware(accountStatusService, userSessionService, apiTokenService, false)
+}
+
+func newScopedUserAuthMiddleware(accountStatusService service.AccountStatusService, userSessionService service.UserSessionService, apiTokenService service.APITokenService, allowPartnerKeys bool) func(domain.APIScope, httprouter.Handle) httprouter.Handle {
+	userAuth := NewUserAuthMiddleware(accountStatusService, userSessionService)
+
+	return func(scope domain.APIScope, next httprouter.Handle) httprouter.Handle {
+		sessionAuth := userAuth(next)
+
+		return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+			tokenString := strings.TrimPrefix(request.Header.Get("Authorization"), "Bearer ")
+			if !service.IsAPIToken(tokenString) {
+				sessionAuth(writer, request, params)
+				return
+			}
+
+			token, err := apiTokenService.Authenticate(request.Context(), tokenString)
+			if err != nil {
+				helper.WriteToResponseBody(writer, web.WebResponse{
+					Code:   http.StatusUnauthorized,
+					Status: "UNAUTHORIZED",
+					Data:   err.Error(),
+				})
+				return
+			}
+
+			if !token.HasScope(scope) {
+				helper.WriteToResponseBody(writer, web.WebResponse{
+					Code:   http.StatusForbidden,
+					Status: "FORBIDDEN",
+					Data:   fmt.Sprintf("API token is missing the %s scope", scope),
+				})
+				return
+			}
+
+			// Partner keys only reach the company they were issued for
+			if token.CompanyId != nil {
+				err = authorizePartnerKey(request.Context(), apiTokenService, *token.CompanyId, params, allowPartnerKeys)
+				if err != nil {
+					helper.WriteToResponseBody(writer, web.WebResponse{
+						Code:   http.StatusForbidden,
+						Status: "FORBIDDEN",
+						Data:   err.Error(),
+					})
+					return
+				}
+			}
+
+			err = accountStatusService.CheckAccountStatus(request.Context(), token.UserId)
+			if err != nil {
+				var restrictedErr exception.AccountRestrictedError
+				if errors.As(err, &restrictedErr) {
+					writer.Header().Set("Content-Type", "application/json")
+					writer.WriteHeader(http.StatusForbidden)
+					helper.WriteToResponseBody(writer, web.WebResponse{
+						Code:   http.StatusForbidden,
+						Status: "ACCOUNT_RESTRICTED",
+						Data:   restrictedErr,
+					})
+					return
+				}
+
+				helper.WriteToResponseBody(writer, web.WebResponse{
+					Code:   http.StatusUnauthorized,
+					Status: "UNAUTHORIZED",
+					Data:   "Invalid API token: " + err.Error(),
+				})
+				return
+			}
+
+			// API tokens have no session, handlers that need one reject them
+			ctx := context.WithValue(request.Context(), "user_id", token.UserId.String())
+			ctx = context.WithValue(ctx, "user_role", "user")
+			ctx = context.WithValue(ctx, "api_token_id", token.Id.String())
+
+			next(writer, request.WithContext(ctx), params)
+		}
+	}
+}
+
+// authorizePartnerKey checks that the resource a route names belongs to the key's company. Routes
+// naming no company resource are closed to partner keys.
+func authorizePartnerKey(ctx context.Context, apiTokenService service.APITokenService, keyCompanyId uuid.UUID, params httprouter.Params, allowPartnerKeys bool) error {
+	if allowPartnerKeys {
+		for _, resourceParam := range partnerKeyResourceParams {
+			value := params.ByName(resourceParam.param)
+			if value == "" {
+				continue
+			}
+
+			resourceId, err := uuid.Parse(value)
+			if err != nil {
+				return fmt.Errorf("invalid %s", resourceParam.param)
+			}
+			// A missing resource is reported like a foreign one, so keys cannot probe other companies
+			companyId, err := apiTokenService.FindResourceCompanyId(ctx, resourceParam.resource, resourceId)
+			if err != nil || companyId != keyCompanyId {
+				return errors.New("API key was issued for a different company")
+			}
+			return nil
+		}
+	}
+
+	return errors.New("API keys of a company cannot be used on this route")
+}
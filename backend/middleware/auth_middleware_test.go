@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"evoconnect/backend/model/domain"
+	"evoconnect/backend/model/web"
+	"evoconnect/backend/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+)
+
+type fakeAPITokenService struct {
+	service.APITokenService
+	tokens    map[string]domain.APIToken
+	companies map[uuid.UUID]uuid.UUID
+}
+
+func (fake fakeAPITokenService) Authenticate(ctx context.Context, rawToken string) (domain.APIToken, error) {
+	token, ok := fake.tokens[rawToken]
+	if !ok {
+		return domain.APIToken{}, errors.New("invalid API token")
+	}
+	return token, nil
+}
+
+func (fake fakeAPITokenService) FindResourceCompanyId(ctx context.Context, resource domain.APIResource, resourceId uuid.UUID) (uuid.UUID, error) {
+	if resource == domain.APIResourceCompany {
+		return resourceId, nil
+	}
+	companyId, ok := fake.companies[resourceId]
+	if !ok {
+		return uuid.Nil, errors.New("not found")
+	}
+	return companyId, nil
+}
+
+type fakeAccountStatusService struct {
+	service.AccountStatusService
+}
+
+func (fakeAccountStatusService) CheckAccountStatus(ctx context.Context, userId uuid.UUID) error {
+	return nil
+}
+
+// serveScoped runs one request through the scoped middleware and returns the response code and
+// whether the handler was reached
+func serveScoped(t *testing.T, auth func(domain.APIScope, httprouter.Handle) httprouter.Handle, scope domain.APIScope, token string, params httprouter.Params) (int, bool) {
+	t.Helper()
+	reached := false
+	handler := auth(scope, func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		reached = true
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Authorization", "Bearer "+token)
+	recorder := httptest.NewRecorder()
+	handler(recorder, request, params)
+
+	if reached {
+		return http.StatusOK, true
+	}
+	var response web.WebResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return response.Code, false
+}
+
+func TestPartnerKeyScoping(t *testing.T) {
+	companyA, companyB := uuid.New(), uuid.New()
+	vacancyA, vacancyB := uuid.New(), uuid.New()
+	applicationA, applicationB := uuid.New(), uuid.New()
+
+	allScopes := []domain.APIScope{
+		domain.APIScopeProfileRead,
+		domain.APIScopeJobsRead, domain.APIScopeJobsWrite,
+		domain.APIScopeApplicationsRead, domain.APIScopeApplicationsWrite,
+	}
+	tokens := fakeAPITokenService{
+		tokens: map[string]domain.APIToken{
+			"evo_key_company_a": {Id: uuid.New(), UserId: uuid.New(), CompanyId: &companyA, Scopes: allScopes, ExpiresAt: time.Now().Add(time.Hour)},
+			"evo_pat_personal":  {Id: uuid.New(), UserId: uuid.New(), Scopes: allScopes, ExpiresAt: time.Now().Add(time.Hour)},
+		},
+		companies: map[uuid.UUID]uuid.UUID{
+			vacancyA:     companyA,
+			vacancyB:     companyB,
+			applicationA: companyA,
+			applicationB: companyB,
+		},
+	}
+	tokenAuth := NewScopedUserAuthMiddleware(fakeAccountStatusService{}, nil, tokens)
+	personalTokenAuth := NewPersonalScopedUserAuthMiddleware(fakeAccountStatusService{}, nil, tokens)
+
+	param := func(key string, value uuid.UUID) httprouter.Params {
+		return httprouter.Params{{Key: key, Value: value.String()}}
+	}
+
+	tests := []struct {
+		name     string
+		auth     func(domain.APIScope, httprouter.Handle) httprouter.Handle
+		token    string
+		params   httprouter.Params
+		wantCode int
+	}{
+		{"own company", tokenAuth, "evo_key_company_a", param("companyId", companyA), http.StatusOK},
+		{"other company", tokenAuth, "evo_key_company_a", param("companyId", companyB), http.StatusForbidden},
+		{"own job vacancy", tokenAuth, "evo_key_company_a", param("jobVacancyId", vacancyA), http.StatusOK},
+		{"other company's job vacancy", tokenAuth, "evo_key_company_a", param("jobVacancyId", vacancyB), http.StatusForbidden},
+		{"own application", tokenAuth, "evo_key_company_a", param("applicationId", applicationA), http.StatusOK},
+		{"other company's application", tokenAuth, "evo_key_company_a", param("applicationId", applicationB), http.StatusForbidden},
+		{"missing resource", tokenAuth, "evo_key_company_a", param("applicationId", uuid.New()), http.StatusForbidden},
+		{"invalid resource id", tokenAuth, "evo_key_company_a", httprouter.Params{{Key: "jobVacancyId", Value: "not-a-uuid"}}, http.StatusForbidden},
+		{"route without a company resource", tokenAuth, "evo_key_company_a", nil, http.StatusForbidden},
+		{"personal route", personalTokenAuth, "evo_key_company_a", param("jobVacancyId", vacancyA), http.StatusForbidden},
+		{"personal token on a personal route", personalTokenAuth, "evo_pat_personal", param("jobVacancyId", vacancyB), http.StatusOK},
+		{"personal token on a company route", tokenAuth, "evo_pat_personal", param("companyId", companyB), http.StatusOK},
+		{"unknown token", tokenAuth, "evo_key_unknown", param("companyId", companyA), http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			code, _ := serveScoped(t, test.auth, domain.APIScopeApplicationsRead, test.token, test.params)
+			if code != test.wantCode {
+				t.Errorf("code = %d, want %d", code, test.wantCode)
+			}
+		})
+	}
+}
+
+func TestScopedAuthRequiresScope(t *testing.T) {
+	companyId := uuid.New()
+	tokens := fakeAPITokenService{
+		tokens: map[string]domain.APIToken{
+			"evo_key_read_only": {Id: uuid.New(), UserId: uuid.New(), CompanyId: &companyId, Scopes: []domain.APIScope{domain.APIScopeJobsRead}, ExpiresAt: time.Now().Add(time.Hour)},
+		},
+	}
+	tokenAuth := NewScopedUserAuthMiddleware(fakeAccountStatusService{}, nil, tokens)
+
+	code, reached := serveScoped(t, tokenAuth, domain.APIScopeJobsWrite, "evo_key_read_only", httprouter.Params{{Key: "companyId", Value: companyId.String()}})
+	if reached || code != http.StatusForbidden {
+		t.Errorf("code = %d, reached = %v, want a forbidden response", code, reached)
+	}
+}
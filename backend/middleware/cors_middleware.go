@@ -1,24 +1,46 @@
 package middleware
 
 import (
+	"evoconnect/backend/config"
 	"net/http"
 )
 
-// CORSMiddleware adds required CORS headers to allow Swagger UI testing
-func CORSMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Tambahkan header CORS tetapi jangan menggantikan header lain
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With")
-		w.Header().Set("Access-Control-Allow-Credentials", "true")
-
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
+// CORSMiddleware adds required CORS headers to allow Swagger UI testing. With an origin list
+// instead of "*" only those origins get the headers, echoed back with Vary: Origin.
+func CORSMiddleware(corsConfig config.CORSConfig) func(http.Handler) http.Handler {
+	allowAny := false
+	allowed := make(map[string]bool, len(corsConfig.AllowedOrigins))
+	for _, origin := range corsConfig.AllowedOrigins {
+		if origin == "*" {
+			allowAny = true
 		}
+		allowed[origin] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Tambahkan header CORS tetapi jangan menggantikan header lain
+			origin := r.Header.Get("Origin")
+			if allowAny {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Add("Vary", "Origin")
+				if allowed[origin] {
+					w.Header().Set("Access-Control-Allow-Origin", origin)
+				}
+			}
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, X-Request-ID")
+			w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+
+			if r.Method == "OPTIONS" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
 
-		// Lewatkan ke handler berikutnya
-		next.ServeHTTP(w, r)
-	})
+			// Lewatkan ke handler berikutnya
+			next.ServeHTTP(w, r)
+		})
+	}
 }
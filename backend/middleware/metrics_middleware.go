@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"evoconnect/backend/helper"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// Paths that match no route share one label, otherwise scanners would create a series per URL
+const unmatchedRoute = "unmatched"
+
+// MetricsMiddleware counts requests and records their latency, labelled by the route pattern
+// of the router (like /api/users/:userId) instead of the raw path.
+func MetricsMiddleware(router *httprouter.Router) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Resolved first, some handlers rewrite the URL path
+			route := routePattern(router, r)
+
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			start := time.Now()
+
+			next.ServeHTTP(recorder, r)
+
+			status := strconv.Itoa(recorder.status)
+			helper.HTTPRequestsTotal.WithLabelValues(r.Method, route, status).Inc()
+			helper.HTTPRequestDuration.WithLabelValues(r.Method, route, status).Observe(time.Since(start).Seconds())
+		})
+	}
+}
+
+// routePattern rebuilds the registered pattern by putting the parameter names back in place of their values
+func routePattern(router *httprouter.Router, r *http.Request) string {
+	handle, params, _ := router.Lookup(r.Method, r.URL.Path)
+	if handle == nil {
+		return unmatchedRoute
+	}
+	if len(params) == 0 {
+		return r.URL.Path
+	}
+
+	path, suffix := r.URL.Path, ""
+	// A catch-all parameter holds the rest of the path, starting with a slash
+	if last := params[len(params)-1]; strings.HasPrefix(last.Value, "/") && strings.HasSuffix(path, last.Value) {
+		path = strings.TrimSuffix(path, last.Value)
+		suffix = "/*" + last.Key
+		params = params[:len(params)-1]
+	}
+
+	segments := strings.Split(path, "/")
+	next := 0
+	for i := 1; i < len(segments) && next < len(params); i++ {
+		if segments[i] == params[next].Value {
+			segments[i] = ":" + params[next].Key
+			next++
+		}
+	}
+	return strings.Join(segments, "/") + suffix
+}
+
+// NewMetricsAuthMiddleware guards /metrics with its own bearer token, kept apart from user and
+// admin credentials so a scraper cannot call the API. Without a token the endpoint is disabled.
+func NewMetricsAuthMiddleware(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token == "" {
+				http.NotFound(w, r)
+				return
+			}
+
+			provided := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
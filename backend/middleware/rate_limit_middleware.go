@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"evoconnect/backend/helper"
+	"evoconnect/backend/service"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// NewIPRateLimitMiddleware counts every request to the wrapped route against rule, keyed by the
+// client IP. Requests over the limit get 429 with a Retry-After header.
+func NewIPRateLimitMiddleware(rateLimiter service.RateLimiter, rule service.RateLimitRule) func(httprouter.Handle) httprouter.Handle {
+	return func(next httprouter.Handle) httprouter.Handle {
+		return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+			rateLimiter.Allow(r.Context(), rule, service.RateLimitKeyIP(helper.GetClientIP(r.Context())))
+			next(w, r, ps)
+		}
+	}
+}
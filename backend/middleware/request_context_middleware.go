@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+)
+
+// RequestContextMiddleware stores the incoming request in its context so services can
+// read the client IP and User-Agent through helper.GetClientIP and helper.GetUserAgent
+func RequestContextMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), "http_request", r)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
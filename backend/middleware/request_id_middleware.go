@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"evoconnect/backend/helper"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// Incoming ids are echoed into logs and headers, so only short plain values are accepted
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestIDMiddleware keeps the X-Request-ID of the caller, or assigns one, and writes one access
+// log line per request. Log lines written with the request context carry the same id.
+func RequestIDMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(RequestIDHeader)
+			if !validRequestID.MatchString(requestID) {
+				requestID = uuid.NewString()
+			}
+
+			ctx := helper.WithRequestID(r.Context(), requestID)
+			w.Header().Set(RequestIDHeader, requestID)
+
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			start := time.Now()
+
+			next.ServeHTTP(recorder, r.WithContext(ctx))
+
+			// The query string is left out, some links carry tokens in it
+			logger.LogAttrs(ctx, requestLogLevel(r.URL.Path, recorder.status), "request completed",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", recorder.status),
+				slog.Duration("duration", time.Since(start)),
+				slog.String("client_ip", helper.GetClientIP(ctx)),
+			)
+		})
+	}
+}
+
+// Successful probes arrive every few seconds and would drown the access log
+var quietLogPaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+func requestLogLevel(path string, status int) slog.Level {
+	switch {
+	case status >= 500:
+		return slog.LevelError
+	case status >= 400:
+		return slog.LevelWarn
+	case quietLogPaths[path]:
+		return slog.LevelDebug
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// statusRecorder remembers the status code written by the handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (recorder *statusRecorder) WriteHeader(status int) {
+	if !recorder.wroteHeader {
+		recorder.status = status
+		recorder.wroteHeader = true
+	}
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+func (recorder *statusRecorder) Write(body []byte) (int, error) {
+	recorder.wroteHeader = true
+	return recorder.ResponseWriter.Write(body)
+}
+
+// Flush keeps streaming responses working through the recorder
+func (recorder *statusRecorder) Flush() {
+	if flusher, ok := recorder.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (recorder *statusRecorder) Unwrap() http.ResponseWriter {
+	return recorder.ResponseWriter
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/config"
+	"evoconnect/backend/db/migrations"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+)
+
+const migrateUsage = `Usage: evoconnect migrate <command>
+
+Commands:
+  up                apply every pending migration
+  down [-to VERSION] roll back the latest migration, or every migration newer than VERSION (0 for all)
+  status            list migrations and whether they are applied
+  redo              roll back the latest migration and apply it again
+`
+
+// runMigrateCommand runs "evoconnect migrate ..." and returns the process exit code
+func runMigrateCommand(ctx context.Context, cfg config.Config, db *sql.DB, logger *slog.Logger, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	migrator, err := migrations.NewMigrator(db, cfg.Migrations.Table, logger)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "migrate:", err)
+		return 1
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		fmt.Printf("%d migration(s) applied\n", len(applied))
+		return migrateExitCode(err)
+
+	case "down":
+		flags := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+		to := flags.Int64("to", -1, "roll back every migration newer than this version")
+		if err := flags.Parse(args[1:]); err != nil {
+			return 2
+		}
+		if *to < 0 {
+			migration, err := migrator.Down(ctx)
+			if err == nil {
+				fmt.Printf("rolled back %s\n", migration.Name)
+			}
+			return migrateExitCode(err)
+		}
+		rolledBack, err := migrator.DownTo(ctx, *to)
+		fmt.Printf("%d migration(s) rolled back\n", len(rolledBack))
+		return migrateExitCode(err)
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return migrateExitCode(err)
+		}
+		printMigrationStatus(os.Stdout, statuses)
+		return 0
+
+	case "redo":
+		migration, err := migrator.Redo(ctx)
+		if err == nil {
+			fmt.Printf("redone %s\n", migration.Name)
+		}
+		return migrateExitCode(err)
+
+	default:
+		fmt.Fprintf(os.Stderr, "migrate: unknown command %q\n\n%s", args[0], migrateUsage)
+		return 2
+	}
+}
+
+func migrateExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	fmt.Fprintln(os.Stderr, "migrate:", err)
+	return 1
+}
+
+func printMigrationStatus(w io.Writer, statuses []migrations.MigrationStatus) {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "APPLIED AT\tMIGRATION")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		} else if status.Applied {
+			appliedAt = "applied"
+		}
+		fmt.Fprintf(table, "%s\t%s\n", appliedAt, status.Name)
+	}
+	table.Flush()
+}
+
+// requireMigrations stops the server from running against an outdated schema. ALLOW_PENDING_MIGRATIONS
+// is the escape hatch for rolling deploys where the new schema is applied by a separate job.
+func requireMigrations(ctx context.Context, db *sql.DB, logger *slog.Logger, migrationsConfig config.MigrationsConfig) error {
+	pending, err := migrations.PendingVersions(ctx, db, migrationsConfig.Table)
+	if err != nil {
+		return fmt.Errorf("check migrations: %w", err)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	if migrationsConfig.AllowPending {
+		logger.Warn("starting with pending migrations", "pending", pending)
+		return nil
+	}
+	return fmt.Errorf("%d migration(s) pending, run \"evoconnect migrate up\" or set ALLOW_PENDING_MIGRATIONS=true", len(pending))
+}
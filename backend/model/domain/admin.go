@@ -1,16 +1,108 @@
 package domain
 
 import (
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// AdminRole decides which parts of the admin panel an admin can use
+type AdminRole string
+
+const (
+	AdminRoleSuperAdmin      AdminRole = "super_admin"
+	AdminRoleModerator       AdminRole = "moderator"
+	AdminRoleCompanyReviewer AdminRole = "company_reviewer"
+	AdminRoleSupport         AdminRole = "support"
+)
+
+type AdminStatus string
+
+const (
+	AdminStatusActive   AdminStatus = "active"
+	AdminStatusDisabled AdminStatus = "disabled"
+)
+
+// AdminPermission is checked per route by the admin permission middleware
+type AdminPermission string
+
+const (
+	AdminPermissionManageAdmins    AdminPermission = "admins:manage"
+	AdminPermissionManageSettings  AdminPermission = "settings:manage"
+	AdminPermissionViewCompanies   AdminPermission = "companies:read"
+	AdminPermissionReviewCompanies AdminPermission = "companies:review"
+	AdminPermissionViewReports     AdminPermission = "reports:read"
+	AdminPermissionManageReports   AdminPermission = "reports:manage"
+	AdminPermissionViewAuditLogs   AdminPermission = "audit_logs:read"
+)
+
+var adminRolePermissions = map[AdminRole][]AdminPermission{
+	AdminRoleSuperAdmin: {
+		AdminPermissionManageAdmins,
+		AdminPermissionManageSettings,
+		AdminPermissionViewCompanies,
+		AdminPermissionReviewCompanies,
+		AdminPermissionViewReports,
+		AdminPermissionManageReports,
+		AdminPermissionViewAuditLogs,
+	},
+	AdminRoleModerator: {
+		AdminPermissionViewReports,
+		AdminPermissionManageReports,
+	},
+	AdminRoleCompanyReviewer: {
+		AdminPermissionViewCompanies,
+		AdminPermissionReviewCompanies,
+	},
+	AdminRoleSupport: {
+		AdminPermissionViewCompanies,
+		AdminPermissionViewReports,
+	},
+}
+
+// IsValid reports whether the role is one of the known admin roles
+func (role AdminRole) IsValid() bool {
+	_, ok := adminRolePermissions[role]
+	return ok
+}
+
+// HasPermission reports whether the role grants the given permission
+func (role AdminRole) HasPermission(permission AdminPermission) bool {
+	return slices.Contains(adminRolePermissions[role], permission)
+}
+
+// Permissions lists everything the role grants
+func (role AdminRole) Permissions() []AdminPermission {
+	return slices.Clone(adminRolePermissions[role])
+}
+
 type Admin struct {
-	Id        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	Password  string    `json:"password"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Id         uuid.UUID   `json:"id"`
+	Name       string      `json:"name"`
+	Email      string      `json:"email"`
+	Password   string      `json:"password"`
+	Role       AdminRole   `json:"role"`
+	Status     AdminStatus `json:"status"`
+	DisabledAt *time.Time  `json:"disabled_at"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+}
+
+// IsActive reports whether the admin may sign in
+func (admin Admin) IsActive() bool {
+	return admin.Status == AdminStatusActive
+}
+
+// AdminInvitation is sent by a super admin; the invited person picks a password when accepting it
+type AdminInvitation struct {
+	Id         uuid.UUID  `json:"id"`
+	Email      string     `json:"email"`
+	Name       string     `json:"name"`
+	Role       AdminRole  `json:"role"`
+	TokenHash  string     `json:"-"`
+	InvitedBy  *uuid.UUID `json:"invited_by"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	AcceptedAt *time.Time `json:"accepted_at"`
+	CreatedAt  time.Time  `json:"created_at"`
 }
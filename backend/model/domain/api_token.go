@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIScope limits what an API token may do. Logged-in sessions are not scoped.
+type APIScope string
+
+const (
+	APIScopeProfileRead       APIScope = "profile:read"
+	APIScopeJobsRead          APIScope = "jobs:read"
+	APIScopeJobsWrite         APIScope = "jobs:write"
+	APIScopeApplicationsRead  APIScope = "applications:read"
+	APIScopeApplicationsWrite APIScope = "applications:write"
+)
+
+// PersonalAPIScopes can be granted to personal access tokens
+var PersonalAPIScopes = []APIScope{
+	APIScopeProfileRead,
+	APIScopeJobsRead,
+	APIScopeJobsWrite,
+	APIScopeApplicationsRead,
+	APIScopeApplicationsWrite,
+}
+
+// PartnerAPIScopes can be granted to company API keys, they only cover recruiting
+var PartnerAPIScopes = []APIScope{
+	APIScopeJobsRead,
+	APIScopeJobsWrite,
+	APIScopeApplicationsRead,
+	APIScopeApplicationsWrite,
+}
+
+// APIResource is a resource a route names in its path. Partner keys only reach resources
+// belonging to the company they were issued for.
+type APIResource string
+
+const (
+	APIResourceCompany        APIResource = "company"
+	APIResourceJobVacancy     APIResource = "job_vacancy"
+	APIResourceJobApplication APIResource = "job_application"
+)
+
+// APIToken is a personal access token, or a partner API key when CompanyId is set. A token acts
+// as the user who created it.
+type APIToken struct {
+	Id          uuid.UUID  `json:"id"`
+	UserId      uuid.UUID  `json:"user_id"`
+	CompanyId   *uuid.UUID `json:"company_id"`
+	Name        string     `json:"name"`
+	TokenPrefix string     `json:"token_prefix"`
+	TokenHash   string     `json:"-"`
+	Scopes      []APIScope `json:"scopes"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	LastUsedIp  *string    `json:"last_used_ip"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// IsActive reports whether the token can still be used to authenticate
+func (token APIToken) IsActive() bool {
+	return token.RevokedAt == nil && time.Now().Before(token.ExpiresAt)
+}
+
+func (token APIToken) HasScope(scope APIScope) bool {
+	for _, granted := range token.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
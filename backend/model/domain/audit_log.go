@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit actions written by admin and moderation endpoints
+const (
+	AuditActionReportAction             = "report.action"
+	AuditActionCompanySubmissionReview  = "company_submission.review"
+	AuditActionCompanyEditRequestReview = "company_edit_request.review"
+	AuditActionAdminInvite              = "admin.invite"
+	AuditActionAdminCreate              = "admin.create"
+	AuditActionAdminRoleUpdate          = "admin.role_update"
+	AuditActionAdminStatusUpdate        = "admin.status_update"
+	AuditActionSettingUpdate            = "setting.update"
+	AuditActionUserSuspend              = "user.suspend"
+	AuditActionUserUnsuspend            = "user.unsuspend"
+	AuditActionUserVerify               = "user.verify"
+)
+
+type AuditLog struct {
+	Id         uuid.UUID       `json:"id"`
+	ActorType  string          `json:"actor_type"`
+	ActorId    *uuid.UUID      `json:"actor_id"`
+	ActorEmail string          `json:"actor_email"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetId   string          `json:"target_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	IpAddress  string          `json:"ip_address"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// AuditLogFilter narrows the audit log listing, zero values are ignored
+type AuditLogFilter struct {
+	ActorId    *uuid.UUID
+	Action     string
+	TargetType string
+	TargetId   string
+	From       *time.Time
+	To         *time.Time
+}
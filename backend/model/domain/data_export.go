@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DataExportStatus string
+
+const (
+	DataExportStatusPending    DataExportStatus = "pending"
+	DataExportStatusProcessing DataExportStatus = "processing"
+	DataExportStatusReady      DataExportStatus = "ready"
+	DataExportStatusFailed     DataExportStatus = "failed"
+	DataExportStatusExpired    DataExportStatus = "expired"
+)
+
+type DataExport struct {
+	Id           uuid.UUID        `json:"id"`
+	UserId       uuid.UUID        `json:"user_id"`
+	Status       DataExportStatus `json:"status"`
+	FilePath     *string          `json:"-"`
+	FileSize     *int64           `json:"file_size"`
+	ErrorMessage *string          `json:"error_message"`
+	StartedAt    *time.Time       `json:"started_at"`
+	CompletedAt  *time.Time       `json:"completed_at"`
+	ExpiresAt    *time.Time       `json:"expires_at"`
+	CreatedAt    time.Time        `json:"created_at"`
+}
+
+// IsDownloadable reports whether the archive is built and its link has not expired yet
+func (export DataExport) IsDownloadable() bool {
+	return export.Status == DataExportStatusReady &&
+		export.FilePath != nil &&
+		export.ExpiresAt != nil && time.Now().Before(*export.ExpiresAt)
+}
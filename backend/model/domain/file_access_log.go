@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Why a private file was accessed
+const (
+	FileAccessDownload = "download"
+	FileAccessView     = "view"
+)
+
+// What a private file is attached to
+const (
+	FileReferenceCv             = "user_cv_storage"
+	FileReferenceJobApplication = "job_application"
+	FileReferenceMessage        = "message"
+)
+
+// FileAccessLog records a signed URL issued for a private file
+type FileAccessLog struct {
+	Id            uuid.UUID `json:"id"`
+	UserId        uuid.UUID `json:"user_id"`
+	FileKey       string    `json:"file_key"`
+	Action        string    `json:"action"`
+	ReferenceType string    `json:"reference_type"`
+	ReferenceId   uuid.UUID `json:"reference_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FileScanStatus string
+
+const (
+	FileScanStatusPending  FileScanStatus = "pending"
+	FileScanStatusScanning FileScanStatus = "scanning"
+	FileScanStatusClean    FileScanStatus = "clean"
+	FileScanStatusInfected FileScanStatus = "infected"
+	// The scanner kept failing, the file stays in quarantine
+	FileScanStatusFailed FileScanStatus = "failed"
+)
+
+// Kinds of scanned uploads
+const (
+	FileScanKindCv   = "cv"
+	FileScanKindChat = "chat"
+)
+
+// FileScan tracks the malware scan of one uploaded file, FileKey is where it is served from once clean
+type FileScan struct {
+	Id        uuid.UUID      `json:"id"`
+	UserId    uuid.UUID      `json:"user_id"`
+	FileKey   string         `json:"file_key"`
+	FileName  string         `json:"file_name"`
+	Kind      string         `json:"kind"`
+	Status    FileScanStatus `json:"status"`
+	Signature *string        `json:"signature"`
+	Attempts  int            `json:"attempts"`
+	StartedAt *time.Time     `json:"started_at"`
+	ScannedAt *time.Time     `json:"scanned_at"`
+	CreatedAt time.Time      `json:"created_at"`
+}
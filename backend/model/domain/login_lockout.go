@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountType tells whether an account is a user or an admin
+type AccountType string
+
+const (
+	AccountTypeUser  AccountType = "user"
+	AccountTypeAdmin AccountType = "admin"
+)
+
+type LoginLockout struct {
+	OwnerType    AccountType `json:"owner_type"`
+	OwnerId      uuid.UUID   `json:"owner_id"`
+	FailedCount  int         `json:"failed_count"`
+	LockoutCount int         `json:"lockout_count"`
+	LastFailedAt *time.Time  `json:"last_failed_at"`
+	LockedUntil  *time.Time  `json:"locked_until"`
+}
+
+// IsLocked reports whether logins are refused right now
+func (lockout LoginLockout) IsLocked() bool {
+	return lockout.LockedUntil != nil && time.Now().Before(*lockout.LockedUntil)
+}
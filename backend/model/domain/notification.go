@@ -51,6 +51,14 @@ const (
     NotificationTypeJobApplicationReviewed NotificationType = "job_application_reviewed"
 
 	NotificationTypeCompanyPostCommentTakenDown NotificationType = "company_post_comment_taken_down"
+
+	// Account notifications
+	NotificationTypeDataExportReady  NotificationType = "data_export_ready"
+	NotificationTypeDataExportFailed NotificationType = "data_export_failed"
+
+	// Upload notifications
+	NotificationTypeFileInfected   NotificationType = "file_infected"
+	NotificationTypeFileScanFailed NotificationType = "file_scan_failed"
 )
 
 // NotificationStatus represents the status of a notification
@@ -0,0 +1,6 @@
+package domain
+
+// Keys of the system_settings table
+const (
+	SettingAdminTwoFactorRequired = "admin_two_factor_required"
+)
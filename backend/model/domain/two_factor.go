@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TwoFactorOwnerType tells whether a credential belongs to a user or an admin
+type TwoFactorOwnerType string
+
+const (
+	TwoFactorOwnerUser  TwoFactorOwnerType = "user"
+	TwoFactorOwnerAdmin TwoFactorOwnerType = "admin"
+)
+
+type TwoFactorCredential struct {
+	Id           uuid.UUID          `json:"id"`
+	OwnerType    TwoFactorOwnerType `json:"owner_type"`
+	OwnerId      uuid.UUID          `json:"owner_id"`
+	Secret       string             `json:"-"`
+	LastUsedStep int64              `json:"-"`
+	EnabledAt    *time.Time         `json:"enabled_at"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+}
+
+// IsEnabled reports whether enrollment was confirmed with a valid code
+func (credential TwoFactorCredential) IsEnabled() bool {
+	return credential.EnabledAt != nil
+}
+
+type TwoFactorRecoveryCode struct {
+	Id           uuid.UUID  `json:"id"`
+	CredentialId uuid.UUID  `json:"credential_id"`
+	CodeHash     string     `json:"-"`
+	UsedAt       *time.Time `json:"used_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
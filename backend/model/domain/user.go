@@ -62,6 +62,35 @@ func (s *SocialMedia) Scan(value interface{}) error {
 	return json.Unmarshal(bytes, s)
 }
 
+// UserStatus represents the moderation state of a user account
+type UserStatus string
+
+const (
+	UserStatusActive    UserStatus = "active"
+	UserStatusSuspended UserStatus = "suspended"
+	UserStatusBanned    UserStatus = "banned"
+	UserStatusDeleted   UserStatus = "deleted"
+)
+
+// DeletedUserName replaces the name of purged accounts, chat history keeps showing it
+const DeletedUserName = "Deleted user"
+
+// UserAccountStatus holds the moderation columns of a user row
+type UserAccountStatus struct {
+	UserId         uuid.UUID  `json:"user_id"`
+	Status         UserStatus `json:"status"`
+	SuspendedUntil *time.Time `json:"suspended_until"`
+}
+
+// UserEmailChange is a requested email change waiting for the new address to confirm it
+type UserEmailChange struct {
+	UserId       uuid.UUID
+	Name         string
+	Email        string
+	PendingEmail string
+	ExpiresAt    time.Time
+}
+
 type User struct {
 	Id                  uuid.UUID      `json:"id"`
 	Name                string         `json:"name"`
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity links a user to an account at an external identity provider
+type UserIdentity struct {
+	Id          uuid.UUID  `json:"id"`
+	UserId      uuid.UUID  `json:"user_id"`
+	Provider    string     `json:"provider"`
+	Subject     string     `json:"subject"`
+	Email       string     `json:"email"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+}
+
+// Why an authorization request was started
+const (
+	OAuthPurposeLogin   = "login"
+	OAuthPurposeConnect = "connect"
+)
+
+// OAuthState is a pending authorization request; only the hash of the state is stored
+type OAuthState struct {
+	Id           uuid.UUID  `json:"id"`
+	StateHash    string     `json:"-"`
+	Provider     string     `json:"provider"`
+	Purpose      string     `json:"purpose"`
+	UserId       *uuid.UUID `json:"user_id"`
+	Nonce        string     `json:"-"`
+	CodeVerifier string     `json:"-"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
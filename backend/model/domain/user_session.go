@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserSession struct {
+	Id            uuid.UUID  `json:"id"`
+	UserId        uuid.UUID  `json:"user_id"`
+	Device        string     `json:"device"`
+	UserAgent     string     `json:"user_agent"`
+	IpAddress     string     `json:"ip_address"`
+	LastSeenAt    time.Time  `json:"last_seen_at"`
+	ExpiresAt     time.Time  `json:"expires_at"`
+	RevokedAt     *time.Time `json:"revoked_at"`
+	RevokedReason *string    `json:"revoked_reason"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// IsActive reports whether the session can still be used to authenticate
+func (session UserSession) IsActive() bool {
+	return session.RevokedAt == nil && time.Now().Before(session.ExpiresAt)
+}
+
+type UserRefreshToken struct {
+	Id        uuid.UUID  `json:"id"`
+	SessionId uuid.UUID  `json:"session_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,12 @@
+package web
+
+import "time"
+
+type DeleteAccountRequest struct {
+	Confirmation string `json:"confirmation" validate:"required,eq=DELETE"`
+}
+
+type AccountDeletionResponse struct {
+	Message      string    `json:"message"`
+	ScheduledFor time.Time `json:"scheduled_for"`
+}
@@ -1,6 +1,7 @@
 package web
 
 import (
+	"evoconnect/backend/model/domain"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,21 +12,60 @@ type AdminLoginRequest struct {
 	Password string `json:"password" validate:"required,min=6"`
 }
 
-type AdminRegisterRequest struct {
+type AdminInviteRequest struct {
+	Name  string `json:"name" validate:"required,min=2,max=100"`
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=super_admin moderator company_reviewer support"`
+}
+
+// AdminCreateRequest creates an active admin directly, used by the command line where no inbox is needed
+type AdminCreateRequest struct {
 	Name     string `json:"name" validate:"required,min=2,max=100"`
 	Email    string `json:"email" validate:"required,email"`
+	Role     string `json:"role" validate:"required,oneof=super_admin moderator company_reviewer support"`
+	Password string `json:"password" validate:"required"`
+}
+
+type AdminAcceptInvitationRequest struct {
+	Token    string `json:"token" validate:"required"`
 	Password string `json:"password" validate:"required,min=6"`
 }
 
+type AdminUpdateRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=super_admin moderator company_reviewer support"`
+}
+
+type AdminUpdateStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=active disabled"`
+}
+
+type AdminInvitationResponse struct {
+	Id        uuid.UUID  `json:"id"`
+	Email     string     `json:"email"`
+	Name      string     `json:"name"`
+	Role      string     `json:"role"`
+	InvitedBy *uuid.UUID `json:"invited_by"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
 type AdminResponse struct {
-	ID        uuid.UUID `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          uuid.UUID                `json:"id"`
+	Name        string                   `json:"name"`
+	Email       string                   `json:"email"`
+	Role        string                   `json:"role,omitempty"`
+	Status      string                   `json:"status,omitempty"`
+	Permissions []domain.AdminPermission `json:"permissions,omitempty"`
+	DisabledAt  *time.Time               `json:"disabled_at,omitempty"`
+	CreatedAt   time.Time                `json:"created_at"`
+	UpdatedAt   time.Time                `json:"updated_at"`
 }
 
 type AdminLoginResponse struct {
-	Token string        `json:"token"`
-	Admin AdminResponse `json:"admin"`
+	Token                 string         `json:"token,omitempty"`
+	MfaRequired           bool           `json:"mfa_required"`
+	MfaEnrollmentRequired bool           `json:"mfa_enrollment_required,omitempty"`
+	MfaToken              string         `json:"mfa_token,omitempty"`
+	RecoveryCodes         []string       `json:"recovery_codes,omitempty"`
+	Admin                 *AdminResponse `json:"admin,omitempty"`
 }
@@ -0,0 +1,27 @@
+package web
+
+import "time"
+
+type CreateAPITokenRequest struct {
+	Name          string   `json:"name" validate:"required,max=100"`
+	Scopes        []string `json:"scopes" validate:"required,min=1,dive,required"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"omitempty,min=1,max=365"`
+}
+
+type APITokenResponse struct {
+	Id          string     `json:"id"`
+	Name        string     `json:"name"`
+	TokenPrefix string     `json:"token_prefix"`
+	Scopes      []string   `json:"scopes"`
+	CompanyId   *string    `json:"company_id,omitempty"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIp  *string    `json:"last_used_ip,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// CreatedAPITokenResponse carries the token itself, it is only returned once
+type CreatedAPITokenResponse struct {
+	APITokenResponse
+	Token string `json:"token"`
+}
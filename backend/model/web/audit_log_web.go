@@ -0,0 +1,27 @@
+package web
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditLogResponse struct {
+	Id         uuid.UUID       `json:"id"`
+	ActorType  string          `json:"actor_type"`
+	ActorId    *uuid.UUID      `json:"actor_id"`
+	ActorEmail string          `json:"actor_email"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetId   string          `json:"target_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	IpAddress  string          `json:"ip_address"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type AuditLogListResponse struct {
+	AuditLogs  []AuditLogResponse `json:"audit_logs"`
+	Pagination PaginationResponse `json:"pagination"`
+}
@@ -1,6 +1,9 @@
 package web
 
-import "evoconnect/backend/model/domain"
+import (
+	"evoconnect/backend/model/domain"
+	"time"
+)
 
 type GoogleAuthRequest struct {
 	Token string `json:"token" validate:"required"`
@@ -17,7 +20,7 @@ type RegisterRequest struct {
 	Name     string `json:"name" validate:"required"`
 	Username string `json:"username" validate:"required"`
 	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=6"`
+	Password string `json:"password" validate:"required"`
 }
 
 // EmailRequest for verification email or password reset requests
@@ -25,6 +28,11 @@ type EmailRequest struct {
 	Email string `json:"email" validate:"required,email"`
 }
 
+// MagicLinkLoginRequest carries the token from a passwordless sign-in link
+type MagicLinkLoginRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
 // VerificationRequest for email verification
 type VerificationRequest struct {
 	Token string `json:"token" validate:"required"`
@@ -33,20 +41,38 @@ type VerificationRequest struct {
 // ResetPasswordRequest for password reset
 type ResetPasswordRequest struct {
 	Token    string `json:"token" validate:"required"`
-	Password string `json:"password" validate:"required,min=6"`
+	Password string `json:"password" validate:"required"`
+}
+
+// ChangePasswordRequest for logged in users, the new password is checked against the password policy
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required"`
 }
 
 // Response models
 // LoginResponse represents successful login response
 type LoginResponse struct {
-	Token string      `json:"token"`
-	User  domain.User `json:"user"`
+	Token                  string       `json:"token,omitempty"`
+	RefreshToken           string       `json:"refresh_token,omitempty"`
+	ExpiresAt              *time.Time   `json:"expires_at,omitempty"`
+	MfaRequired            bool         `json:"mfa_required"`
+	MfaToken               string       `json:"mfa_token,omitempty"`
+	DeletionCancelled      bool         `json:"deletion_cancelled,omitempty"`
+	PasswordChangeRequired bool         `json:"password_change_required,omitempty"`
+	User                   *domain.User `json:"user,omitempty"`
 }
 
 // RegisterResponse represents successful registration response
 type RegisterResponse struct {
-	Token string      `json:"token"`
-	User  domain.User `json:"user"`
+	Token                  string       `json:"token,omitempty"`
+	RefreshToken           string       `json:"refresh_token,omitempty"`
+	ExpiresAt              *time.Time   `json:"expires_at,omitempty"`
+	MfaRequired            bool         `json:"mfa_required"`
+	MfaToken               string       `json:"mfa_token,omitempty"`
+	DeletionCancelled      bool         `json:"deletion_cancelled,omitempty"`
+	PasswordChangeRequired bool         `json:"password_change_required,omitempty"`
+	User                   *domain.User `json:"user,omitempty"`
 }
 
 // MessageResponse for simple message responses
@@ -12,6 +12,7 @@ type BlogUserResponse struct {
 	Name     string `json:"name"`
 	Username string `json:"username"`
 	Photo    string `json:"photo"`
+	PhotoVariants *ImageVariants `json:"photo_variants,omitempty"`
 	IsConnected bool `json:"is_connected"`
 }
 
@@ -22,6 +23,7 @@ type BlogResponse struct {
 	Category  string           `json:"category"`
 	Content   string           `json:"content"`
 	Photo     string           `json:"photo"`
+	PhotoVariants *ImageVariants `json:"photo_variants,omitempty"`
 	UserID    string           `json:"user_id"`
 	Warning   string    		`json:"warning,omitempty"`
 	CreatedAt string           `json:"created_at"`
@@ -28,9 +28,10 @@ type CompanyFollowerResponse struct {
 }
 
 type CompanyBasicInfo struct {
-	Id   string `json:"id"`
-	Name string `json:"name"`
-	Logo string `json:"logo"`
+	Id           string         `json:"id"`
+	Name         string         `json:"name"`
+	Logo         string         `json:"logo"`
+	LogoVariants *ImageVariants `json:"logo_variants,omitempty"`
 }
 
 type CompanyFollowersListResponse struct {
@@ -36,9 +36,10 @@ type CompanyJoinRequestResponse struct {
 }
 
 type CompanyBriefResponse struct {
-	Id       uuid.UUID `json:"id"`
-	Name     string    `json:"name"`
-	Logo     *string   `json:"logo"`
-	Industry string    `json:"industry"`
-	Website  *string   `json:"website"`
+	Id           uuid.UUID      `json:"id"`
+	Name         string         `json:"name"`
+	Logo         *string        `json:"logo"`
+	LogoVariants *ImageVariants `json:"logo_variants,omitempty"`
+	Industry     string         `json:"industry"`
+	Website      *string        `json:"website"`
 }
@@ -36,6 +36,7 @@ type CompanyPostResponse struct {
 	CreatorId      uuid.UUID                 `json:"creator_id"`
 	Content        string                    `json:"content"`
 	Images         []string                  `json:"images"`
+	ImageVariants  []ImageVariants           `json:"image_variants"`
 	Visibility     string                    `json:"visibility"`
 	IsAnnouncement bool                      `json:"is_announcement"`
 	CreatedAt      time.Time                 `json:"created_at"`
@@ -31,6 +31,7 @@ type CompanySubmissionResponse struct {
 	Size            string             `json:"size"`
 	Type            string             `json:"type"`
 	Logo            string             `json:"logo"`
+	LogoVariants    *ImageVariants     `json:"logo_variants,omitempty"`
 	Tagline         string             `json:"tagline"`
 	Status          string             `json:"status"`
 	RejectionReason string             `json:"rejection_reason,omitempty"`
@@ -43,20 +44,21 @@ type CompanySubmissionResponse struct {
 }
 
 type CompanyResponse struct {
-	ID          uuid.UUID          `json:"id"`
-	OwnerId     uuid.UUID          `json:"owner_id"`
-	Name        string             `json:"name"`
-	LinkedinUrl string             `json:"linkedin_url"`
-	Website     string             `json:"website"`
-	Industry    string             `json:"industry"`
-	Size        string             `json:"size"`
-	Type        string             `json:"type"`
-	Logo        string             `json:"logo"`
-	Location    string             `json:"location"`
-	Tagline     string             `json:"tagline"`
-	Description string             `json:"description"`
-	IsVerified  bool               `json:"is_verified"`
-	CreatedAt   time.Time          `json:"created_at"`
-	UpdatedAt   time.Time          `json:"updated_at"`
-	Owner       *UserBriefResponse `json:"owner,omitempty"`
+	ID           uuid.UUID          `json:"id"`
+	OwnerId      uuid.UUID          `json:"owner_id"`
+	Name         string             `json:"name"`
+	LinkedinUrl  string             `json:"linkedin_url"`
+	Website      string             `json:"website"`
+	Industry     string             `json:"industry"`
+	Size         string             `json:"size"`
+	Type         string             `json:"type"`
+	Logo         string             `json:"logo"`
+	LogoVariants *ImageVariants     `json:"logo_variants,omitempty"`
+	Location     string             `json:"location"`
+	Tagline      string             `json:"tagline"`
+	Description  string             `json:"description"`
+	IsVerified   bool               `json:"is_verified"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+	Owner        *UserBriefResponse `json:"owner,omitempty"`
 }
@@ -25,6 +25,7 @@ type CompanyManagementResponse struct {
 	Size        string    `json:"size"`
 	Type        string    `json:"type"`
 	Logo        string    `json:"logo"`
+	LogoVariants *ImageVariants `json:"logo_variants,omitempty"`
 	Tagline     string    `json:"tagline"`
 	Location    string    `json:"location"`
 	IsVerified  bool      `json:"is_verified"`
@@ -57,6 +58,7 @@ type CompanyPublicResponse struct {
 	Size        string    `json:"size"`
 	Type        string    `json:"type"`
 	Logo        string    `json:"logo"`
+	LogoVariants *ImageVariants `json:"logo_variants,omitempty"`
 	Tagline     string    `json:"tagline"`
 	IsVerified  bool      `json:"is_verified"`
 	CreatedAt   time.Time `json:"created_at"`
@@ -96,6 +98,7 @@ type CompanyDetailResponse struct {
 	Size        string    `json:"size"`
 	Type        string    `json:"type"`
 	Logo        string    `json:"logo"`
+	LogoVariants *ImageVariants `json:"logo_variants,omitempty"`
 	Tagline     string    `json:"tagline"`
 	Location    string    `json:"location"`
 	IsVerified  bool      `json:"is_verified"`
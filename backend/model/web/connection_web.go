@@ -43,13 +43,14 @@ type ConnectionListResponse struct {
 
 // UserShort represents a shortened user response with basic info
 type UserShort struct {
-    Id                 uuid.UUID `json:"id"`
-    Name               string    `json:"name"`
-    Username           string    `json:"username"`
-    Photo              *string   `json:"photo"`
-    Headline           *string   `json:"headline,omitempty"`
-    IsConnected        bool      `json:"is_connected"`
-    IsConnectedRequest string    `json:"is_connected_request,omitempty"` // Pastikan tag JSON benar
+    Id                 uuid.UUID      `json:"id"`
+    Name               string         `json:"name"`
+    Username           string         `json:"username"`
+    Photo              *string        `json:"photo"`
+    PhotoVariants      *ImageVariants `json:"photo_variants,omitempty"`
+    Headline           *string        `json:"headline,omitempty"`
+    IsConnected        bool           `json:"is_connected"`
+    IsConnectedRequest string         `json:"is_connected_request,omitempty"` // Pastikan tag JSON benar
 }
 
 type DisconnectResponse struct {
@@ -0,0 +1,14 @@
+package web
+
+import "time"
+
+type DataExportResponse struct {
+	Id           string     `json:"id"`
+	Status       string     `json:"status"`
+	FileSize     *int64     `json:"file_size,omitempty"`
+	ErrorMessage *string    `json:"error_message,omitempty"`
+	DownloadUrl  string     `json:"download_url,omitempty"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
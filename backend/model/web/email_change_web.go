@@ -0,0 +1,19 @@
+package web
+
+import "time"
+
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" validate:"required,email,max=255"`
+	Password string `json:"password" validate:"required"`
+}
+
+// EmailChangeTokenRequest carries the token from a confirm or cancel link
+type EmailChangeTokenRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type EmailChangeResponse struct {
+	Message      string    `json:"message"`
+	PendingEmail string    `json:"pending_email"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
@@ -0,0 +1,9 @@
+package web
+
+import "time"
+
+// SignedFileResponse is a short-lived link to a private file, relative to the API base URL
+type SignedFileResponse struct {
+	Url       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
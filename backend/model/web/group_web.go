@@ -31,6 +31,7 @@ type GroupBrief struct {
 	Name         string    `json:"name"`
 	Description  string    `json:"description"`
 	Image        *string   `json:"image"`
+	ImageVariants *ImageVariants `json:"image_variants,omitempty"`
 	PrivacyLevel string    `json:"privacy_level"`
 	MembersCount int       `json:"members_count"`
 }
@@ -42,6 +43,7 @@ type GroupResponse struct {
 	Description  string             `json:"description"`
 	Rule         string             `json:"rule"`
 	Image        *string            `json:"image"`
+	ImageVariants *ImageVariants    `json:"image_variants,omitempty"`
 	PrivacyLevel string             `json:"privacy_level"`
 	InvitePolicy string             `json:"invite_policy"`
 	PostApproval bool               `json:"post_approval"` // Tambahkan field ini
@@ -90,6 +92,7 @@ type GroupBriefResponse struct {
 	Name         string    `json:"name"`
 	Description  string    `json:"description"`
 	Image        *string   `json:"image"`
+	ImageVariants *ImageVariants `json:"image_variants,omitempty"`
 	PrivacyLevel string    `json:"privacy_level"`
 	MembersCount int       `json:"members_count"`
 }
@@ -0,0 +1,5 @@
+package web
+
+type HealthResponse struct {
+	Status string `json:"status"`
+}
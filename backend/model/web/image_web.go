@@ -0,0 +1,9 @@
+package web
+
+// ImageVariants are the sizes an uploaded image is stored in, so clients can pick the
+// smallest one that fits. Images uploaded before variants existed use one path for all.
+type ImageVariants struct {
+	Thumbnail string `json:"thumbnail"`
+	Medium    string `json:"medium"`
+	Full      string `json:"full"`
+}
@@ -110,6 +110,8 @@ type UserCvStorageResponse struct {
 	FileSize         int64     `json:"file_size"`
 	UploadedAt       time.Time `json:"uploaded_at"`
 	UpdatedAt        time.Time `json:"updated_at"`
+	// pending or scanning while the file is checked for malware, it can only be downloaded once clean
+	ScanStatus string `json:"scan_status"`
 }
 
 type UploadCvRequest struct {
@@ -117,8 +119,9 @@ type UploadCvRequest struct {
 }
 
 type UploadCvResponse struct {
-	Message  string `json:"message"`
-	CvPath   string `json:"cv_path"`
-	Filename string `json:"filename"`
-	FileSize int64  `json:"file_size"`
+	Message    string `json:"message"`
+	CvPath     string `json:"cv_path"`
+	Filename   string `json:"filename"`
+	FileSize   int64  `json:"file_size"`
+	ScanStatus string `json:"scan_status"`
 }
@@ -78,11 +78,12 @@ type JobVacancyResponse struct {
 }
 
 type CompanyBasicResponse struct {
-	Id       string  `json:"id"`
-	Name     string  `json:"name"`
-	Logo     *string `json:"logo"`
-	Industry string  `json:"industry"`
-	Location string  `json:"location"`
+	Id           string         `json:"id"`
+	Name         string         `json:"name"`
+	Logo         *string        `json:"logo"`
+	LogoVariants *ImageVariants `json:"logo_variants,omitempty"`
+	Industry     string         `json:"industry"`
+	Location     string         `json:"location"`
 }
 
 type UserBasicResponse struct {
@@ -0,0 +1,29 @@
+package web
+
+import "time"
+
+type OAuthProviderResponse struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+// OAuthAuthorizeResponse tells the frontend where to send the browser. The state comes back
+// on the redirect and has to be posted to the callback together with the code.
+type OAuthAuthorizeResponse struct {
+	AuthorizationUrl string    `json:"authorization_url"`
+	State            string    `json:"state"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+type OAuthCallbackRequest struct {
+	Code  string `json:"code" validate:"required"`
+	State string `json:"state" validate:"required"`
+}
+
+type UserIdentityResponse struct {
+	Provider    string     `json:"provider"`
+	DisplayName string     `json:"display_name"`
+	Email       string     `json:"email,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
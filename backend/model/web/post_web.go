@@ -23,23 +23,24 @@ type UpdatePostRequest struct {
 
 // Response models
 type PostResponse struct {
-	Id            uuid.UUID      `json:"id"`
-	UserId        uuid.UUID      `json:"user_id"`
-	Content       string         `json:"content"`
-	Images        []string       `json:"images"`
-	LikesCount    int            `json:"likes_count"`
-	Visibility    string         `json:"visibility"`
-	IsLiked       bool           `json:"is_liked"`
-	CommentsCount int            `json:"comments_count"`
-	User          UserShort      `json:"user"`
-	GroupId       *uuid.UUID     `json:"group_id,omitempty"`
-	Group         *GroupResponse `json:"group,omitempty"`
-	Status        string         `json:"status"`
-	CreatedAt     time.Time      `json:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at"`
-	IsPinned      bool           `json:"is_pinned"`
-	PinnedAt      *time.Time     `json:"pinned_at,omitempty"`
-	IsReported    bool           `json:"is_reported"`
+	Id            uuid.UUID       `json:"id"`
+	UserId        uuid.UUID       `json:"user_id"`
+	Content       string          `json:"content"`
+	Images        []string        `json:"images"`
+	ImageVariants []ImageVariants `json:"image_variants"`
+	LikesCount    int             `json:"likes_count"`
+	Visibility    string          `json:"visibility"`
+	IsLiked       bool            `json:"is_liked"`
+	CommentsCount int             `json:"comments_count"`
+	User          UserShort       `json:"user"`
+	GroupId       *uuid.UUID      `json:"group_id,omitempty"`
+	Group         *GroupResponse  `json:"group,omitempty"`
+	Status        string          `json:"status"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+	IsPinned      bool            `json:"is_pinned"`
+	PinnedAt      *time.Time      `json:"pinned_at,omitempty"`
+	IsReported    bool            `json:"is_reported"`
 }
 
 // Add this struct to the file
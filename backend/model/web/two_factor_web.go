@@ -0,0 +1,43 @@
+package web
+
+import "time"
+
+// TwoFactorCodeRequest carries a TOTP code or a recovery code
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// TwoFactorLoginRequest is the second login step
+type TwoFactorLoginRequest struct {
+	MfaToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+// TwoFactorEnrollRequest starts mandatory enrollment during admin login
+type TwoFactorEnrollRequest struct {
+	MfaToken string `json:"mfa_token" validate:"required"`
+}
+
+type TwoFactorPolicyRequest struct {
+	AdminTwoFactorRequired *bool `json:"admin_two_factor_required" validate:"required"`
+}
+
+type TwoFactorStatusResponse struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at"`
+	RecoveryCodesRemaining int        `json:"recovery_codes_remaining"`
+}
+
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// TwoFactorRecoveryCodesResponse is the only time recovery codes are shown in plain text
+type TwoFactorRecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type TwoFactorPolicyResponse struct {
+	AdminTwoFactorRequired bool `json:"admin_two_factor_required"`
+}
@@ -0,0 +1,29 @@
+package web
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshTokenRequest is used by the refresh and logout endpoints
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// TokenResponse holds a new access token and its rotating refresh token
+type TokenResponse struct {
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type UserSessionResponse struct {
+	Id         uuid.UUID `json:"id"`
+	Device     string    `json:"device"`
+	IpAddress  string    `json:"ip_address"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	IsCurrent  bool      `json:"is_current"`
+}
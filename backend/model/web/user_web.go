@@ -45,6 +45,7 @@ type UserProfileResponse struct {
     Skills            interface{} `json:"skills"`
     Socials           interface{} `json:"socials"`
     Photo             string      `json:"photo"`
+    PhotoVariants     *ImageVariants `json:"photo_variants,omitempty"`
     IsVerified        bool        `json:"is_verified"`
     CreatedAt         string      `json:"created_at"`
     UpdatedAt         string      `json:"updated_at"`
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountPurgeRepository holds the queries used to erase accounts once their deletion grace period is over
+type AccountPurgeRepository interface {
+	FindDueUserIds(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]uuid.UUID, error)
+	LockDueUser(ctx context.Context, tx *sql.Tx, userId uuid.UUID, now time.Time) (bool, error)
+	FindOwnedGroupIds(ctx context.Context, tx *sql.Tx, userId uuid.UUID) ([]uuid.UUID, error)
+	FindGroupSuccessor(ctx context.Context, tx *sql.Tx, groupId uuid.UUID, userId uuid.UUID) (*uuid.UUID, error)
+	TransferGroup(ctx context.Context, tx *sql.Tx, groupId uuid.UUID, newOwnerId uuid.UUID) error
+	DeleteGroup(ctx context.Context, tx *sql.Tx, groupId uuid.UUID) error
+	FindOwnedCompanyIds(ctx context.Context, tx *sql.Tx, userId uuid.UUID) ([]uuid.UUID, error)
+	FindCompanySuccessor(ctx context.Context, tx *sql.Tx, companyId uuid.UUID, userId uuid.UUID) (*uuid.UUID, error)
+	TransferCompany(ctx context.Context, tx *sql.Tx, companyId uuid.UUID, newOwnerId uuid.UUID) error
+	CloseCompany(ctx context.Context, tx *sql.Tx, companyId uuid.UUID) error
+	DeletePersonalData(ctx context.Context, tx *sql.Tx, userId uuid.UUID) error
+	AnonymizeUser(ctx context.Context, tx *sql.Tx, userId uuid.UUID, email string, username string, passwordHash string) error
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/model/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AccountPurgeRepositoryImpl struct{}
+
+func NewAccountPurgeRepository() AccountPurgeRepository {
+	return &AccountPurgeRepositoryImpl{}
+}
+
+func (repository *AccountPurgeRepositoryImpl) FindDueUserIds(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]uuid.UUID, error) {
+	SQL := `SELECT id FROM users
+			WHERE deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= $1 AND deleted_at IS NULL
+			ORDER BY deletion_scheduled_at ASC
+			LIMIT $2`
+	return queryIds(ctx, tx, SQL, now, limit)
+}
+
+// LockDueUser locks the user row for the purge and re-checks the schedule, a login may have cancelled it meanwhile
+func (repository *AccountPurgeRepositoryImpl) LockDueUser(ctx context.Context, tx *sql.Tx, userId uuid.UUID, now time.Time) (bool, error) {
+	SQL := `SELECT id FROM users
+			WHERE id = $1 AND deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= $2 AND deleted_at IS NULL
+			FOR UPDATE SKIP LOCKED`
+
+	var id uuid.UUID
+	err := tx.QueryRowContext(ctx, SQL, userId, now).Scan(&id)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (repository *AccountPurgeRepositoryImpl) FindOwnedGroupIds(ctx context.Context, tx *sql.Tx, userId uuid.UUID) ([]uuid.UUID, error) {
+	return queryIds(ctx, tx, "SELECT id FROM groups WHERE creator_id = $1", userId)
+}
+
+// FindGroupSuccessor picks the longest-standing admin, then moderator, then member
+func (repository *AccountPurgeRepositoryImpl) FindGroupSuccessor(ctx context.Context, tx *sql.Tx, groupId uuid.UUID, userId uuid.UUID) (*uuid.UUID, error) {
+	SQL := `SELECT user_id FROM group_members
+			WHERE group_id = $1 AND user_id != $2 AND is_active = true
+			ORDER BY CASE role WHEN 'admin' THEN 0 WHEN 'moderator' THEN 1 ELSE 2 END, joined_at ASC
+			LIMIT 1`
+	return querySuccessor(ctx, tx, SQL, groupId, userId)
+}
+
+func (repository *AccountPurgeRepositoryImpl) TransferGroup(ctx context.Context, tx *sql.Tx, groupId uuid.UUID, newOwnerId uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, "UPDATE groups SET creator_id = $1, updated_at = $2 WHERE id = $3", newOwnerId, time.Now(), groupId)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE group_members SET role = 'admin' WHERE group_id = $1 AND user_id = $2", groupId, newOwnerId)
+	return err
+}
+
+// DeleteGroup closes a group nobody can take over, removing the same data as a group ban
+func (repository *AccountPurgeRepositoryImpl) DeleteGroup(ctx context.Context, tx *sql.Tx, groupId uuid.UUID) error {
+	queries := []string{
+		"DELETE FROM posts WHERE group_id = $1",
+		"DELETE FROM pending_posts WHERE group_id = $1",
+		"DELETE FROM group_join_requests WHERE group_id = $1",
+		"DELETE FROM group_invitations WHERE group_id = $1",
+		"DELETE FROM group_members WHERE group_id = $1",
+		"DELETE FROM groups WHERE id = $1",
+	}
+
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, groupId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (repository *AccountPurgeRepositoryImpl) FindOwnedCompanyIds(ctx context.Context, tx *sql.Tx, userId uuid.UUID) ([]uuid.UUID, error) {
+	return queryIds(ctx, tx, "SELECT id FROM companies WHERE owner_id = $1 AND taken_down_at IS NULL", userId)
+}
+
+// FindCompanySuccessor only hands a company to another active super admin or admin
+func (repository *AccountPurgeRepositoryImpl) FindCompanySuccessor(ctx context.Context, tx *sql.Tx, companyId uuid.UUID, userId uuid.UUID) (*uuid.UUID, error) {
+	SQL := `SELECT user_id FROM member_company
+			WHERE company_id = $1 AND user_id != $2 AND status = 'active' AND role IN ('super_admin', 'admin')
+			ORDER BY role ASC, joined_at ASC
+			LIMIT 1`
+	return querySuccessor(ctx, tx, SQL, companyId, userId)
+}
+
+func (repository *AccountPurgeRepositoryImpl) TransferCompany(ctx context.Context, tx *sql.Tx, companyId uuid.UUID, newOwnerId uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, "UPDATE companies SET owner_id = $1 WHERE id = $2", newOwnerId, companyId)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE member_company SET role = 'super_admin' WHERE company_id = $1 AND user_id = $2", companyId, newOwnerId)
+	return err
+}
+
+// CloseCompany takes the company down the same way moderation does
+func (repository *AccountPurgeRepositoryImpl) CloseCompany(ctx context.Context, tx *sql.Tx, companyId uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, "UPDATE companies SET is_verified = false, taken_down_at = $1 WHERE id = $2", time.Now(), companyId)
+	return err
+}
+
+// DeletePersonalData removes the profile data and relations of the user. Posts, blogs and
+// chat messages stay and show the anonymized name.
+func (repository *AccountPurgeRepositoryImpl) DeletePersonalData(ctx context.Context, tx *sql.Tx, userId uuid.UUID) error {
+	queries := []string{
+		"DELETE FROM user_sessions WHERE user_id = $1",
+		"DELETE FROM two_factor_credentials WHERE owner_type = 'user' AND owner_id = $1",
+		"DELETE FROM login_lockouts WHERE owner_type = 'user' AND owner_id = $1",
+		"DELETE FROM user_identities WHERE user_id = $1",
+		"DELETE FROM api_tokens WHERE user_id = $1",
+		"DELETE FROM oauth_states WHERE user_id = $1",
+		"DELETE FROM connections WHERE user_id_1 = $1 OR user_id_2 = $1",
+		"DELETE FROM connection_requests WHERE sender_id = $1 OR receiver_id = $1",
+		"DELETE FROM profile_views WHERE profile_user_id = $1 OR viewer_id = $1",
+		"DELETE FROM user_education WHERE user_id = $1",
+		"DELETE FROM user_experiences WHERE user_id = $1",
+		"DELETE FROM user_cv_storage WHERE user_id = $1",
+		"DELETE FROM saved_jobs WHERE user_id = $1",
+		"DELETE FROM company_followers WHERE user_id = $1",
+		"DELETE FROM company_join_requests WHERE user_id = $1",
+		"DELETE FROM member_company WHERE user_id = $1",
+		"DELETE FROM group_join_requests WHERE user_id = $1",
+		"DELETE FROM group_invitations WHERE inviter_id = $1 OR invitee_id = $1",
+		"DELETE FROM group_members WHERE user_id = $1",
+		"DELETE FROM notifications WHERE user_id = $1",
+		"DELETE FROM data_exports WHERE user_id = $1",
+	}
+
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query, userId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AnonymizeUser blanks text columns instead of nulling them, several listings scan them as plain strings
+func (repository *AccountPurgeRepositoryImpl) AnonymizeUser(ctx context.Context, tx *sql.Tx, userId uuid.UUID, email string, username string, passwordHash string) error {
+	SQL := `UPDATE users SET
+			name = $1, email = $2, username = $3, password = $4,
+			birthdate = NULL, gender = '', location = '', organization = '', website = '',
+			phone = '', headline = '', about = '', skills = NULL, socials = NULL,
+			photo = '', cover_image = '', is_verified = false,
+			verification_token = NULL, verification_expires = NULL, reset_token = NULL, reset_expires = NULL,
+			pending_email = NULL, email_change_cancel_token = NULL, magic_link_token = NULL, magic_link_expires = NULL,
+			status = $5, suspended_until = NULL, deletion_scheduled_at = NULL,
+			deleted_at = $6, updated_at = $6
+			WHERE id = $7`
+
+	_, err := tx.ExecContext(ctx, SQL, domain.DeletedUserName, email, username, passwordHash, domain.UserStatusDeleted, time.Now(), userId)
+	return err
+}
+
+func queryIds(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]uuid.UUID, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func querySuccessor(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (*uuid.UUID, error) {
+	var successorId uuid.UUID
+	err := tx.QueryRowContext(ctx, query, args...).Scan(&successorId)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &successorId, nil
+}
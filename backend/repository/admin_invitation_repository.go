@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/model/domain"
+
+	"github.com/google/uuid"
+)
+
+type AdminInvitationRepository interface {
+	Save(ctx context.Context, tx *sql.Tx, invitation domain.AdminInvitation) (domain.AdminInvitation, error)
+	FindByTokenHash(ctx context.Context, tx *sql.Tx, tokenHash string) (domain.AdminInvitation, error)
+	FindPending(ctx context.Context, tx *sql.Tx) ([]domain.AdminInvitation, error)
+	MarkAccepted(ctx context.Context, tx *sql.Tx, invitationId uuid.UUID) error
+	DeletePendingByEmail(ctx context.Context, tx *sql.Tx, email string) error
+}
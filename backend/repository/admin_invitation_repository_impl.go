@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"evoconnect/backend/model/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AdminInvitationRepositoryImpl struct{}
+
+func NewAdminInvitationRepository() AdminInvitationRepository {
+	return &AdminInvitationRepositoryImpl{}
+}
+
+func (repository *AdminInvitationRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, invitation domain.AdminInvitation) (domain.AdminInvitation, error) {
+	SQL := `INSERT INTO admin_invitations (email, name, role, token_hash, invited_by, expires_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+
+	err := tx.QueryRowContext(ctx, SQL, invitation.Email, invitation.Name, invitation.Role, invitation.TokenHash,
+		invitation.InvitedBy, invitation.ExpiresAt, invitation.CreatedAt).Scan(&invitation.Id)
+	if err != nil {
+		return domain.AdminInvitation{}, err
+	}
+
+	return invitation, nil
+}
+
+func (repository *AdminInvitationRepositoryImpl) FindByTokenHash(ctx context.Context, tx *sql.Tx, tokenHash string) (domain.AdminInvitation, error) {
+	SQL := `SELECT id, email, name, role, token_hash, invited_by, expires_at, accepted_at, created_at
+			FROM admin_invitations WHERE token_hash = $1`
+
+	invitation := domain.AdminInvitation{}
+	err := tx.QueryRowContext(ctx, SQL, tokenHash).Scan(&invitation.Id, &invitation.Email, &invitation.Name, &invitation.Role,
+		&invitation.TokenHash, &invitation.InvitedBy, &invitation.ExpiresAt, &invitation.AcceptedAt, &invitation.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return invitation, errors.New("invitation not found")
+		}
+		return invitation, err
+	}
+
+	return invitation, nil
+}
+
+func (repository *AdminInvitationRepositoryImpl) FindPending(ctx context.Context, tx *sql.Tx) ([]domain.AdminInvitation, error) {
+	SQL := `SELECT id, email, name, role, token_hash, invited_by, expires_at, accepted_at, created_at
+			FROM admin_invitations
+			WHERE accepted_at IS NULL AND expires_at > $1
+			ORDER BY created_at DESC`
+
+	rows, err := tx.QueryContext(ctx, SQL, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invitations []domain.AdminInvitation
+	for rows.Next() {
+		invitation := domain.AdminInvitation{}
+		err := rows.Scan(&invitation.Id, &invitation.Email, &invitation.Name, &invitation.Role,
+			&invitation.TokenHash, &invitation.InvitedBy, &invitation.ExpiresAt, &invitation.AcceptedAt, &invitation.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	return invitations, rows.Err()
+}
+
+func (repository *AdminInvitationRepositoryImpl) MarkAccepted(ctx context.Context, tx *sql.Tx, invitationId uuid.UUID) error {
+	SQL := "UPDATE admin_invitations SET accepted_at = $1 WHERE id = $2 AND accepted_at IS NULL"
+
+	result, err := tx.ExecContext(ctx, SQL, time.Now(), invitationId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("invitation already accepted")
+	}
+
+	return nil
+}
+
+// DeletePendingByEmail drops earlier unaccepted invitations so only the newest link works
+func (repository *AdminInvitationRepositoryImpl) DeletePendingByEmail(ctx context.Context, tx *sql.Tx, email string) error {
+	SQL := "DELETE FROM admin_invitations WHERE email = $1 AND accepted_at IS NULL"
+	_, err := tx.ExecContext(ctx, SQL, email)
+	return err
+}
@@ -11,6 +11,9 @@ type AdminRepository interface {
 	Create(ctx context.Context, tx *sql.Tx, admin domain.Admin) domain.Admin
 	FindById(ctx context.Context, tx *sql.Tx, id uuid.UUID) (domain.Admin, error)
 	FindByEmail(ctx context.Context, tx *sql.Tx, email string) (domain.Admin, error)
+	FindAll(ctx context.Context, tx *sql.Tx) []domain.Admin
+	CountActiveByRole(ctx context.Context, tx *sql.Tx, role domain.AdminRole) int
+	LockActiveByRole(ctx context.Context, tx *sql.Tx, role domain.AdminRole) []uuid.UUID
 	Update(ctx context.Context, tx *sql.Tx, admin domain.Admin) domain.Admin
 	Delete(ctx context.Context, tx *sql.Tx, id uuid.UUID)
 }
@@ -17,16 +17,16 @@ func NewAdminRepository() AdminRepository {
 }
 
 func (repository *AdminRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, admin domain.Admin) domain.Admin {
-	SQL := `INSERT INTO admins (id, email, password, name, created_at, updated_at) 
-            VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err := tx.ExecContext(ctx, SQL, admin.Id, admin.Email, admin.Password, admin.Name, admin.CreatedAt, admin.UpdatedAt)
+	SQL := `INSERT INTO admins (id, email, password, name, role, status, created_at, updated_at) 
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := tx.ExecContext(ctx, SQL, admin.Id, admin.Email, admin.Password, admin.Name, admin.Role, admin.Status, admin.CreatedAt, admin.UpdatedAt)
 	helper.PanicIfError(err)
 
 	return admin
 }
 
 func (repository *AdminRepositoryImpl) FindById(ctx context.Context, tx *sql.Tx, id uuid.UUID) (domain.Admin, error) {
-	SQL := `SELECT id, name, email, password, created_at, updated_at 
+	SQL := `SELECT id, name, email, password, role, status, disabled_at, created_at, updated_at 
             FROM admins 
             WHERE id = $1`
 
@@ -34,18 +34,15 @@ func (repository *AdminRepositoryImpl) FindById(ctx context.Context, tx *sql.Tx,
 	helper.PanicIfError(err)
 	defer rows.Close()
 
-	admin := domain.Admin{}
 	if rows.Next() {
-		err := rows.Scan(&admin.Id, &admin.Name, &admin.Email, &admin.Password, &admin.CreatedAt, &admin.UpdatedAt)
-		helper.PanicIfError(err)
-		return admin, nil
+		return scanAdmin(rows), nil
 	} else {
-		return admin, fmt.Errorf("admin not found")
+		return domain.Admin{}, fmt.Errorf("admin not found")
 	}
 }
 
 func (repository *AdminRepositoryImpl) FindByEmail(ctx context.Context, tx *sql.Tx, email string) (domain.Admin, error) {
-	SQL := `SELECT id, name, email, password, created_at, updated_at 
+	SQL := `SELECT id, name, email, password, role, status, disabled_at, created_at, updated_at 
             FROM admins 
             WHERE email = $1`
 
@@ -53,22 +50,67 @@ func (repository *AdminRepositoryImpl) FindByEmail(ctx context.Context, tx *sql.
 	helper.PanicIfError(err)
 	defer rows.Close()
 
-	admin := domain.Admin{}
 	if rows.Next() {
-		err := rows.Scan(&admin.Id, &admin.Name, &admin.Email, &admin.Password, &admin.CreatedAt, &admin.UpdatedAt)
-		helper.PanicIfError(err)
-		return admin, nil
+		return scanAdmin(rows), nil
 	} else {
-		return admin, fmt.Errorf("admin not found")
+		return domain.Admin{}, fmt.Errorf("admin not found")
+	}
+}
+
+func (repository *AdminRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx) []domain.Admin {
+	SQL := `SELECT id, name, email, password, role, status, disabled_at, created_at, updated_at 
+            FROM admins 
+            ORDER BY created_at ASC`
+
+	rows, err := tx.QueryContext(ctx, SQL)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	var admins []domain.Admin
+	for rows.Next() {
+		admins = append(admins, scanAdmin(rows))
+	}
+
+	return admins
+}
+
+func (repository *AdminRepositoryImpl) CountActiveByRole(ctx context.Context, tx *sql.Tx, role domain.AdminRole) int {
+	SQL := `SELECT COUNT(*) FROM admins WHERE role = $1 AND status = $2`
+
+	var count int
+	err := tx.QueryRowContext(ctx, SQL, role, domain.AdminStatusActive).Scan(&count)
+	helper.PanicIfError(err)
+
+	return count
+}
+
+// LockActiveByRole locks the active admins of a role until tx ends, so concurrent changes
+// to them are checked one after the other
+func (repository *AdminRepositoryImpl) LockActiveByRole(ctx context.Context, tx *sql.Tx, role domain.AdminRole) []uuid.UUID {
+	SQL := `SELECT id FROM admins WHERE role = $1 AND status = $2 ORDER BY id FOR UPDATE`
+
+	rows, err := tx.QueryContext(ctx, SQL, role, domain.AdminStatusActive)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		err := rows.Scan(&id)
+		helper.PanicIfError(err)
+		ids = append(ids, id)
 	}
+	helper.PanicIfError(rows.Err())
+
+	return ids
 }
 
 func (repository *AdminRepositoryImpl) Update(ctx context.Context, tx *sql.Tx, admin domain.Admin) domain.Admin {
 	SQL := `UPDATE admins 
-            SET name = $1, email = $2, password = $3, updated_at = $4 
-            WHERE id = $5`
+            SET name = $1, email = $2, password = $3, role = $4, status = $5, disabled_at = $6, updated_at = $7 
+            WHERE id = $8`
 
-	_, err := tx.ExecContext(ctx, SQL, admin.Name, admin.Email, admin.Password, admin.UpdatedAt, admin.Id)
+	_, err := tx.ExecContext(ctx, SQL, admin.Name, admin.Email, admin.Password, admin.Role, admin.Status, admin.DisabledAt, admin.UpdatedAt, admin.Id)
 	helper.PanicIfError(err)
 
 	return admin
@@ -80,3 +122,10 @@ func (repository *AdminRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, i
 	_, err := tx.ExecContext(ctx, SQL, id)
 	helper.PanicIfError(err)
 }
+
+func scanAdmin(rows *sql.Rows) domain.Admin {
+	admin := domain.Admin{}
+	err := rows.Scan(&admin.Id, &admin.Name, &admin.Email, &admin.Password, &admin.Role, &admin.Status, &admin.DisabledAt, &admin.CreatedAt, &admin.UpdatedAt)
+	helper.PanicIfError(err)
+	return admin
+}
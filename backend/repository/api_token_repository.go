@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/model/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type APITokenRepository interface {
+	Save(ctx context.Context, tx *sql.Tx, token domain.APIToken) (domain.APIToken, error)
+	FindByHash(ctx context.Context, tx *sql.Tx, tokenHash string) (domain.APIToken, error)
+	FindById(ctx context.Context, tx *sql.Tx, tokenId uuid.UUID) (domain.APIToken, error)
+	FindByUserId(ctx context.Context, tx *sql.Tx, userId uuid.UUID) ([]domain.APIToken, error)
+	FindByCompanyId(ctx context.Context, tx *sql.Tx, companyId uuid.UUID) ([]domain.APIToken, error)
+	Revoke(ctx context.Context, tx *sql.Tx, tokenId uuid.UUID) error
+	RevokePersonalByUserId(ctx context.Context, tx *sql.Tx, userId uuid.UUID) error
+	TouchLastUsed(ctx context.Context, tx *sql.Tx, tokenId uuid.UUID, ipAddress string, olderThan time.Time) error
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"evoconnect/backend/model/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type APITokenRepositoryImpl struct{}
+
+func NewAPITokenRepository() APITokenRepository {
+	return &APITokenRepositoryImpl{}
+}
+
+const apiTokenColumns = `id, user_id, company_id, name, token_prefix, token_hash, scopes, expires_at,
+			last_used_at, last_used_ip, revoked_at, created_at`
+
+func (repository *APITokenRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, token domain.APIToken) (domain.APIToken, error) {
+	if token.Id == uuid.Nil {
+		token.Id = uuid.New()
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+
+	SQL := `INSERT INTO api_tokens(id, user_id, company_id, name, token_prefix, token_hash, scopes, expires_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err := tx.ExecContext(ctx, SQL,
+		token.Id,
+		token.UserId,
+		token.CompanyId,
+		token.Name,
+		token.TokenPrefix,
+		token.TokenHash,
+		pq.Array(apiScopeStrings(token.Scopes)),
+		token.ExpiresAt,
+		token.CreatedAt)
+
+	return token, err
+}
+
+func (repository *APITokenRepositoryImpl) FindByHash(ctx context.Context, tx *sql.Tx, tokenHash string) (domain.APIToken, error) {
+	SQL := `SELECT ` + apiTokenColumns + ` FROM api_tokens WHERE token_hash = $1`
+	return scanAPIToken(tx.QueryRowContext(ctx, SQL, tokenHash))
+}
+
+func (repository *APITokenRepositoryImpl) FindById(ctx context.Context, tx *sql.Tx, tokenId uuid.UUID) (domain.APIToken, error) {
+	SQL := `SELECT ` + apiTokenColumns + ` FROM api_tokens WHERE id = $1`
+	return scanAPIToken(tx.QueryRowContext(ctx, SQL, tokenId))
+}
+
+// FindByUserId returns the user's personal access tokens, partner keys are listed per company
+func (repository *APITokenRepositoryImpl) FindByUserId(ctx context.Context, tx *sql.Tx, userId uuid.UUID) ([]domain.APIToken, error) {
+	SQL := `SELECT ` + apiTokenColumns + ` FROM api_tokens
+			WHERE user_id = $1 AND company_id IS NULL AND revoked_at IS NULL
+			ORDER BY created_at DESC`
+	return queryAPITokens(ctx, tx, SQL, userId)
+}
+
+func (repository *APITokenRepositoryImpl) FindByCompanyId(ctx context.Context, tx *sql.Tx, companyId uuid.UUID) ([]domain.APIToken, error) {
+	SQL := `SELECT ` + apiTokenColumns + ` FROM api_tokens
+			WHERE company_id = $1 AND revoked_at IS NULL
+			ORDER BY created_at DESC`
+	return queryAPITokens(ctx, tx, SQL, companyId)
+}
+
+func (repository *APITokenRepositoryImpl) Revoke(ctx context.Context, tx *sql.Tx, tokenId uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, "UPDATE api_tokens SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL", time.Now(), tokenId)
+	return err
+}
+
+// RevokePersonalByUserId revokes the personal access tokens of a user, partner API keys belong to the company
+func (repository *APITokenRepositoryImpl) RevokePersonalByUserId(ctx context.Context, tx *sql.Tx, userId uuid.UUID) error {
+	SQL := "UPDATE api_tokens SET revoked_at = $1 WHERE user_id = $2 AND company_id IS NULL AND revoked_at IS NULL"
+	_, err := tx.ExecContext(ctx, SQL, time.Now(), userId)
+	return err
+}
+
+// TouchLastUsed records a use, skipping the write when the last one is newer than olderThan
+func (repository *APITokenRepositoryImpl) TouchLastUsed(ctx context.Context, tx *sql.Tx, tokenId uuid.UUID, ipAddress string, olderThan time.Time) error {
+	SQL := `UPDATE api_tokens SET last_used_at = $1, last_used_ip = $2
+			WHERE id = $3 AND (last_used_at IS NULL OR last_used_at < $4)`
+	_, err := tx.ExecContext(ctx, SQL, time.Now(), ipAddress, tokenId, olderThan)
+	return err
+}
+
+func queryAPITokens(ctx context.Context, tx *sql.Tx, SQL string, args ...interface{}) ([]domain.APIToken, error) {
+	rows, err := tx.QueryContext(ctx, SQL, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []domain.APIToken
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, rows.Err()
+}
+
+func scanAPIToken(row interface {
+	Scan(dest ...interface{}) error
+}) (domain.APIToken, error) {
+	token := domain.APIToken{}
+	var companyId uuid.NullUUID
+	var scopes []string
+	var lastUsedAt, revokedAt sql.NullTime
+	var lastUsedIp sql.NullString
+
+	err := row.Scan(
+		&token.Id,
+		&token.UserId,
+		&companyId,
+		&token.Name,
+		&token.TokenPrefix,
+		&token.TokenHash,
+		pq.Array(&scopes),
+		&token.ExpiresAt,
+		&lastUsedAt,
+		&lastUsedIp,
+		&revokedAt,
+		&token.CreatedAt)
+	if err == sql.ErrNoRows {
+		return token, errors.New("api token not found")
+	}
+	if err != nil {
+		return token, err
+	}
+
+	if companyId.Valid {
+		token.CompanyId = &companyId.UUID
+	}
+	for _, scope := range scopes {
+		token.Scopes = append(token.Scopes, domain.APIScope(scope))
+	}
+	if lastUsedAt.Valid {
+		token.LastUsedAt = &lastUsedAt.Time
+	}
+	if lastUsedIp.Valid {
+		token.LastUsedIp = &lastUsedIp.String
+	}
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+
+	return token, nil
+}
+
+func apiScopeStrings(scopes []domain.APIScope) []string {
+	values := make([]string, len(scopes))
+	for i, scope := range scopes {
+		values[i] = string(scope)
+	}
+	return values
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/model/domain"
+)
+
+// AuditLogRepository only ever inserts and reads, the table rejects updates and deletes
+type AuditLogRepository interface {
+	Save(ctx context.Context, tx *sql.Tx, auditLog domain.AuditLog) (domain.AuditLog, error)
+	FindAll(ctx context.Context, tx *sql.Tx, filter domain.AuditLogFilter, limit, offset int) ([]domain.AuditLog, error)
+	Count(ctx context.Context, tx *sql.Tx, filter domain.AuditLogFilter) (int, error)
+}
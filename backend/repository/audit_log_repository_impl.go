@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"evoconnect/backend/model/domain"
+	"fmt"
+	"strings"
+)
+
+type AuditLogRepositoryImpl struct{}
+
+func NewAuditLogRepository() AuditLogRepository {
+	return &AuditLogRepositoryImpl{}
+}
+
+func (repository *AuditLogRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, auditLog domain.AuditLog) (domain.AuditLog, error) {
+	SQL := `INSERT INTO audit_logs (actor_type, actor_id, actor_email, action, target_type, target_id, before_data, after_data, ip_address, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`
+
+	err := tx.QueryRowContext(ctx, SQL, auditLog.ActorType, auditLog.ActorId, auditLog.ActorEmail, auditLog.Action,
+		auditLog.TargetType, auditLog.TargetId, nullableJSON(auditLog.Before), nullableJSON(auditLog.After),
+		auditLog.IpAddress, auditLog.CreatedAt).Scan(&auditLog.Id)
+	if err != nil {
+		return domain.AuditLog{}, err
+	}
+
+	return auditLog, nil
+}
+
+func (repository *AuditLogRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx, filter domain.AuditLogFilter, limit, offset int) ([]domain.AuditLog, error) {
+	where, args := auditLogWhereClause(filter)
+	args = append(args, limit, offset)
+
+	SQL := fmt.Sprintf(`SELECT id, actor_type, actor_id, COALESCE(actor_email, ''), action, target_type, target_id,
+			before_data, after_data, COALESCE(ip_address, ''), created_at
+			FROM audit_logs %s
+			ORDER BY created_at DESC, id DESC
+			LIMIT $%d OFFSET $%d`, where, len(args)-1, len(args))
+
+	rows, err := tx.QueryContext(ctx, SQL, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var auditLogs []domain.AuditLog
+	for rows.Next() {
+		auditLog := domain.AuditLog{}
+		var before, after []byte
+		err := rows.Scan(&auditLog.Id, &auditLog.ActorType, &auditLog.ActorId, &auditLog.ActorEmail, &auditLog.Action,
+			&auditLog.TargetType, &auditLog.TargetId, &before, &after, &auditLog.IpAddress, &auditLog.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		auditLog.Before = before
+		auditLog.After = after
+		auditLogs = append(auditLogs, auditLog)
+	}
+
+	return auditLogs, rows.Err()
+}
+
+func (repository *AuditLogRepositoryImpl) Count(ctx context.Context, tx *sql.Tx, filter domain.AuditLogFilter) (int, error) {
+	where, args := auditLogWhereClause(filter)
+
+	var count int
+	err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM audit_logs "+where, args...).Scan(&count)
+	return count, err
+}
+
+func auditLogWhereClause(filter domain.AuditLogFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.ActorId != nil {
+		add("actor_id = $%d", *filter.ActorId)
+	}
+	if filter.Action != "" {
+		add("action = $%d", filter.Action)
+	}
+	if filter.TargetType != "" {
+		add("target_type = $%d", filter.TargetType)
+	}
+	if filter.TargetId != "" {
+		add("target_id = $%d", filter.TargetId)
+	}
+	if filter.From != nil {
+		add("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("created_at < $%d", *filter.To)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// nullableJSON stores empty snapshots as NULL instead of an invalid JSON document
+func nullableJSON(data json.RawMessage) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
@@ -6,6 +6,7 @@ import (
     "evoconnect/backend/model/domain"
     "fmt"
     "github.com/google/uuid"
+    "log/slog"
 )
 
 type BlogRepositoryImpl struct {
@@ -232,11 +233,10 @@ func (repository *BlogRepositoryImpl) Search(ctx context.Context, tx *sql.Tx, qu
    
     searchPattern := "%" + query + "%"
     exactPattern := "%" + query + "%"
-    fmt.Printf("Executing blog search SQL with pattern: %s\n", searchPattern)
    
     rows, err := tx.QueryContext(ctx, SQL, searchPattern, exactPattern, limit, offset)
     if err != nil {
-        fmt.Printf("Error executing blog search: %v\n", err)
+        slog.ErrorContext(ctx, "error executing blog search", "error", err)
         return []domain.Blog{}
     }
     defer rows.Close()
@@ -246,11 +246,10 @@ func (repository *BlogRepositoryImpl) Search(ctx context.Context, tx *sql.Tx, qu
         blog := domain.Blog{}
         err := rows.Scan(&blog.ID, &blog.Title, &blog.Slug, &blog.Content, &blog.Category, &blog.ImagePath, &blog.UserID, &blog.CreatedAt, &blog.UpdatedAt)
         if err != nil {
-            fmt.Printf("Error scanning blog: %v\n", err)
+            slog.ErrorContext(ctx, "error scanning blog", "error", err)
             continue
         }
         blogs = append(blogs, blog)
-        fmt.Printf("Found blog: %s\n", blog.Title)
     }
    
     return blogs
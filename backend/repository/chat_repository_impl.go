@@ -526,7 +526,6 @@ func (repository *ChatRepositoryImpl) FindMessageById(ctx context.Context, tx *s
 
 	message.Sender = &user
 
-	fmt.Println("Message found:", message.DeletedAt)
 	return message, nil
 }
 
@@ -7,6 +7,7 @@ import (
 	"evoconnect/backend/helper"
 	"evoconnect/backend/model/domain"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -229,7 +230,7 @@ func (repository *CommentBlogRepositoryImpl) FindByBlogId(ctx context.Context, t
 
 			repliesRows, err := tx.QueryContext(ctx, repliesSQL, commentIds...)
 			if err != nil {
-				fmt.Printf("Error fetching replies: %v\n", err)
+				slog.ErrorContext(ctx, "error fetching replies", "error", err)
 			} else {
 				defer repliesRows.Close()
 
@@ -252,7 +253,7 @@ func (repository *CommentBlogRepositoryImpl) FindByBlogId(ctx context.Context, t
 						&user.Photo,
 					)
 					if err != nil {
-						fmt.Printf("Error scanning reply: %v\n", err)
+						slog.ErrorContext(ctx, "error scanning reply", "error", err)
 						continue
 					}
 
@@ -270,7 +271,7 @@ func (repository *CommentBlogRepositoryImpl) FindByBlogId(ctx context.Context, t
 				}
 
 				if err = repliesRows.Err(); err != nil {
-					fmt.Printf("Error in replies rows: %v\n", err)
+					slog.ErrorContext(ctx, "error in replies rows", "error", err)
 				}
 			}
 		}
@@ -7,6 +7,7 @@ import (
 	"evoconnect/backend/helper"
 	"evoconnect/backend/model/domain"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 	"github.com/google/uuid"
@@ -247,7 +248,7 @@ func (repository *CommentRepositoryImpl) FindByPostId(ctx context.Context, tx *s
 			repliesRows, err := tx.QueryContext(ctx, repliesSQL, commentIds...)
 			if err != nil {
 				// Log error but continue without replies
-				fmt.Printf("Error fetching replies: %v\n", err)
+				slog.ErrorContext(ctx, "error fetching replies", "error", err)
 			} else {
 				defer repliesRows.Close()
 
@@ -271,7 +272,7 @@ func (repository *CommentRepositoryImpl) FindByPostId(ctx context.Context, tx *s
 					)
 					if err != nil {
 						// Log error but continue
-						fmt.Printf("Error scanning reply: %v\n", err)
+						slog.ErrorContext(ctx, "error scanning reply", "error", err)
 						continue
 					}
 
@@ -292,7 +293,7 @@ func (repository *CommentRepositoryImpl) FindByPostId(ctx context.Context, tx *s
 
 				if err = repliesRows.Err(); err != nil {
 					// Log error but continue
-					fmt.Printf("Error in replies rows: %v\n", err)
+					slog.ErrorContext(ctx, "error in replies rows", "error", err)
 				}
 			}
 		}
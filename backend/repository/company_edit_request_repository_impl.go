@@ -315,7 +315,6 @@ func (repository *CompanyEditRequestRepositoryImpl) FindAll(ctx context.Context,
 	helper.PanicIfError(err)
 	defer rows.Close()
 
-	fmt.Println("Executing FindAll with limit:", limit, "and offset:", offset)
 	var editRequests []domain.CompanyEditRequest
 	for rows.Next() {
 		editRequest := domain.CompanyEditRequest{}
@@ -5,7 +5,6 @@ import (
 	"database/sql"
 	"evoconnect/backend/helper"
 	"evoconnect/backend/model/domain"
-	"fmt"
 	"strconv"
 	"time"
 
@@ -119,8 +118,6 @@ func (repository *CompanyJoinRequestRepositoryImpl) FindByUserIdAndCompanyId(ctx
 			request.ResponsedAt = &responsedAt
 		}
 
-		fmt.Printf("Found join request: %+v\n", request)
-
 		return request, nil
 	} else {
 		// Return a custom error or nil if no rows is an expected condition
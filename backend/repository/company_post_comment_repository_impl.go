@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"evoconnect/backend/model/domain"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
@@ -213,7 +214,7 @@ func (repository *CompanyPostCommentRepositoryImpl) FindRepliesByParentId(ctx co
 	var total int
 	err := tx.QueryRowContext(ctx, countSQL, parentId).Scan(&total)
 	if err != nil {
-		fmt.Printf("Count query error: %v\n", err)
+		slog.ErrorContext(ctx, "failed to count comments", "error", err)
 		return replies, 0, fmt.Errorf("failed to count replies: %w", err)
 	}
 
@@ -233,7 +234,7 @@ func (repository *CompanyPostCommentRepositoryImpl) FindRepliesByParentId(ctx co
 
 	rows, err := tx.QueryContext(ctx, SQL, parentId, limit, offset)
 	if err != nil {
-		fmt.Printf("Query error: %v\n", err)
+		slog.ErrorContext(ctx, "failed to query comments", "error", err)
 		return replies, 0, fmt.Errorf("failed to query replies: %w", err)
 	}
 	defer rows.Close()
@@ -257,7 +258,7 @@ func (repository *CompanyPostCommentRepositoryImpl) FindRepliesByParentId(ctx co
 			&commentToCommentUserId, &commentToCommentUserName, &commentToCommentUserUsername, &commentToCommentUserPhoto,
 		)
 		if err != nil {
-			fmt.Printf("Scan error: %v\n", err)
+			slog.ErrorContext(ctx, "failed to scan comment", "error", err)
 			return replies, 0, fmt.Errorf("failed to scan reply: %w", err)
 		}
 
@@ -311,7 +312,7 @@ func (repository *CompanyPostCommentRepositoryImpl) FindRepliesByParentId(ctx co
 
 	// Check for any errors that occurred during iteration
 	if err = rows.Err(); err != nil {
-		fmt.Printf("Rows iteration error: %v\n", err)
+		slog.ErrorContext(ctx, "failed to iterate comments", "error", err)
 		return replies, 0, fmt.Errorf("error during rows iteration: %w", err)
 	}
 
@@ -291,7 +291,7 @@ func (repository *companyPostRepositoryImpl) FindWithFilters(ctx context.Context
 	}
 
 	if search != "" {
-		whereConditions = append(whereConditions, fmt.Sprintf("(cp.content ILIKE $%d)", argIndex, argIndex))
+		whereConditions = append(whereConditions, fmt.Sprintf("(cp.content ILIKE $%d)", argIndex))
 		args = append(args, "%"+search+"%")
 		argIndex++
 	}
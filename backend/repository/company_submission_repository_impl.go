@@ -104,7 +104,6 @@ func (repository *CompanySubmissionRepositoryImpl) FindByStatus(ctx context.Cont
 }
 
 func (repository *CompanySubmissionRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx, limit, offset int) []domain.CompanySubmission {
-	fmt.Println("Fetching all company submissions with limit:", limit, "and offset:", offset)
 	SQL := `SELECT 
                 cs.id, cs.user_id, cs.name, cs.linkedin_url, cs.website, cs.industry, 
                 cs.size, cs.type, cs.logo, cs.tagline, cs.status, cs.rejection_reason,
@@ -121,7 +120,6 @@ func (repository *CompanySubmissionRepositoryImpl) FindAll(ctx context.Context,
 
 	var submissions []domain.CompanySubmission
 	for rows.Next() {
-		fmt.Println("Processing a row in company submissions")
 		submission := domain.CompanySubmission{}
 
 		// Handle nullable submission fields
@@ -134,7 +132,6 @@ func (repository *CompanySubmissionRepositoryImpl) FindAll(ctx context.Context,
 		var userId, userName, userEmail, userUsername sql.NullString
 		var userPhoto sql.NullString // Changed from string to sql.NullString
 
-		fmt.Println("Scanning row into submission and user fields")
 		err := rows.Scan(
 			&submission.Id, &submission.UserId, &submission.Name, &submission.LinkedinUrl,
 			&submissionWebsite, &submission.Industry, &submission.Size, &submission.Type,
@@ -143,7 +140,6 @@ func (repository *CompanySubmissionRepositoryImpl) FindAll(ctx context.Context,
 			&userId, &userName, &userEmail, &userUsername, &userPhoto) // Fixed here
 		helper.PanicIfError(err)
 
-		fmt.Println("Row scanned successfully, handling nullable fields")
 		// Handle nullable submission fields
 		submission.Website = submissionWebsite.String
 		submission.Logo = submissionLogo.String
@@ -151,14 +147,11 @@ func (repository *CompanySubmissionRepositoryImpl) FindAll(ctx context.Context,
 		submission.RejectionReason = rejectionReason.String
 		submission.ReviewedBy = reviewedBy
 
-		fmt.Println("Checking if reviewedAt is valid")
 		// FIXED: Uncomment and properly handle ReviewedAt
 		if reviewedAt.Valid {
-			fmt.Println("Setting ReviewedAt time")
 			submission.ReviewedAt = &reviewedAt.Time
 		}
 
-		fmt.Println("Building User object if userId is valid")
 		// Build User object if data exists - FIXED NULL HANDLING
 		if userId.Valid {
 			userUUID, err := uuid.Parse(userId.String)
@@ -205,7 +198,6 @@ func (repository *CompanySubmissionRepositoryImpl) FindById(ctx context.Context,
 		var userId, userName, userEmail, userUsername sql.NullString
 		var userPhoto sql.NullString // Changed from string to sql.NullString
 
-		fmt.Println("Scanning row into submission and user fields")
 		err := rows.Scan(
 			&submission.Id, &submission.UserId, &submission.Name, &submission.LinkedinUrl,
 			&submissionWebsite, &submission.Industry, &submission.Size, &submission.Type,
@@ -214,7 +206,6 @@ func (repository *CompanySubmissionRepositoryImpl) FindById(ctx context.Context,
 			&userId, &userName, &userEmail, &userUsername, &userPhoto) // Fixed here
 		helper.PanicIfError(err)
 
-		fmt.Println("Row scanned successfully, handling nullable fields")
 		// Handle nullable submission fields
 		submission.Website = submissionWebsite.String
 		submission.Logo = submissionLogo.String
@@ -222,14 +213,11 @@ func (repository *CompanySubmissionRepositoryImpl) FindById(ctx context.Context,
 		submission.RejectionReason = rejectionReason.String
 		submission.ReviewedBy = reviewedBy
 
-		fmt.Println("Checking if reviewedAt is valid")
 		// FIXED: Uncomment and properly handle ReviewedAt
 		if reviewedAt.Valid {
-			fmt.Println("Setting ReviewedAt time")
 			submission.ReviewedAt = &reviewedAt.Time
 		}
 
-		fmt.Println("Building User object if userId is valid")
 		// Build User object if data exists - FIXED NULL HANDLING
 		if userId.Valid {
 			userUUID, err := uuid.Parse(userId.String)
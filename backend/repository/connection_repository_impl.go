@@ -6,7 +6,6 @@ import (
 	"errors"
 	"evoconnect/backend/helper"
 	"evoconnect/backend/model/domain"
-	"fmt"
 	"github.com/google/uuid"
 	"sort"
 	"time"
@@ -207,8 +206,6 @@ func (repository *ConnectionRepositoryImpl) FindConnectionRequestsByReceiverId(c
 
 func (repository *ConnectionRepositoryImpl) FindConnectionRequestBySenderIdAndReceiverId(ctx context.Context, tx *sql.Tx, senderId, receiverId uuid.UUID) (domain.ConnectionRequest, error) {
 	// Log untuk debugging
-	fmt.Printf("DEBUG: FindConnectionRequestBySenderIdAndReceiverId called with senderId=%s, receiverId=%s\n",
-		senderId.String(), receiverId.String())
 
 	SQL := `SELECT id, sender_id, receiver_id, message, status, created_at, updated_at
             FROM connection_requests
@@ -218,7 +215,6 @@ func (repository *ConnectionRepositoryImpl) FindConnectionRequestBySenderIdAndRe
 
 	rows, err := tx.QueryContext(ctx, SQL, senderId, receiverId)
 	if err != nil {
-		fmt.Printf("DEBUG: Error querying connection requests: %v\n", err)
 		return domain.ConnectionRequest{}, err
 	}
 	defer rows.Close()
@@ -230,7 +226,6 @@ func (repository *ConnectionRepositoryImpl) FindConnectionRequestBySenderIdAndRe
 			&request.Id, &request.SenderId, &request.ReceiverId, &message, &request.Status, &request.CreatedAt, &request.UpdatedAt,
 		)
 		if err != nil {
-			fmt.Printf("DEBUG: Error scanning connection request: %v\n", err)
 			return domain.ConnectionRequest{}, err
 		}
 
@@ -240,10 +235,8 @@ func (repository *ConnectionRepositoryImpl) FindConnectionRequestBySenderIdAndRe
 			request.Message = &messageStr
 		}
 
-		fmt.Printf("DEBUG: Found connection request with status: %s\n", request.Status)
 		return request, nil
 	} else {
-		fmt.Printf("DEBUG: No connection request found\n")
 		return domain.ConnectionRequest{}, errors.New("connection request not found")
 	}
 }
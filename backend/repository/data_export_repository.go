@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/model/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DataExportRepository interface {
+	Save(ctx context.Context, tx *sql.Tx, export domain.DataExport) domain.DataExport
+	FindById(ctx context.Context, tx *sql.Tx, exportId uuid.UUID) (domain.DataExport, error)
+	FindByUserId(ctx context.Context, tx *sql.Tx, userId uuid.UUID, limit int) []domain.DataExport
+	FindLatestByUserId(ctx context.Context, tx *sql.Tx, userId uuid.UUID) (domain.DataExport, error)
+	ClaimPending(ctx context.Context, tx *sql.Tx) (domain.DataExport, error)
+	RequeueStale(ctx context.Context, tx *sql.Tx, startedBefore time.Time) (int64, error)
+	MarkReady(ctx context.Context, tx *sql.Tx, exportId uuid.UUID, filePath string, fileSize int64, expiresAt time.Time) error
+	MarkFailed(ctx context.Context, tx *sql.Tx, exportId uuid.UUID, message string) error
+	FindExpired(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]domain.DataExport, error)
+	MarkExpired(ctx context.Context, tx *sql.Tx, exportId uuid.UUID) error
+	FindSection(ctx context.Context, tx *sql.Tx, section string, userId uuid.UUID) ([]byte, error)
+	FindMediaPaths(ctx context.Context, tx *sql.Tx, userId uuid.UUID) ([]string, error)
+}
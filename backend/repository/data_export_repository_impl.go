@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/domain"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DataExportSections lists the JSON files of an export archive, in the order they are written
+var DataExportSections = []string{
+	"profile",
+	"education",
+	"experience",
+	"posts",
+	"comments",
+	"blogs",
+	"blog_comments",
+	"connections",
+	"connection_requests",
+	"messages",
+	"job_applications",
+	"cv",
+	"notifications",
+	"profile_views",
+	"connected_accounts",
+}
+
+// dataExportSectionQueries return one JSON document per section. Credentials and tokens are never selected.
+var dataExportSectionQueries = map[string]string{
+	"profile": `SELECT to_jsonb(u) - 'password' - 'verification_token' - 'verification_expires' - 'reset_token' - 'reset_expires' - 'email_change_cancel_token'
+			- 'magic_link_token' - 'magic_link_expires'
+			FROM users u WHERE u.id = $1`,
+	"education": `SELECT COALESCE(json_agg(t ORDER BY t.start_year DESC), '[]'::json)
+			FROM (SELECT * FROM user_education WHERE user_id = $1) t`,
+	"experience": `SELECT COALESCE(json_agg(t ORDER BY t.start_year DESC), '[]'::json)
+			FROM (SELECT * FROM user_experiences WHERE user_id = $1) t`,
+	"posts": `SELECT COALESCE(json_agg(t ORDER BY t.created_at), '[]'::json)
+			FROM (SELECT * FROM posts WHERE user_id = $1) t`,
+	"comments": `SELECT COALESCE(json_agg(t ORDER BY t.created_at), '[]'::json)
+			FROM (SELECT * FROM comments WHERE user_id = $1) t`,
+	"blogs": `SELECT COALESCE(json_agg(t ORDER BY t.created_at), '[]'::json)
+			FROM (SELECT * FROM tb_blog WHERE user_id = $1) t`,
+	"blog_comments": `SELECT COALESCE(json_agg(t ORDER BY t.created_at), '[]'::json)
+			FROM (SELECT * FROM comment_blog WHERE user_id = $1) t`,
+	"connections": `SELECT COALESCE(json_agg(t ORDER BY t.connected_at), '[]'::json)
+			FROM (
+				SELECT u.id AS user_id, u.name, u.username, c.created_at AS connected_at
+				FROM connections c
+				JOIN users u ON u.id = CASE WHEN c.user_id_1 = $1 THEN c.user_id_2 ELSE c.user_id_1 END
+				WHERE c.user_id_1 = $1 OR c.user_id_2 = $1
+			) t`,
+	"connection_requests": `SELECT COALESCE(json_agg(t ORDER BY t.created_at), '[]'::json)
+			FROM (SELECT * FROM connection_requests WHERE sender_id = $1 OR receiver_id = $1) t`,
+	"messages": `SELECT COALESCE(json_agg(t ORDER BY t.conversation_id, t.created_at), '[]'::json)
+			FROM (
+				SELECT m.id, m.conversation_id, m.sender_id, u.name AS sender_name, m.message_type, m.content,
+					m.file_name, m.file_path, m.file_size, m.file_type, m.reply_to_id, m.created_at, m.updated_at
+				FROM messages m
+				JOIN conversation_participants cp ON cp.conversation_id = m.conversation_id AND cp.user_id = $1
+				LEFT JOIN users u ON u.id = m.sender_id
+				WHERE m.deleted_at IS NULL
+			) t`,
+	"job_applications": `SELECT COALESCE(json_agg(t ORDER BY t.submitted_at), '[]'::json)
+			FROM (
+				SELECT ja.id, ja.job_vacancy_id, jv.title AS job_title, ja.cv_file_path, ja.contact_info,
+					ja.motivation_letter, ja.cover_letter, ja.expected_salary, ja.available_start_date,
+					ja.status, ja.rejection_reason, ja.reviewed_at, ja.interview_scheduled_at, ja.submitted_at, ja.updated_at
+				FROM job_applications ja
+				JOIN job_vacancies jv ON jv.id = ja.job_vacancy_id
+				WHERE ja.applicant_id = $1
+			) t`,
+	"cv": `SELECT COALESCE(json_agg(t), '[]'::json)
+			FROM (SELECT * FROM user_cv_storage WHERE user_id = $1) t`,
+	"notifications": `SELECT COALESCE(json_agg(t ORDER BY t.created_at), '[]'::json)
+			FROM (SELECT * FROM notifications WHERE user_id = $1) t`,
+	"profile_views": `SELECT COALESCE(json_agg(t ORDER BY t.viewed_at), '[]'::json)
+			FROM (
+				SELECT CASE WHEN pv.profile_user_id = $1 THEN 'received' ELSE 'made' END AS direction,
+					u.id AS other_user_id, u.name AS other_user_name, pv.viewed_at
+				FROM profile_views pv
+				JOIN users u ON u.id = CASE WHEN pv.profile_user_id = $1 THEN pv.viewer_id ELSE pv.profile_user_id END
+				WHERE pv.profile_user_id = $1 OR pv.viewer_id = $1
+			) t`,
+	"connected_accounts": `SELECT COALESCE(json_agg(t ORDER BY t.created_at), '[]'::json)
+			FROM (SELECT provider, email, created_at, last_login_at FROM user_identities WHERE user_id = $1) t`,
+}
+
+type DataExportRepositoryImpl struct{}
+
+func NewDataExportRepository() DataExportRepository {
+	return &DataExportRepositoryImpl{}
+}
+
+const dataExportColumns = "id, user_id, status, file_path, file_size, error_message, started_at, completed_at, expires_at, created_at"
+
+func (repository *DataExportRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, export domain.DataExport) domain.DataExport {
+	if export.Id == uuid.Nil {
+		export.Id = uuid.New()
+	}
+
+	SQL := "INSERT INTO data_exports(id, user_id, status, created_at) VALUES ($1, $2, $3, $4)"
+	_, err := tx.ExecContext(ctx, SQL, export.Id, export.UserId, export.Status, export.CreatedAt)
+	helper.PanicIfError(err)
+
+	return export
+}
+
+func (repository *DataExportRepositoryImpl) FindById(ctx context.Context, tx *sql.Tx, exportId uuid.UUID) (domain.DataExport, error) {
+	SQL := "SELECT " + dataExportColumns + " FROM data_exports WHERE id = $1"
+	return queryDataExport(ctx, tx, SQL, exportId)
+}
+
+func (repository *DataExportRepositoryImpl) FindByUserId(ctx context.Context, tx *sql.Tx, userId uuid.UUID, limit int) []domain.DataExport {
+	SQL := "SELECT " + dataExportColumns + " FROM data_exports WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2"
+	exports, err := queryDataExports(ctx, tx, SQL, userId, limit)
+	helper.PanicIfError(err)
+	return exports
+}
+
+func (repository *DataExportRepositoryImpl) FindLatestByUserId(ctx context.Context, tx *sql.Tx, userId uuid.UUID) (domain.DataExport, error) {
+	SQL := "SELECT " + dataExportColumns + " FROM data_exports WHERE user_id = $1 ORDER BY created_at DESC LIMIT 1"
+	return queryDataExport(ctx, tx, SQL, userId)
+}
+
+// ClaimPending moves the oldest pending export to processing, SKIP LOCKED lets several workers run side by side
+func (repository *DataExportRepositoryImpl) ClaimPending(ctx context.Context, tx *sql.Tx) (domain.DataExport, error) {
+	SQL := `UPDATE data_exports SET status = 'processing', started_at = $1
+			WHERE id = (
+				SELECT id FROM data_exports WHERE status = 'pending'
+				ORDER BY created_at ASC
+				LIMIT 1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING ` + dataExportColumns
+	return queryDataExport(ctx, tx, SQL, time.Now())
+}
+
+// RequeueStale puts back exports whose worker died while building them
+func (repository *DataExportRepositoryImpl) RequeueStale(ctx context.Context, tx *sql.Tx, startedBefore time.Time) (int64, error) {
+	SQL := "UPDATE data_exports SET status = 'pending', started_at = NULL WHERE status = 'processing' AND started_at < $1"
+	result, err := tx.ExecContext(ctx, SQL, startedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (repository *DataExportRepositoryImpl) MarkReady(ctx context.Context, tx *sql.Tx, exportId uuid.UUID, filePath string, fileSize int64, expiresAt time.Time) error {
+	SQL := `UPDATE data_exports SET status = 'ready', file_path = $1, file_size = $2, completed_at = $3, expires_at = $4
+			WHERE id = $5`
+	_, err := tx.ExecContext(ctx, SQL, filePath, fileSize, time.Now(), expiresAt, exportId)
+	return err
+}
+
+func (repository *DataExportRepositoryImpl) MarkFailed(ctx context.Context, tx *sql.Tx, exportId uuid.UUID, message string) error {
+	SQL := "UPDATE data_exports SET status = 'failed', error_message = $1, completed_at = $2 WHERE id = $3"
+	_, err := tx.ExecContext(ctx, SQL, message, time.Now(), exportId)
+	return err
+}
+
+func (repository *DataExportRepositoryImpl) FindExpired(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]domain.DataExport, error) {
+	SQL := "SELECT " + dataExportColumns + ` FROM data_exports
+			WHERE status = 'ready' AND expires_at <= $1
+			ORDER BY expires_at ASC
+			LIMIT $2`
+	return queryDataExports(ctx, tx, SQL, now, limit)
+}
+
+func (repository *DataExportRepositoryImpl) MarkExpired(ctx context.Context, tx *sql.Tx, exportId uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, "UPDATE data_exports SET status = 'expired', file_path = NULL WHERE id = $1", exportId)
+	return err
+}
+
+func (repository *DataExportRepositoryImpl) FindSection(ctx context.Context, tx *sql.Tx, section string, userId uuid.UUID) ([]byte, error) {
+	SQL, ok := dataExportSectionQueries[section]
+	if !ok {
+		return nil, fmt.Errorf("unknown export section %q", section)
+	}
+
+	var document []byte
+	err := tx.QueryRowContext(ctx, SQL, userId).Scan(&document)
+	return document, err
+}
+
+// FindMediaPaths returns the stored paths of every file the user uploaded
+func (repository *DataExportRepositoryImpl) FindMediaPaths(ctx context.Context, tx *sql.Tx, userId uuid.UUID) ([]string, error) {
+	SQL := `SELECT path FROM (
+				SELECT photo AS path FROM users WHERE id = $1
+				UNION SELECT cover_image FROM users WHERE id = $1
+				UNION SELECT photo FROM user_education WHERE user_id = $1
+				UNION SELECT photo FROM user_experiences WHERE user_id = $1
+				UNION SELECT jsonb_array_elements_text(images) FROM posts WHERE user_id = $1 AND jsonb_typeof(images) = 'array'
+				UNION SELECT image_path FROM tb_blog WHERE user_id = $1
+				UNION SELECT file_path FROM messages WHERE sender_id = $1 AND deleted_at IS NULL
+				UNION SELECT cv_file_path FROM user_cv_storage WHERE user_id = $1
+				UNION SELECT cv_file_path FROM job_applications WHERE applicant_id = $1
+			) media
+			WHERE path IS NOT NULL AND path != ''
+			ORDER BY path`
+	rows, err := tx.QueryContext(ctx, SQL, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var paths []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, rows.Err()
+}
+
+func queryDataExport(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (domain.DataExport, error) {
+	exports, err := queryDataExports(ctx, tx, query, args...)
+	if err != nil {
+		return domain.DataExport{}, err
+	}
+	if len(exports) == 0 {
+		return domain.DataExport{}, errors.New("data export not found")
+	}
+	return exports[0], nil
+}
+
+func queryDataExports(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]domain.DataExport, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exports []domain.DataExport
+	for rows.Next() {
+		export := domain.DataExport{}
+		var filePath, errorMessage sql.NullString
+		var fileSize sql.NullInt64
+		var startedAt, completedAt, expiresAt sql.NullTime
+
+		err := rows.Scan(
+			&export.Id,
+			&export.UserId,
+			&export.Status,
+			&filePath,
+			&fileSize,
+			&errorMessage,
+			&startedAt,
+			&completedAt,
+			&expiresAt,
+			&export.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if filePath.Valid {
+			export.FilePath = &filePath.String
+		}
+		if fileSize.Valid {
+			export.FileSize = &fileSize.Int64
+		}
+		if errorMessage.Valid {
+			export.ErrorMessage = &errorMessage.String
+		}
+		if startedAt.Valid {
+			export.StartedAt = &startedAt.Time
+		}
+		if completedAt.Valid {
+			export.CompletedAt = &completedAt.Time
+		}
+		if expiresAt.Valid {
+			export.ExpiresAt = &expiresAt.Time
+		}
+		exports = append(exports, export)
+	}
+
+	return exports, rows.Err()
+}
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/model/domain"
+)
+
+type FileAccessLogRepository interface {
+	Save(ctx context.Context, tx *sql.Tx, accessLog domain.FileAccessLog) domain.FileAccessLog
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/domain"
+
+	"github.com/google/uuid"
+)
+
+type FileAccessLogRepositoryImpl struct{}
+
+func NewFileAccessLogRepository() FileAccessLogRepository {
+	return &FileAccessLogRepositoryImpl{}
+}
+
+func (repository *FileAccessLogRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, accessLog domain.FileAccessLog) domain.FileAccessLog {
+	if accessLog.Id == uuid.Nil {
+		accessLog.Id = uuid.New()
+	}
+
+	SQL := `INSERT INTO file_access_logs(id, user_id, file_key, action, reference_type, reference_id, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := tx.ExecContext(ctx, SQL, accessLog.Id, accessLog.UserId, accessLog.FileKey, accessLog.Action,
+		accessLog.ReferenceType, accessLog.ReferenceId, accessLog.CreatedAt)
+	helper.PanicIfError(err)
+
+	return accessLog
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/model/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FileScanRepository interface {
+	Save(ctx context.Context, tx *sql.Tx, scan domain.FileScan) domain.FileScan
+	FindByFileKey(ctx context.Context, tx *sql.Tx, fileKey string) (domain.FileScan, error)
+	ClaimPending(ctx context.Context, tx *sql.Tx, skip []uuid.UUID) (domain.FileScan, error)
+	RequeueStale(ctx context.Context, tx *sql.Tx, startedBefore time.Time) (int64, error)
+	Requeue(ctx context.Context, tx *sql.Tx, scanId uuid.UUID) error
+	MarkClean(ctx context.Context, tx *sql.Tx, scanId uuid.UUID) error
+	MarkInfected(ctx context.Context, tx *sql.Tx, scanId uuid.UUID, signature string) error
+	MarkFailed(ctx context.Context, tx *sql.Tx, scanId uuid.UUID) error
+	DeleteFileReferences(ctx context.Context, tx *sql.Tx, fileKey string) ([]domain.Message, error)
+}
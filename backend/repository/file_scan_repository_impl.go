@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type FileScanRepositoryImpl struct{}
+
+func NewFileScanRepository() FileScanRepository {
+	return &FileScanRepositoryImpl{}
+}
+
+const fileScanColumns = "id, user_id, file_key, file_name, kind, status, signature, attempts, started_at, scanned_at, created_at"
+
+// Save queues a scan, uploading a new file under a key that was scanned before starts over
+func (repository *FileScanRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, scan domain.FileScan) domain.FileScan {
+	if scan.Id == uuid.Nil {
+		scan.Id = uuid.New()
+	}
+
+	SQL := `INSERT INTO file_scans(id, user_id, file_key, file_name, kind, status, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (file_key) DO UPDATE SET
+				id = EXCLUDED.id, user_id = EXCLUDED.user_id, file_name = EXCLUDED.file_name, kind = EXCLUDED.kind,
+				status = EXCLUDED.status, signature = NULL, attempts = 0, started_at = NULL, scanned_at = NULL,
+				created_at = EXCLUDED.created_at`
+	_, err := tx.ExecContext(ctx, SQL, scan.Id, scan.UserId, scan.FileKey, scan.FileName, scan.Kind, scan.Status, scan.CreatedAt)
+	helper.PanicIfError(err)
+
+	return scan
+}
+
+func (repository *FileScanRepositoryImpl) FindByFileKey(ctx context.Context, tx *sql.Tx, fileKey string) (domain.FileScan, error) {
+	SQL := "SELECT " + fileScanColumns + " FROM file_scans WHERE file_key = $1"
+	return queryFileScan(ctx, tx, SQL, fileKey)
+}
+
+// ClaimPending moves the oldest pending scan to scanning, SKIP LOCKED lets several workers run side by side.
+// skip holds the scans that already failed in this batch, they wait for the next run.
+func (repository *FileScanRepositoryImpl) ClaimPending(ctx context.Context, tx *sql.Tx, skip []uuid.UUID) (domain.FileScan, error) {
+	if skip == nil {
+		skip = []uuid.UUID{}
+	}
+	SQL := `UPDATE file_scans SET status = 'scanning', started_at = $1, attempts = attempts + 1
+			WHERE id = (
+				SELECT id FROM file_scans WHERE status = 'pending' AND id <> ALL($2)
+				ORDER BY created_at ASC
+				LIMIT 1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING ` + fileScanColumns
+	return queryFileScan(ctx, tx, SQL, time.Now(), pq.Array(skip))
+}
+
+// RequeueStale puts back scans whose worker died while scanning them
+func (repository *FileScanRepositoryImpl) RequeueStale(ctx context.Context, tx *sql.Tx, startedBefore time.Time) (int64, error) {
+	SQL := "UPDATE file_scans SET status = 'pending', started_at = NULL WHERE status = 'scanning' AND started_at < $1"
+	result, err := tx.ExecContext(ctx, SQL, startedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (repository *FileScanRepositoryImpl) Requeue(ctx context.Context, tx *sql.Tx, scanId uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, "UPDATE file_scans SET status = 'pending', started_at = NULL WHERE id = $1", scanId)
+	return err
+}
+
+func (repository *FileScanRepositoryImpl) MarkClean(ctx context.Context, tx *sql.Tx, scanId uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, "UPDATE file_scans SET status = 'clean', scanned_at = $1 WHERE id = $2", time.Now(), scanId)
+	return err
+}
+
+func (repository *FileScanRepositoryImpl) MarkInfected(ctx context.Context, tx *sql.Tx, scanId uuid.UUID, signature string) error {
+	SQL := "UPDATE file_scans SET status = 'infected', signature = $1, scanned_at = $2 WHERE id = $3"
+	_, err := tx.ExecContext(ctx, SQL, signature, time.Now(), scanId)
+	return err
+}
+
+func (repository *FileScanRepositoryImpl) MarkFailed(ctx context.Context, tx *sql.Tx, scanId uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, "UPDATE file_scans SET status = 'failed', scanned_at = $1 WHERE id = $2", time.Now(), scanId)
+	return err
+}
+
+// DeleteFileReferences removes the stored CV and deletes the chat messages pointing at a file.
+// The deleted messages are returned with their id and conversation.
+func (repository *FileScanRepositoryImpl) DeleteFileReferences(ctx context.Context, tx *sql.Tx, fileKey string) ([]domain.Message, error) {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM user_cv_storage WHERE cv_file_path = $1", fileKey); err != nil {
+		return nil, err
+	}
+
+	SQL := `UPDATE messages SET deleted_at = $1
+			WHERE file_path = $2 AND deleted_at IS NULL
+			RETURNING id, conversation_id`
+	rows, err := tx.QueryContext(ctx, SQL, time.Now(), fileKey)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []domain.Message
+	for rows.Next() {
+		message := domain.Message{}
+		if err := rows.Scan(&message.Id, &message.ConversationId); err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, rows.Err()
+}
+
+func queryFileScan(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (domain.FileScan, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return domain.FileScan{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return domain.FileScan{}, err
+		}
+		return domain.FileScan{}, sql.ErrNoRows
+	}
+
+	scan := domain.FileScan{}
+	var signature sql.NullString
+	var startedAt, scannedAt sql.NullTime
+	err = rows.Scan(
+		&scan.Id,
+		&scan.UserId,
+		&scan.FileKey,
+		&scan.FileName,
+		&scan.Kind,
+		&scan.Status,
+		&signature,
+		&scan.Attempts,
+		&startedAt,
+		&scannedAt,
+		&scan.CreatedAt)
+	if err != nil {
+		return domain.FileScan{}, err
+	}
+
+	if signature.Valid {
+		scan.Signature = &signature.String
+	}
+	if startedAt.Valid {
+		scan.StartedAt = &startedAt.Time
+	}
+	if scannedAt.Valid {
+		scan.ScannedAt = &scannedAt.Time
+	}
+
+	return scan, nil
+}
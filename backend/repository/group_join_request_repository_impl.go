@@ -5,8 +5,8 @@ import (
 	"database/sql"
 	"evoconnect/backend/helper"
 	"evoconnect/backend/model/domain"
-	"fmt"
 	"github.com/google/uuid"
+	"log/slog"
 	"time"
 	"errors"
 )
@@ -35,14 +35,14 @@ func (repository *GroupJoinRequestRepositoryImpl) Save(ctx context.Context, tx *
     
     if err != nil {
         // Log error untuk debugging
-        fmt.Printf("ERROR: Failed to save join request: %v\n", err)
+        slog.ErrorContext(ctx, "failed to save join request", "error", err)
         helper.PanicIfError(err)
     }
     
     // Verifikasi bahwa data berhasil disimpan
     rowsAffected, err := result.RowsAffected()
     if err != nil || rowsAffected == 0 {
-        fmt.Printf("ERROR: No rows affected when saving join request: %v\n", err)
+        slog.ErrorContext(ctx, "no rows affected when saving join request", "error", err)
         helper.PanicIfError(errors.New("failed to save join request"))
     }
 
@@ -117,7 +117,7 @@ func (repository *GroupJoinRequestRepositoryImpl) FindByGroupId(ctx context.Cont
 			&request.UpdatedAt,
 		)
 		if err != nil {
-			fmt.Printf("ERROR: Failed to scan join request: %v\n", err)
+			slog.ErrorContext(ctx, "failed to scan join request", "error", err)
 			continue
 		}
 		requests = append(requests, request)
@@ -153,13 +153,13 @@ func (repository *GroupJoinRequestRepositoryImpl) Delete(ctx context.Context, tx
     
     result, err := tx.ExecContext(ctx, SQL, id)
     if err != nil {
-        fmt.Printf("ERROR: Failed to delete join request: %v\n", err)
+        slog.ErrorContext(ctx, "failed to delete join request", "error", err)
         helper.PanicIfError(err)
     }
     
     rowsAffected, err := result.RowsAffected()
     if err != nil || rowsAffected == 0 {
-        fmt.Printf("WARNING: No rows affected when deleting join request: %v\n", err)
+        slog.WarnContext(ctx, "no rows affected when deleting join request", "error", err)
         // Tidak perlu panic di sini, karena mungkin request sudah dihapus
     }
 }
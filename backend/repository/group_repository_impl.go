@@ -8,6 +8,7 @@ import (
 	"evoconnect/backend/model/domain"
 	"fmt"
 	"github.com/google/uuid"
+	"log/slog"
 	"time"
 )
 
@@ -104,8 +105,6 @@ func (repository *GroupRepositoryImpl) FindById(ctx context.Context, tx *sql.Tx,
 			FROM groups 
 			WHERE id = $1`
 
-	fmt.Printf("DEBUG SQL: %s with param: %s\n", SQL, groupId)
-
 	rows, err := tx.QueryContext(ctx, SQL, groupId)
 	if err != nil {
 		return domain.Group{}, err
@@ -144,7 +143,7 @@ func (repository *GroupRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx,
 
 	rows, err := tx.QueryContext(ctx, SQL, limit, offset)
 	if err != nil {
-		fmt.Printf("ERROR: Failed to query groups: %v\n", err)
+		slog.ErrorContext(ctx, "failed to query groups", "error", err)
 		panic(fmt.Sprintf("Failed to query groups: %v", err))
 	}
 	defer rows.Close()
@@ -168,7 +167,7 @@ func (repository *GroupRepositoryImpl) FindAll(ctx context.Context, tx *sql.Tx,
 		)
 
 		if err != nil {
-			fmt.Printf("ERROR: Failed to scan group: %v\n", err)
+			slog.ErrorContext(ctx, "failed to scan group", "error", err)
 			continue // Skip this row instead of panicking
 		}
 
@@ -426,11 +425,10 @@ func (repository *GroupRepositoryImpl) Search(ctx context.Context, tx *sql.Tx, q
             LIMIT $2 OFFSET $3`
 
 	searchPattern := "%" + query + "%"
-	fmt.Printf("Executing group search SQL with pattern: %s\n", searchPattern)
 
 	rows, err := tx.QueryContext(ctx, SQL, searchPattern, limit, offset)
 	if err != nil {
-		fmt.Printf("Error executing group search: %v\n", err)
+		slog.ErrorContext(ctx, "error executing group search", "error", err)
 		return []domain.Group{}
 	}
 	defer rows.Close()
@@ -451,7 +449,7 @@ func (repository *GroupRepositoryImpl) Search(ctx context.Context, tx *sql.Tx, q
 			&group.UpdatedAt,
 		)
 		if err != nil {
-			fmt.Printf("Error scanning group: %v\n", err)
+			slog.ErrorContext(ctx, "error scanning group", "error", err)
 			continue
 		}
 		groups = append(groups, group)
@@ -478,11 +476,10 @@ func (repository *GroupRepositoryImpl) SearchWithPrivacy(ctx context.Context, tx
             LIMIT $2 OFFSET $3`
 
 	searchPattern := "%" + query + "%"
-	fmt.Printf("Executing group search SQL with pattern: %s\n", searchPattern)
 
 	rows, err := tx.QueryContext(ctx, SQL, searchPattern, limit, offset, currentUserId)
 	if err != nil {
-		fmt.Printf("Error executing group search: %v\n", err)
+		slog.ErrorContext(ctx, "error executing group search", "error", err)
 		return []domain.Group{}
 	}
 	defer rows.Close()
@@ -503,11 +500,10 @@ func (repository *GroupRepositoryImpl) SearchWithPrivacy(ctx context.Context, tx
 			&group.UpdatedAt,
 		)
 		if err != nil {
-			fmt.Printf("Error scanning group: %v\n", err)
+			slog.ErrorContext(ctx, "error scanning group", "error", err)
 			continue
 		}
 		groups = append(groups, group)
-		fmt.Printf("Found group: %s (privacy: %s)\n", group.Name, group.PrivacyLevel)
 	}
 
 	return groups
@@ -519,7 +515,7 @@ func (repository *GroupRepositoryImpl) CountMembers(ctx context.Context, tx *sql
 	var count int
 	err := tx.QueryRowContext(ctx, SQL, groupId).Scan(&count)
 	if err != nil {
-		fmt.Printf("Error counting members: %v\n", err)
+		slog.ErrorContext(ctx, "error counting members", "error", err)
 		return 0
 	}
 
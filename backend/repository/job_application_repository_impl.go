@@ -19,15 +19,11 @@ func NewJobApplicationRepository() JobApplicationRepository {
 }
 
 func (repository *JobApplicationRepositoryImpl) Create(ctx context.Context, tx *sql.Tx, jobApplication domain.JobApplication) domain.JobApplication {
-	fmt.Printf("DEBUG: JobApplicationRepository.Create called\n")
-	fmt.Printf("DEBUG: Creating job application in repository\n")
-
 	SQL := `INSERT INTO job_applications (id, job_vacancy_id, applicant_id, cv_file_path, contact_info, 
             motivation_letter, cover_letter, expected_salary, available_start_date, status, 
             submitted_at, created_at, updated_at) 
             VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
 
-	fmt.Printf("DEBUG: About to execute INSERT query\n")
 	_, err := tx.ExecContext(ctx, SQL,
 		jobApplication.Id, jobApplication.JobVacancyId, jobApplication.ApplicantId,
 		jobApplication.CvFilePath, jobApplication.ContactInfo, jobApplication.MotivationLetter,
@@ -35,9 +31,6 @@ func (repository *JobApplicationRepositoryImpl) Create(ctx context.Context, tx *
 		jobApplication.Status, jobApplication.SubmittedAt, jobApplication.CreatedAt, jobApplication.UpdatedAt)
 
 	if err != nil {
-		fmt.Printf("DEBUG: Error in JobApplicationRepository.Create: %v\n", err)
-	} else {
-		fmt.Printf("DEBUG: JobApplicationRepository.Create completed successfully\n")
 	}
 
 	helper.PanicIfError(err)
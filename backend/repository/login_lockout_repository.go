@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/model/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LoginLockoutRepository interface {
+	FindByOwner(ctx context.Context, tx *sql.Tx, ownerType domain.AccountType, ownerId uuid.UUID) (domain.LoginLockout, error)
+	RecordFailure(ctx context.Context, tx *sql.Tx, ownerType domain.AccountType, ownerId uuid.UUID, countSince time.Time) (domain.LoginLockout, error)
+	Lock(ctx context.Context, tx *sql.Tx, ownerType domain.AccountType, ownerId uuid.UUID, until time.Time) error
+	Delete(ctx context.Context, tx *sql.Tx, ownerType domain.AccountType, ownerId uuid.UUID) error
+}
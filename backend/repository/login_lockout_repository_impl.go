@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"evoconnect/backend/model/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LoginLockoutRepositoryImpl struct{}
+
+func NewLoginLockoutRepository() LoginLockoutRepository {
+	return &LoginLockoutRepositoryImpl{}
+}
+
+func (repository *LoginLockoutRepositoryImpl) FindByOwner(ctx context.Context, tx *sql.Tx, ownerType domain.AccountType, ownerId uuid.UUID) (domain.LoginLockout, error) {
+	SQL := `SELECT owner_type, owner_id, failed_count, lockout_count, last_failed_at, locked_until
+			FROM login_lockouts WHERE owner_type = $1 AND owner_id = $2`
+	return scanLoginLockout(tx.QueryRowContext(ctx, SQL, ownerType, ownerId))
+}
+
+// RecordFailure counts one failed login. Failures before countSince are forgotten and the count starts over.
+func (repository *LoginLockoutRepositoryImpl) RecordFailure(ctx context.Context, tx *sql.Tx, ownerType domain.AccountType, ownerId uuid.UUID, countSince time.Time) (domain.LoginLockout, error) {
+	SQL := `INSERT INTO login_lockouts(owner_type, owner_id, failed_count, last_failed_at)
+			VALUES ($1, $2, 1, $3)
+			ON CONFLICT (owner_type, owner_id) DO UPDATE SET
+				failed_count = CASE WHEN login_lockouts.last_failed_at IS NULL OR login_lockouts.last_failed_at < $4
+					THEN 1 ELSE login_lockouts.failed_count + 1 END,
+				last_failed_at = EXCLUDED.last_failed_at
+			RETURNING owner_type, owner_id, failed_count, lockout_count, last_failed_at, locked_until`
+	return scanLoginLockout(tx.QueryRowContext(ctx, SQL, ownerType, ownerId, time.Now(), countSince))
+}
+
+// Lock refuses logins until the given time and starts counting failures again
+func (repository *LoginLockoutRepositoryImpl) Lock(ctx context.Context, tx *sql.Tx, ownerType domain.AccountType, ownerId uuid.UUID, until time.Time) error {
+	SQL := `UPDATE login_lockouts SET locked_until = $1, lockout_count = lockout_count + 1, failed_count = 0
+			WHERE owner_type = $2 AND owner_id = $3`
+	_, err := tx.ExecContext(ctx, SQL, until, ownerType, ownerId)
+	return err
+}
+
+func (repository *LoginLockoutRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, ownerType domain.AccountType, ownerId uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM login_lockouts WHERE owner_type = $1 AND owner_id = $2", ownerType, ownerId)
+	return err
+}
+
+func scanLoginLockout(row *sql.Row) (domain.LoginLockout, error) {
+	lockout := domain.LoginLockout{}
+	var lastFailedAt, lockedUntil sql.NullTime
+
+	err := row.Scan(
+		&lockout.OwnerType,
+		&lockout.OwnerId,
+		&lockout.FailedCount,
+		&lockout.LockoutCount,
+		&lastFailedAt,
+		&lockedUntil)
+	if err == sql.ErrNoRows {
+		return lockout, errors.New("login lockout not found")
+	}
+	if err != nil {
+		return lockout, err
+	}
+
+	if lastFailedAt.Valid {
+		lockout.LastFailedAt = &lastFailedAt.Time
+	}
+	if lockedUntil.Valid {
+		lockout.LockedUntil = &lockedUntil.Time
+	}
+
+	return lockout, nil
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/model/domain"
+)
+
+type OAuthStateRepository interface {
+	Save(ctx context.Context, tx *sql.Tx, state domain.OAuthState) (domain.OAuthState, error)
+	Consume(ctx context.Context, tx *sql.Tx, stateHash string, provider string) (domain.OAuthState, error)
+	DeleteExpired(ctx context.Context, tx *sql.Tx) error
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"evoconnect/backend/model/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OAuthStateRepositoryImpl struct{}
+
+func NewOAuthStateRepository() OAuthStateRepository {
+	return &OAuthStateRepositoryImpl{}
+}
+
+func (repository *OAuthStateRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, state domain.OAuthState) (domain.OAuthState, error) {
+	if state.Id == uuid.Nil {
+		state.Id = uuid.New()
+	}
+	if state.CreatedAt.IsZero() {
+		state.CreatedAt = time.Now()
+	}
+
+	SQL := `INSERT INTO oauth_states(id, state_hash, provider, purpose, user_id, nonce, code_verifier, expires_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err := tx.ExecContext(ctx, SQL,
+		state.Id,
+		state.StateHash,
+		state.Provider,
+		state.Purpose,
+		state.UserId,
+		state.Nonce,
+		state.CodeVerifier,
+		state.ExpiresAt,
+		state.CreatedAt)
+
+	return state, err
+}
+
+// Consume deletes the state and returns it, so a callback can only be completed once
+func (repository *OAuthStateRepositoryImpl) Consume(ctx context.Context, tx *sql.Tx, stateHash string, provider string) (domain.OAuthState, error) {
+	SQL := `DELETE FROM oauth_states WHERE state_hash = $1 AND provider = $2 AND expires_at > $3
+			RETURNING id, state_hash, provider, purpose, user_id, nonce, code_verifier, expires_at, created_at`
+
+	state := domain.OAuthState{}
+	var userId uuid.NullUUID
+	err := tx.QueryRowContext(ctx, SQL, stateHash, provider, time.Now()).Scan(
+		&state.Id,
+		&state.StateHash,
+		&state.Provider,
+		&state.Purpose,
+		&userId,
+		&state.Nonce,
+		&state.CodeVerifier,
+		&state.ExpiresAt,
+		&state.CreatedAt)
+	if err == sql.ErrNoRows {
+		return state, errors.New("oauth state not found")
+	}
+	if err != nil {
+		return state, err
+	}
+
+	if userId.Valid {
+		state.UserId = &userId.UUID
+	}
+	return state, nil
+}
+
+func (repository *OAuthStateRepositoryImpl) DeleteExpired(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM oauth_states WHERE expires_at <= $1", time.Now())
+	return err
+}
@@ -7,8 +7,8 @@ import (
 	"errors"
 	"evoconnect/backend/helper"
 	"evoconnect/backend/model/domain"
-	"fmt"
 	"github.com/google/uuid"
+	"log/slog"
 	"time"
 )
 
@@ -354,7 +354,6 @@ func (repository *PostRepositoryImpl) FindByUserId(ctx context.Context, tx *sql.
 		helper.PanicIfError(err)
 
 		// Setelah scan
-		fmt.Printf("DEBUG: Post ID: %s, Reporter ID: %s, Is Reported: %v\n", post.Id, currentUserId, isReported)
 
 		// Set is_reported
 		post.IsReported = isReported
@@ -601,11 +600,10 @@ func (repository *PostRepositoryImpl) Search(ctx context.Context, tx *sql.Tx, qu
         LIMIT $2 OFFSET $3`
 
 	searchPattern := "%" + query + "%"
-	fmt.Printf("Executing SQL: %s with pattern: %s\n", SQL, searchPattern)
 
 	rows, err := tx.QueryContext(ctx, SQL, searchPattern, limit, offset)
 	if err != nil {
-		fmt.Printf("Error executing search query: %v\n", err)
+		slog.ErrorContext(ctx, "error executing search query", "error", err)
 		return []domain.Post{}
 	}
 	defer rows.Close()
@@ -633,13 +631,12 @@ func (repository *PostRepositoryImpl) Search(ctx context.Context, tx *sql.Tx, qu
 		)
 
 		if err != nil {
-			fmt.Printf("Error scanning post: %v\n", err)
+			slog.ErrorContext(ctx, "error scanning post", "error", err)
 			continue
 		}
 
 		post.User = &user
 		posts = append(posts, post)
-		fmt.Printf("Found post: %s by %s\n", post.Id, user.Name)
 	}
 
 	return posts
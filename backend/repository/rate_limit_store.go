@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimitStore keeps the hits counted by the rate limiter. Postgres shares them between
+// instances, memory is for a single instance or tests.
+type RateLimitStore interface {
+	Add(ctx context.Context, key string, at time.Time, window time.Duration) error
+	Hits(ctx context.Context, key string, since time.Time) ([]time.Time, error)
+	Reset(ctx context.Context, key string) error
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryRateLimitBucket struct {
+	hits   []time.Time
+	window time.Duration
+}
+
+type MemoryRateLimitStore struct {
+	mutex     sync.Mutex
+	buckets   map[string]*memoryRateLimitBucket
+	lastSweep time.Time
+}
+
+func NewMemoryRateLimitStore() RateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets:   make(map[string]*memoryRateLimitBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+func (store *MemoryRateLimitStore) Add(ctx context.Context, key string, at time.Time, window time.Duration) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	bucket, ok := store.buckets[key]
+	if !ok {
+		bucket = &memoryRateLimitBucket{}
+		store.buckets[key] = bucket
+	}
+	bucket.window = window
+	bucket.hits = append(pruneHits(bucket.hits, at.Add(-window)), at)
+
+	// Drop buckets that have not been hit for a whole window
+	if at.Sub(store.lastSweep) > time.Minute {
+		for bucketKey, candidate := range store.buckets {
+			candidate.hits = pruneHits(candidate.hits, at.Add(-candidate.window))
+			if len(candidate.hits) == 0 {
+				delete(store.buckets, bucketKey)
+			}
+		}
+		store.lastSweep = at
+	}
+
+	return nil
+}
+
+func (store *MemoryRateLimitStore) Hits(ctx context.Context, key string, since time.Time) ([]time.Time, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	bucket, ok := store.buckets[key]
+	if !ok {
+		return nil, nil
+	}
+
+	hits := pruneHits(bucket.hits, since)
+	return append([]time.Time(nil), hits...), nil
+}
+
+func (store *MemoryRateLimitStore) Reset(ctx context.Context, key string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	delete(store.buckets, key)
+	return nil
+}
+
+// pruneHits drops the hits at or before cutoff, hits are kept in ascending order
+func pruneHits(hits []time.Time, cutoff time.Time) []time.Time {
+	for len(hits) > 0 && !hits[0].After(cutoff) {
+		hits = hits[1:]
+	}
+	return hits
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"math/rand"
+	"time"
+)
+
+// Hits older than this are of no use to any rule and are swept out now and then
+const rateLimitRetention = 24 * time.Hour
+
+type PostgresRateLimitStore struct {
+	DB *sql.DB
+}
+
+func NewPostgresRateLimitStore(db *sql.DB) RateLimitStore {
+	return &PostgresRateLimitStore{DB: db}
+}
+
+func (store *PostgresRateLimitStore) Add(ctx context.Context, key string, at time.Time, window time.Duration) error {
+	_, err := store.DB.ExecContext(ctx, "INSERT INTO rate_limit_hits(bucket_key, hit_at) VALUES ($1, $2)", key, at)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.DB.ExecContext(ctx, "DELETE FROM rate_limit_hits WHERE bucket_key = $1 AND hit_at <= $2", key, at.Add(-window))
+	if err != nil {
+		return err
+	}
+
+	// Keys that are never hit again would otherwise stay forever
+	if rand.Intn(100) == 0 {
+		_, err = store.DB.ExecContext(ctx, "DELETE FROM rate_limit_hits WHERE hit_at <= $1", at.Add(-rateLimitRetention))
+	}
+	return err
+}
+
+func (store *PostgresRateLimitStore) Hits(ctx context.Context, key string, since time.Time) ([]time.Time, error) {
+	rows, err := store.DB.QueryContext(ctx,
+		"SELECT hit_at FROM rate_limit_hits WHERE bucket_key = $1 AND hit_at > $2 ORDER BY hit_at ASC", key, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hits []time.Time
+	for rows.Next() {
+		var hitAt time.Time
+		if err := rows.Scan(&hitAt); err != nil {
+			return nil, err
+		}
+		hits = append(hits, hitAt)
+	}
+
+	return hits, rows.Err()
+}
+
+func (store *PostgresRateLimitStore) Reset(ctx context.Context, key string) error {
+	_, err := store.DB.ExecContext(ctx, "DELETE FROM rate_limit_hits WHERE bucket_key = $1", key)
+	return err
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"evoconnect/backend/model/domain"
 )
 
@@ -10,5 +12,7 @@ type ReportRepository interface {
 	HasReported(ctx context.Context, reporterID, targetType, targetID string) (bool, error)
 	FindAll(ctx context.Context, page, limit int, targetType string) ([]domain.Report, int, error)
 	FindById(ctx context.Context, id string) (domain.Report, error)
-	UpdateStatus(ctx context.Context, id string, status string) (domain.Report, error)
+	UpdateStatus(ctx context.Context, tx *sql.Tx, id string, status string) (domain.Report, error)
+	// FindTargetSnapshot returns the reported row as JSON, nil when it does not exist (anymore)
+	FindTargetSnapshot(ctx context.Context, tx *sql.Tx, targetType, targetID string) (json.RawMessage, error)
 }
\ No newline at end of file
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"evoconnect/backend/model/domain"
 	"fmt"
 )
@@ -17,7 +18,6 @@ func NewReportRepository(db *sql.DB) ReportRepository {
 
 func (r *reportRepositoryImpl) HasReported(ctx context.Context, reporterID, targetType, targetID string) (bool, error) {
 	var count int
-	fmt.Printf("sedang mencari report dengan reporterID: %s, targetType: %s, targetID: %s\n", reporterID, targetType, targetID)
 	query := `SELECT COUNT(*) FROM reports WHERE reporter_id = $1 AND target_type = $2 AND target_id = $3`
 	err := r.db.QueryRowContext(ctx, query, reporterID, targetType, targetID).Scan(&count)
 	return count > 0, err
@@ -124,7 +124,7 @@ func (r *reportRepositoryImpl) FindById(ctx context.Context, id string) (domain.
 	return report, nil
 }
 
-func (r *reportRepositoryImpl) UpdateStatus(ctx context.Context, id string, status string) (domain.Report, error) {
+func (r *reportRepositoryImpl) UpdateStatus(ctx context.Context, tx *sql.Tx, id string, status string) (domain.Report, error) {
 	query := `
 		UPDATE reports
 		SET status = $1
@@ -133,7 +133,7 @@ func (r *reportRepositoryImpl) UpdateStatus(ctx context.Context, id string, stat
 	`
 
 	var report domain.Report
-	err := r.db.QueryRowContext(ctx, query, status, id).Scan(
+	err := tx.QueryRowContext(ctx, query, status, id).Scan(
 		&report.ID,
 		&report.ReporterID,
 		&report.TargetType,
@@ -150,3 +150,38 @@ func (r *reportRepositoryImpl) UpdateStatus(ctx context.Context, id string, stat
 
 	return report, nil
 }
+
+// reportTargetSnapshots lists where each report target type is stored. Users are limited to their
+// moderation columns, password hashes and tokens do not belong in the audit log.
+var reportTargetSnapshots = map[string]struct{ table, columns string }{
+	"user":                 {"users", "id, status, suspended_until"},
+	"post":                 {"posts", "*"},
+	"comment":              {"comments", "*"},
+	"blog":                 {"tb_blog", "*"},
+	"comment_blog":         {"comment_blog", "*"},
+	"group":                {"groups", "*"},
+	"company":              {"companies", "*"},
+	"company_post":         {"company_posts", "*"},
+	"company_post_comment": {"company_post_comments", "*"},
+	"vacancy_job":          {"job_vacancies", "*"},
+}
+
+func (r *reportRepositoryImpl) FindTargetSnapshot(ctx context.Context, tx *sql.Tx, targetType, targetID string) (json.RawMessage, error) {
+	target, ok := reportTargetSnapshots[targetType]
+	if !ok {
+		return nil, fmt.Errorf("unknown report target type %q", targetType)
+	}
+
+	query := fmt.Sprintf("SELECT row_to_json(t) FROM (SELECT %s FROM %s WHERE id = $1) t", target.columns, target.table)
+
+	var snapshot []byte
+	err := tx.QueryRowContext(ctx, query, targetID).Scan(&snapshot)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return json.RawMessage(snapshot), nil
+}
@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+type SystemSettingRepository interface {
+	Get(ctx context.Context, tx *sql.Tx, key string) (string, error)
+	Set(ctx context.Context, tx *sql.Tx, key string, value string) error
+}
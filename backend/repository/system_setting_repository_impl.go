@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type SystemSettingRepositoryImpl struct{}
+
+func NewSystemSettingRepository() SystemSettingRepository {
+	return &SystemSettingRepositoryImpl{}
+}
+
+func (repository *SystemSettingRepositoryImpl) Get(ctx context.Context, tx *sql.Tx, key string) (string, error) {
+	SQL := "SELECT value FROM system_settings WHERE key = $1"
+
+	var value string
+	err := tx.QueryRowContext(ctx, SQL, key).Scan(&value)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", errors.New("setting not found")
+		}
+		return "", err
+	}
+
+	return value, nil
+}
+
+func (repository *SystemSettingRepositoryImpl) Set(ctx context.Context, tx *sql.Tx, key string, value string) error {
+	SQL := `INSERT INTO system_settings(key, value, updated_at) VALUES ($1, $2, $3)
+			ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, updated_at = EXCLUDED.updated_at`
+	_, err := tx.ExecContext(ctx, SQL, key, value, time.Now())
+	return err
+}
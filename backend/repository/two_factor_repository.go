@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"evoconnect/backend/model/domain"
+
+	"github.com/google/uuid"
+)
+
+// ErrTwoFactorStepUsed is returned when a TOTP code was already accepted, possibly by a concurrent request
+var ErrTwoFactorStepUsed = errors.New("authentication code already used")
+
+type TwoFactorRepository interface {
+	Upsert(ctx context.Context, tx *sql.Tx, credential domain.TwoFactorCredential) domain.TwoFactorCredential
+	FindByOwner(ctx context.Context, tx *sql.Tx, ownerType domain.TwoFactorOwnerType, ownerId uuid.UUID) (domain.TwoFactorCredential, error)
+	Enable(ctx context.Context, tx *sql.Tx, credentialId uuid.UUID) error
+	UpdateLastUsedStep(ctx context.Context, tx *sql.Tx, credentialId uuid.UUID, step int64) error
+	Delete(ctx context.Context, tx *sql.Tx, credentialId uuid.UUID) error
+	ReplaceRecoveryCodes(ctx context.Context, tx *sql.Tx, credentialId uuid.UUID, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, tx *sql.Tx, credentialId uuid.UUID, codeHash string) (bool, error)
+	CountUnusedRecoveryCodes(ctx context.Context, tx *sql.Tx, credentialId uuid.UUID) (int, error)
+}
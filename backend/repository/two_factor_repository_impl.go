@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TwoFactorRepositoryImpl struct{}
+
+func NewTwoFactorRepository() TwoFactorRepository {
+	return &TwoFactorRepositoryImpl{}
+}
+
+// Upsert stores a new (not yet enabled) secret, replacing any previous credential of the owner
+func (repository *TwoFactorRepositoryImpl) Upsert(ctx context.Context, tx *sql.Tx, credential domain.TwoFactorCredential) domain.TwoFactorCredential {
+	if credential.Id == uuid.Nil {
+		credential.Id = uuid.New()
+	}
+
+	SQL := `INSERT INTO two_factor_credentials(id, owner_type, owner_id, secret, last_used_step, enabled_at, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, 0, NULL, $5, $6)
+			ON CONFLICT (owner_type, owner_id)
+			DO UPDATE SET secret = EXCLUDED.secret, last_used_step = 0, enabled_at = NULL, updated_at = EXCLUDED.updated_at
+			RETURNING id`
+	err := tx.QueryRowContext(ctx, SQL,
+		credential.Id,
+		credential.OwnerType,
+		credential.OwnerId,
+		credential.Secret,
+		credential.CreatedAt,
+		credential.UpdatedAt).Scan(&credential.Id)
+	helper.PanicIfError(err)
+
+	// Recovery codes of a replaced secret are no longer valid
+	_, err = tx.ExecContext(ctx, "DELETE FROM two_factor_recovery_codes WHERE credential_id = $1", credential.Id)
+	helper.PanicIfError(err)
+
+	credential.EnabledAt = nil
+	credential.LastUsedStep = 0
+	return credential
+}
+
+func (repository *TwoFactorRepositoryImpl) FindByOwner(ctx context.Context, tx *sql.Tx, ownerType domain.TwoFactorOwnerType, ownerId uuid.UUID) (domain.TwoFactorCredential, error) {
+	SQL := `SELECT id, owner_type, owner_id, secret, last_used_step, enabled_at, created_at, updated_at
+			FROM two_factor_credentials WHERE owner_type = $1 AND owner_id = $2`
+
+	credential := domain.TwoFactorCredential{}
+	var enabledAt sql.NullTime
+	err := tx.QueryRowContext(ctx, SQL, ownerType, ownerId).Scan(
+		&credential.Id,
+		&credential.OwnerType,
+		&credential.OwnerId,
+		&credential.Secret,
+		&credential.LastUsedStep,
+		&enabledAt,
+		&credential.CreatedAt,
+		&credential.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return credential, errors.New("two-factor credential not found")
+		}
+		return credential, err
+	}
+
+	if enabledAt.Valid {
+		credential.EnabledAt = &enabledAt.Time
+	}
+
+	return credential, nil
+}
+
+func (repository *TwoFactorRepositoryImpl) Enable(ctx context.Context, tx *sql.Tx, credentialId uuid.UUID) error {
+	SQL := "UPDATE two_factor_credentials SET enabled_at = $1, updated_at = $1 WHERE id = $2"
+	_, err := tx.ExecContext(ctx, SQL, time.Now(), credentialId)
+	return err
+}
+
+// UpdateLastUsedStep records the time step of an accepted code. The step only moves forward, so of
+// concurrent requests with the same code one updates the row and the others get ErrTwoFactorStepUsed.
+func (repository *TwoFactorRepositoryImpl) UpdateLastUsedStep(ctx context.Context, tx *sql.Tx, credentialId uuid.UUID, step int64) error {
+	SQL := `UPDATE two_factor_credentials SET last_used_step = $1, updated_at = $2
+			WHERE id = $3 AND (last_used_step IS NULL OR last_used_step < $1)`
+
+	result, err := tx.ExecContext(ctx, SQL, step, time.Now(), credentialId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected != 1 {
+		return ErrTwoFactorStepUsed
+	}
+
+	return nil
+}
+
+func (repository *TwoFactorRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, credentialId uuid.UUID) error {
+	SQL := "DELETE FROM two_factor_credentials WHERE id = $1"
+	_, err := tx.ExecContext(ctx, SQL, credentialId)
+	return err
+}
+
+func (repository *TwoFactorRepositoryImpl) ReplaceRecoveryCodes(ctx context.Context, tx *sql.Tx, credentialId uuid.UUID, codeHashes []string) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM two_factor_recovery_codes WHERE credential_id = $1", credentialId)
+	if err != nil {
+		return err
+	}
+
+	SQL := "INSERT INTO two_factor_recovery_codes(id, credential_id, code_hash, created_at) VALUES ($1, $2, $3, $4)"
+	for _, codeHash := range codeHashes {
+		_, err = tx.ExecContext(ctx, SQL, uuid.New(), credentialId, codeHash, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UseRecoveryCode marks a matching unused code as used and reports whether one was found
+func (repository *TwoFactorRepositoryImpl) UseRecoveryCode(ctx context.Context, tx *sql.Tx, credentialId uuid.UUID, codeHash string) (bool, error) {
+	SQL := `UPDATE two_factor_recovery_codes SET used_at = $1
+			WHERE credential_id = $2 AND code_hash = $3 AND used_at IS NULL`
+	result, err := tx.ExecContext(ctx, SQL, time.Now(), credentialId, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func (repository *TwoFactorRepositoryImpl) CountUnusedRecoveryCodes(ctx context.Context, tx *sql.Tx, credentialId uuid.UUID) (int, error) {
+	SQL := "SELECT COUNT(*) FROM two_factor_recovery_codes WHERE credential_id = $1 AND used_at IS NULL"
+
+	var count int
+	err := tx.QueryRowContext(ctx, SQL, credentialId).Scan(&count)
+	return count, err
+}
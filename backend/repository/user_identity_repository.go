@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/model/domain"
+
+	"github.com/google/uuid"
+)
+
+type UserIdentityRepository interface {
+	Save(ctx context.Context, tx *sql.Tx, identity domain.UserIdentity) (domain.UserIdentity, error)
+	FindByProviderSubject(ctx context.Context, tx *sql.Tx, provider string, subject string) (domain.UserIdentity, error)
+	FindByUserIdAndProvider(ctx context.Context, tx *sql.Tx, userId uuid.UUID, provider string) (domain.UserIdentity, error)
+	FindByUserId(ctx context.Context, tx *sql.Tx, userId uuid.UUID) ([]domain.UserIdentity, error)
+	UpdateLastLogin(ctx context.Context, tx *sql.Tx, identityId uuid.UUID, email string) error
+	Delete(ctx context.Context, tx *sql.Tx, userId uuid.UUID, provider string) error
+}
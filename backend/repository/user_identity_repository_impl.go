@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"evoconnect/backend/model/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserIdentityRepositoryImpl struct{}
+
+func NewUserIdentityRepository() UserIdentityRepository {
+	return &UserIdentityRepositoryImpl{}
+}
+
+const userIdentityColumns = `id, user_id, provider, subject, email, created_at, last_login_at`
+
+func (repository *UserIdentityRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, identity domain.UserIdentity) (domain.UserIdentity, error) {
+	if identity.Id == uuid.Nil {
+		identity.Id = uuid.New()
+	}
+	if identity.CreatedAt.IsZero() {
+		identity.CreatedAt = time.Now()
+	}
+
+	SQL := `INSERT INTO user_identities(id, user_id, provider, subject, email, created_at, last_login_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := tx.ExecContext(ctx, SQL,
+		identity.Id,
+		identity.UserId,
+		identity.Provider,
+		identity.Subject,
+		sql.NullString{String: identity.Email, Valid: identity.Email != ""},
+		identity.CreatedAt,
+		identity.LastLoginAt)
+
+	return identity, err
+}
+
+func (repository *UserIdentityRepositoryImpl) FindByProviderSubject(ctx context.Context, tx *sql.Tx, provider string, subject string) (domain.UserIdentity, error) {
+	SQL := `SELECT ` + userIdentityColumns + ` FROM user_identities WHERE provider = $1 AND subject = $2`
+	return scanUserIdentity(tx.QueryRowContext(ctx, SQL, provider, subject))
+}
+
+func (repository *UserIdentityRepositoryImpl) FindByUserIdAndProvider(ctx context.Context, tx *sql.Tx, userId uuid.UUID, provider string) (domain.UserIdentity, error) {
+	SQL := `SELECT ` + userIdentityColumns + ` FROM user_identities WHERE user_id = $1 AND provider = $2`
+	return scanUserIdentity(tx.QueryRowContext(ctx, SQL, userId, provider))
+}
+
+func (repository *UserIdentityRepositoryImpl) FindByUserId(ctx context.Context, tx *sql.Tx, userId uuid.UUID) ([]domain.UserIdentity, error) {
+	SQL := `SELECT ` + userIdentityColumns + ` FROM user_identities WHERE user_id = $1 ORDER BY created_at`
+	rows, err := tx.QueryContext(ctx, SQL, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var identities []domain.UserIdentity
+	for rows.Next() {
+		identity, err := scanUserIdentity(rows)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+
+	return identities, rows.Err()
+}
+
+// UpdateLastLogin also refreshes the email, providers let users change it
+func (repository *UserIdentityRepositoryImpl) UpdateLastLogin(ctx context.Context, tx *sql.Tx, identityId uuid.UUID, email string) error {
+	SQL := `UPDATE user_identities SET last_login_at = $1, email = COALESCE($2, email) WHERE id = $3`
+	_, err := tx.ExecContext(ctx, SQL, time.Now(), sql.NullString{String: email, Valid: email != ""}, identityId)
+	return err
+}
+
+func (repository *UserIdentityRepositoryImpl) Delete(ctx context.Context, tx *sql.Tx, userId uuid.UUID, provider string) error {
+	result, err := tx.ExecContext(ctx, "DELETE FROM user_identities WHERE user_id = $1 AND provider = $2", userId, provider)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("identity not found")
+	}
+	return nil
+}
+
+func scanUserIdentity(row interface {
+	Scan(dest ...interface{}) error
+}) (domain.UserIdentity, error) {
+	identity := domain.UserIdentity{}
+	var email sql.NullString
+	var lastLoginAt sql.NullTime
+
+	err := row.Scan(
+		&identity.Id,
+		&identity.UserId,
+		&identity.Provider,
+		&identity.Subject,
+		&email,
+		&identity.CreatedAt,
+		&lastLoginAt)
+	if err == sql.ErrNoRows {
+		return identity, errors.New("identity not found")
+	}
+	if err != nil {
+		return identity, err
+	}
+
+	identity.Email = email.String
+	if lastLoginAt.Valid {
+		identity.LastLoginAt = &lastLoginAt.Time
+	}
+
+	return identity, nil
+}
@@ -20,13 +20,24 @@ type UserRepository interface {
 	VerifyEmail(ctx context.Context, tx *sql.Tx, token string) (domain.User, error)
 	SaveResetToken(ctx context.Context, tx *sql.Tx, email string, token string, expires time.Time) error
 	FindByResetToken(ctx context.Context, tx *sql.Tx, token string) (domain.User, error)
+	SaveMagicLinkToken(ctx context.Context, tx *sql.Tx, userId uuid.UUID, tokenHash string, expires time.Time) error
+	ConsumeMagicLinkToken(ctx context.Context, tx *sql.Tx, tokenHash string) (domain.User, error)
+	ClearMagicLinkToken(ctx context.Context, tx *sql.Tx, userId uuid.UUID) error
 	FindByVerificationToken(ctx context.Context, tx *sql.Tx, token string) (domain.User, error)
 	UpdatePassword(ctx context.Context, tx *sql.Tx, userId uuid.UUID, hashedPassword string) error
-	GetFailedAttempts(ctx context.Context, tx *sql.Tx, clientIP string, actionType string, window time.Duration) (int, error)
-	LogFailedAttempt(ctx context.Context, tx *sql.Tx, clientIP string, actionType string, token string) error
-	ClearFailedAttempts(ctx context.Context, tx *sql.Tx, userID uuid.UUID, actionType string) error
-	IsRateLimited(ctx context.Context, tx *sql.Tx, clientIP string, actionType string, maxAttempts int, window time.Duration) (bool, error)
+	SetPasswordChangeRequired(ctx context.Context, tx *sql.Tx, userId uuid.UUID, required bool) error
+	IsPasswordChangeRequired(ctx context.Context, tx *sql.Tx, userId uuid.UUID) (bool, error)
 	UpdateVerificationStatus(ctx context.Context, tx *sql.Tx, userId uuid.UUID, isVerified bool) error
+	SaveEmailChange(ctx context.Context, tx *sql.Tx, userId uuid.UUID, pendingEmail string, confirmTokenHash string, cancelTokenHash string, expires time.Time) error
+	FindEmailChange(ctx context.Context, tx *sql.Tx, userId uuid.UUID) (domain.UserEmailChange, error)
+	FindEmailChangeByConfirmToken(ctx context.Context, tx *sql.Tx, tokenHash string) (domain.UserEmailChange, error)
+	FindEmailChangeByCancelToken(ctx context.Context, tx *sql.Tx, tokenHash string) (domain.UserEmailChange, error)
+	ApplyEmailChange(ctx context.Context, tx *sql.Tx, userId uuid.UUID) error
+	ClearEmailChange(ctx context.Context, tx *sql.Tx, userId uuid.UUID) error
 	FindUsersNotConnectedWith(ctx context.Context, tx *sql.Tx, currentUserId uuid.UUID, limit int, offset int) ([]domain.User, error)
+	FindAccountStatus(ctx context.Context, tx *sql.Tx, userId uuid.UUID) (domain.UserAccountStatus, error)
+	UpdateAccountStatus(ctx context.Context, tx *sql.Tx, userId uuid.UUID, status domain.UserStatus, suspendedUntil *time.Time) error
+	ScheduleDeletion(ctx context.Context, tx *sql.Tx, userId uuid.UUID, scheduledFor time.Time) error
+	CancelDeletion(ctx context.Context, tx *sql.Tx, userId uuid.UUID) (bool, error)
 	Search(ctx context.Context, tx *sql.Tx, query string, limit int, offset int, currentUserId uuid.UUID) []domain.User
 }
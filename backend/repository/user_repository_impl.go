@@ -6,7 +6,7 @@ import (
 	"errors"
 	"evoconnect/backend/helper"
 	"evoconnect/backend/model/domain"
-	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
@@ -206,7 +206,8 @@ func (repository *UserRepositoryImpl) FindByUsername(ctx context.Context, tx *sq
 }
 
 func (repository *UserRepositoryImpl) SaveVerificationToken(ctx context.Context, tx *sql.Tx, userId uuid.UUID, token string, expires time.Time) error {
-	SQL := "UPDATE users SET verification_token = $1, verification_expires = $2 WHERE id = $3"
+	// A new verification code replaces the link of a pending email change, which shares the column
+	SQL := "UPDATE users SET verification_token = $1, verification_expires = $2, pending_email = NULL, email_change_cancel_token = NULL WHERE id = $3"
 	_, err := tx.ExecContext(ctx, SQL, token, expires, userId)
 	return err
 }
@@ -260,6 +261,33 @@ func (repository *UserRepositoryImpl) FindByResetToken(ctx context.Context, tx *
 	}
 }
 
+func (repository *UserRepositoryImpl) SaveMagicLinkToken(ctx context.Context, tx *sql.Tx, userId uuid.UUID, tokenHash string, expires time.Time) error {
+	SQL := "UPDATE users SET magic_link_token = $1, magic_link_expires = $2 WHERE id = $3"
+	_, err := tx.ExecContext(ctx, SQL, tokenHash, expires, userId)
+	return err
+}
+
+// ConsumeMagicLinkToken clears the token in the same statement that finds it, so a link works once
+// even when it is opened twice at the same time
+func (repository *UserRepositoryImpl) ConsumeMagicLinkToken(ctx context.Context, tx *sql.Tx, tokenHash string) (domain.User, error) {
+	SQL := "UPDATE users SET magic_link_token = NULL, magic_link_expires = NULL WHERE magic_link_token = $1 AND magic_link_expires > $2 RETURNING id, name, email, password, is_verified, created_at, updated_at"
+
+	var user domain.User
+	err := tx.QueryRowContext(ctx, SQL, tokenHash, time.Now()).Scan(&user.Id, &user.Name, &user.Email, &user.Password, &user.IsVerified, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return user, errors.New("invalid or expired sign-in link")
+	}
+
+	return user, nil
+}
+
+// ClearMagicLinkToken invalidates a sign-in link that was sent but not used yet
+func (repository *UserRepositoryImpl) ClearMagicLinkToken(ctx context.Context, tx *sql.Tx, userId uuid.UUID) error {
+	SQL := "UPDATE users SET magic_link_token = NULL, magic_link_expires = NULL WHERE id = $1"
+	_, err := tx.ExecContext(ctx, SQL, userId)
+	return err
+}
+
 func (repository *UserRepositoryImpl) FindByVerificationToken(ctx context.Context, tx *sql.Tx, token string) (domain.User, error) {
 	SQL := "SELECT id, name, email, password, is_verified, verification_token, verification_expires, created_at, updated_at FROM users WHERE verification_token = $1 AND verification_expires > $2"
 	rows, err := tx.QueryContext(ctx, SQL, token, time.Now())
@@ -276,65 +304,137 @@ func (repository *UserRepositoryImpl) FindByVerificationToken(ctx context.Contex
 	}
 }
 
+// UpdatePassword also invalidates outstanding reset and sign-in links, they were sent for the old credentials
 func (repository *UserRepositoryImpl) UpdatePassword(ctx context.Context, tx *sql.Tx, userId uuid.UUID, hashedPassword string) error {
-	SQL := "UPDATE users SET password = $1, reset_token = NULL, reset_expires = NULL, updated_at = $2 WHERE id = $3"
+	SQL := "UPDATE users SET password = $1, reset_token = NULL, reset_expires = NULL, magic_link_token = NULL, magic_link_expires = NULL, password_change_required = false, updated_at = $2 WHERE id = $3"
 	_, err := tx.ExecContext(ctx, SQL, hashedPassword, time.Now(), userId)
 	return err
 }
 
-// GetFailedAttempts retrieves the count of failed attempts for a specific IP and action type
-func (repository *UserRepositoryImpl) GetFailedAttempts(ctx context.Context, tx *sql.Tx, clientIP string, actionType string, window time.Duration) (int, error) {
-	SQL := "SELECT COUNT(*) FROM failed_attempts WHERE ip_address = $1 AND action_type = $2 AND attempt_time > $3"
-	windowStart := time.Now().Add(-window)
+func (repository *UserRepositoryImpl) SetPasswordChangeRequired(ctx context.Context, tx *sql.Tx, userId uuid.UUID, required bool) error {
+	SQL := "UPDATE users SET password_change_required = $1 WHERE id = $2"
+	_, err := tx.ExecContext(ctx, SQL, required, userId)
+	return err
+}
 
-	var count int
-	err := tx.QueryRowContext(ctx, SQL, clientIP, actionType, windowStart).Scan(&count)
-	if err != nil {
-		return 0, err
-	}
+func (repository *UserRepositoryImpl) IsPasswordChangeRequired(ctx context.Context, tx *sql.Tx, userId uuid.UUID) (bool, error) {
+	SQL := "SELECT password_change_required FROM users WHERE id = $1"
+	var required bool
+	err := tx.QueryRowContext(ctx, SQL, userId).Scan(&required)
+	return required, err
+}
 
-	return count, nil
+func (repository *UserRepositoryImpl) UpdateVerificationStatus(ctx context.Context, tx *sql.Tx, userId uuid.UUID, isVerified bool) error {
+	SQL := "UPDATE users SET is_verified = $1, verification_token = NULL, verification_expires = NULL WHERE id = $2"
+	_, err := tx.ExecContext(ctx, SQL, isVerified, userId)
+	return err
 }
 
-// LogFailedAttempt records a failed attempt for rate limiting
-func (repository *UserRepositoryImpl) LogFailedAttempt(ctx context.Context, tx *sql.Tx, clientIP string, actionType string, token string) error {
-	SQL := "INSERT INTO failed_attempts(ip_address, action_type, token, attempt_time) VALUES ($1, $2, $3, $4)"
-	_, err := tx.ExecContext(ctx, SQL, clientIP, actionType, token, time.Now())
+// SaveEmailChange keeps the confirmation link in verification_token, like the verification code
+// it replaces. Only hashes of the link tokens are stored.
+func (repository *UserRepositoryImpl) SaveEmailChange(ctx context.Context, tx *sql.Tx, userId uuid.UUID, pendingEmail string, confirmTokenHash string, cancelTokenHash string, expires time.Time) error {
+	SQL := `UPDATE users SET pending_email = $1, verification_token = $2, verification_expires = $3,
+			email_change_cancel_token = $4 WHERE id = $5`
+	_, err := tx.ExecContext(ctx, SQL, pendingEmail, confirmTokenHash, expires, cancelTokenHash, userId)
 	return err
 }
 
-// ClearFailedAttempts removes rate limiting entries for a user
-func (repository *UserRepositoryImpl) ClearFailedAttempts(ctx context.Context, tx *sql.Tx, userID uuid.UUID, actionType string) error {
-	// First, find the user's associated attempts (by getting their email)
-	userSQL := "SELECT email FROM users WHERE id = $1"
-	var email string
-	err := tx.QueryRowContext(ctx, userSQL, userID).Scan(&email)
-	if err != nil {
-		return err
-	}
+func (repository *UserRepositoryImpl) FindEmailChange(ctx context.Context, tx *sql.Tx, userId uuid.UUID) (domain.UserEmailChange, error) {
+	SQL := `SELECT id, name, email, pending_email, verification_expires FROM users
+			WHERE id = $1 AND pending_email IS NOT NULL AND verification_expires > $2`
+	return scanEmailChange(tx.QueryRowContext(ctx, SQL, userId, time.Now()))
+}
+
+func (repository *UserRepositoryImpl) FindEmailChangeByConfirmToken(ctx context.Context, tx *sql.Tx, tokenHash string) (domain.UserEmailChange, error) {
+	SQL := `SELECT id, name, email, pending_email, verification_expires FROM users
+			WHERE verification_token = $1 AND verification_expires > $2 AND pending_email IS NOT NULL
+			FOR UPDATE`
+	return scanEmailChange(tx.QueryRowContext(ctx, SQL, tokenHash, time.Now()))
+}
+
+// FindEmailChangeByCancelToken ignores the expiry, cancelling an expired change does no harm
+func (repository *UserRepositoryImpl) FindEmailChangeByCancelToken(ctx context.Context, tx *sql.Tx, tokenHash string) (domain.UserEmailChange, error) {
+	SQL := `SELECT id, name, email, pending_email, verification_expires FROM users
+			WHERE email_change_cancel_token = $1 AND pending_email IS NOT NULL
+			FOR UPDATE`
+	return scanEmailChange(tx.QueryRowContext(ctx, SQL, tokenHash))
+}
+
+// ApplyEmailChange swaps in the pending email, which counts as verified since the link reached it.
+// A sign-in link sent to the old address stops working.
+func (repository *UserRepositoryImpl) ApplyEmailChange(ctx context.Context, tx *sql.Tx, userId uuid.UUID) error {
+	SQL := `UPDATE users SET email = pending_email, is_verified = true, pending_email = NULL,
+			verification_token = NULL, verification_expires = NULL, email_change_cancel_token = NULL,
+			magic_link_token = NULL, magic_link_expires = NULL,
+			updated_at = $1 WHERE id = $2 AND pending_email IS NOT NULL`
+	_, err := tx.ExecContext(ctx, SQL, time.Now(), userId)
+	return err
+}
 
-	// Then clear any attempts related to this user's email
-	SQL := "DELETE FROM failed_attempts WHERE (ip_address = $1 OR token IN (SELECT reset_token FROM users WHERE id = $2) OR token IN (SELECT verification_token FROM users WHERE id = $2))"
-	_, err = tx.ExecContext(ctx, SQL, email, userID)
+func (repository *UserRepositoryImpl) ClearEmailChange(ctx context.Context, tx *sql.Tx, userId uuid.UUID) error {
+	SQL := `UPDATE users SET pending_email = NULL, verification_token = NULL, verification_expires = NULL,
+			email_change_cancel_token = NULL WHERE id = $1 AND pending_email IS NOT NULL`
+	_, err := tx.ExecContext(ctx, SQL, userId)
 	return err
 }
 
-// IsRateLimited checks if a user is rate-limited based on their IP and action type
-func (repository *UserRepositoryImpl) IsRateLimited(ctx context.Context, tx *sql.Tx, clientIP string, actionType string, maxAttempts int, window time.Duration) (bool, error) {
-	count, err := repository.GetFailedAttempts(ctx, tx, clientIP, actionType, window)
+func scanEmailChange(row *sql.Row) (domain.UserEmailChange, error) {
+	change := domain.UserEmailChange{}
+	err := row.Scan(&change.UserId, &change.Name, &change.Email, &change.PendingEmail, &change.ExpiresAt)
 	if err != nil {
-		return false, err
+		if err == sql.ErrNoRows {
+			return change, errors.New("email change not found")
+		}
+		return change, err
+	}
+	return change, nil
+}
+
+func (repository *UserRepositoryImpl) FindAccountStatus(ctx context.Context, tx *sql.Tx, userId uuid.UUID) (domain.UserAccountStatus, error) {
+	SQL := "SELECT id, status, suspended_until FROM users WHERE id = $1"
+
+	accountStatus := domain.UserAccountStatus{}
+	var suspendedUntil sql.NullTime
+	err := tx.QueryRowContext(ctx, SQL, userId).Scan(&accountStatus.UserId, &accountStatus.Status, &suspendedUntil)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return accountStatus, errors.New("user not found")
+		}
+		return accountStatus, err
 	}
 
-	return count >= maxAttempts, nil
+	if suspendedUntil.Valid {
+		accountStatus.SuspendedUntil = &suspendedUntil.Time
+	}
+
+	return accountStatus, nil
 }
 
-func (repository *UserRepositoryImpl) UpdateVerificationStatus(ctx context.Context, tx *sql.Tx, userId uuid.UUID, isVerified bool) error {
-	SQL := "UPDATE users SET is_verified = $1, verification_token = NULL, verification_expires = NULL WHERE id = $2"
-	_, err := tx.ExecContext(ctx, SQL, isVerified, userId)
+func (repository *UserRepositoryImpl) UpdateAccountStatus(ctx context.Context, tx *sql.Tx, userId uuid.UUID, status domain.UserStatus, suspendedUntil *time.Time) error {
+	SQL := "UPDATE users SET status = $1, suspended_until = $2, updated_at = $3 WHERE id = $4"
+	_, err := tx.ExecContext(ctx, SQL, status, suspendedUntil, time.Now(), userId)
+	return err
+}
+
+func (repository *UserRepositoryImpl) ScheduleDeletion(ctx context.Context, tx *sql.Tx, userId uuid.UUID, scheduledFor time.Time) error {
+	SQL := "UPDATE users SET deletion_requested_at = $1, deletion_scheduled_at = $2, updated_at = $1 WHERE id = $3"
+	_, err := tx.ExecContext(ctx, SQL, time.Now(), scheduledFor, userId)
 	return err
 }
 
+// CancelDeletion clears a pending deletion and reports whether there was one
+func (repository *UserRepositoryImpl) CancelDeletion(ctx context.Context, tx *sql.Tx, userId uuid.UUID) (bool, error) {
+	SQL := `UPDATE users SET deletion_requested_at = NULL, deletion_scheduled_at = NULL, updated_at = $1
+			WHERE id = $2 AND deletion_scheduled_at IS NOT NULL AND deleted_at IS NULL`
+	result, err := tx.ExecContext(ctx, SQL, time.Now(), userId)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
 func (repository *UserRepositoryImpl) FindUsersNotConnectedWith(ctx context.Context, tx *sql.Tx, currentUserId uuid.UUID, limit int, offset int) ([]domain.User, error) {
 	query := `
         SELECT u.id, u.name, u.email, 
@@ -392,13 +492,11 @@ func (repository *UserRepositoryImpl) Search(ctx context.Context, tx *sql.Tx, qu
             ORDER BY name
             LIMIT $2 OFFSET $3`
 
-	fmt.Printf("User search params: query=%s, limit=%d, offset=%d, currentUserId=%s\n", query, limit, offset, currentUserId.String())
 	searchPattern := "%" + query + "%"
-	fmt.Printf("Search pattern: %s\n", searchPattern)
 
 	rows, err := tx.QueryContext(ctx, SQL, searchPattern, limit, offset, currentUserId)
 	if err != nil {
-		fmt.Printf("Error in user search: %v\n", err)
+		slog.ErrorContext(ctx, "error in user search", "error", err)
 		return []domain.User{}
 	}
 	defer rows.Close()
@@ -408,11 +506,10 @@ func (repository *UserRepositoryImpl) Search(ctx context.Context, tx *sql.Tx, qu
 		user := domain.User{}
 		err := rows.Scan(&user.Id, &user.Name, &user.Username, &user.Email, &user.Headline, &user.Photo, &user.CreatedAt, &user.UpdatedAt)
 		if err != nil {
-			fmt.Printf("Error scanning user: %v\n", err)
+			slog.ErrorContext(ctx, "error scanning user", "error", err)
 			continue
 		}
 		users = append(users, user)
-		fmt.Printf("Found user: %s (%s)\n", user.Name, user.Username)
 	}
 
 	return users
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"evoconnect/backend/model/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrRefreshTokenUsed is returned when a refresh token was already rotated, possibly by a concurrent request
+var ErrRefreshTokenUsed = errors.New("refresh token already used")
+
+type UserSessionRepository interface {
+	Save(ctx context.Context, tx *sql.Tx, session domain.UserSession) domain.UserSession
+	FindById(ctx context.Context, tx *sql.Tx, sessionId uuid.UUID) (domain.UserSession, error)
+	FindActiveByUserId(ctx context.Context, tx *sql.Tx, userId uuid.UUID) []domain.UserSession
+	Touch(ctx context.Context, tx *sql.Tx, sessionId uuid.UUID, ipAddress string) error
+	ExtendExpiry(ctx context.Context, tx *sql.Tx, sessionId uuid.UUID, expiresAt time.Time) error
+	Revoke(ctx context.Context, tx *sql.Tx, sessionId uuid.UUID, reason string) error
+	RevokeAllByUserId(ctx context.Context, tx *sql.Tx, userId uuid.UUID, exceptSessionId *uuid.UUID, reason string) error
+	SaveRefreshToken(ctx context.Context, tx *sql.Tx, token domain.UserRefreshToken) domain.UserRefreshToken
+	FindRefreshTokenByHash(ctx context.Context, tx *sql.Tx, tokenHash string) (domain.UserRefreshToken, error)
+	MarkRefreshTokenUsed(ctx context.Context, tx *sql.Tx, tokenId uuid.UUID) error
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserSessionRepositoryImpl struct{}
+
+func NewUserSessionRepository() UserSessionRepository {
+	return &UserSessionRepositoryImpl{}
+}
+
+func (repository *UserSessionRepositoryImpl) Save(ctx context.Context, tx *sql.Tx, session domain.UserSession) domain.UserSession {
+	if session.Id == uuid.Nil {
+		session.Id = uuid.New()
+	}
+
+	SQL := `INSERT INTO user_sessions(id, user_id, device, user_agent, ip_address, last_seen_at, expires_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := tx.ExecContext(ctx, SQL,
+		session.Id,
+		session.UserId,
+		session.Device,
+		session.UserAgent,
+		session.IpAddress,
+		session.LastSeenAt,
+		session.ExpiresAt,
+		session.CreatedAt)
+	helper.PanicIfError(err)
+
+	return session
+}
+
+func (repository *UserSessionRepositoryImpl) FindById(ctx context.Context, tx *sql.Tx, sessionId uuid.UUID) (domain.UserSession, error) {
+	SQL := `SELECT id, user_id, device, user_agent, ip_address, last_seen_at, expires_at, revoked_at, revoked_reason, created_at
+			FROM user_sessions WHERE id = $1`
+	rows, err := tx.QueryContext(ctx, SQL, sessionId)
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	if rows.Next() {
+		session, err := scanUserSession(rows)
+		helper.PanicIfError(err)
+		return session, nil
+	}
+
+	return domain.UserSession{}, errors.New("session not found")
+}
+
+func (repository *UserSessionRepositoryImpl) FindActiveByUserId(ctx context.Context, tx *sql.Tx, userId uuid.UUID) []domain.UserSession {
+	SQL := `SELECT id, user_id, device, user_agent, ip_address, last_seen_at, expires_at, revoked_at, revoked_reason, created_at
+			FROM user_sessions
+			WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+			ORDER BY last_seen_at DESC`
+	rows, err := tx.QueryContext(ctx, SQL, userId, time.Now())
+	helper.PanicIfError(err)
+	defer rows.Close()
+
+	var sessions []domain.UserSession
+	for rows.Next() {
+		session, err := scanUserSession(rows)
+		helper.PanicIfError(err)
+		sessions = append(sessions, session)
+	}
+
+	return sessions
+}
+
+func (repository *UserSessionRepositoryImpl) Touch(ctx context.Context, tx *sql.Tx, sessionId uuid.UUID, ipAddress string) error {
+	SQL := "UPDATE user_sessions SET last_seen_at = $1, ip_address = $2 WHERE id = $3"
+	_, err := tx.ExecContext(ctx, SQL, time.Now(), ipAddress, sessionId)
+	return err
+}
+
+func (repository *UserSessionRepositoryImpl) ExtendExpiry(ctx context.Context, tx *sql.Tx, sessionId uuid.UUID, expiresAt time.Time) error {
+	SQL := "UPDATE user_sessions SET expires_at = $1 WHERE id = $2"
+	_, err := tx.ExecContext(ctx, SQL, expiresAt, sessionId)
+	return err
+}
+
+func (repository *UserSessionRepositoryImpl) Revoke(ctx context.Context, tx *sql.Tx, sessionId uuid.UUID, reason string) error {
+	SQL := "UPDATE user_sessions SET revoked_at = $1, revoked_reason = $2 WHERE id = $3 AND revoked_at IS NULL"
+	_, err := tx.ExecContext(ctx, SQL, time.Now(), reason, sessionId)
+	return err
+}
+
+func (repository *UserSessionRepositoryImpl) RevokeAllByUserId(ctx context.Context, tx *sql.Tx, userId uuid.UUID, exceptSessionId *uuid.UUID, reason string) error {
+	if exceptSessionId != nil {
+		SQL := "UPDATE user_sessions SET revoked_at = $1, revoked_reason = $2 WHERE user_id = $3 AND id != $4 AND revoked_at IS NULL"
+		_, err := tx.ExecContext(ctx, SQL, time.Now(), reason, userId, *exceptSessionId)
+		return err
+	}
+
+	SQL := "UPDATE user_sessions SET revoked_at = $1, revoked_reason = $2 WHERE user_id = $3 AND revoked_at IS NULL"
+	_, err := tx.ExecContext(ctx, SQL, time.Now(), reason, userId)
+	return err
+}
+
+func (repository *UserSessionRepositoryImpl) SaveRefreshToken(ctx context.Context, tx *sql.Tx, token domain.UserRefreshToken) domain.UserRefreshToken {
+	if token.Id == uuid.Nil {
+		token.Id = uuid.New()
+	}
+
+	SQL := "INSERT INTO user_refresh_tokens(id, session_id, token_hash, expires_at, created_at) VALUES ($1, $2, $3, $4, $5)"
+	_, err := tx.ExecContext(ctx, SQL, token.Id, token.SessionId, token.TokenHash, token.ExpiresAt, token.CreatedAt)
+	helper.PanicIfError(err)
+
+	return token
+}
+
+func (repository *UserSessionRepositoryImpl) FindRefreshTokenByHash(ctx context.Context, tx *sql.Tx, tokenHash string) (domain.UserRefreshToken, error) {
+	SQL := "SELECT id, session_id, token_hash, expires_at, used_at, created_at FROM user_refresh_tokens WHERE token_hash = $1"
+
+	token := domain.UserRefreshToken{}
+	var usedAt sql.NullTime
+	err := tx.QueryRowContext(ctx, SQL, tokenHash).Scan(
+		&token.Id,
+		&token.SessionId,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&usedAt,
+		&token.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return token, errors.New("refresh token not found")
+		}
+		return token, err
+	}
+
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+
+	return token, nil
+}
+
+// MarkRefreshTokenUsed claims a refresh token for one rotation. Only the first of concurrent
+// requests updates the row, the others get ErrRefreshTokenUsed.
+func (repository *UserSessionRepositoryImpl) MarkRefreshTokenUsed(ctx context.Context, tx *sql.Tx, tokenId uuid.UUID) error {
+	SQL := "UPDATE user_refresh_tokens SET used_at = $1 WHERE id = $2 AND used_at IS NULL"
+
+	result, err := tx.ExecContext(ctx, SQL, time.Now(), tokenId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrRefreshTokenUsed
+	}
+
+	return nil
+}
+
+func scanUserSession(rows *sql.Rows) (domain.UserSession, error) {
+	session := domain.UserSession{}
+	var revokedAt sql.NullTime
+	var revokedReason sql.NullString
+
+	err := rows.Scan(
+		&session.Id,
+		&session.UserId,
+		&session.Device,
+		&session.UserAgent,
+		&session.IpAddress,
+		&session.LastSeenAt,
+		&session.ExpiresAt,
+		&revokedAt,
+		&revokedReason,
+		&session.CreatedAt)
+	if err != nil {
+		return session, err
+	}
+
+	if revokedAt.Valid {
+		session.RevokedAt = &revokedAt.Time
+	}
+	if revokedReason.Valid {
+		session.RevokedReason = &revokedReason.String
+	}
+
+	return session, nil
+}
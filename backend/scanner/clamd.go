@@ -0,0 +1,114 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// Chunk size of INSTREAM, clamd rejects a stream larger than its StreamMaxLength (25 MB by default)
+const clamdChunkSize = 64 * 1024
+
+// ClamdScanner sends files to a ClamAV daemon over TCP with the INSTREAM command
+type ClamdScanner struct {
+	Address string
+	Timeout time.Duration
+}
+
+func NewClamdScanner(address string, timeout time.Duration) *ClamdScanner {
+	return &ClamdScanner{Address: address, Timeout: timeout}
+}
+
+func (scanner *ClamdScanner) Scan(ctx context.Context, body io.Reader) (Result, error) {
+	dialer := net.Dialer{Timeout: scanner.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", scanner.Address)
+	if err != nil {
+		return Result{}, fmt.Errorf("connect to clamd: %w", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(scanner.Timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+
+	// clamd closes the connection when it refuses the stream, its reply then explains why
+	if err := writeClamdStream(conn, body); err != nil {
+		if reply, replyErr := readClamdReply(conn); replyErr == nil && reply != "" {
+			return parseClamdReply(reply)
+		}
+		return Result{}, fmt.Errorf("send file to clamd: %w", err)
+	}
+
+	reply, err := readClamdReply(conn)
+	if err != nil {
+		return Result{}, fmt.Errorf("read clamd reply: %w", err)
+	}
+	return parseClamdReply(reply)
+}
+
+// writeClamdStream sends the command and the body as length prefixed chunks, ended by an empty chunk
+func writeClamdStream(conn net.Conn, body io.Reader) error {
+	writer := bufio.NewWriterSize(conn, clamdChunkSize+4)
+	if _, err := writer.WriteString("zINSTREAM\x00"); err != nil {
+		return err
+	}
+
+	chunk := make([]byte, clamdChunkSize)
+	var size [4]byte
+	for {
+		n, err := io.ReadFull(body, chunk)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size[:], uint32(n))
+			if _, err := writer.Write(size[:]); err != nil {
+				return err
+			}
+			if _, err := writer.Write(chunk[:n]); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	binary.BigEndian.PutUint32(size[:], 0)
+	if _, err := writer.Write(size[:]); err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+func readClamdReply(conn net.Conn) (string, error) {
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && !(errors.Is(err, io.EOF) && reply != "") {
+		return "", err
+	}
+	return strings.TrimSpace(strings.TrimRight(reply, "\x00")), nil
+}
+
+// parseClamdReply reads "stream: OK", "stream: <signature> FOUND" or "<message> ERROR"
+func parseClamdReply(reply string) (Result, error) {
+	switch {
+	case strings.HasSuffix(reply, " FOUND"):
+		signature := strings.TrimSuffix(reply, " FOUND")
+		if index := strings.IndexByte(signature, ':'); index >= 0 {
+			signature = strings.TrimSpace(signature[index+1:])
+		}
+		return Result{Infected: true, Signature: signature}, nil
+	case strings.HasSuffix(reply, " OK"):
+		return Result{}, nil
+	default:
+		return Result{}, fmt.Errorf("clamd: %s", reply)
+	}
+}
@@ -0,0 +1,140 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// fakeClamd speaks enough of the clamd INSTREAM protocol to answer one scan per connection.
+// Streams longer than maxStream are refused the way clamd refuses them past StreamMaxLength.
+type fakeClamd struct {
+	listener  net.Listener
+	maxStream int
+	received  chan []byte
+}
+
+func newFakeClamd(t *testing.T, maxStream int) *fakeClamd {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	clamd := &fakeClamd{listener: listener, maxStream: maxStream, received: make(chan []byte, 1)}
+	go clamd.serve()
+	return clamd
+}
+
+func (clamd *fakeClamd) serve() {
+	for {
+		conn, err := clamd.listener.Accept()
+		if err != nil {
+			return
+		}
+		go clamd.handle(conn)
+	}
+}
+
+func (clamd *fakeClamd) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	command, err := reader.ReadString(0)
+	if err != nil || command != "zINSTREAM\x00" {
+		io.WriteString(conn, "UNKNOWN COMMAND\x00")
+		return
+	}
+
+	var body bytes.Buffer
+	var size [4]byte
+	for {
+		if _, err := io.ReadFull(reader, size[:]); err != nil {
+			return
+		}
+		length := binary.BigEndian.Uint32(size[:])
+		if length == 0 {
+			break
+		}
+		if body.Len()+int(length) > clamd.maxStream {
+			io.WriteString(conn, "INSTREAM size limit exceeded. ERROR\x00")
+			return
+		}
+		if _, err := io.CopyN(&body, reader, int64(length)); err != nil {
+			return
+		}
+	}
+	clamd.received <- body.Bytes()
+
+	if bytes.Contains(body.Bytes(), []byte(eicar)) {
+		io.WriteString(conn, "stream: Eicar-Test-Signature FOUND\x00")
+		return
+	}
+	io.WriteString(conn, "stream: OK\x00")
+}
+
+func (clamd *fakeClamd) scanner() *ClamdScanner {
+	return NewClamdScanner(clamd.listener.Addr().String(), 5*time.Second)
+}
+
+func TestClamdScannerCleanFile(t *testing.T) {
+	clamd := newFakeClamd(t, 1<<20)
+
+	// Larger than one chunk, so the stream is split
+	body := bytes.Repeat([]byte("evoconnect"), clamdChunkSize/5)
+	result, err := clamd.scanner().Scan(context.Background(), bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if result.Infected {
+		t.Fatalf("Scan() = %+v, want a clean file", result)
+	}
+	if received := <-clamd.received; !bytes.Equal(received, body) {
+		t.Fatalf("clamd received %d bytes, want the %d bytes of the file", len(received), len(body))
+	}
+}
+
+func TestClamdScannerInfectedFile(t *testing.T) {
+	clamd := newFakeClamd(t, 1<<20)
+
+	result, err := clamd.scanner().Scan(context.Background(), strings.NewReader(eicar))
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if !result.Infected || result.Signature != "Eicar-Test-Signature" {
+		t.Fatalf("Scan() = %+v, want the EICAR signature", result)
+	}
+}
+
+func TestClamdScannerStreamTooLarge(t *testing.T) {
+	clamd := newFakeClamd(t, clamdChunkSize)
+
+	body := bytes.Repeat([]byte("a"), 4*clamdChunkSize)
+	_, err := clamd.scanner().Scan(context.Background(), bytes.NewReader(body))
+	if err == nil || !strings.Contains(err.Error(), "size limit exceeded") {
+		t.Fatalf("Scan() error = %v, want the clamd refusal", err)
+	}
+}
+
+func TestClamdScannerUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	_, err = NewClamdScanner(address, time.Second).Scan(context.Background(), strings.NewReader("file"))
+	if err == nil {
+		t.Fatal("Scan() without clamd returned a verdict")
+	}
+}
@@ -0,0 +1,40 @@
+package scanner
+
+import (
+	"context"
+	"evoconnect/backend/config"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Result is the verdict on one file
+type Result struct {
+	Infected bool
+	// Name of the detected malware, empty when the file is clean
+	Signature string
+}
+
+// Scanner checks uploaded files for malware. An error means no verdict, the file should be scanned again later.
+type Scanner interface {
+	Scan(ctx context.Context, body io.Reader) (Result, error)
+}
+
+// New returns the scanner selected by the configuration
+func New(scannerConfig config.ScannerConfig) (Scanner, error) {
+	switch scannerConfig.Driver {
+	case config.ScannerDriverNone:
+		return NoopScanner{}, nil
+	case config.ScannerDriverClamd:
+		return NewClamdScanner(scannerConfig.ClamdAddress, time.Duration(scannerConfig.TimeoutSeconds)*time.Second), nil
+	default:
+		return nil, fmt.Errorf("unknown malware scanner %q", scannerConfig.Driver)
+	}
+}
+
+// NoopScanner reports every file as clean, for development and installations without a scanner
+type NoopScanner struct{}
+
+func (NoopScanner) Scan(ctx context.Context, body io.Reader) (Result, error) {
+	return Result{}, nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/config"
+	"evoconnect/backend/db/seeder"
+	"flag"
+	"log/slog"
+)
+
+func runSeedCommand(ctx context.Context, cfg config.Config, db *sql.DB, logger *slog.Logger, args []string) int {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	seedAdmin := flags.Bool("admin", true, "create the default admin when there is no admin yet")
+	seedDemo := flags.Bool("demo", false, "insert demo users, posts, blogs, groups, connections and chats")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 {
+		return usageError("seed takes no arguments")
+	}
+
+	// Demo data includes accounts with a known password, it never belongs in production
+	if *seedDemo && !cfg.Debug {
+		return usageError("seed -demo requires DEBUG_MODE=true")
+	}
+
+	if *seedAdmin {
+		seeder.SeedAdmin(db)
+	}
+	if *seedDemo {
+		seeder.SeedAllData(db)
+	}
+	return 0
+}
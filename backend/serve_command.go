@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/config"
+	"evoconnect/backend/middleware"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// runServeCommand starts the API and the background workers and blocks until ctx is cancelled
+func runServeCommand(ctx context.Context, cfg config.Config, db *sql.DB, logger *slog.Logger, args []string) int {
+	if len(args) > 0 {
+		return usageError("serve takes no arguments")
+	}
+
+	if err := requireMigrations(ctx, db, logger, cfg.Migrations); err != nil {
+		logger.Error("refusing to start", "error", err)
+		return 1
+	}
+
+	application, err := newApplication(ctx, cfg, db, logger)
+	if err != nil {
+		logger.Error("failed to initialize", "error", err)
+		return 1
+	}
+
+	// The workers also stop when the server fails to listen
+	ctx, stopWorkers := context.WithCancel(ctx)
+	defer stopWorkers()
+	var workers sync.WaitGroup
+
+	// Purge accounts whose deletion grace period has ended
+	purgeInterval := time.Duration(cfg.Accounts.PurgeIntervalMinutes) * time.Minute
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		application.accountDeletionService.RunPurgeWorker(ctx, purgeInterval)
+	}()
+
+	// Build requested data exports and remove expired archives
+	exportInterval := time.Duration(cfg.DataExport.WorkerIntervalSeconds) * time.Second
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		application.dataExportService.RunExportWorker(ctx, exportInterval)
+	}()
+
+	// Scan quarantined uploads, new uploads wake the worker right away
+	scanInterval := time.Duration(cfg.Scanner.WorkerIntervalSeconds) * time.Second
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		application.fileScanService.RunScanWorker(ctx, scanInterval)
+	}()
+
+	// Create middleware chain (only CORS needed now since auth is handled per route)
+	router := application.router
+	var handler http.Handler = router
+	handler = middleware.MetricsMiddleware(router)(handler)
+	handler = middleware.RequestIDMiddleware(logger)(handler)
+	handler = middleware.RequestContextMiddleware(handler)
+	handler = middleware.CORSMiddleware(cfg.CORS)(handler)
+
+	address := cfg.Server.Address
+
+	server := &http.Server{
+		Addr:              address,
+		Handler:           handler,
+		ReadHeaderTimeout: time.Duration(cfg.Server.ReadHeaderTimeoutSeconds) * time.Second,
+		ReadTimeout:       time.Duration(cfg.Server.ReadTimeoutSeconds) * time.Second,
+		WriteTimeout:      time.Duration(cfg.Server.WriteTimeoutSeconds) * time.Second,
+		IdleTimeout:       time.Duration(cfg.Server.IdleTimeoutSeconds) * time.Second,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Info("server starting", "address", "http://"+address)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	// SIGINT or SIGTERM cancels ctx, which stops the workers and starts the graceful shutdown
+	select {
+	case err = <-serverErr:
+		logger.Error("server stopped", "error", err)
+		stopWorkers()
+		workers.Wait()
+		return 1
+	case <-ctx.Done():
+	}
+
+	// Fail readiness first so the load balancer stops sending traffic, then drain in-flight requests
+	logger.Info("shutdown signal received, draining requests")
+	application.healthService.StartDraining()
+	time.Sleep(time.Duration(cfg.Server.ShutdownDrainDelaySeconds) * time.Second)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Server.ShutdownTimeoutSeconds)*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("graceful shutdown did not finish", "error", err)
+	}
+
+	workers.Wait()
+	logger.Info("server stopped")
+	return 0
+}
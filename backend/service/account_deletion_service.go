@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+	"evoconnect/backend/model/web"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AccountDeletionService interface {
+	RequestDeletion(ctx context.Context, userId uuid.UUID, request web.DeleteAccountRequest) web.AccountDeletionResponse
+	PurgeDueAccounts(ctx context.Context) int
+	RunPurgeWorker(ctx context.Context, interval time.Duration)
+}
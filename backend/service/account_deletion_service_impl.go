@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/exception"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/web"
+	"evoconnect/backend/repository"
+	"evoconnect/backend/utils"
+	"fmt"
+	"log/slog"
+	"path"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// Accounts are purged in batches so one run never holds many row locks
+const accountPurgeBatchSize = 50
+
+type AccountDeletionServiceImpl struct {
+	UserRepository         repository.UserRepository
+	UserSessionRepository  repository.UserSessionRepository
+	AccountPurgeRepository repository.AccountPurgeRepository
+	// How long a user can still cancel by logging in
+	GracePeriod time.Duration
+	DB          *sql.DB
+	Validate    *validator.Validate
+	Logger      *slog.Logger
+}
+
+func NewAccountDeletionService(
+	userRepository repository.UserRepository,
+	userSessionRepository repository.UserSessionRepository,
+	accountPurgeRepository repository.AccountPurgeRepository,
+	gracePeriod time.Duration,
+	db *sql.DB,
+	validate *validator.Validate,
+	logger *slog.Logger,
+) AccountDeletionService {
+	return &AccountDeletionServiceImpl{
+		UserRepository:         userRepository,
+		UserSessionRepository:  userSessionRepository,
+		AccountPurgeRepository: accountPurgeRepository,
+		GracePeriod:            gracePeriod,
+		DB:                     db,
+		Validate:               validate,
+		Logger:                 logger,
+	}
+}
+
+// RequestDeletion schedules the purge and signs the user out everywhere. Logging in again
+// before the scheduled date cancels it.
+func (service *AccountDeletionServiceImpl) RequestDeletion(ctx context.Context, userId uuid.UUID, request web.DeleteAccountRequest) web.AccountDeletionResponse {
+	err := service.Validate.Struct(request)
+	if err != nil {
+		panic(exception.NewBadRequestError("Send confirmation \"DELETE\" to delete your account"))
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	user, err := service.UserRepository.FindById(ctx, tx, userId)
+	if err != nil {
+		panic(exception.NewNotFoundError("User not found"))
+	}
+
+	scheduledFor := time.Now().Add(service.GracePeriod)
+	err = service.UserRepository.ScheduleDeletion(ctx, tx, userId, scheduledFor)
+	helper.PanicIfError(err)
+
+	err = service.UserSessionRepository.RevokeAllByUserId(ctx, tx, userId, nil, SessionRevokedDeletion)
+	helper.PanicIfError(err)
+
+	emailBody := fmt.Sprintf(`
+        <html>
+        <body>
+            <h1>Your account will be deleted</h1>
+            <p>Hello %s,</p>
+            <p>We received a request to delete your EvoConnect account. It will be permanently deleted on <strong>%s</strong>.</p>
+            <p>Changed your mind? Simply log in before that date and the deletion will be cancelled.</p>
+            <p>Best regards,<br/>The EvoConnect Team</p>
+        </body>
+        </html>
+    `, user.Name, scheduledFor.Format("January 2, 2006"))
+
+	// The deletion is already scheduled, a failed notice should not undo it
+	if err := helper.EmailSender(user.Email, "Your EvoConnect account will be deleted", emailBody); err != nil {
+		service.Logger.WarnContext(ctx, "failed to send account deletion email", "user_id", userId, "error", err)
+	}
+
+	return web.AccountDeletionResponse{
+		Message:      "Your account will be deleted. Log in again before the scheduled date to cancel.",
+		ScheduledFor: scheduledFor,
+	}
+}
+
+// PurgeDueAccounts erases every account whose grace period is over and returns how many were purged
+func (service *AccountDeletionServiceImpl) PurgeDueAccounts(ctx context.Context) int {
+	purged := 0
+
+	for {
+		tx, err := service.DB.Begin()
+		if err != nil {
+			service.Logger.ErrorContext(ctx, "account purge failed to begin transaction", "error", err)
+			return purged
+		}
+		userIds, err := service.AccountPurgeRepository.FindDueUserIds(ctx, tx, time.Now(), accountPurgeBatchSize)
+		tx.Rollback()
+		if err != nil {
+			service.Logger.ErrorContext(ctx, "account purge failed to find due accounts", "error", err)
+			return purged
+		}
+
+		batchPurged := 0
+		for _, userId := range userIds {
+			done, err := service.purgeAccount(ctx, userId)
+			if err != nil {
+				service.Logger.ErrorContext(ctx, "account purge failed", "user_id", userId, "error", err)
+				continue
+			}
+			if done {
+				batchPurged++
+			}
+		}
+		purged += batchPurged
+
+		// Stop when the queue is drained or only failing accounts are left
+		if len(userIds) < accountPurgeBatchSize || batchPurged == 0 {
+			return purged
+		}
+	}
+}
+
+// RunPurgeWorker purges due accounts every interval until ctx is cancelled
+func (service *AccountDeletionServiceImpl) RunPurgeWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if purged := service.PurgeDueAccounts(ctx); purged > 0 {
+			service.Logger.InfoContext(ctx, "accounts purged", "count", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeAccount anonymizes one user in a single transaction and removes their files after it commits
+func (service *AccountDeletionServiceImpl) purgeAccount(ctx context.Context, userId uuid.UUID) (bool, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	due, err := service.AccountPurgeRepository.LockDueUser(ctx, tx, userId, time.Now())
+	if err != nil || !due {
+		return false, err
+	}
+
+	// Groups go to the next admin, moderator or member and are closed when nobody is left
+	groupIds, err := service.AccountPurgeRepository.FindOwnedGroupIds(ctx, tx, userId)
+	if err != nil {
+		return false, err
+	}
+	for _, groupId := range groupIds {
+		successorId, err := service.AccountPurgeRepository.FindGroupSuccessor(ctx, tx, groupId, userId)
+		if err != nil {
+			return false, err
+		}
+		if successorId != nil {
+			err = service.AccountPurgeRepository.TransferGroup(ctx, tx, groupId, *successorId)
+		} else {
+			err = service.AccountPurgeRepository.DeleteGroup(ctx, tx, groupId)
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+
+	// Companies go to another company admin and are taken down otherwise
+	companyIds, err := service.AccountPurgeRepository.FindOwnedCompanyIds(ctx, tx, userId)
+	if err != nil {
+		return false, err
+	}
+	for _, companyId := range companyIds {
+		successorId, err := service.AccountPurgeRepository.FindCompanySuccessor(ctx, tx, companyId, userId)
+		if err != nil {
+			return false, err
+		}
+		if successorId != nil {
+			err = service.AccountPurgeRepository.TransferCompany(ctx, tx, companyId, *successorId)
+		} else {
+			err = service.AccountPurgeRepository.CloseCompany(ctx, tx, companyId)
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+
+	err = service.AccountPurgeRepository.DeletePersonalData(ctx, tx, userId)
+	if err != nil {
+		return false, err
+	}
+
+	// The row stays so chat messages and posts keep a valid author, shown as a deleted user
+	passwordHash, err := utils.HashPassword(helper.GenerateSecureToken(32))
+	if err != nil {
+		return false, err
+	}
+	err = service.AccountPurgeRepository.AnonymizeUser(ctx, tx, userId,
+		fmt.Sprintf("deleted-%s@deleted.invalid", userId),
+		fmt.Sprintf("deleted-%s", userId),
+		passwordHash,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	for _, dir := range []string{helper.DirUsers, helper.DirEducation, helper.DirExperience, helper.DirCVStorage} {
+		uploads := path.Join("uploads", dir, userId.String()) + "/"
+		// CVs still waiting for their scan sit under the quarantine copy of the prefix
+		for _, prefix := range []string{uploads, helper.QuarantineKey(uploads)} {
+			if err := helper.FileStorage().DeletePrefix(ctx, prefix); err != nil {
+				service.Logger.WarnContext(ctx, "account purge failed to remove uploads", "user_id", userId, "prefix", prefix, "error", err)
+			}
+		}
+	}
+	if err := helper.FileStorage().DeletePrefix(ctx, dataExportUserPrefix(userId)); err != nil {
+		service.Logger.WarnContext(ctx, "account purge failed to remove data exports", "user_id", userId, "error", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AccountStatusService interface {
+	CheckAccountStatus(ctx context.Context, userId uuid.UUID) error
+	CheckAccountStatusInTx(ctx context.Context, tx *sql.Tx, userId uuid.UUID) error
+	FindUserId(ctx context.Context, emailOrId string) (uuid.UUID, error)
+	Suspend(ctx context.Context, userId uuid.UUID, until *time.Time) error
+	Unsuspend(ctx context.Context, userId uuid.UUID) error
+	MarkVerified(ctx context.Context, userId uuid.UUID) error
+}
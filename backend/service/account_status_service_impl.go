@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"evoconnect/backend/exception"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/domain"
+	"evoconnect/backend/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AccountStatusServiceImpl struct {
+	UserRepository  repository.UserRepository
+	AuditLogService AuditLogService
+	DB              *sql.DB
+}
+
+func NewAccountStatusService(userRepository repository.UserRepository, auditLogService AuditLogService, db *sql.DB) AccountStatusService {
+	return &AccountStatusServiceImpl{
+		UserRepository:  userRepository,
+		AuditLogService: auditLogService,
+		DB:              db,
+	}
+}
+
+// CheckAccountStatus is the single account-state check shared by login, Google auth
+// and the user auth middleware. It returns exception.AccountRestrictedError for banned
+// users and for suspensions that have not expired yet, and lifts expired suspensions.
+func (service *AccountStatusServiceImpl) CheckAccountStatus(ctx context.Context, userId uuid.UUID) error {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	return service.CheckAccountStatusInTx(ctx, tx, userId)
+}
+
+// CheckAccountStatusInTx is CheckAccountStatus for callers that already hold a lock on the user row,
+// restoring an expired suspension from a second transaction would wait on that lock forever
+func (service *AccountStatusServiceImpl) CheckAccountStatusInTx(ctx context.Context, tx *sql.Tx, userId uuid.UUID) error {
+	accountStatus, err := service.UserRepository.FindAccountStatus(ctx, tx, userId)
+	if err != nil {
+		return err
+	}
+
+	switch accountStatus.Status {
+	case domain.UserStatusBanned, domain.UserStatusDeleted:
+		return exception.NewAccountRestrictedError(string(accountStatus.Status), nil)
+
+	case domain.UserStatusSuspended:
+		// A suspension without an end date stays in place until an admin lifts it
+		if accountStatus.SuspendedUntil == nil || time.Now().Before(*accountStatus.SuspendedUntil) {
+			return exception.NewAccountRestrictedError(string(domain.UserStatusSuspended), accountStatus.SuspendedUntil)
+		}
+
+		// Suspension has expired, restore the account
+		err = service.UserRepository.UpdateAccountStatus(ctx, tx, userId, domain.UserStatusActive, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FindUserId accepts either a user id or an email address
+func (service *AccountStatusServiceImpl) FindUserId(ctx context.Context, emailOrId string) (uuid.UUID, error) {
+	if userId, err := uuid.Parse(emailOrId); err == nil {
+		return userId, nil
+	}
+
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	user, err := service.UserRepository.FindByEmail(ctx, tx, emailOrId)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return user.Id, nil
+}
+
+// Suspend restricts the account until the given time, or until it is lifted when until is nil
+func (service *AccountStatusServiceImpl) Suspend(ctx context.Context, userId uuid.UUID, until *time.Time) error {
+	return service.changeStatus(ctx, userId, domain.AuditActionUserSuspend, func(accountStatus domain.UserAccountStatus) (domain.UserAccountStatus, error) {
+		if accountStatus.Status == domain.UserStatusBanned || accountStatus.Status == domain.UserStatusDeleted {
+			return accountStatus, errors.New("user is " + string(accountStatus.Status))
+		}
+		accountStatus.Status = domain.UserStatusSuspended
+		accountStatus.SuspendedUntil = until
+		return accountStatus, nil
+	})
+}
+
+func (service *AccountStatusServiceImpl) Unsuspend(ctx context.Context, userId uuid.UUID) error {
+	return service.changeStatus(ctx, userId, domain.AuditActionUserUnsuspend, func(accountStatus domain.UserAccountStatus) (domain.UserAccountStatus, error) {
+		if accountStatus.Status != domain.UserStatusSuspended {
+			return accountStatus, errors.New("user is not suspended")
+		}
+		accountStatus.Status = domain.UserStatusActive
+		accountStatus.SuspendedUntil = nil
+		return accountStatus, nil
+	})
+}
+
+// changeStatus applies an operator action to the moderation columns and records it in the audit log
+func (service *AccountStatusServiceImpl) changeStatus(ctx context.Context, userId uuid.UUID, action string, apply func(domain.UserAccountStatus) (domain.UserAccountStatus, error)) error {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	before, err := service.UserRepository.FindAccountStatus(ctx, tx, userId)
+	if err != nil {
+		return err
+	}
+
+	after, err := apply(before)
+	if err != nil {
+		return err
+	}
+
+	// Panics roll the change back, CommitOrRollback commits on a returned error
+	err = service.UserRepository.UpdateAccountStatus(ctx, tx, userId, after.Status, after.SuspendedUntil)
+	helper.PanicIfError(err)
+
+	err = service.AuditLogService.Record(ctx, tx, action, "user", userId.String(), before, after)
+	helper.PanicIfError(err)
+
+	return nil
+}
+
+// MarkVerified confirms the email address of a user whose verification mail never arrived
+func (service *AccountStatusServiceImpl) MarkVerified(ctx context.Context, userId uuid.UUID) error {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	user, err := service.UserRepository.FindById(ctx, tx, userId)
+	if err != nil {
+		return err
+	}
+	if user.IsVerified {
+		return errors.New("user is already verified")
+	}
+
+	// Also clears the pending verification code, which is unused for an unverified account
+	err = service.UserRepository.UpdateVerificationStatus(ctx, tx, userId, true)
+	helper.PanicIfError(err)
+
+	err = service.AuditLogService.Record(ctx, tx, domain.AuditActionUserVerify, "user", userId.String(),
+		map[string]bool{"is_verified": false}, map[string]bool{"is_verified": true})
+	helper.PanicIfError(err)
+
+	return nil
+}
@@ -9,6 +9,9 @@ import (
 
 type AdminAuthService interface {
 	Login(ctx context.Context, request web.AdminLoginRequest) web.AdminLoginResponse
-	Register(ctx context.Context, request web.AdminRegisterRequest) web.AdminResponse
+	AcceptInvitation(ctx context.Context, request web.AdminAcceptInvitationRequest) web.AdminResponse
 	FindById(ctx context.Context, adminId uuid.UUID) web.AdminResponse
+	CheckAdminAccess(ctx context.Context, adminId uuid.UUID) (web.AdminResponse, error)
+	EnrollTwoFactor(ctx context.Context, request web.TwoFactorEnrollRequest) web.TwoFactorSetupResponse
+	VerifyTwoFactorLogin(ctx context.Context, request web.TwoFactorLoginRequest) web.AdminLoginResponse
 }
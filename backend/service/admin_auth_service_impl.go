@@ -3,12 +3,14 @@ package service
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"evoconnect/backend/exception"
 	"evoconnect/backend/helper"
 	"evoconnect/backend/model/domain"
 	"evoconnect/backend/model/web"
 	"evoconnect/backend/repository"
 	"evoconnect/backend/utils"
+	"slices"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -17,16 +19,26 @@ import (
 )
 
 type AdminAuthServiceImpl struct {
-	AdminRepository repository.AdminRepository
-	DB              *sql.DB
-	Validate        *validator.Validate
+	AdminRepository           repository.AdminRepository
+	AdminInvitationRepository repository.AdminInvitationRepository
+	TwoFactorService          TwoFactorService
+	RateLimiter               RateLimiter
+	LoginLockoutService       LoginLockoutService
+	PasswordPolicy            *utils.PasswordPolicy
+	DB                        *sql.DB
+	Validate                  *validator.Validate
 }
 
-func NewAdminAuthService(adminRepository repository.AdminRepository, db *sql.DB, validate *validator.Validate) AdminAuthService {
+func NewAdminAuthService(adminRepository repository.AdminRepository, adminInvitationRepository repository.AdminInvitationRepository, twoFactorService TwoFactorService, rateLimiter RateLimiter, loginLockoutService LoginLockoutService, passwordPolicy *utils.PasswordPolicy, db *sql.DB, validate *validator.Validate) AdminAuthService {
 	return &AdminAuthServiceImpl{
-		AdminRepository: adminRepository,
-		DB:              db,
-		Validate:        validate,
+		AdminRepository:           adminRepository,
+		AdminInvitationRepository: adminInvitationRepository,
+		TwoFactorService:          twoFactorService,
+		RateLimiter:               rateLimiter,
+		LoginLockoutService:       loginLockoutService,
+		PasswordPolicy:            passwordPolicy,
+		DB:                        db,
+		Validate:                  validate,
 	}
 }
 
@@ -34,36 +46,66 @@ func (service *AdminAuthServiceImpl) Login(ctx context.Context, request web.Admi
 	err := service.Validate.Struct(request)
 	helper.PanicIfError(err)
 
+	service.RateLimiter.Allow(ctx, RateLimitAdminLogin, RateLimitKeyIP(helper.GetClientIP(ctx)))
+	emailKey := RateLimitKeyEmail(request.Email)
+	service.RateLimiter.Check(ctx, RateLimitAdminLoginFailure, emailKey)
+
 	tx, err := service.DB.Begin()
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
 
 	admin, err := service.AdminRepository.FindByEmail(ctx, tx, request.Email)
 	if err != nil {
+		service.RateLimiter.Record(ctx, RateLimitAdminLoginFailure, emailKey)
 		panic(exception.NewNotFoundError("Admin not found"))
 	}
 
+	service.LoginLockoutService.CheckLocked(ctx, domain.AccountTypeAdmin, admin.Id)
+
 	// Verify password
 	err = bcrypt.CompareHashAndPassword([]byte(admin.Password), []byte(request.Password))
 	if err != nil {
+		service.RateLimiter.Record(ctx, RateLimitAdminLoginFailure, emailKey)
+		service.LoginLockoutService.RecordFailure(ctx, domain.AccountTypeAdmin, admin.Id, admin.Email, admin.Name)
 		panic(exception.NewUnauthorizedError("Invalid credentials"))
 	}
 
+	service.LoginLockoutService.Reset(ctx, tx, domain.AccountTypeAdmin, admin.Id)
+	service.RateLimiter.Reset(ctx, RateLimitAdminLoginFailure, emailKey)
+
+	if !admin.IsActive() {
+		panic(exception.NewForbiddenError("Admin account is disabled"))
+	}
+
+	// Admins with two-factor enabled must complete the second step
+	if service.TwoFactorService.IsEnabled(ctx, domain.TwoFactorOwnerAdmin, admin.Id) {
+		return web.AdminLoginResponse{
+			MfaRequired: true,
+			MfaToken:    service.generateMFAPendingToken(admin.Id, utils.MFAPurposePending),
+		}
+	}
+
+	// When two-factor is mandatory, admins without it have to enroll before getting a token
+	if service.TwoFactorService.IsAdminTwoFactorRequired(ctx) {
+		return web.AdminLoginResponse{
+			MfaRequired:           true,
+			MfaEnrollmentRequired: true,
+			MfaToken:              service.generateMFAPendingToken(admin.Id, utils.MFAPurposeEnrollment),
+		}
+	}
+
 	// Generate JWT token using the new utility function
-	token, err := utils.GenerateAdminToken(
-		admin.Id.String(),
-		admin.Email,
-		time.Hour*24*7, // 7 days
-	)
-	helper.PanicIfError(err)
+	token := service.generateAdminToken(admin)
 
+	adminResponse := helper.ToAdminResponse(admin)
 	return web.AdminLoginResponse{
 		Token: token,
-		Admin: helper.ToAdminResponse(admin),
+		Admin: &adminResponse,
 	}
 }
 
-func (service *AdminAuthServiceImpl) Register(ctx context.Context, request web.AdminRegisterRequest) web.AdminResponse {
+// AcceptInvitation creates the invited admin with the role chosen by the inviting super admin
+func (service *AdminAuthServiceImpl) AcceptInvitation(ctx context.Context, request web.AdminAcceptInvitationRequest) web.AdminResponse {
 	err := service.Validate.Struct(request)
 	helper.PanicIfError(err)
 
@@ -71,27 +113,39 @@ func (service *AdminAuthServiceImpl) Register(ctx context.Context, request web.A
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
 
+	invitation, err := service.AdminInvitationRepository.FindByTokenHash(ctx, tx, helper.HashToken(request.Token))
+	if err != nil || invitation.AcceptedAt != nil || time.Now().After(invitation.ExpiresAt) {
+		panic(exception.NewBadRequestError("Invalid or expired invitation"))
+	}
+
 	// Check if admin already exists
-	admin, err := service.AdminRepository.FindByEmail(ctx, tx, request.Email)
+	_, err = service.AdminRepository.FindByEmail(ctx, tx, invitation.Email)
 	if err == nil {
 		panic(exception.NewBadRequestError("Admin with this email already exists"))
 	}
 
+	// Same rules as accounts created by an operator, checked before the invitation is used up
+	service.PasswordPolicy.Validate(request.Password, invitation.Name, invitation.Email)
+
+	err = service.AdminInvitationRepository.MarkAccepted(ctx, tx, invitation.Id)
+	if err != nil {
+		panic(exception.NewBadRequestError("Invalid or expired invitation"))
+	}
+
 	// Hash password using the utility function
 	hashedPassword, err := utils.HashPassword(request.Password)
 	helper.PanicIfError(err)
 
-	// Create admin
-	admin = domain.Admin{
+	admin := domain.Admin{
 		Id:        uuid.New(),
-		Name:      request.Name,
-		Email:     request.Email,
+		Name:      invitation.Name,
+		Email:     invitation.Email,
 		Password:  hashedPassword,
+		Role:      invitation.Role,
+		Status:    domain.AdminStatusActive,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
-
-	// Ubah dari Save ke Create
 	admin = service.AdminRepository.Create(ctx, tx, admin)
 
 	return helper.ToAdminResponse(admin)
@@ -109,3 +163,107 @@ func (service *AdminAuthServiceImpl) FindById(ctx context.Context, id uuid.UUID)
 
 	return helper.ToAdminResponse(admin)
 }
+
+// CheckAdminAccess loads the current role of a token holder so that role changes and
+// disabled accounts take effect without waiting for the token to expire
+func (service *AdminAuthServiceImpl) CheckAdminAccess(ctx context.Context, adminId uuid.UUID) (web.AdminResponse, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return web.AdminResponse{}, err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	admin, err := service.AdminRepository.FindById(ctx, tx, adminId)
+	if err != nil {
+		return web.AdminResponse{}, err
+	}
+
+	if !admin.IsActive() {
+		return web.AdminResponse{}, errors.New("admin account is disabled")
+	}
+
+	return helper.ToAdminResponse(admin), nil
+}
+
+// EnrollTwoFactor starts mandatory enrollment for an admin holding an enrollment token
+func (service *AdminAuthServiceImpl) EnrollTwoFactor(ctx context.Context, request web.TwoFactorEnrollRequest) web.TwoFactorSetupResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	admin, _ := service.findAdminByMFAToken(ctx, request.MfaToken, utils.MFAPurposeEnrollment)
+
+	return service.TwoFactorService.Setup(ctx, domain.TwoFactorOwnerAdmin, admin.Id, admin.Email)
+}
+
+// VerifyTwoFactorLogin completes the admin login. With an enrollment token the code
+// confirms the new secret and the recovery codes are returned once.
+func (service *AdminAuthServiceImpl) VerifyTwoFactorLogin(ctx context.Context, request web.TwoFactorLoginRequest) web.AdminLoginResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	admin, purpose := service.findAdminByMFAToken(ctx, request.MfaToken, utils.MFAPurposePending, utils.MFAPurposeEnrollment)
+
+	var recoveryCodes []string
+	if purpose == utils.MFAPurposeEnrollment {
+		recoveryCodes = service.TwoFactorService.Enable(ctx, domain.TwoFactorOwnerAdmin, admin.Id, web.TwoFactorCodeRequest{Code: request.Code}).RecoveryCodes
+	} else {
+		err = service.TwoFactorService.VerifyCode(ctx, domain.TwoFactorOwnerAdmin, admin.Id, request.Code)
+		if err != nil {
+			panic(exception.NewUnauthorizedError(err.Error()))
+		}
+	}
+
+	token := service.generateAdminToken(admin)
+
+	adminResponse := helper.ToAdminResponse(admin)
+	return web.AdminLoginResponse{
+		Token:         token,
+		RecoveryCodes: recoveryCodes,
+		Admin:         &adminResponse,
+	}
+}
+
+// findAdminByMFAToken loads the admin of a pending token whose purpose is one of allowedPurposes
+func (service *AdminAuthServiceImpl) findAdminByMFAToken(ctx context.Context, mfaToken string, allowedPurposes ...string) (domain.Admin, string) {
+	claims, err := utils.ValidateMFAPendingToken(mfaToken, string(domain.TwoFactorOwnerAdmin))
+	if err != nil || !slices.Contains(allowedPurposes, claims.Purpose) {
+		panic(exception.NewUnauthorizedError("Invalid or expired two-factor session, please log in again"))
+	}
+
+	adminId, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		panic(exception.NewUnauthorizedError("Invalid two-factor session"))
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	admin, err := service.AdminRepository.FindById(ctx, tx, adminId)
+	if err != nil {
+		panic(exception.NewNotFoundError("Admin not found"))
+	}
+
+	if !admin.IsActive() {
+		panic(exception.NewForbiddenError("Admin account is disabled"))
+	}
+
+	return admin, claims.Purpose
+}
+
+func (service *AdminAuthServiceImpl) generateMFAPendingToken(adminId uuid.UUID, purpose string) string {
+	token, err := utils.GenerateMFAPendingToken(adminId.String(), string(domain.TwoFactorOwnerAdmin), purpose, mfaPendingTokenTTL)
+	helper.PanicIfError(err)
+	return token
+}
+
+func (service *AdminAuthServiceImpl) generateAdminToken(admin domain.Admin) string {
+	token, err := utils.GenerateAdminToken(
+		admin.Id.String(),
+		admin.Email,
+		string(admin.Role),
+		time.Hour*24*7, // 7 days
+	)
+	helper.PanicIfError(err)
+	return token
+}
@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+	"evoconnect/backend/model/web"
+
+	"github.com/google/uuid"
+)
+
+type AdminManagementService interface {
+	FindAll(ctx context.Context) []web.AdminResponse
+	FindPendingInvitations(ctx context.Context) []web.AdminInvitationResponse
+	Invite(ctx context.Context, inviterId uuid.UUID, request web.AdminInviteRequest) web.AdminInvitationResponse
+	Create(ctx context.Context, request web.AdminCreateRequest) web.AdminResponse
+	UpdateRole(ctx context.Context, actorId uuid.UUID, adminId uuid.UUID, request web.AdminUpdateRoleRequest) web.AdminResponse
+	UpdateStatus(ctx context.Context, actorId uuid.UUID, adminId uuid.UUID, request web.AdminUpdateStatusRequest) web.AdminResponse
+}
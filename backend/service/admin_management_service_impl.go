@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/exception"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/domain"
+	"evoconnect/backend/model/web"
+	"evoconnect/backend/repository"
+	"evoconnect/backend/utils"
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+const adminInvitationTTL = 72 * time.Hour
+
+type AdminManagementServiceImpl struct {
+	AdminRepository           repository.AdminRepository
+	AdminInvitationRepository repository.AdminInvitationRepository
+	AuditLogService           AuditLogService
+	PasswordPolicy            *utils.PasswordPolicy
+	ClientURL                 string
+	DB                        *sql.DB
+	Validate                  *validator.Validate
+}
+
+func NewAdminManagementService(adminRepository repository.AdminRepository, adminInvitationRepository repository.AdminInvitationRepository, auditLogService AuditLogService, passwordPolicy *utils.PasswordPolicy, clientURL string, db *sql.DB, validate *validator.Validate) AdminManagementService {
+	return &AdminManagementServiceImpl{
+		AdminRepository:           adminRepository,
+		AdminInvitationRepository: adminInvitationRepository,
+		AuditLogService:           auditLogService,
+		PasswordPolicy:            passwordPolicy,
+		ClientURL:                 clientURL,
+		DB:                        db,
+		Validate:                  validate,
+	}
+}
+
+func (service *AdminManagementServiceImpl) FindAll(ctx context.Context) []web.AdminResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	admins := service.AdminRepository.FindAll(ctx, tx)
+
+	responses := make([]web.AdminResponse, 0, len(admins))
+	for _, admin := range admins {
+		responses = append(responses, helper.ToAdminResponse(admin))
+	}
+
+	return responses
+}
+
+func (service *AdminManagementServiceImpl) FindPendingInvitations(ctx context.Context) []web.AdminInvitationResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	invitations, err := service.AdminInvitationRepository.FindPending(ctx, tx)
+	helper.PanicIfError(err)
+
+	responses := make([]web.AdminInvitationResponse, 0, len(invitations))
+	for _, invitation := range invitations {
+		responses = append(responses, helper.ToAdminInvitationResponse(invitation))
+	}
+
+	return responses
+}
+
+func (service *AdminManagementServiceImpl) Invite(ctx context.Context, inviterId uuid.UUID, request web.AdminInviteRequest) web.AdminInvitationResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	_, err = service.AdminRepository.FindByEmail(ctx, tx, request.Email)
+	if err == nil {
+		panic(exception.NewBadRequestError("Admin with this email already exists"))
+	}
+
+	// Only the latest invitation for an address stays valid
+	err = service.AdminInvitationRepository.DeletePendingByEmail(ctx, tx, request.Email)
+	helper.PanicIfError(err)
+
+	token := helper.GenerateSecureToken(32)
+	invitation, err := service.AdminInvitationRepository.Save(ctx, tx, domain.AdminInvitation{
+		Email:     request.Email,
+		Name:      request.Name,
+		Role:      domain.AdminRole(request.Role),
+		TokenHash: helper.HashToken(token),
+		InvitedBy: &inviterId,
+		ExpiresAt: time.Now().Add(adminInvitationTTL),
+		CreatedAt: time.Now(),
+	})
+	helper.PanicIfError(err)
+
+	err = service.AuditLogService.Record(ctx, tx, domain.AuditActionAdminInvite, "admin_invitation", invitation.Id.String(),
+		nil, helper.ToAdminInvitationResponse(invitation))
+	helper.PanicIfError(err)
+
+	inviteLink := fmt.Sprintf("%s/admin/accept-invitation?token=%s", service.ClientURL, token)
+	emailBody := fmt.Sprintf(`
+        <html>
+        <body>
+            <h1>Admin Invitation</h1>
+            <p>Hello %s,</p>
+            <p>You have been invited to the EvoConnect admin panel as <strong>%s</strong>.</p>
+            <p><a href="%s">Accept the invitation</a> and choose your password.</p>
+            <p>This invitation is valid for 72 hours.</p>
+            <p>Best regards,<br/>The EvoConnect Team</p>
+        </body>
+        </html>
+    `, invitation.Name, invitation.Role, inviteLink)
+
+	// A failed email rolls the invitation back so it can simply be sent again
+	err = helper.EmailSender(invitation.Email, "You're invited to the EvoConnect admin panel", emailBody)
+	if err != nil {
+		panic(exception.NewBadRequestError("Failed to send invitation email: " + err.Error()))
+	}
+
+	return helper.ToAdminInvitationResponse(invitation)
+}
+
+// Create skips the invitation flow, it exists for bootstrapping and for operators on the command line
+func (service *AdminManagementServiceImpl) Create(ctx context.Context, request web.AdminCreateRequest) web.AdminResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	service.PasswordPolicy.Validate(request.Password, request.Name, request.Email)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	_, err = service.AdminRepository.FindByEmail(ctx, tx, request.Email)
+	if err == nil {
+		panic(exception.NewBadRequestError("Admin with this email already exists"))
+	}
+
+	hashedPassword, err := utils.HashPassword(request.Password)
+	helper.PanicIfError(err)
+
+	now := time.Now()
+	admin := service.AdminRepository.Create(ctx, tx, domain.Admin{
+		Id:        uuid.New(),
+		Name:      request.Name,
+		Email:     request.Email,
+		Password:  hashedPassword,
+		Role:      domain.AdminRole(request.Role),
+		Status:    domain.AdminStatusActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+
+	err = service.AuditLogService.Record(ctx, tx, domain.AuditActionAdminCreate, "admin", admin.Id.String(),
+		nil, helper.ToAdminResponse(admin))
+	helper.PanicIfError(err)
+
+	return helper.ToAdminResponse(admin)
+}
+
+func (service *AdminManagementServiceImpl) UpdateRole(ctx context.Context, actorId uuid.UUID, adminId uuid.UUID, request web.AdminUpdateRoleRequest) web.AdminResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	admin, superAdmins := service.findManagedAdmin(ctx, tx, actorId, adminId)
+	previous := admin
+	before := helper.ToAdminResponse(admin)
+
+	admin.Role = domain.AdminRole(request.Role)
+	admin.UpdatedAt = time.Now()
+	keepActiveSuperAdmin(previous, admin, superAdmins)
+	admin = service.AdminRepository.Update(ctx, tx, admin)
+
+	err = service.AuditLogService.Record(ctx, tx, domain.AuditActionAdminRoleUpdate, "admin", admin.Id.String(), before, helper.ToAdminResponse(admin))
+	helper.PanicIfError(err)
+
+	return helper.ToAdminResponse(admin)
+}
+
+func (service *AdminManagementServiceImpl) UpdateStatus(ctx context.Context, actorId uuid.UUID, adminId uuid.UUID, request web.AdminUpdateStatusRequest) web.AdminResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	admin, superAdmins := service.findManagedAdmin(ctx, tx, actorId, adminId)
+	previous := admin
+	before := helper.ToAdminResponse(admin)
+
+	now := time.Now()
+	admin.Status = domain.AdminStatus(request.Status)
+	if admin.Status == domain.AdminStatusDisabled {
+		admin.DisabledAt = &now
+	} else {
+		admin.DisabledAt = nil
+	}
+	admin.UpdatedAt = now
+	keepActiveSuperAdmin(previous, admin, superAdmins)
+	admin = service.AdminRepository.Update(ctx, tx, admin)
+
+	err = service.AuditLogService.Record(ctx, tx, domain.AuditActionAdminStatusUpdate, "admin", admin.Id.String(), before, helper.ToAdminResponse(admin))
+	helper.PanicIfError(err)
+
+	return helper.ToAdminResponse(admin)
+}
+
+// findManagedAdmin loads the target of a role or status change together with the active super admins.
+// Super admins cannot change their own account. The super admins stay locked until tx ends, so two
+// super admins disabling or demoting each other at the same time are checked one after the other.
+func (service *AdminManagementServiceImpl) findManagedAdmin(ctx context.Context, tx *sql.Tx, actorId uuid.UUID, adminId uuid.UUID) (domain.Admin, []uuid.UUID) {
+	if actorId == adminId {
+		panic(exception.NewForbiddenError("You cannot change your own role or status"))
+	}
+
+	superAdmins := service.AdminRepository.LockActiveByRole(ctx, tx, domain.AdminRoleSuperAdmin)
+
+	admin, err := service.AdminRepository.FindById(ctx, tx, adminId)
+	if err != nil {
+		panic(exception.NewNotFoundError("Admin not found"))
+	}
+
+	return admin, superAdmins
+}
+
+// keepActiveSuperAdmin refuses a change that would leave no active super admin
+func keepActiveSuperAdmin(before domain.Admin, after domain.Admin, superAdmins []uuid.UUID) {
+	isActiveSuperAdmin := func(admin domain.Admin) bool {
+		return admin.Role == domain.AdminRoleSuperAdmin && admin.IsActive()
+	}
+	if isActiveSuperAdmin(before) && !isActiveSuperAdmin(after) && len(superAdmins) <= 1 {
+		panic(exception.NewBadRequestError("At least one active super admin must remain"))
+	}
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+	"evoconnect/backend/model/domain"
+	"evoconnect/backend/model/web"
+
+	"github.com/google/uuid"
+)
+
+type APITokenService interface {
+	CreatePersonalToken(ctx context.Context, userId uuid.UUID, request web.CreateAPITokenRequest) web.CreatedAPITokenResponse
+	FindPersonalTokens(ctx context.Context, userId uuid.UUID) []web.APITokenResponse
+	RevokePersonalToken(ctx context.Context, userId uuid.UUID, tokenId uuid.UUID) web.MessageResponse
+	CreatePartnerKey(ctx context.Context, userId uuid.UUID, companyId uuid.UUID, request web.CreateAPITokenRequest) web.CreatedAPITokenResponse
+	FindPartnerKeys(ctx context.Context, userId uuid.UUID, companyId uuid.UUID) []web.APITokenResponse
+	RevokePartnerKey(ctx context.Context, userId uuid.UUID, companyId uuid.UUID, keyId uuid.UUID) web.MessageResponse
+	Authenticate(ctx context.Context, rawToken string) (domain.APIToken, error)
+	FindResourceCompanyId(ctx context.Context, resource domain.APIResource, resourceId uuid.UUID) (uuid.UUID, error)
+}
@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"evoconnect/backend/exception"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/domain"
+	"evoconnect/backend/model/entity"
+	"evoconnect/backend/model/web"
+	"evoconnect/backend/repository"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// API tokens are told apart from JWTs by their prefix
+const (
+	personalAccessTokenPrefix = "evo_pat_"
+	partnerAPIKeyPrefix       = "evo_key_"
+	apiTokenDisplayLength     = 12
+	apiTokenDefaultDays       = 90
+	apiTokenMaxPerOwner       = 25
+)
+
+// IsAPIToken reports whether a bearer token is an API token rather than a JWT
+func IsAPIToken(token string) bool {
+	return strings.HasPrefix(token, personalAccessTokenPrefix) || strings.HasPrefix(token, partnerAPIKeyPrefix)
+}
+
+type APITokenServiceImpl struct {
+	APITokenRepository       repository.APITokenRepository
+	MemberCompanyRepository  repository.MemberCompanyRepository
+	JobVacancyRepository     repository.JobVacancyRepository
+	JobApplicationRepository repository.JobApplicationRepository
+	DB                       *sql.DB
+	Validate                 *validator.Validate
+}
+
+func NewAPITokenService(
+	apiTokenRepository repository.APITokenRepository,
+	memberCompanyRepository repository.MemberCompanyRepository,
+	jobVacancyRepository repository.JobVacancyRepository,
+	jobApplicationRepository repository.JobApplicationRepository,
+	db *sql.DB,
+	validate *validator.Validate,
+) APITokenService {
+	return &APITokenServiceImpl{
+		APITokenRepository:       apiTokenRepository,
+		MemberCompanyRepository:  memberCompanyRepository,
+		JobVacancyRepository:     jobVacancyRepository,
+		JobApplicationRepository: jobApplicationRepository,
+		DB:                       db,
+		Validate:                 validate,
+	}
+}
+
+func (service *APITokenServiceImpl) CreatePersonalToken(ctx context.Context, userId uuid.UUID, request web.CreateAPITokenRequest) web.CreatedAPITokenResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	scopes := parseAPIScopes(request.Scopes, domain.PersonalAPIScopes)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	existing, err := service.APITokenRepository.FindByUserId(ctx, tx, userId)
+	helper.PanicIfError(err)
+	if countActiveAPITokens(existing) >= apiTokenMaxPerOwner {
+		panic(exception.NewBadRequestError(fmt.Sprintf("You can have at most %d active tokens, revoke one first", apiTokenMaxPerOwner)))
+	}
+
+	return service.createToken(ctx, tx, personalAccessTokenPrefix, userId, nil, request, scopes)
+}
+
+func (service *APITokenServiceImpl) FindPersonalTokens(ctx context.Context, userId uuid.UUID) []web.APITokenResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	tokens, err := service.APITokenRepository.FindByUserId(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	return toAPITokenResponses(tokens)
+}
+
+func (service *APITokenServiceImpl) RevokePersonalToken(ctx context.Context, userId uuid.UUID, tokenId uuid.UUID) web.MessageResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	token, err := service.APITokenRepository.FindById(ctx, tx, tokenId)
+	if err != nil || token.UserId != userId || token.CompanyId != nil || token.RevokedAt != nil {
+		panic(exception.NewNotFoundError("Token not found"))
+	}
+
+	err = service.APITokenRepository.Revoke(ctx, tx, tokenId)
+	helper.PanicIfError(err)
+
+	return web.MessageResponse{Message: "Token revoked"}
+}
+
+// CreatePartnerKey issues a key for an integration of the company. It acts as the admin who
+// created it and stops working when they are no longer a company admin.
+func (service *APITokenServiceImpl) CreatePartnerKey(ctx context.Context, userId uuid.UUID, companyId uuid.UUID, request web.CreateAPITokenRequest) web.CreatedAPITokenResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	scopes := parseAPIScopes(request.Scopes, domain.PartnerAPIScopes)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	service.checkCompanyAdmin(ctx, tx, userId, companyId)
+
+	existing, err := service.APITokenRepository.FindByCompanyId(ctx, tx, companyId)
+	helper.PanicIfError(err)
+	if countActiveAPITokens(existing) >= apiTokenMaxPerOwner {
+		panic(exception.NewBadRequestError(fmt.Sprintf("A company can have at most %d active API keys, revoke one first", apiTokenMaxPerOwner)))
+	}
+
+	return service.createToken(ctx, tx, partnerAPIKeyPrefix, userId, &companyId, request, scopes)
+}
+
+func (service *APITokenServiceImpl) FindPartnerKeys(ctx context.Context, userId uuid.UUID, companyId uuid.UUID) []web.APITokenResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	service.checkCompanyAdmin(ctx, tx, userId, companyId)
+
+	keys, err := service.APITokenRepository.FindByCompanyId(ctx, tx, companyId)
+	helper.PanicIfError(err)
+
+	return toAPITokenResponses(keys)
+}
+
+func (service *APITokenServiceImpl) RevokePartnerKey(ctx context.Context, userId uuid.UUID, companyId uuid.UUID, keyId uuid.UUID) web.MessageResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	service.checkCompanyAdmin(ctx, tx, userId, companyId)
+
+	key, err := service.APITokenRepository.FindById(ctx, tx, keyId)
+	if err != nil || key.CompanyId == nil || *key.CompanyId != companyId || key.RevokedAt != nil {
+		panic(exception.NewNotFoundError("API key not found"))
+	}
+
+	err = service.APITokenRepository.Revoke(ctx, tx, keyId)
+	helper.PanicIfError(err)
+
+	return web.MessageResponse{Message: "API key revoked"}
+}
+
+// Authenticate looks up an API token presented as a bearer token and records its use
+func (service *APITokenServiceImpl) Authenticate(ctx context.Context, rawToken string) (domain.APIToken, error) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return domain.APIToken{}, err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	token, err := service.APITokenRepository.FindByHash(ctx, tx, helper.HashToken(rawToken))
+	if err != nil {
+		return domain.APIToken{}, errors.New("invalid API token")
+	}
+	if token.RevokedAt != nil {
+		return domain.APIToken{}, errors.New("API token has been revoked")
+	}
+	if !token.IsActive() {
+		return domain.APIToken{}, errors.New("API token has expired")
+	}
+
+	if token.CompanyId != nil {
+		role, err := service.MemberCompanyRepository.GetUserRoleInCompany(ctx, tx, token.UserId, *token.CompanyId)
+		if err != nil || !isCompanyAdminRole(role) {
+			return domain.APIToken{}, errors.New("the owner of this API key is no longer a company admin")
+		}
+	}
+
+	err = service.APITokenRepository.TouchLastUsed(ctx, tx, token.Id, helper.GetClientIP(ctx), time.Now().Add(-sessionTouchInterval))
+	if err != nil {
+		return domain.APIToken{}, err
+	}
+
+	return token, nil
+}
+
+// FindResourceCompanyId returns the company a resource named in a route belongs to
+func (service *APITokenServiceImpl) FindResourceCompanyId(ctx context.Context, resource domain.APIResource, resourceId uuid.UUID) (uuid.UUID, error) {
+	if resource == domain.APIResourceCompany {
+		return resourceId, nil
+	}
+
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	switch resource {
+	case domain.APIResourceJobVacancy:
+		jobVacancy, err := service.JobVacancyRepository.FindById(ctx, tx, resourceId)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return jobVacancy.CompanyId, nil
+	case domain.APIResourceJobApplication:
+		jobApplication, err := service.JobApplicationRepository.FindById(ctx, tx, resourceId)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		if jobApplication.JobVacancy == nil {
+			return uuid.Nil, errors.New("job application has no job vacancy")
+		}
+		return jobApplication.JobVacancy.CompanyId, nil
+	}
+
+	return uuid.Nil, fmt.Errorf("unknown API resource %q", resource)
+}
+
+func (service *APITokenServiceImpl) createToken(ctx context.Context, tx *sql.Tx, prefix string, userId uuid.UUID, companyId *uuid.UUID, request web.CreateAPITokenRequest, scopes []domain.APIScope) web.CreatedAPITokenResponse {
+	days := request.ExpiresInDays
+	if days == 0 {
+		days = apiTokenDefaultDays
+	}
+
+	rawToken := prefix + helper.GenerateSecureToken(32)
+
+	token, err := service.APITokenRepository.Save(ctx, tx, domain.APIToken{
+		UserId:      userId,
+		CompanyId:   companyId,
+		Name:        strings.TrimSpace(request.Name),
+		TokenPrefix: rawToken[:apiTokenDisplayLength],
+		TokenHash:   helper.HashToken(rawToken),
+		Scopes:      scopes,
+		ExpiresAt:   time.Now().AddDate(0, 0, days),
+	})
+	helper.PanicIfError(err)
+
+	return web.CreatedAPITokenResponse{
+		APITokenResponse: helper.ToAPITokenResponse(token),
+		Token:            rawToken,
+	}
+}
+
+func (service *APITokenServiceImpl) checkCompanyAdmin(ctx context.Context, tx *sql.Tx, userId uuid.UUID, companyId uuid.UUID) {
+	role, err := service.MemberCompanyRepository.GetUserRoleInCompany(ctx, tx, userId, companyId)
+	if err != nil || !isCompanyAdminRole(role) {
+		panic(exception.NewForbiddenError("Only company admins can manage API keys"))
+	}
+}
+
+func isCompanyAdminRole(role entity.MemberCompanyRole) bool {
+	return role == entity.RoleSuperAdmin || role == entity.RoleAdmin
+}
+
+// parseAPIScopes rejects scopes that are unknown or not allowed for this kind of token
+func parseAPIScopes(requested []string, allowed []domain.APIScope) []domain.APIScope {
+	var scopes []domain.APIScope
+	seen := make(map[domain.APIScope]bool)
+
+	for _, value := range requested {
+		scope := domain.APIScope(strings.TrimSpace(value))
+		valid := false
+		for _, candidate := range allowed {
+			if scope == candidate {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			panic(exception.NewBadRequestError(fmt.Sprintf("Scope %q is not allowed", value)))
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return scopes
+}
+
+func countActiveAPITokens(tokens []domain.APIToken) int {
+	count := 0
+	for _, token := range tokens {
+		if token.IsActive() {
+			count++
+		}
+	}
+	return count
+}
+
+func toAPITokenResponses(tokens []domain.APIToken) []web.APITokenResponse {
+	responses := []web.APITokenResponse{}
+	for _, token := range tokens {
+		responses = append(responses, helper.ToAPITokenResponse(token))
+	}
+	return responses
+}
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/model/domain"
+	"evoconnect/backend/model/web"
+	"io"
+)
+
+type AuditLogService interface {
+	// Record appends an entry inside the caller's transaction so it is only kept if the change is
+	Record(ctx context.Context, tx *sql.Tx, action, targetType, targetId string, before, after interface{}) error
+	FindAll(ctx context.Context, filter domain.AuditLogFilter, limit, offset int) web.AuditLogListResponse
+	ExportCSV(ctx context.Context, filter domain.AuditLogFilter, writer io.Writer)
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/domain"
+	"evoconnect/backend/model/web"
+	"evoconnect/backend/repository"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	auditLogExportBatchSize = 500
+	auditLogExportMaxRows   = 50000
+)
+
+type AuditLogServiceImpl struct {
+	AuditLogRepository repository.AuditLogRepository
+	DB                 *sql.DB
+}
+
+func NewAuditLogService(auditLogRepository repository.AuditLogRepository, db *sql.DB) AuditLogService {
+	return &AuditLogServiceImpl{
+		AuditLogRepository: auditLogRepository,
+		DB:                 db,
+	}
+}
+
+// Record takes the actor from the admin auth middleware context and the IP from the request.
+// Commands run outside HTTP set "audit_actor_type" and "audit_actor" in the context instead.
+func (service *AuditLogServiceImpl) Record(ctx context.Context, tx *sql.Tx, action, targetType, targetId string, before, after interface{}) error {
+	beforeData, err := auditSnapshot(before)
+	if err != nil {
+		return err
+	}
+	afterData, err := auditSnapshot(after)
+	if err != nil {
+		return err
+	}
+
+	auditLog := domain.AuditLog{
+		ActorType:  "admin",
+		Action:     action,
+		TargetType: targetType,
+		TargetId:   targetId,
+		Before:     beforeData,
+		After:      afterData,
+		IpAddress:  helper.GetClientIP(ctx),
+		CreatedAt:  time.Now(),
+	}
+
+	if adminIdStr, ok := ctx.Value("admin_id").(string); ok {
+		if adminId, err := uuid.Parse(adminIdStr); err == nil {
+			auditLog.ActorId = &adminId
+		}
+	}
+	if adminEmail, ok := ctx.Value("admin_email").(string); ok {
+		auditLog.ActorEmail = adminEmail
+	}
+	if actorType, ok := ctx.Value("audit_actor_type").(string); ok {
+		auditLog.ActorType = actorType
+		if actor, ok := ctx.Value("audit_actor").(string); ok {
+			auditLog.ActorEmail = actor
+		}
+	}
+
+	_, err = service.AuditLogRepository.Save(ctx, tx, auditLog)
+	return err
+}
+
+func (service *AuditLogServiceImpl) FindAll(ctx context.Context, filter domain.AuditLogFilter, limit, offset int) web.AuditLogListResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	total, err := service.AuditLogRepository.Count(ctx, tx, filter)
+	helper.PanicIfError(err)
+
+	auditLogs, err := service.AuditLogRepository.FindAll(ctx, tx, filter, limit, offset)
+	helper.PanicIfError(err)
+
+	responses := make([]web.AuditLogResponse, 0, len(auditLogs))
+	for _, auditLog := range auditLogs {
+		responses = append(responses, helper.ToAuditLogResponse(auditLog))
+	}
+
+	return web.AuditLogListResponse{
+		AuditLogs: responses,
+		Pagination: web.PaginationResponse{
+			Total:   total,
+			Limit:   limit,
+			Offset:  offset,
+			PerPage: limit,
+			HasNext: offset+limit < total,
+			HasPrev: offset > 0,
+		},
+	}
+}
+
+// ExportCSV writes the matching entries, newest first, in batches to keep memory flat
+func (service *AuditLogServiceImpl) ExportCSV(ctx context.Context, filter domain.AuditLogFilter, writer io.Writer) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	csvWriter := csv.NewWriter(writer)
+	err = csvWriter.Write([]string{"id", "created_at", "actor_type", "actor_id", "actor_email", "action", "target_type", "target_id", "ip_address", "before", "after"})
+	helper.PanicIfError(err)
+
+	for offset := 0; offset < auditLogExportMaxRows; offset += auditLogExportBatchSize {
+		auditLogs, err := service.AuditLogRepository.FindAll(ctx, tx, filter, auditLogExportBatchSize, offset)
+		helper.PanicIfError(err)
+
+		for _, auditLog := range auditLogs {
+			actorId := ""
+			if auditLog.ActorId != nil {
+				actorId = auditLog.ActorId.String()
+			}
+
+			err = csvWriter.Write([]string{
+				auditLog.Id.String(),
+				auditLog.CreatedAt.Format(time.RFC3339),
+				auditLog.ActorType,
+				actorId,
+				auditLog.ActorEmail,
+				auditLog.Action,
+				auditLog.TargetType,
+				auditLog.TargetId,
+				auditLog.IpAddress,
+				string(auditLog.Before),
+				string(auditLog.After),
+			})
+			helper.PanicIfError(err)
+		}
+
+		if len(auditLogs) < auditLogExportBatchSize {
+			break
+		}
+	}
+
+	csvWriter.Flush()
+	helper.PanicIfError(csvWriter.Error())
+}
+
+func auditSnapshot(value interface{}) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+	return json.Marshal(value)
+}
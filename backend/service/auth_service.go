@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"evoconnect/backend/model/web"
+
+	"github.com/google/uuid"
 )
 
 type AuthService interface {
@@ -13,4 +15,10 @@ type AuthService interface {
 	ForgotPassword(ctx context.Context, request web.EmailRequest) web.MessageResponse
 	ResetPassword(ctx context.Context, request web.ResetPasswordRequest) web.MessageResponse
 	GoogleAuth(ctx context.Context, request web.GoogleAuthRequest) (web.RegisterResponse, error) // Add this line
+	RefreshToken(ctx context.Context, request web.RefreshTokenRequest) web.TokenResponse
+	Logout(ctx context.Context, request web.RefreshTokenRequest) web.MessageResponse
+	VerifyTwoFactorLogin(ctx context.Context, request web.TwoFactorLoginRequest) web.LoginResponse
+	SendMagicLink(ctx context.Context, request web.EmailRequest) web.MessageResponse
+	MagicLinkLogin(ctx context.Context, request web.MagicLinkLoginRequest) web.LoginResponse
+	ChangePassword(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID, request web.ChangePasswordRequest) web.MessageResponse
 }
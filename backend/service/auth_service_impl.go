@@ -3,8 +3,7 @@ package service
 import (
 	"context"
 	"database/sql"
-	"encoding/base64"
-	"encoding/json"
+	"errors"
 	"evoconnect/backend/exception"
 	"evoconnect/backend/helper"
 	"evoconnect/backend/model/domain"
@@ -12,6 +11,7 @@ import (
 	"evoconnect/backend/repository"
 	"evoconnect/backend/utils"
 	"fmt"
+	"log/slog"
 	"math/rand"
 	"regexp"
 	"strings"
@@ -22,23 +22,42 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
-	"golang.org/x/oauth2"
 )
 
 type AuthServiceImpl struct {
-	UserRepository repository.UserRepository
-	DB             *sql.DB
-	Validate       *validator.Validate
-	JWTSecret      string
-	CurrentTx      *sql.Tx
+	UserRepository        repository.UserRepository
+	UserSessionRepository repository.UserSessionRepository
+	AccountStatusService  AccountStatusService
+	TwoFactorService      TwoFactorService
+	RateLimiter           RateLimiter
+	LoginLockoutService   LoginLockoutService
+	OAuthService          OAuthService
+	PasswordPolicy        *utils.PasswordPolicy
+	SessionLifetimes      SessionLifetimes
+	ClientURL             string
+	DB                    *sql.DB
+	Validate              *validator.Validate
+	JWTSecret             string
+	CurrentTx             *sql.Tx
+	Logger                *slog.Logger
 }
 
-func NewAuthService(userRepository repository.UserRepository, db *sql.DB, validate *validator.Validate, jwtSecret string) AuthService {
+func NewAuthService(userRepository repository.UserRepository, userSessionRepository repository.UserSessionRepository, accountStatusService AccountStatusService, twoFactorService TwoFactorService, rateLimiter RateLimiter, loginLockoutService LoginLockoutService, oauthService OAuthService, passwordPolicy *utils.PasswordPolicy, sessionLifetimes SessionLifetimes, clientURL string, db *sql.DB, validate *validator.Validate, jwtSecret string, logger *slog.Logger) AuthService {
 	return &AuthServiceImpl{
-		UserRepository: userRepository,
-		DB:             db,
-		Validate:       validate,
-		JWTSecret:      jwtSecret,
+		UserRepository:        userRepository,
+		UserSessionRepository: userSessionRepository,
+		AccountStatusService:  accountStatusService,
+		TwoFactorService:      twoFactorService,
+		RateLimiter:           rateLimiter,
+		LoginLockoutService:   loginLockoutService,
+		OAuthService:          oauthService,
+		PasswordPolicy:        passwordPolicy,
+		SessionLifetimes:      sessionLifetimes,
+		ClientURL:             clientURL,
+		DB:                    db,
+		Validate:              validate,
+		JWTSecret:             jwtSecret,
+		Logger:                logger,
 	}
 }
 
@@ -57,6 +76,9 @@ func NewAuthService(userRepository repository.UserRepository, db *sql.DB, valida
 // 	return signedToken
 // }
 
+// Sign-in links are as good as a password, so they expire quickly
+const magicLinkTTL = 15 * time.Minute
+
 // generateRandomToken creates a random token for verification/reset
 func generateRandomToken() string {
 	// Generate a random number between 100000 and 999999 (6 digits)
@@ -69,143 +91,20 @@ func generateRandomToken() string {
 	return fmt.Sprintf("%06d", n%900000+100000)
 }
 
-// Add this method to your AuthServiceImpl
+// GoogleAuth signs in with an ID token from the Google sign-in button. The token is verified
+// against Google's signing keys by the OAuth service, which also links or creates the account.
 func (service *AuthServiceImpl) GoogleAuth(ctx context.Context, request web.GoogleAuthRequest) (web.RegisterResponse, error) {
-	// Get Google OAuth config values
-	CLIENT_ID := helper.GetEnv("GOOGLE_CLIENT_ID", "630548216793-u72hegqjlqli4petjg5lsgkrp8fn0foc.apps.googleusercontent.com")
-
-	// For ID token verification, we should use a validator
-	idToken := request.Token
-
-	// Create a validator for the ID token
-	_ = oauth2.Config{
-		ClientID: CLIENT_ID,
-	}
-
-	// Parse and verify the token without making a remote call
-	payload, err := validateGoogleIDToken(idToken, CLIENT_ID)
+	err := service.Validate.Struct(request)
 	if err != nil {
-		fmt.Printf("Error validating token: %v\n", err)
 		return web.RegisterResponse{}, err
 	}
 
-	// Extract user info from validated token
-	email := payload["email"].(string)
-	name := payload["name"].(string)
-	picture := payload["picture"].(string)
-
-	// Start database transaction
-	tx, err := service.DB.Begin()
-	helper.PanicIfError(err)
-	defer helper.CommitOrRollback(tx)
-
-	// Check if user exists by email
-	existingUser, err := service.UserRepository.FindByEmail(ctx, tx, email)
-	if err == nil {
-		// User exists, update their profile information from Google
-		existingUser.Name = name
-		existingUser.Photo = picture
-		existingUser.UpdatedAt = time.Now()
-
-		// Update user in database
-		updatedUser := service.UserRepository.Update(ctx, tx, existingUser)
-
-		// Generate token and return
-		jwtToken, err := utils.GenerateToken(
-			updatedUser.Id.String(),
-			updatedUser.Email,
-			"user",
-			time.Hour*24*7, // 7 days
-		)
-		if err != nil {
-			fmt.Printf("Error generating token: %v\n", err)
-			return web.RegisterResponse{}, err
-		}
-
-		return web.RegisterResponse{
-			Token: jwtToken,
-			User:  updatedUser,
-		}, nil
-	}
-
-	// User doesn't exist, create a new account
-	// Generate username from name
-	username := generateUsernameFromName(name)
-
-	// Generate random password for account security
-	randomPassword := generateRandomString(12)
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(randomPassword), bcrypt.DefaultCost)
-	helper.PanicIfError(err)
-
-	// Create new user
-	newUser := domain.User{
-		Id:         uuid.New(),
-		Name:       name,
-		Email:      email,
-		Username:   username,
-		Password:   string(hashedPassword),
-		IsVerified: true, // Google accounts are already verified
-		Photo:      picture,
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
-	}
-
-	// Save new user to database
-	savedUser := service.UserRepository.Save(ctx, tx, newUser)
-	jwtToken, err := utils.GenerateToken(
-		savedUser.Id.String(),
-		savedUser.Email,
-		"user",
-		time.Hour*24*7, // 7 days
-	)
+	response, err := service.OAuthService.LoginWithIDToken(ctx, "google", request.Token)
 	if err != nil {
-		fmt.Printf("Error generating token: %v\n", err)
 		return web.RegisterResponse{}, err
 	}
 
-	return web.RegisterResponse{
-		Token: jwtToken,
-		User:  savedUser,
-	}, nil
-}
-
-// Helper function to validate Google ID token without making a remote call
-func validateGoogleIDToken(tokenString string, clientID string) (map[string]interface{}, error) {
-	// Split the token to get parts
-	parts := strings.Split(tokenString, ".")
-	if len(parts) != 3 {
-		return nil, fmt.Errorf("invalid token format")
-	}
-
-	// Decode the payload (middle part)
-	payloadBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
-	if err != nil {
-		return nil, err
-	}
-
-	// Parse the JSON payload
-	var payload map[string]interface{}
-	err = json.Unmarshal(payloadBytes, &payload)
-	if err != nil {
-		return nil, err
-	}
-
-	// Verify audience (aud) matches your client ID
-	if aud, ok := payload["aud"].(string); !ok || aud != clientID {
-		return nil, fmt.Errorf("token not intended for this audience")
-	}
-
-	// Verify the token is not expired
-	if exp, ok := payload["exp"].(float64); ok {
-		if time.Now().Unix() > int64(exp) {
-			return nil, fmt.Errorf("token expired")
-		}
-	} else {
-		return nil, fmt.Errorf("missing expiration time")
-	}
-
-	// Token is valid!
-	return payload, nil
+	return web.RegisterResponse(response), nil
 }
 
 // Helper function to generate username from name
@@ -247,6 +146,8 @@ func (service *AuthServiceImpl) Register(ctx context.Context, request web.Regist
 	err := service.Validate.Struct(request)
 	helper.PanicIfError(err)
 
+	service.PasswordPolicy.Validate(request.Password, request.Name, request.Email)
+
 	tx, err := service.DB.Begin()
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
@@ -301,20 +202,17 @@ func (service *AuthServiceImpl) Register(ctx context.Context, request web.Regist
 
 	err = helper.EmailSender(user.Email, "Welcome to EvoConnect - Verify Your Email", emailBody)
 	if err != nil {
-		fmt.Printf("Failed to send verification email: %v\n", err)
+		service.Logger.ErrorContext(ctx, "failed to send verification email", "error", err)
 	}
 
-	// Generate JWT token using the new utility function
-	jwtToken, err := utils.GenerateUserToken(
-		user.Id.String(),
-		user.Email,
-		time.Hour*24*7, // 7 days
-	)
-	helper.PanicIfError(err)
+	// Open a session for the new account
+	tokens := issueUserSession(ctx, tx, service.UserSessionRepository, service.SessionLifetimes, user)
 
 	return web.RegisterResponse{
-		Token: jwtToken,
-		User:  user,
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresAt:    &tokens.ExpiresAt,
+		User:         &user,
 	}
 }
 
@@ -322,32 +220,65 @@ func (service *AuthServiceImpl) Login(ctx context.Context, request web.LoginRequ
 	err := service.Validate.Struct(request)
 	helper.PanicIfError(err)
 
+	// Every attempt counts per IP, failures also count per email address
+	service.RateLimiter.Allow(ctx, RateLimitLogin, RateLimitKeyIP(helper.GetClientIP(ctx)))
+	emailKey := RateLimitKeyEmail(request.Email)
+	service.RateLimiter.Check(ctx, RateLimitLoginFailure, emailKey)
+
 	tx, err := service.DB.Begin()
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
 
 	user, err := service.UserRepository.FindByEmail(ctx, tx, request.Email)
 	if err != nil {
+		service.RateLimiter.Record(ctx, RateLimitLoginFailure, emailKey)
 		panic(exception.NewNotFoundError(err.Error()))
 	}
 
+	service.LoginLockoutService.CheckLocked(ctx, domain.AccountTypeUser, user.Id)
+
 	// Verify password
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password))
 	if err != nil {
+		service.RateLimiter.Record(ctx, RateLimitLoginFailure, emailKey)
+		service.LoginLockoutService.RecordFailure(ctx, domain.AccountTypeUser, user.Id, user.Email, user.Name)
 		panic(exception.NewUnauthorizedError("Invalid credentials"))
 	}
 
-	// Generate JWT token using the new utility function
-	token, err := utils.GenerateUserToken(
-		user.Id.String(),
-		user.Email,
-		time.Hour*24*7, // 7 days
-	)
-	helper.PanicIfError(err)
+	service.LoginLockoutService.Reset(ctx, tx, domain.AccountTypeUser, user.Id)
+	service.RateLimiter.Reset(ctx, RateLimitLoginFailure, emailKey)
+
+	// Reject banned and suspended accounts. Runs before anything in tx writes the user row,
+	// lifting an expired suspension updates that row from another transaction.
+	err = service.AccountStatusService.CheckAccountStatus(ctx, user.Id)
+	if err != nil {
+		panic(err)
+	}
+
+	// Passwords set before the current policy are flagged so the client asks for a new one
+	if violations := service.PasswordPolicy.Check(request.Password, user.Name, user.Email); len(violations) > 0 {
+		err = service.UserRepository.SetPasswordChangeRequired(ctx, tx, user.Id, true)
+		helper.PanicIfError(err)
+	}
+
+	// Users with two-factor enabled get a pending token to exchange in the second step
+	if service.TwoFactorService.IsEnabled(ctx, domain.TwoFactorOwnerUser, user.Id) {
+		return web.LoginResponse{
+			MfaRequired: true,
+			MfaToken:    generateUserMFAPendingToken(user.Id),
+		}
+	}
+
+	// Open a session and issue a short-lived access token plus a refresh token
+	tokens, deletionCancelled := openUserSession(ctx, tx, service.UserRepository, service.UserSessionRepository, service.SessionLifetimes, user)
 
 	return web.LoginResponse{
-		Token: token,
-		User:  user,
+		Token:                  tokens.Token,
+		RefreshToken:           tokens.RefreshToken,
+		ExpiresAt:              &tokens.ExpiresAt,
+		DeletionCancelled:      deletionCancelled,
+		PasswordChangeRequired: passwordChangeRequired(ctx, tx, service.UserRepository, user.Id),
+		User:                   &user,
 	}
 }
 
@@ -358,34 +289,7 @@ func (service *AuthServiceImpl) SendVerificationEmail(ctx context.Context, reque
 
 	// Get client IP for rate limiting
 	clientIP := helper.GetClientIP(ctx)
-
-	// Check rate limiting first
-	rateLimitTx, err := service.DB.Begin()
-	if err != nil {
-		panic(exception.NewInternalServerError("Database error: " + err.Error()))
-	}
-
-	var isLimited bool
-	func() {
-		defer func() {
-			if r := recover(); r != nil {
-				rateLimitTx.Rollback()
-				panic(r)
-			}
-		}()
-
-		isLimited, err = service.UserRepository.IsRateLimited(ctx, rateLimitTx, clientIP, "send_verification_email", 3, 15*time.Minute)
-		if err != nil {
-			rateLimitTx.Rollback()
-			isLimited = false
-		} else {
-			rateLimitTx.Commit()
-		}
-	}()
-
-	if isLimited {
-		panic(exception.NewTooManyRequestsError("Too many verification email requests. Please try again later."))
-	}
+	service.RateLimiter.Check(ctx, RateLimitSendVerificationEmail, RateLimitKeyIP(clientIP))
 
 	// Main transaction for sending verification email
 	tx, err := service.DB.Begin()
@@ -435,16 +339,8 @@ func (service *AuthServiceImpl) SendVerificationEmail(ctx context.Context, reque
 		panic(exception.NewBadRequestError("Failed to send verification email: " + err.Error()))
 	}
 
-	// Log successful email send
-	logTx, err := service.DB.Begin()
-	if err == nil {
-		err = service.UserRepository.LogFailedAttempt(ctx, logTx, clientIP, "send_verification_email", "")
-		if err != nil {
-			logTx.Rollback()
-		} else {
-			logTx.Commit()
-		}
-	}
+	// Count successful sends only
+	service.RateLimiter.Record(ctx, RateLimitSendVerificationEmail, RateLimitKeyIP(clientIP))
 
 	return web.MessageResponse{
 		Message: "Verification email sent",
@@ -457,34 +353,7 @@ func (service *AuthServiceImpl) VerifyEmail(ctx context.Context, request web.Ver
 
 	// Get client IP for rate limiting
 	clientIP := helper.GetClientIP(ctx)
-
-	// Check rate limiting first
-	rateLimitTx, err := service.DB.Begin()
-	if err != nil {
-		panic(exception.NewInternalServerError("Database error: " + err.Error()))
-	}
-
-	var isLimited bool
-	func() {
-		defer func() {
-			if r := recover(); r != nil {
-				rateLimitTx.Rollback()
-				panic(r)
-			}
-		}()
-
-		isLimited, err = service.UserRepository.IsRateLimited(ctx, rateLimitTx, clientIP, "email_verification", 5, 5*time.Minute)
-		if err != nil {
-			rateLimitTx.Rollback()
-			isLimited = false
-		} else {
-			rateLimitTx.Commit()
-		}
-	}()
-
-	if isLimited {
-		panic(exception.NewTooManyRequestsError("Too many verification attempts. Please try again later."))
-	}
+	service.RateLimiter.Check(ctx, RateLimitVerifyEmail, RateLimitKeyIP(clientIP))
 
 	tx, err := service.DB.Begin()
 	helper.PanicIfError(err)
@@ -493,7 +362,7 @@ func (service *AuthServiceImpl) VerifyEmail(ctx context.Context, request web.Ver
 	// Check if token is a reasonable length
 	if len(request.Token) != 6 {
 		// Log failed attempt
-		_ = service.UserRepository.LogFailedAttempt(ctx, tx, clientIP, "email_verification", request.Token)
+		service.RateLimiter.Record(ctx, RateLimitVerifyEmail, RateLimitKeyIP(clientIP))
 		panic(exception.NewBadRequestError("Invalid verification token format"))
 	}
 
@@ -501,7 +370,7 @@ func (service *AuthServiceImpl) VerifyEmail(ctx context.Context, request web.Ver
 	user, err := service.UserRepository.FindByVerificationToken(ctx, tx, request.Token)
 	if err != nil {
 		// Log failed attempt
-		_ = service.UserRepository.LogFailedAttempt(ctx, tx, clientIP, "email_verification", request.Token)
+		service.RateLimiter.Record(ctx, RateLimitVerifyEmail, RateLimitKeyIP(clientIP))
 		panic(exception.NewBadRequestError("Invalid or expired verification token"))
 	}
 
@@ -517,81 +386,20 @@ func (service *AuthServiceImpl) VerifyEmail(ctx context.Context, request web.Ver
 	helper.PanicIfError(err)
 
 	// Clear rate limiting for successful verification
-	clearTx, err := service.DB.Begin()
-	if err == nil {
-		_ = service.UserRepository.ClearFailedAttempts(ctx, clearTx, user.Id, "email_verification")
-		clearTx.Commit()
-	}
+	service.RateLimiter.Reset(ctx, RateLimitVerifyEmail, RateLimitKeyIP(clientIP))
 
 	return web.MessageResponse{
 		Message: "Email successfully verified",
 	}
 }
 
-func (service *AuthServiceImpl) isRateLimited(ctx context.Context, tx *sql.Tx, clientIP string, actionType string) bool {
-	fmt.Printf("Checking rate limit for IP: %s, action: %s\n", clientIP, actionType)
-
-	attempts, err := service.UserRepository.GetFailedAttempts(ctx, tx, clientIP, actionType, 5*time.Minute)
-	if err != nil {
-		fmt.Printf("Error checking rate limits: %v\n", err)
-		return false
-	}
-
-	fmt.Printf("Found %d failed attempts\n", attempts)
-	return attempts >= 5
-}
-
-// Ensure the failed attempt logging works properly
-func (service *AuthServiceImpl) logFailedVerificationAttempt(ctx context.Context, tx *sql.Tx, clientIP string, token string) error {
-	fmt.Printf("Logging failed verification attempt from IP: %s\n", clientIP)
-	err := service.UserRepository.LogFailedAttempt(ctx, tx, clientIP, "email_verification", token)
-	if err != nil {
-		fmt.Printf("Failed to log attempt: %v\n", err)
-	}
-	return err
-}
-
-// clearRateLimiting removes rate limiting for a user after successful action
-func (service *AuthServiceImpl) clearRateLimiting(ctx context.Context, tx *sql.Tx, userID uuid.UUID, actionType string) error {
-	// You would need to implement this method in your UserRepository
-	return service.UserRepository.ClearFailedAttempts(ctx, tx, userID, actionType)
-}
-
 func (service *AuthServiceImpl) ForgotPassword(ctx context.Context, request web.EmailRequest) web.MessageResponse {
 	err := service.Validate.Struct(request)
 	helper.PanicIfError(err)
 
-	// Get client IP for rate limiting
+	// Get client IP for rate limiting, allow only 3 reset requests within 15 minutes
 	clientIP := helper.GetClientIP(ctx)
-
-	// Check rate limiting first
-	rateLimitTx, err := service.DB.Begin()
-	if err != nil {
-		panic(exception.NewInternalServerError("Database error: " + err.Error()))
-	}
-
-	var isLimited bool
-	func() {
-		defer func() {
-			if r := recover(); r != nil {
-				rateLimitTx.Rollback()
-				panic(r)
-			}
-		}()
-
-		// Check if rate limited - allow only 3 reset requests within 15 minutes
-		isLimited, err = service.UserRepository.IsRateLimited(ctx, rateLimitTx, clientIP, "password_reset_request", 3, 15*time.Minute)
-		if err != nil {
-			rateLimitTx.Rollback()
-			isLimited = false
-		} else {
-			rateLimitTx.Commit()
-		}
-	}()
-
-	if isLimited {
-		panic(exception.NewTooManyRequestsError("Too many password reset requests. Please try again later."))
-	}
+	service.RateLimiter.Check(ctx, RateLimitForgotPassword, RateLimitKeyIP(clientIP))
 
 	tx, err := service.DB.Begin()
 	helper.PanicIfError(err)
@@ -635,11 +443,7 @@ func (service *AuthServiceImpl) ForgotPassword(ctx context.Context, request web.
 	}
 
 	// Log successful email send (for rate limiting)
-	logTx, err := service.DB.Begin()
-	if err == nil {
-		_ = service.UserRepository.LogFailedAttempt(ctx, logTx, clientIP, "password_reset_request", "")
-		logTx.Commit()
-	}
+	service.RateLimiter.Record(ctx, RateLimitForgotPassword, RateLimitKeyIP(clientIP))
 
 	return web.MessageResponse{
 		Message: "Password reset instructions sent to your email",
@@ -661,18 +465,18 @@ func (service *AuthServiceImpl) ResetPassword(ctx context.Context, request web.R
 
 	// Check for rate limiting based on IP address
 	clientIP := helper.GetClientIP(ctx)
-	if service.isRateLimited(ctx, tx, clientIP, "password_reset") {
-		panic(exception.NewTooManyRequestsError("Too many reset attempts. Please try again later."))
-	}
+	service.RateLimiter.Check(ctx, RateLimitResetPassword, RateLimitKeyIP(clientIP))
 
 	// Find user by reset token
 	user, err := service.UserRepository.FindByResetToken(ctx, tx, request.Token)
 	if err != nil {
 		// Log failed attempt
-		_ = service.logFailedResetAttempt(ctx, tx, clientIP, request.Token)
+		service.RateLimiter.Record(ctx, RateLimitResetPassword, RateLimitKeyIP(clientIP))
 		panic(exception.NewBadRequestError("Invalid or expired reset token"))
 	}
 
+	service.PasswordPolicy.Validate(request.Password, user.Name, user.Email)
+
 	// Hash the new password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
 	helper.PanicIfError(err)
@@ -681,15 +485,306 @@ func (service *AuthServiceImpl) ResetPassword(ctx context.Context, request web.R
 	err = service.UserRepository.UpdatePassword(ctx, tx, user.Id, string(hashedPassword))
 	helper.PanicIfError(err)
 
-	// Clear any rate limiting records for this user now that reset succeeded
-	_ = service.clearRateLimiting(ctx, tx, user.Id, "password_reset")
+	// Clear any rate limiting records for this address now that reset succeeded
+	service.RateLimiter.Reset(ctx, RateLimitResetPassword, RateLimitKeyIP(clientIP))
 
 	return web.MessageResponse{
 		Message: "Password successfully reset",
 	}
 }
 
-func (service *AuthServiceImpl) logFailedResetAttempt(ctx context.Context, tx *sql.Tx, clientIP string, token string) error {
-	// You would need to implement this method in your UserRepository
-	return service.UserRepository.LogFailedAttempt(ctx, tx, clientIP, "password_reset", token)
+func (service *AuthServiceImpl) RefreshToken(ctx context.Context, request web.RefreshTokenRequest) web.TokenResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	tokens, userId, err := service.rotateRefreshToken(ctx, request.RefreshToken)
+	if err != nil {
+		panic(exception.NewUnauthorizedError(err.Error()))
+	}
+
+	// Banned or suspended users cannot keep their session alive
+	err = service.AccountStatusService.CheckAccountStatus(ctx, userId)
+	if err != nil {
+		panic(err)
+	}
+
+	return tokens
+}
+
+// rotateRefreshToken exchanges a refresh token for a new token pair. Errors are returned
+// instead of panicking so that a session revoked on token reuse is still committed.
+func (service *AuthServiceImpl) rotateRefreshToken(ctx context.Context, refreshToken string) (web.TokenResponse, uuid.UUID, error) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	token, err := service.UserSessionRepository.FindRefreshTokenByHash(ctx, tx, helper.HashToken(refreshToken))
+	if err != nil {
+		return web.TokenResponse{}, uuid.Nil, fmt.Errorf("invalid refresh token")
+	}
+
+	session, err := service.UserSessionRepository.FindById(ctx, tx, token.SessionId)
+	if err != nil {
+		return web.TokenResponse{}, uuid.Nil, fmt.Errorf("invalid refresh token")
+	}
+
+	if session.RevokedAt != nil {
+		return web.TokenResponse{}, uuid.Nil, fmt.Errorf("session has been revoked")
+	}
+
+	if !session.IsActive() || time.Now().After(token.ExpiresAt) {
+		return web.TokenResponse{}, uuid.Nil, fmt.Errorf("refresh token expired")
+	}
+
+	user, err := service.UserRepository.FindById(ctx, tx, session.UserId)
+	if err != nil {
+		return web.TokenResponse{}, uuid.Nil, fmt.Errorf("user not found")
+	}
+
+	// A refresh token that was already rotated is being replayed, so the whole session is
+	// treated as compromised. The token is claimed by a conditional update, a concurrent
+	// request with the same token that read it as unused loses here.
+	err = service.UserSessionRepository.MarkRefreshTokenUsed(ctx, tx, token.Id)
+	if token.UsedAt != nil || errors.Is(err, repository.ErrRefreshTokenUsed) {
+		err = service.UserSessionRepository.Revoke(ctx, tx, session.Id, SessionRevokedTokenReuse)
+		helper.PanicIfError(err)
+		return web.TokenResponse{}, uuid.Nil, fmt.Errorf("refresh token reuse detected, session revoked")
+	}
+	helper.PanicIfError(err)
+
+	err = service.UserSessionRepository.Touch(ctx, tx, session.Id, helper.GetClientIP(ctx))
+	helper.PanicIfError(err)
+
+	err = service.UserSessionRepository.ExtendExpiry(ctx, tx, session.Id, time.Now().Add(service.SessionLifetimes.RefreshToken))
+	helper.PanicIfError(err)
+
+	return issueSessionTokens(ctx, tx, service.UserSessionRepository, service.SessionLifetimes, user, session.Id), user.Id, nil
+}
+
+func (service *AuthServiceImpl) Logout(ctx context.Context, request web.RefreshTokenRequest) web.MessageResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	token, err := service.UserSessionRepository.FindRefreshTokenByHash(ctx, tx, helper.HashToken(request.RefreshToken))
+	if err != nil {
+		panic(exception.NewUnauthorizedError("Invalid refresh token"))
+	}
+
+	err = service.UserSessionRepository.Revoke(ctx, tx, token.SessionId, SessionRevokedLogout)
+	helper.PanicIfError(err)
+
+	return web.MessageResponse{
+		Message: "Logged out successfully",
+	}
+}
+
+// VerifyTwoFactorLogin exchanges the pending token from Login plus a TOTP or recovery
+// code for a full session
+func (service *AuthServiceImpl) VerifyTwoFactorLogin(ctx context.Context, request web.TwoFactorLoginRequest) web.LoginResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	claims, err := utils.ValidateMFAPendingToken(request.MfaToken, string(domain.TwoFactorOwnerUser))
+	if err != nil || claims.Purpose != utils.MFAPurposePending {
+		panic(exception.NewUnauthorizedError("Invalid or expired two-factor session, please log in again"))
+	}
+
+	userId, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		panic(exception.NewUnauthorizedError("Invalid two-factor session"))
+	}
+
+	err = service.TwoFactorService.VerifyCode(ctx, domain.TwoFactorOwnerUser, userId, request.Code)
+	if err != nil {
+		panic(exception.NewUnauthorizedError(err.Error()))
+	}
+
+	err = service.AccountStatusService.CheckAccountStatus(ctx, userId)
+	if err != nil {
+		panic(err)
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	user, err := service.UserRepository.FindById(ctx, tx, userId)
+	if err != nil {
+		panic(exception.NewNotFoundError("User not found"))
+	}
+
+	tokens, deletionCancelled := openUserSession(ctx, tx, service.UserRepository, service.UserSessionRepository, service.SessionLifetimes, user)
+
+	return web.LoginResponse{
+		Token:                  tokens.Token,
+		RefreshToken:           tokens.RefreshToken,
+		ExpiresAt:              &tokens.ExpiresAt,
+		DeletionCancelled:      deletionCancelled,
+		PasswordChangeRequired: passwordChangeRequired(ctx, tx, service.UserRepository, user.Id),
+		User:                   &user,
+	}
+}
+
+// SendMagicLink emails a one-time sign-in link. It also serves accounts created through Google
+// that never had a usable password. Unknown addresses get the same answer so the endpoint
+// cannot be used to find out who has an account.
+func (service *AuthServiceImpl) SendMagicLink(ctx context.Context, request web.EmailRequest) web.MessageResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	clientIP := helper.GetClientIP(ctx)
+	service.RateLimiter.Check(ctx, RateLimitSendMagicLink, RateLimitKeyIP(clientIP))
+
+	response := web.MessageResponse{
+		Message: "If an account exists for this email, a sign-in link has been sent",
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	user, err := service.UserRepository.FindByEmail(ctx, tx, request.Email)
+	if err != nil {
+		service.RateLimiter.Record(ctx, RateLimitSendMagicLink, RateLimitKeyIP(clientIP))
+		return response
+	}
+
+	token := helper.GenerateSecureToken(32)
+	err = service.UserRepository.SaveMagicLinkToken(ctx, tx, user.Id, helper.HashToken(token), time.Now().Add(magicLinkTTL))
+	helper.PanicIfError(err)
+
+	magicLink := fmt.Sprintf("%s/magic-link?token=%s", service.ClientURL, token)
+	emailBody := fmt.Sprintf(`
+        <html>
+        <body>
+            <h1>Sign in to EvoConnect</h1>
+            <p>Hello %s,</p>
+            <p><a href="%s">Click here</a> to sign in. The link can be used once and is valid for %d minutes.</p>
+            <p>If you did not request this link, please ignore this email.</p>
+            <p>Best regards,<br/>The EvoConnect Team</p>
+        </body>
+        </html>
+    `, user.Name, magicLink, int(magicLinkTTL.Minutes()))
+
+	// A failure gets the same answer as well, an error would only be returned for existing accounts
+	err = helper.EmailSender(user.Email, "Your EvoConnect sign-in link", emailBody)
+	if err != nil {
+		service.Logger.ErrorContext(ctx, "failed to send sign-in link", "user_id", user.Id, "error", err)
+		return response
+	}
+
+	// Count successful sends only
+	service.RateLimiter.Record(ctx, RateLimitSendMagicLink, RateLimitKeyIP(clientIP))
+
+	return response
+}
+
+// MagicLinkLogin exchanges a sign-in link for a session, the same way Login does after the
+// password check. Users with two-factor enabled still get the second step.
+func (service *AuthServiceImpl) MagicLinkLogin(ctx context.Context, request web.MagicLinkLoginRequest) web.LoginResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	clientIP := helper.GetClientIP(ctx)
+	service.RateLimiter.Check(ctx, RateLimitMagicLinkLogin, RateLimitKeyIP(clientIP))
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	user, err := service.UserRepository.ConsumeMagicLinkToken(ctx, tx, helper.HashToken(request.Token))
+	if err != nil {
+		service.RateLimiter.Record(ctx, RateLimitMagicLinkLogin, RateLimitKeyIP(clientIP))
+		panic(exception.NewUnauthorizedError("Invalid or expired sign-in link"))
+	}
+
+	// Consuming the token locked the user row, the status check has to run in the same transaction
+	err = service.AccountStatusService.CheckAccountStatusInTx(ctx, tx, user.Id)
+	if err != nil {
+		panic(err)
+	}
+
+	if service.TwoFactorService.IsEnabled(ctx, domain.TwoFactorOwnerUser, user.Id) {
+		return web.LoginResponse{
+			MfaRequired: true,
+			MfaToken:    generateUserMFAPendingToken(user.Id),
+		}
+	}
+
+	tokens, deletionCancelled := openUserSession(ctx, tx, service.UserRepository, service.UserSessionRepository, service.SessionLifetimes, user)
+
+	return web.LoginResponse{
+		Token:                  tokens.Token,
+		RefreshToken:           tokens.RefreshToken,
+		ExpiresAt:              &tokens.ExpiresAt,
+		DeletionCancelled:      deletionCancelled,
+		PasswordChangeRequired: passwordChangeRequired(ctx, tx, service.UserRepository, user.Id),
+		User:                   &user,
+	}
+}
+
+// ChangePassword replaces the password of a logged in user and signs out their other sessions
+func (service *AuthServiceImpl) ChangePassword(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID, request web.ChangePasswordRequest) web.MessageResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	// Counts wrong current passwords too, so the endpoint cannot be used to guess them
+	service.RateLimiter.Allow(ctx, RateLimitChangePassword, RateLimitKeyUser(userId))
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	user, err := service.UserRepository.FindById(ctx, tx, userId)
+	if err != nil {
+		panic(exception.NewNotFoundError("User not found"))
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.CurrentPassword))
+	if err != nil {
+		panic(exception.NewUnauthorizedError("Current password is incorrect"))
+	}
+	if request.NewPassword == request.CurrentPassword {
+		panic(exception.NewBadRequestError("New password must be different from the current password"))
+	}
+
+	service.PasswordPolicy.Validate(request.NewPassword, user.Name, user.Email)
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(request.NewPassword), bcrypt.DefaultCost)
+	helper.PanicIfError(err)
+
+	err = service.UserRepository.UpdatePassword(ctx, tx, userId, string(hashedPassword))
+	helper.PanicIfError(err)
+
+	err = service.UserSessionRepository.RevokeAllByUserId(ctx, tx, userId, &sessionId, SessionRevokedPasswordChange)
+	helper.PanicIfError(err)
+
+	return web.MessageResponse{
+		Message: "Password changed successfully",
+	}
+}
+
+// openUserSession starts a session after a completed login. Logging in is also how users keep an
+// account they asked to delete, so a pending deletion is cancelled here.
+func openUserSession(ctx context.Context, tx *sql.Tx, userRepository repository.UserRepository, sessionRepository repository.UserSessionRepository, lifetimes SessionLifetimes, user domain.User) (web.TokenResponse, bool) {
+	deletionCancelled, err := userRepository.CancelDeletion(ctx, tx, user.Id)
+	helper.PanicIfError(err)
+
+	return issueUserSession(ctx, tx, sessionRepository, lifetimes, user), deletionCancelled
+}
+
+func passwordChangeRequired(ctx context.Context, tx *sql.Tx, userRepository repository.UserRepository, userId uuid.UUID) bool {
+	required, err := userRepository.IsPasswordChangeRequired(ctx, tx, userId)
+	helper.PanicIfError(err)
+	return required
+}
+
+func generateUserMFAPendingToken(userId uuid.UUID) string {
+	token, err := utils.GenerateMFAPendingToken(userId.String(), string(domain.TwoFactorOwnerUser), utils.MFAPurposePending, mfaPendingTokenTTL)
+	helper.PanicIfError(err)
+	return token
 }
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"evoconnect/backend/config"
+	"evoconnect/backend/exception"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/domain"
+	"evoconnect/backend/model/web"
+	"evoconnect/backend/repository"
+	"evoconnect/backend/utils"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// fakeSessionRepository keeps sessions and refresh tokens in memory, with the same
+// claim-once semantics as the conditional update of MarkRefreshTokenUsed
+type fakeSessionRepository struct {
+	repository.UserSessionRepository
+
+	mu       sync.Mutex
+	sessions map[uuid.UUID]domain.UserSession
+	tokens   map[string]domain.UserRefreshToken
+
+	// When set, every lookup waits here, so concurrent refreshes all read the token before any claims it
+	lookupBarrier *sync.WaitGroup
+}
+
+func newFakeSessionRepository() *fakeSessionRepository {
+	return &fakeSessionRepository{
+		sessions: map[uuid.UUID]domain.UserSession{},
+		tokens:   map[string]domain.UserRefreshToken{},
+	}
+}
+
+func (fake *fakeSessionRepository) Save(ctx context.Context, tx *sql.Tx, session domain.UserSession) domain.UserSession {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.sessions[session.Id] = session
+	return session
+}
+
+func (fake *fakeSessionRepository) FindById(ctx context.Context, tx *sql.Tx, sessionId uuid.UUID) (domain.UserSession, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	session, ok := fake.sessions[sessionId]
+	if !ok {
+		return session, errors.New("session not found")
+	}
+	return session, nil
+}
+
+func (fake *fakeSessionRepository) Touch(ctx context.Context, tx *sql.Tx, sessionId uuid.UUID, ipAddress string) error {
+	return nil
+}
+
+func (fake *fakeSessionRepository) ExtendExpiry(ctx context.Context, tx *sql.Tx, sessionId uuid.UUID, expiresAt time.Time) error {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	session := fake.sessions[sessionId]
+	session.ExpiresAt = expiresAt
+	fake.sessions[sessionId] = session
+	return nil
+}
+
+func (fake *fakeSessionRepository) Revoke(ctx context.Context, tx *sql.Tx, sessionId uuid.UUID, reason string) error {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	session := fake.sessions[sessionId]
+	if session.RevokedAt == nil {
+		now := time.Now()
+		session.RevokedAt = &now
+		session.RevokedReason = &reason
+		fake.sessions[sessionId] = session
+	}
+	return nil
+}
+
+func (fake *fakeSessionRepository) SaveRefreshToken(ctx context.Context, tx *sql.Tx, token domain.UserRefreshToken) domain.UserRefreshToken {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.tokens[token.TokenHash] = token
+	return token
+}
+
+func (fake *fakeSessionRepository) FindRefreshTokenByHash(ctx context.Context, tx *sql.Tx, tokenHash string) (domain.UserRefreshToken, error) {
+	fake.mu.Lock()
+	token, ok := fake.tokens[tokenHash]
+	barrier := fake.lookupBarrier
+	fake.mu.Unlock()
+
+	if barrier != nil {
+		barrier.Done()
+		barrier.Wait()
+	}
+	if !ok {
+		return token, errors.New("refresh token not found")
+	}
+	return token, nil
+}
+
+func (fake *fakeSessionRepository) MarkRefreshTokenUsed(ctx context.Context, tx *sql.Tx, tokenId uuid.UUID) error {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	for hash, token := range fake.tokens {
+		if token.Id != tokenId {
+			continue
+		}
+		if token.UsedAt != nil {
+			return repository.ErrRefreshTokenUsed
+		}
+		now := time.Now()
+		token.UsedAt = &now
+		fake.tokens[hash] = token
+		return nil
+	}
+	return errors.New("refresh token not found")
+}
+
+type fakeUserRepository struct {
+	repository.UserRepository
+	user domain.User
+}
+
+func (fake fakeUserRepository) FindById(ctx context.Context, tx *sql.Tx, userId uuid.UUID) (domain.User, error) {
+	if userId != fake.user.Id {
+		return domain.User{}, errors.New("user not found")
+	}
+	return fake.user, nil
+}
+
+type fakeAccountStatusService struct {
+	AccountStatusService
+}
+
+func (fakeAccountStatusService) CheckAccountStatus(ctx context.Context, userId uuid.UUID) error {
+	return nil
+}
+
+// newRefreshTestService returns an auth service with one active session and its refresh token
+func newRefreshTestService(t *testing.T) (*AuthServiceImpl, *fakeSessionRepository, uuid.UUID, string) {
+	t.Helper()
+	if err := utils.InitJWT(config.JWTConfig{Secret: "refresh-test-secret-0123456789abcdef"}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	user := domain.User{Id: uuid.New(), Email: "user@example.com"}
+	sessions := newFakeSessionRepository()
+	sessionId := uuid.New()
+	sessions.sessions[sessionId] = domain.UserSession{
+		Id:        sessionId,
+		UserId:    user.Id,
+		ExpiresAt: time.Now().Add(time.Hour),
+		CreatedAt: time.Now(),
+	}
+
+	refreshToken := "initial-refresh-token"
+	sessions.tokens[helper.HashToken(refreshToken)] = domain.UserRefreshToken{
+		Id:        uuid.New(),
+		SessionId: sessionId,
+		TokenHash: helper.HashToken(refreshToken),
+		ExpiresAt: time.Now().Add(time.Hour),
+		CreatedAt: time.Now(),
+	}
+
+	service := &AuthServiceImpl{
+		UserRepository:        fakeUserRepository{user: user},
+		UserSessionRepository: sessions,
+		AccountStatusService:  fakeAccountStatusService{},
+		SessionLifetimes:      SessionLifetimes{AccessToken: 15 * time.Minute, RefreshToken: 30 * 24 * time.Hour},
+		DB:                    newFakeDB(t),
+		Validate:              validator.New(),
+	}
+	return service, sessions, sessionId, refreshToken
+}
+
+func refresh(service *AuthServiceImpl, refreshToken string) (tokens web.TokenResponse, failure interface{}) {
+	failure = recoverPanic(func() {
+		tokens = service.RefreshToken(context.Background(), web.RefreshTokenRequest{RefreshToken: refreshToken})
+	})
+	return tokens, failure
+}
+
+func TestRefreshTokenRotates(t *testing.T) {
+	service, sessions, sessionId, refreshToken := newRefreshTestService(t)
+
+	first, failure := refresh(service, refreshToken)
+	if failure != nil {
+		t.Fatalf("first refresh failed: %v", failure)
+	}
+	if first.Token == "" || first.RefreshToken == "" || first.RefreshToken == refreshToken {
+		t.Fatalf("refresh did not issue a new token pair: %+v", first)
+	}
+
+	// The rotated token keeps the session alive
+	if _, failure := refresh(service, first.RefreshToken); failure != nil {
+		t.Fatalf("refresh with the rotated token failed: %v", failure)
+	}
+	if session := sessions.sessions[sessionId]; session.RevokedAt != nil {
+		t.Fatalf("session revoked after a normal rotation: %s", *session.RevokedReason)
+	}
+}
+
+func TestRefreshTokenReuseRevokesSession(t *testing.T) {
+	service, sessions, sessionId, refreshToken := newRefreshTestService(t)
+
+	rotated, failure := refresh(service, refreshToken)
+	if failure != nil {
+		t.Fatalf("first refresh failed: %v", failure)
+	}
+
+	_, failure = refresh(service, refreshToken)
+	if _, ok := failure.(exception.UnauthorizedError); !ok {
+		t.Fatalf("replayed refresh token = %v, want an unauthorized error", failure)
+	}
+
+	session := sessions.sessions[sessionId]
+	if session.RevokedAt == nil || *session.RevokedReason != SessionRevokedTokenReuse {
+		t.Fatalf("session not revoked for token reuse: %+v", session)
+	}
+
+	// The token issued by the legitimate rotation dies with the session
+	if _, failure := refresh(service, rotated.RefreshToken); failure == nil {
+		t.Fatal("rotated token still works after reuse was detected")
+	}
+}
+
+func TestRefreshTokenConcurrentReuse(t *testing.T) {
+	service, sessions, sessionId, refreshToken := newRefreshTestService(t)
+
+	const requests = 2
+	sessions.lookupBarrier = &sync.WaitGroup{}
+	sessions.lookupBarrier.Add(requests)
+
+	failures := make(chan interface{}, requests)
+	var wait sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			_, failure := refresh(service, refreshToken)
+			failures <- failure
+		}()
+	}
+	wait.Wait()
+	close(failures)
+
+	succeeded := 0
+	for failure := range failures {
+		if failure == nil {
+			succeeded++
+		}
+	}
+	if succeeded > 1 {
+		t.Fatalf("%d concurrent refreshes with the same token succeeded", succeeded)
+	}
+
+	session := sessions.sessions[sessionId]
+	if session.RevokedAt == nil || *session.RevokedReason != SessionRevokedTokenReuse {
+		t.Fatalf("concurrent reuse not detected, session: %+v", session)
+	}
+}
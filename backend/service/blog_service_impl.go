@@ -8,10 +8,7 @@ import (
 	"evoconnect/backend/repository"
 	"fmt"
 	"github.com/google/uuid"
-	"io"
 	"mime/multipart"
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
 	"errors"
@@ -132,13 +129,11 @@ func (s *BlogServiceImpl) Create(ctx context.Context, req web.BlogCreateRequest,
 func (s *BlogServiceImpl) sendBlogNotifications(ctx context.Context, blog domain.Blog, user domain.User) {
     // Cek apakah service tersedia
     if s.NotificationService == nil {
-        fmt.Println("DEBUG: NotificationService is nil")
         return
     }
 
     // Hanya kirim notifikasi dengan probabilitas tertentu (70%)
     if rand.Intn(100) > 70 {
-        fmt.Println("DEBUG: Skipping notifications for this blog (random)")
         return
     }
     
@@ -221,14 +216,10 @@ func (s *BlogServiceImpl) FindBySlug(ctx context.Context, slug string) (web.Blog
         return web.BlogResponse{}, fmt.Errorf("blog dengan slug %s tidak ditemukan: %w", slug, err)
     }
     
-    // Debug untuk melihat nilai status
-    fmt.Printf("DEBUG: Blog status: '%s'\n", blog.Status)
-    
     // Cek status blog terlebih dahulu
     if blog.Status == "taken_down" {
         // Ambil ID user yang sedang login dari context
         currentUserIdStr, ok := ctx.Value("user_id").(string)
-        fmt.Printf("DEBUG: Current user ID: '%s', ok: %v\n", currentUserIdStr, ok)
         
         // Jika tidak ada user yang login atau user bukan pemilik blog, kembalikan error not found
         if !ok || currentUserIdStr != blog.UserID {
@@ -236,7 +227,6 @@ func (s *BlogServiceImpl) FindBySlug(ctx context.Context, slug string) (web.Blog
         }
         
         // Jika sampai di sini, berarti user adalah pemilik blog
-        fmt.Printf("DEBUG: User is owner, showing blog with warning\n")
     }
 
     userUUID, err := uuid.Parse(blog.UserID)
@@ -267,7 +257,6 @@ func (s *BlogServiceImpl) FindBySlug(ctx context.Context, slug string) (web.Blog
     // Tambahkan warning jika blog di-take down dan user adalah pemiliknya
     if blog.Status == "taken_down" && currentUserIdStr == blog.UserID {
         response.Warning = "This blog has been removed for violating our community guidelines. Only you can view this content."
-        fmt.Printf("DEBUG: Added warning to response\n")
     }
     
     return response, nil
@@ -356,26 +345,16 @@ func (s *BlogServiceImpl) UploadPhoto(ctx context.Context, blogId string, userId
 		return "", fmt.Errorf("anda tidak memiliki izin untuk mengupload photo untuk blog ini")
 	}
 
-	uploadDir := fmt.Sprintf("uploads/blogs/%s", userId)
-	if err := os.MkdirAll(uploadDir, os.ModePerm); err != nil {
-		return "", fmt.Errorf("gagal membuat direktori upload: %w", err)
-	}
-
-	fileName := fmt.Sprintf("blog-%d%s", time.Now().Unix(), filepath.Ext(fileHeader.Filename))
-	filePath := fmt.Sprintf("%s/%s", uploadDir, fileName)
-	outFile, err := os.Create(filePath)
-	if err != nil {
-		return "", fmt.Errorf("gagal menyimpan file: %w", err)
-	}
-	defer outFile.Close()
-
-	_, err = io.Copy(outFile, file)
+	// Diperiksa dengan kebijakan upload yang sama seperti gambar blog lainnya
+	filePath, err := helper.SaveBlogImage(file, fileHeader, userId)
 	if err != nil {
+		if _, ok := err.(helper.UploadRejectedError); ok {
+			return "", err
+		}
 		return "", fmt.Errorf("gagal menyimpan file: %w", err)
 	}
 
-	publicPath := fmt.Sprintf("/static/blogs/%s/%s", userId, fileName)
-	return publicPath, nil
+	return filePath, nil
 }
 
 func (s *BlogServiceImpl) GetRandomBlogs(ctx context.Context, limit int) ([]web.BlogResponse, error) {
@@ -558,6 +537,7 @@ func buildBlogResponse(blog domain.Blog, user domain.User, isConnected ...bool)
         Category:  blog.Category,
         Content:   blog.Content,
         Photo:     blog.ImagePath,
+        PhotoVariants: helper.ImageVariantsOf(blog.ImagePath),
         UserID:    blog.UserID,
         Warning:   "", // Default kosong, akan diisi di fungsi yang memanggil
         CreatedAt: blog.CreatedAt,
@@ -567,6 +547,7 @@ func buildBlogResponse(blog domain.Blog, user domain.User, isConnected ...bool)
             Name:        user.Name,
             Username:    user.Username,
             Photo:       user.Photo,
+            PhotoVariants: helper.ImageVariantsOf(user.Photo),
             IsConnected: connected,
         },
     }
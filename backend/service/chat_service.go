@@ -22,4 +22,5 @@ type ChatService interface {
 	FindMessagesByConversationId(ctx context.Context, userId, conversationId uuid.UUID, limit, offset int) web.MessagesResponse
 	UpdateMessage(ctx context.Context, userId, messageId uuid.UUID, request web.SendMessageRequest) web.ChatMessageResponse
 	DeleteMessage(ctx context.Context, userId, messageId uuid.UUID)
+	GetMessageFileUrl(ctx context.Context, userId, messageId uuid.UUID) web.SignedFileResponse
 }
@@ -10,31 +10,34 @@ import (
 	"evoconnect/backend/repository"
 	"evoconnect/backend/utils"
 	"fmt"
-	"io"
+	"log/slog"
 	"mime/multipart"
-	"os"
-	"path/filepath"
 	"time"
 
-	"github.com/gabriel-vasile/mimetype"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	_ "github.com/pusher/pusher-http-go/v5"
 )
 
 type ChatServiceImpl struct {
-	ChatRepository repository.ChatRepository
-	DB             *sql.DB
-	Validate       *validator.Validate
-	UserRepository repository.UserRepository
+	ChatRepository    repository.ChatRepository
+	DB                *sql.DB
+	Validate          *validator.Validate
+	UserRepository    repository.UserRepository
+	FileScanService   FileScanService
+	FileAccessService FileAccessService
+	Logger            *slog.Logger
 }
 
-func NewChatService(chatRepository repository.ChatRepository, userRepository repository.UserRepository, DB *sql.DB, validator *validator.Validate) ChatService {
+func NewChatService(chatRepository repository.ChatRepository, userRepository repository.UserRepository, fileScanService FileScanService, fileAccessService FileAccessService, DB *sql.DB, validator *validator.Validate, logger *slog.Logger) ChatService {
 	return &ChatServiceImpl{
-		ChatRepository: chatRepository,
-		DB:             DB,
-		Validate:       validator,
-		UserRepository: userRepository,
+		ChatRepository:    chatRepository,
+		DB:                DB,
+		Validate:          validator,
+		UserRepository:    userRepository,
+		FileScanService:   fileScanService,
+		FileAccessService: fileAccessService,
+		Logger:            logger,
 	}
 }
 
@@ -187,16 +190,15 @@ func (service *ChatServiceImpl) CreateConversation(ctx context.Context, userId u
 	tx4, err := service.DB.Begin()
 	helper.PanicIfError(err)
 
-	for i, participantId := range participantIds {
+	for _, participantId := range participantIds {
 		participant := domain.ConversationParticipant{
 			ConversationId: conversation.Id,
 			UserId:         participantId,
 		}
-		fmt.Printf("Adding participant %d of %d: %s\n", i+1, len(participantIds), participantId)
 		_, err := service.ChatRepository.AddParticipant(ctx, tx4, participant)
 		if err != nil {
 			tx4.Rollback()
-			fmt.Printf("Error adding participant: %v\n", err)
+			service.Logger.ErrorContext(ctx, "error adding participant", "error", err)
 			helper.PanicIfError(err)
 		}
 	}
@@ -245,7 +247,7 @@ func (service *ChatServiceImpl) CreateConversation(ctx context.Context, userId u
 	go func() {
 		for _, participant := range conversation.Participants {
 			if participant.UserId != userId {
-				utils.PusherClient.Trigger(fmt.Sprintf("private-user-%s", participant.UserId), "new-conversation", conversationResponse)
+				utils.TriggerPusher(fmt.Sprintf("private-user-%s", participant.UserId), "new-conversation", conversationResponse)
 			}
 		}
 		// fmt.Println("Notifications sent to participants")
@@ -315,7 +317,7 @@ func (service *ChatServiceImpl) MarkConversationAsRead(ctx context.Context, user
 	conversation.UnreadCount = 0
 
 	// Trigger Pusher event to update read status
-	utils.PusherClient.Trigger(fmt.Sprintf("private-conversation-%s", conversationId), "messages-read", map[string]interface{}{
+	utils.TriggerPusher(fmt.Sprintf("private-conversation-%s", conversationId), "messages-read", map[string]interface{}{
 		"user_id":         userId,
 		"conversation_id": conversationId,
 		"read_at":         time.Now(),
@@ -368,7 +370,7 @@ func (service *ChatServiceImpl) SendMessage(ctx context.Context, userId, convers
 	// Trigger Pusher event
 	messageResponse := service.toChatMessageResponse(message)
 
-	utils.PusherClient.Trigger(fmt.Sprintf("private-conversation-%s", conversationId), "new-message", messageResponse)
+	utils.TriggerPusher(fmt.Sprintf("private-conversation-%s", conversationId), "new-message", messageResponse)
 
 	// Also trigger notifications for each participant except the sender
 	conversation, err := service.ChatRepository.FindConversationById(ctx, tx, conversationId)
@@ -377,7 +379,7 @@ func (service *ChatServiceImpl) SendMessage(ctx context.Context, userId, convers
 	for _, participant := range conversation.Participants {
 		if participant.UserId != userId {
 			// Send notification to each participant's personal channel
-			utils.PusherClient.Trigger(fmt.Sprintf("private-user-%s", participant.UserId), "new-message-notification", map[string]interface{}{
+			utils.TriggerPusher(fmt.Sprintf("private-user-%s", participant.UserId), "new-message-notification", map[string]interface{}{
 				"message":         messageResponse,
 				"sender_name":     user.Name,
 				"conversation_id": conversationId,
@@ -388,17 +390,21 @@ func (service *ChatServiceImpl) SendMessage(ctx context.Context, userId, convers
 	return messageResponse
 }
 
+// chatUploadPolicies maps a file message type to the files it may carry
+var chatUploadPolicies = map[string]helper.UploadPolicy{
+	"image":    helper.UploadPolicyChatImage,
+	"document": helper.UploadPolicyChatDocument,
+	"audio":    helper.UploadPolicyChatAudio,
+}
+
 func (service *ChatServiceImpl) SendFileMessage(ctx context.Context, userId, conversationId uuid.UUID, messageType string, fileHeader *multipart.FileHeader) web.ChatMessageResponse {
-	if messageType != "image" && messageType != "document" && messageType != "audio" {
+	policy, ok := chatUploadPolicies[messageType]
+	if !ok {
 		panic(exception.NewBadRequestError("Invalid message type"))
 	}
 
-	// Validate file size
-	if (messageType == "image" && fileHeader.Size > 4*1024*1024) ||
-		(messageType == "document" && fileHeader.Size > 10*1024*1024) ||
-		(messageType == "audio" && fileHeader.Size > 10*1024*1024) {
-		panic(exception.NewBadRequestError("File size exceeds maximum allowed limit"))
-	}
+	// Runs after the commit deferred below
+	defer service.FileScanService.Wake()
 
 	tx, err := service.DB.Begin()
 	helper.PanicIfError(err)
@@ -409,46 +415,32 @@ func (service *ChatServiceImpl) SendFileMessage(ctx context.Context, userId, con
 		panic(exception.NewForbiddenError("You are not a participant in this conversation"))
 	}
 
-	// Create upload directory if it doesn't exist
-	uploadDir := fmt.Sprintf("uploads/chat/%s", userId)
-	err = os.MkdirAll(uploadDir, os.ModePerm)
-	helper.PanicIfError(err)
-
-	// Generate unique filename
-	fileExt := filepath.Ext(fileHeader.Filename)
-	fileName := fmt.Sprintf("%s-%d%s", messageType, time.Now().Unix(), fileExt)
-	filePath := filepath.Join(uploadDir, fileName)
-
 	// Open uploaded file
 	file, err := fileHeader.Open()
 	helper.PanicIfError(err)
 	defer file.Close()
 
-	// Detect MIME type
-	mimeType, err := mimetype.DetectReader(file)
-	helper.PanicIfError(err)
-
-	// Reset file pointer
-	file.Seek(0, io.SeekStart)
-
-	// Create destination file
-	dst, err := os.Create(filePath)
-	helper.PanicIfError(err)
-	defer dst.Close()
-
-	// Copy file contents
-	_, err = io.Copy(dst, file)
+	// Check the content against the policy of the message type, then store it in quarantine
+	// until the malware scan is clean
+	uploadResult, err := helper.UploadFile(file, fileHeader, helper.FileUploadOptions{
+		EntityDir:  "chat",
+		EntityID:   userId.String(),
+		FilePrefix: messageType,
+		Policy:     policy,
+		Quarantine: true,
+	})
 	helper.PanicIfError(err)
+	service.FileScanService.Queue(ctx, tx, userId, uploadResult.FilePath, uploadResult.OriginalFilename, domain.FileScanKindChat)
 
 	// Create message
 	message := domain.Message{
 		ConversationId: conversationId,
 		SenderId:       userId,
 		MessageType:    messageType,
-		FilePath:       filePath,
-		FileName:       fileHeader.Filename,
+		FilePath:       uploadResult.FilePath,
+		FileName:       uploadResult.OriginalFilename,
 		FileSize:       int(fileHeader.Size),
-		FileType:       mimeType.String(),
+		FileType:       uploadResult.ContentType,
 		IsRead:         false,
 	}
 
@@ -461,7 +453,7 @@ func (service *ChatServiceImpl) SendFileMessage(ctx context.Context, userId, con
 
 	// Trigger Pusher event
 	messageResponse := service.toChatMessageResponse(message)
-	utils.PusherClient.Trigger(fmt.Sprintf("private-conversation-%s", conversationId), "new-message", messageResponse)
+	utils.TriggerPusher(fmt.Sprintf("private-conversation-%s", conversationId), "new-message", messageResponse)
 
 	// Also trigger notifications for each participant except the sender
 	conversation, err := service.ChatRepository.FindConversationById(ctx, tx, conversationId)
@@ -470,7 +462,7 @@ func (service *ChatServiceImpl) SendFileMessage(ctx context.Context, userId, con
 	for _, participant := range conversation.Participants {
 		if participant.UserId != userId {
 			// Send notification to each participant's personal channel
-			utils.PusherClient.Trigger(fmt.Sprintf("private-user-%s", participant.UserId), "new-message-notification", map[string]interface{}{
+			utils.TriggerPusher(fmt.Sprintf("private-user-%s", participant.UserId), "new-message-notification", map[string]interface{}{
 				"message":         messageResponse,
 				"sender_name":     user.Name,
 				"conversation_id": conversationId,
@@ -546,7 +538,7 @@ func (service *ChatServiceImpl) UpdateMessage(ctx context.Context, userId, messa
 
 	// Trigger Pusher event
 	messageResponse := service.toChatMessageResponse(message)
-	utils.PusherClient.Trigger(fmt.Sprintf("private-conversation-%s", message.ConversationId), "message-updated", messageResponse)
+	utils.TriggerPusher(fmt.Sprintf("private-conversation-%s", message.ConversationId), "message-updated", messageResponse)
 
 	return messageResponse
 }
@@ -568,10 +560,10 @@ func (service *ChatServiceImpl) DeleteMessage(ctx context.Context, userId, messa
 
 	// If message has a file, delete it
 	if message.FilePath != "" {
-		err = os.Remove(message.FilePath)
+		err = helper.DeleteFile(message.FilePath)
 		if err != nil {
 			// Log but continue - don't stop if file can't be deleted
-			fmt.Printf("Warning: Could not delete file %s: %v\n", message.FilePath, err)
+			service.Logger.WarnContext(ctx, "failed to delete message file", "path", message.FilePath, "error", err)
 		}
 	}
 
@@ -580,8 +572,37 @@ func (service *ChatServiceImpl) DeleteMessage(ctx context.Context, userId, messa
 	helper.PanicIfError(err)
 
 	// Trigger Pusher event
-	utils.PusherClient.Trigger(fmt.Sprintf("private-conversation-%s", message.ConversationId), "message-deleted", map[string]interface{}{
+	utils.TriggerPusher(fmt.Sprintf("private-conversation-%s", message.ConversationId), "message-deleted", map[string]interface{}{
 		"message_id":      messageId,
 		"conversation_id": message.ConversationId,
 	})
 }
+
+// GetMessageFileUrl issues a download link for the file of a message to a participant of its conversation
+func (service *ChatServiceImpl) GetMessageFileUrl(ctx context.Context, userId, messageId uuid.UUID) web.SignedFileResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	message, err := service.ChatRepository.FindMessageById(ctx, tx, messageId)
+	if err != nil || message.DeletedAt != nil {
+		panic(exception.NewNotFoundError("Message not found"))
+	}
+
+	if !service.validateParticipant(ctx, tx, message.ConversationId, userId) {
+		panic(exception.NewForbiddenError("You are not a participant in this conversation"))
+	}
+
+	if message.FilePath == "" {
+		panic(exception.NewNotFoundError("This message has no file"))
+	}
+	panicIfNotScanned(service.FileScanService.FindStatus(ctx, tx, message.FilePath), "file")
+
+	return service.FileAccessService.IssueURL(ctx, tx, domain.FileAccessLog{
+		UserId:        userId,
+		FileKey:       message.FilePath,
+		Action:        domain.FileAccessDownload,
+		ReferenceType: domain.FileReferenceMessage,
+		ReferenceId:   message.Id,
+	}, message.FileName)
+}
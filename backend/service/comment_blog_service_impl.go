@@ -82,9 +82,6 @@ func (service *CommentBlogServiceImpl) Create(ctx context.Context, blogId uuid.U
         userName := user.Name
         blogTitle := blog.Title
         
-        fmt.Printf("DEBUG: Sending blog comment notification. From: %s, To: %s, BlogID: %s\n", 
-            userId, blogUserID, blogId)
-        
         refType := "blog_comment"
         go func() {
             service.NotificationService.Create(
@@ -272,9 +269,6 @@ func (service *CommentBlogServiceImpl) Reply(ctx context.Context, commentId uuid
             blogTitle = blog.Title
         }
         
-        fmt.Printf("DEBUG: Sending blog comment reply notification. From: %s, To: %s, CommentID: %s\n", 
-            userId, parentUserId, commentId)
-        
         refType := "blog_comment_reply"
         go func() {
             service.NotificationService.Create(
@@ -221,7 +221,6 @@ func (service *CommentServiceImpl) Reply(ctx context.Context, commentId uuid.UUI
 	// Cari komentar yang akan dibalas
 	parentComment, err := service.CommentRepository.FindById(ctx, tx, commentId)
 	if err != nil {
-		fmt.Println("Error finding parent comment:", err)
 		panic(exception.NewNotFoundError("Comment not found"))
 	}
 
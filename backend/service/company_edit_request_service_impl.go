@@ -10,7 +10,7 @@ import (
 	"evoconnect/backend/model/web"
 	"evoconnect/backend/repository"
 	"fmt"
-	"log"
+	"log/slog"
 	"mime/multipart"
 	"time"
 
@@ -28,8 +28,10 @@ type CompanyManagementServiceImpl struct {
 	AdminRepository              repository.AdminRepository
 	NotificationService          NotificationService
 	ReportRepository             repository.ReportRepository // Optional, can be nil if not used
+	AuditLogService              AuditLogService
 	DB                           *sql.DB
 	Validate                     *validator.Validate
+	Logger                       *slog.Logger
 }
 
 // Update the NewCompanyManagementService function
@@ -43,8 +45,10 @@ func NewCompanyManagementService(
 	adminRepository repository.AdminRepository,
 	notificationService NotificationService,
 	reportRepo repository.ReportRepository,
+	auditLogService AuditLogService,
 	db *sql.DB,
 	validate *validator.Validate,
+	logger *slog.Logger,
 ) CompanyManagementService {
 	return &CompanyManagementServiceImpl{
 		CompanyRepository:            companyRepository,
@@ -56,8 +60,10 @@ func NewCompanyManagementService(
 		AdminRepository:              adminRepository,
 		NotificationService:          notificationService,
 		ReportRepository:             reportRepo, // Optional, can be nil if not used
+		AuditLogService:              auditLogService,
 		DB:                           db,
 		Validate:                     validate,
+		Logger:                       logger,
 	}
 }
 
@@ -118,18 +124,19 @@ func (service *CompanyManagementServiceImpl) GetAllCompanies(ctx context.Context
 		}
 
 		response := web.CompanyDetailResponse{
-			Id:          company.Id.String(),
-			Name:        company.Name,
-			LinkedinUrl: company.LinkedinUrl,
-			Website:     company.Website,
-			Industry:    company.Industry,
-			Size:        company.Size,
-			Type:        company.Type,
-			Logo:        company.Logo,
-			Tagline:     company.Tagline,
-			IsVerified:  company.IsVerified,
-			CreatedAt:   company.CreatedAt,
-			UpdatedAt:   company.UpdatedAt,
+			Id:           company.Id.String(),
+			Name:         company.Name,
+			LinkedinUrl:  company.LinkedinUrl,
+			Website:      company.Website,
+			Industry:     company.Industry,
+			Size:         company.Size,
+			Type:         company.Type,
+			Logo:         company.Logo,
+			LogoVariants: helper.ImageVariantsOf(company.Logo),
+			Tagline:      company.Tagline,
+			IsVerified:   company.IsVerified,
+			CreatedAt:    company.CreatedAt,
+			UpdatedAt:    company.UpdatedAt,
 
 			// Follow information
 			IsFollowing:    followStatus[company.Id],
@@ -178,6 +185,7 @@ func (service *CompanyManagementServiceImpl) GetMyCompanies(ctx context.Context,
 			Size:           company.Size,
 			Type:           company.Type,
 			Logo:           company.Logo,
+			LogoVariants:   helper.ImageVariantsOf(company.Logo),
 			Tagline:        company.Tagline,
 			IsVerified:     company.IsVerified,
 			CreatedAt:      company.CreatedAt,
@@ -359,19 +367,20 @@ func (service *CompanyManagementServiceImpl) GetCompanyDetail(ctx context.Contex
 
 	// Build response
 	response := web.CompanyDetailResponse{
-		Id:          company.Id.String(),
-		Name:        company.Name,
-		LinkedinUrl: company.LinkedinUrl,
-		Website:     company.Website,
-		Industry:    company.Industry,
-		Size:        company.Size,
-		Type:        company.Type,
-		Logo:        company.Logo,
-		Tagline:     company.Tagline,
-		IsVerified:  company.IsVerified,
-		CreatedAt:   company.CreatedAt,
-		UpdatedAt:   company.UpdatedAt,
-		TakenDownAt: company.TakenDownAt,
+		Id:           company.Id.String(),
+		Name:         company.Name,
+		LinkedinUrl:  company.LinkedinUrl,
+		Website:      company.Website,
+		Industry:     company.Industry,
+		Size:         company.Size,
+		Type:         company.Type,
+		Logo:         company.Logo,
+		LogoVariants: helper.ImageVariantsOf(company.Logo),
+		Tagline:      company.Tagline,
+		IsVerified:   company.IsVerified,
+		CreatedAt:    company.CreatedAt,
+		UpdatedAt:    company.UpdatedAt,
+		TakenDownAt:  company.TakenDownAt,
 
 		// Follow information
 		IsFollowing:    isFollowing,
@@ -555,10 +564,9 @@ func (service *CompanyManagementServiceImpl) DeleteCompany(ctx context.Context,
 	if companyLogo != "" {
 		err = helper.DeleteFile(companyLogo)
 		if err != nil {
-			log.Printf("Failed to delete logo file: %v", err)
+			service.Logger.ErrorContext(ctx, "failed to delete company logo", "path", companyLogo, "error", err)
 			panic(exception.NewInternalServerError("Failed to delete logo file"))
 		}
-		log.Printf("Logo file deleted successfully: %s", companyLogo)
 	}
 
 	return nil
@@ -599,10 +607,9 @@ func (service *CompanyManagementServiceImpl) DeleteCompanyEditRequest(ctx contex
 	if companyLogo != "" {
 		err = helper.DeleteFile(companyLogo)
 		if err != nil {
-			log.Printf("Failed to delete logo file: %v", err)
+			service.Logger.ErrorContext(ctx, "failed to delete company logo", "path", companyLogo, "error", err)
 			panic(exception.NewInternalServerError("Failed to delete logo file"))
 		}
-		log.Printf("Logo file deleted successfully: %s", companyLogo)
 	}
 
 	return nil
@@ -677,6 +684,11 @@ func (service *CompanyManagementServiceImpl) ReviewEditRequest(ctx context.Conte
 		panic(exception.NewNotFoundError("Admin reviewer not found"))
 	}
 
+	before := map[string]interface{}{
+		"edit_request": helper.ToCompanyEditRequestResponse(editRequest),
+	}
+	after := map[string]interface{}{}
+
 	// Update edit request
 	now := time.Now()
 	editRequest.Status = domain.CompanyEditRequestStatus(request.Status)
@@ -696,6 +708,7 @@ func (service *CompanyManagementServiceImpl) ReviewEditRequest(ctx context.Conte
 		// Get company and update
 		company, err := service.CompanyRepository.FindById(ctx, tx, editRequest.CompanyId)
 		helper.PanicIfError(err)
+		before["company"] = company
 
 		company.Name = requestedData.Name
 		company.LinkedinUrl = requestedData.LinkedinUrl
@@ -707,9 +720,13 @@ func (service *CompanyManagementServiceImpl) ReviewEditRequest(ctx context.Conte
 		company.Tagline = requestedData.Tagline
 		company.UpdatedAt = time.Now()
 
-		service.CompanyRepository.Update(ctx, tx, company)
+		after["company"] = service.CompanyRepository.Update(ctx, tx, company)
 	}
 
+	after["edit_request"] = helper.ToCompanyEditRequestResponse(editRequest)
+	err = service.AuditLogService.Record(ctx, tx, domain.AuditActionCompanyEditRequestReview, "company_edit_request", editRequest.Id.String(), before, after)
+	helper.PanicIfError(err)
+
 	// Send notification to user
 	if service.NotificationService != nil {
 		go func() {
@@ -760,18 +777,19 @@ func (service *CompanyManagementServiceImpl) GetRandomCompanies(ctx context.Cont
 	var responses []web.CompanyDetailResponse
 	for _, company := range companies {
 		response := web.CompanyDetailResponse{
-			Id:          company.Id.String(),
-			Name:        company.Name,
-			LinkedinUrl: company.LinkedinUrl,
-			Website:     company.Website,
-			Industry:    company.Industry,
-			Size:        company.Size,
-			Type:        company.Type,
-			Logo:        company.Logo,
-			Tagline:     company.Tagline,
-			IsVerified:  company.IsVerified,
-			CreatedAt:   company.CreatedAt,
-			UpdatedAt:   company.UpdatedAt,
+			Id:           company.Id.String(),
+			Name:         company.Name,
+			LinkedinUrl:  company.LinkedinUrl,
+			Website:      company.Website,
+			Industry:     company.Industry,
+			Size:         company.Size,
+			Type:         company.Type,
+			Logo:         company.Logo,
+			LogoVariants: helper.ImageVariantsOf(company.Logo),
+			Tagline:      company.Tagline,
+			IsVerified:   company.IsVerified,
+			CreatedAt:    company.CreatedAt,
+			UpdatedAt:    company.UpdatedAt,
 		}
 		responses = append(responses, response)
 	}
@@ -796,18 +814,15 @@ func (service *CompanyManagementServiceImpl) GetCompanyStats(ctx context.Context
 	}
 
 	// Get total posts
-	fmt.Println("Fetching total posts for company:", companyId)
 	var totalPosts int
 	err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM company_posts WHERE company_id = $1 AND status != 'taken_down'", companyId).Scan(&totalPosts)
 	helper.PanicIfError(err)
 
-	fmt.Println("Total posts fetched:", totalPosts)
 	// Get total job vacancies
 	var totalJobVacancies int
 	err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM job_vacancies WHERE company_id = $1", companyId).Scan(&totalJobVacancies)
 	helper.PanicIfError(err)
 
-	fmt.Println("Total job vacancies fetched:", totalJobVacancies)
 	// Get total members
 	var totalMembers int
 	err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM member_company WHERE company_id = $1 AND status = 'active'", companyId).Scan(&totalMembers)
@@ -902,10 +917,11 @@ func (service *CompanyManagementServiceImpl) GetEditRequestById(ctx context.Cont
 
 	// Set company info
 	response.Company = &web.CompanyResponse{
-		ID:      company.Id,
-		Name:    company.Name,
-		Logo:    company.Logo,
-		Tagline: company.Tagline,
+		ID:           company.Id,
+		Name:         company.Name,
+		Logo:         company.Logo,
+		LogoVariants: helper.ImageVariantsOf(company.Logo),
+		Tagline:      company.Tagline,
 	}
 
 	return response
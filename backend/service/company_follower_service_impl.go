@@ -167,9 +167,10 @@ func (service *CompanyFollowerServiceImpl) GetCompanyFollowers(ctx context.Conte
 		// Add company info if available
 		if follower.Company != nil {
 			response.Company = &web.CompanyBasicInfo{
-				Id:   follower.Company.Id.String(),
-				Name: follower.Company.Name,
-				Logo: follower.Company.Logo,
+				Id:           follower.Company.Id.String(),
+				Name:         follower.Company.Name,
+				Logo:         follower.Company.Logo,
+				LogoVariants: helper.ImageVariantsOf(follower.Company.Logo),
 			}
 		}
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"evoconnect/backend/exception"
@@ -25,6 +26,7 @@ type CompanyJoinRequestServiceImpl struct {
 	MemberCompanyRepository      repository.MemberCompanyRepository
 	NotificationService          NotificationService
 	Validate                     *validator.Validate
+	Logger                       *slog.Logger
 }
 
 func NewCompanyJoinRequestService(
@@ -35,6 +37,7 @@ func NewCompanyJoinRequestService(
 	memberCompanyRepository repository.MemberCompanyRepository,
 	notificationService NotificationService,
 	validate *validator.Validate,
+	logger *slog.Logger,
 ) CompanyJoinRequestService {
 	return &CompanyJoinRequestServiceImpl{
 		DB:                           db,
@@ -44,6 +47,7 @@ func NewCompanyJoinRequestService(
 		MemberCompanyRepository:      memberCompanyRepository,
 		NotificationService:          notificationService,
 		Validate:                     validate,
+		Logger:                       logger,
 	}
 }
 
@@ -99,14 +103,14 @@ func (service *CompanyJoinRequestServiceImpl) Create(ctx context.Context, userId
 	user, err := service.UserRepository.FindById(ctx, tx, userId)
 	if err != nil {
 		// Log error but don't panic - notification is not critical
-		fmt.Printf("Warning: Could not get user data for notification: %v\n", err)
+		service.Logger.WarnContext(ctx, "could not get user data for notification", "error", err)
 	} else if service.NotificationService != nil {
 		// Send notification to company admins/super_admins in a separate transaction
 		go func() {
 			// Create new transaction for notification
 			notifTx, notifErr := service.DB.Begin()
 			if notifErr != nil {
-				fmt.Printf("Warning: Could not start notification transaction: %v\n", notifErr)
+				service.Logger.WarnContext(ctx, "failed to begin notification transaction", "error", notifErr)
 				return
 			}
 			defer func() {
@@ -120,7 +124,7 @@ func (service *CompanyJoinRequestServiceImpl) Create(ctx context.Context, userId
 			// Get company admins and super_admins
 			members, count, err := service.MemberCompanyRepository.FindByCompanyIdAndRoles(ctx, notifTx, request.CompanyId, []entity.MemberCompanyRole{"admin", "super_admin"}, 100, 0)
 			if err != nil || count == 0 {
-				fmt.Printf("Warning: Could not get admins for notification: %v\n", err)
+				service.Logger.WarnContext(ctx, "could not get admins for notification", "error", err)
 				return // No admins to notify
 			}
 
@@ -257,7 +261,7 @@ func (service *CompanyJoinRequestServiceImpl) Review(ctx context.Context, reques
 				// Create new transaction for notification
 				notifTx, notifErr := service.DB.Begin()
 				if notifErr != nil {
-					fmt.Printf("Warning: Could not start notification transaction: %v\n", notifErr)
+					service.Logger.WarnContext(ctx, "failed to begin notification transaction", "error", notifErr)
 					return
 				}
 				defer func() {
@@ -10,6 +10,7 @@ import (
 	"evoconnect/backend/model/web"
 	"evoconnect/backend/repository"
 	"fmt"
+	"log/slog"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
@@ -23,6 +24,7 @@ type CompanyPostCommentServiceImpl struct {
 	UserRepository               repository.UserRepository
 	NotificationService          NotificationService
 	Validate                     *validator.Validate
+	Logger                       *slog.Logger
 }
 
 func NewCompanyPostCommentService(
@@ -33,6 +35,7 @@ func NewCompanyPostCommentService(
 	userRepository repository.UserRepository,
 	notificationService NotificationService,
 	validate *validator.Validate,
+	logger *slog.Logger,
 ) CompanyPostCommentService {
 	return &CompanyPostCommentServiceImpl{
 		DB:                           db,
@@ -42,6 +45,7 @@ func NewCompanyPostCommentService(
 		UserRepository:               userRepository,
 		NotificationService:          notificationService,
 		Validate:                     validate,
+		Logger:                       logger,
 	}
 }
 
@@ -97,34 +101,17 @@ func (service *CompanyPostCommentServiceImpl) CreateReply(ctx context.Context, u
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
 
-	// Debug logging
-	fmt.Printf("CreateReply - PostId: %s, ParentId: %s, UserId: %s\n", postId, request.ParentId, userId)
-
 	// Check if company post exists
 	post, err := service.CompanyPostRepository.FindById(ctx, tx, postId)
 	if err != nil {
-		fmt.Printf("Post not found error: %v\n", err)
 		panic(exception.NewNotFoundError("company post not found"))
 	}
-	fmt.Printf("Post found: %s\n", post.Id)
 
-	// Check if parent comment exists - pastikan query benar
-	fmt.Printf("Looking for parent comment with ID: %s\n", request.ParentId)
+	// Check if parent comment exists
 	parentComment, err := service.CompanyPostCommentRepository.FindById(ctx, tx, request.ParentId)
 	if err != nil {
-		fmt.Printf("Parent comment not found error: %v\n", err)
-		// Let's check if the comment exists with a simple query
-		var exists bool
-		checkSQL := "SELECT EXISTS(SELECT 1 FROM company_post_comments WHERE id = $1)"
-		checkErr := tx.QueryRowContext(ctx, checkSQL, request.ParentId).Scan(&exists)
-		if checkErr != nil {
-			fmt.Printf("Error checking comment existence: %v\n", checkErr)
-		} else {
-			fmt.Printf("Comment exists: %v\n", exists)
-		}
 		panic(exception.NewNotFoundError("parent comment not found"))
 	}
-	fmt.Printf("Parent comment found: %s\n", parentComment.Id)
 
 	// Verify parent comment belongs to the same post
 	if parentComment.PostId != postId {
@@ -150,7 +137,7 @@ func (service *CompanyPostCommentServiceImpl) CreateReply(ctx context.Context, u
 
 	comment, err = service.CompanyPostCommentRepository.Create(ctx, tx, comment)
 	if err != nil {
-		fmt.Printf("Error creating comment: %v\n", err)
+		service.Logger.ErrorContext(ctx, "error creating comment", "error", err)
 		helper.PanicIfError(err)
 	}
 
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"log/slog"
 	"context"
 	"database/sql"
 	"evoconnect/backend/exception"
@@ -25,6 +26,7 @@ type CompanyPostServiceImpl struct {
 	CompanyFollowerRepository repository.CompanyFollowerRepository
 	NotificationService       NotificationService
 	Validate                  *validator.Validate
+	Logger                    *slog.Logger
 }
 
 func NewCompanyPostService(
@@ -36,6 +38,7 @@ func NewCompanyPostService(
 	companyFollowerRepository repository.CompanyFollowerRepository,
 	notificationService NotificationService,
 	validate *validator.Validate,
+	logger *slog.Logger,
 ) CompanyPostService {
 	return &CompanyPostServiceImpl{
 		DB:                        db,
@@ -46,6 +49,7 @@ func NewCompanyPostService(
 		CompanyFollowerRepository: companyFollowerRepository,
 		NotificationService:       notificationService,
 		Validate:                  validate,
+		Logger:                    logger,
 	}
 }
 
@@ -85,6 +89,7 @@ func (service *CompanyPostServiceImpl) Create(ctx context.Context, userId uuid.U
 		file.Close()
 
 		if err != nil {
+			helper.PanicIfUploadRejected(err)
 			panic(exception.NewInternalServerError("Failed to upload image: " + err.Error()))
 		}
 
@@ -144,7 +149,7 @@ func (service *CompanyPostServiceImpl) Update(ctx context.Context, userId, postI
 	for _, removedImage := range request.RemovedImages {
 		if err := helper.DeleteFile(removedImage); err != nil {
 			// Log error but don't fail the request
-			fmt.Printf("Failed to remove image %s: %v\n", removedImage, err)
+			service.Logger.WarnContext(ctx, "failed to remove company post image", "path", removedImage, "error", err)
 		}
 		// Remove from existing images
 		for i, existingImage := range imagePaths {
@@ -166,6 +171,7 @@ func (service *CompanyPostServiceImpl) Update(ctx context.Context, userId, postI
 		file.Close()
 
 		if err != nil {
+			helper.PanicIfUploadRejected(err)
 			panic(exception.NewInternalServerError("Failed to upload image: " + err.Error()))
 		}
 
@@ -534,11 +540,12 @@ func (service *CompanyPostServiceImpl) toCompanyPostResponse(post domain.Company
 	isLiked := service.CompanyPostRepository.IsLiked(ctx, tx, post.Id, userId)
 
 	response := web.CompanyPostResponse{
-		Id:             post.Id,
-		CompanyId:      post.CompanyId,
-		CreatorId:      post.CreatorId,
-		Content:        post.Content,
-		Images:         post.Images,
+		Id:            post.Id,
+		CompanyId:     post.CompanyId,
+		CreatorId:     post.CreatorId,
+		Content:       post.Content,
+		Images:        post.Images,
+		ImageVariants: helper.ImageVariantsOfAll(post.Images),
 		Visibility:     post.Visibility,
 		IsAnnouncement: post.IsAnnouncement,
 		CreatedAt:      post.CreatedAt,
@@ -555,6 +562,7 @@ func (service *CompanyPostServiceImpl) toCompanyPostResponse(post domain.Company
 			Id:       post.Company.Id,
 			Name:     post.Company.Name,
 			Logo:     &post.Company.Logo,
+			LogoVariants:     helper.ImageVariantsOf(post.Company.Logo),
 			Industry: post.Company.Industry,
 		}
 	}
@@ -9,9 +9,9 @@ import (
 	"evoconnect/backend/model/entity"
 	"evoconnect/backend/model/web"
 	"evoconnect/backend/repository"
+	"log/slog"
 
 	"fmt"
-	"log"
 	"mime/multipart"
 	"time"
 
@@ -26,8 +26,10 @@ type CompanySubmissionServiceImpl struct {
 	MemberCompanyRepository     repository.MemberCompanyRepository
 	AdminRepository             repository.AdminRepository
 	NotificationService         NotificationService
+	AuditLogService             AuditLogService
 	DB                          *sql.DB
 	Validate                    *validator.Validate
+	Logger                      *slog.Logger
 }
 
 func NewCompanySubmissionService(
@@ -37,8 +39,9 @@ func NewCompanySubmissionService(
 	memberCompanyRepository repository.MemberCompanyRepository,
 	adminRepository repository.AdminRepository,
 	notificationService NotificationService,
+	auditLogService AuditLogService,
 	db *sql.DB,
-	validate *validator.Validate) CompanySubmissionService {
+	validate *validator.Validate, logger *slog.Logger) CompanySubmissionService {
 	return &CompanySubmissionServiceImpl{
 		CompanySubmissionRepository: companySubmissionRepository,
 		CompanyRepository:           companyRepository,
@@ -46,8 +49,10 @@ func NewCompanySubmissionService(
 		MemberCompanyRepository:     memberCompanyRepository,
 		AdminRepository:             adminRepository,
 		NotificationService:         notificationService,
+		AuditLogService:             auditLogService,
 		DB:                          db,
 		Validate:                    validate,
+		Logger:                      logger,
 	}
 }
 
@@ -183,8 +188,6 @@ func (service *CompanySubmissionServiceImpl) FindByStatus(ctx context.Context, s
 }
 
 func (service *CompanySubmissionServiceImpl) Review(ctx context.Context, submissionId uuid.UUID, reviewerId uuid.UUID, request web.ReviewCompanySubmissionRequest) web.CompanySubmissionResponse {
-	log.Printf("Starting review process for submission %s by reviewer %s", submissionId, reviewerId)
-
 	err := service.Validate.Struct(request)
 	helper.PanicIfError(err)
 
@@ -192,28 +195,24 @@ func (service *CompanySubmissionServiceImpl) Review(ctx context.Context, submiss
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
 
-	log.Printf("Finding submission with ID: %s", submissionId)
 	// Find submission
 	submission, err := service.CompanySubmissionRepository.FindById(ctx, tx, submissionId)
 	if err != nil {
-		log.Printf("Submission not found: %v", err)
 		panic(exception.NewNotFoundError("Company submission not found"))
 	}
-	log.Printf("Submission found: %s", submission.Name)
 
 	// Check if already reviewed
 	if submission.Status != domain.CompanySubmissionStatusPending {
 		panic(exception.NewBadRequestError("Company submission has already been reviewed"))
 	}
 
-	log.Printf("Finding admin reviewer with ID: %s", reviewerId)
 	// Check if admin reviewer exists
 	_, err = service.AdminRepository.FindById(ctx, tx, reviewerId)
 	if err != nil {
-		log.Printf("Admin reviewer not found: %v", err)
 		panic(exception.NewNotFoundError("Admin reviewer not found"))
 	}
-	log.Printf("Admin reviewer found")
+
+	before := helper.ToCompanySubmissionResponse(submission)
 
 	// Update submission
 	now := time.Now()
@@ -223,12 +222,11 @@ func (service *CompanySubmissionServiceImpl) Review(ctx context.Context, submiss
 	submission.ReviewedAt = &now
 	submission.UpdatedAt = now
 
-	log.Printf("Updating submission status to: %s", request.Status)
 	submission = service.CompanySubmissionRepository.Update(ctx, tx, submission)
 
 	// If approved, create company and update user role
+	var createdCompanyId *uuid.UUID
 	if submission.Status == domain.CompanySubmissionStatusApproved {
-		log.Printf("Creating company for approved submission")
 		companyId := uuid.New()
 		// Create company
 		company := domain.Company{
@@ -248,10 +246,9 @@ func (service *CompanySubmissionServiceImpl) Review(ctx context.Context, submiss
 		}
 
 		service.CompanyRepository.Create(ctx, tx, company)
-		log.Printf("Company created successfully")
+		createdCompanyId = &companyId
 
 		// Update user role to company owner
-		log.Printf("Updating user role to company owner for user ID: %s", submission.UserId)
 		// Create member_company record for the user who submitted (as super_admin)
 		memberCompany := entity.MemberCompany{
 			UserID:    submission.UserId,
@@ -263,12 +260,21 @@ func (service *CompanySubmissionServiceImpl) Review(ctx context.Context, submiss
 
 		_, err = service.MemberCompanyRepository.Create(ctx, tx, memberCompany)
 		if err != nil {
-			log.Printf("Failed to create member company record: %v", err)
+			service.Logger.ErrorContext(ctx, "failed to create member company record", "submission_id", submission.Id, "error", err)
 			panic(exception.NewInternalServerError("Failed to create member company record"))
 		}
 
 	}
 
+	err = service.AuditLogService.Record(ctx, tx, domain.AuditActionCompanySubmissionReview, "company_submission", submission.Id.String(),
+		before,
+		map[string]interface{}{
+			"submission": helper.ToCompanySubmissionResponse(submission),
+			"company_id": createdCompanyId,
+		},
+	)
+	helper.PanicIfError(err)
+
 	// Send notification to user
 	if service.NotificationService != nil {
 		go func() {
@@ -299,7 +305,6 @@ func (service *CompanySubmissionServiceImpl) Review(ctx context.Context, submiss
 		}()
 	}
 
-	log.Printf("Review process completed successfully")
 	return helper.ToCompanySubmissionResponse(submission)
 }
 
@@ -349,10 +354,9 @@ func (service *CompanySubmissionServiceImpl) Delete(ctx context.Context, submiss
 	if companyLogo != "" {
 		err = helper.DeleteFile(companyLogo)
 		if err != nil {
-			log.Printf("Failed to delete logo file: %v", err)
+			service.Logger.ErrorContext(ctx, "failed to delete company logo", "path", companyLogo, "error", err)
 			panic(exception.NewInternalServerError("Failed to delete logo file"))
 		}
-		log.Printf("Logo file deleted successfully: %s", companyLogo)
 	}
 
 	return nil
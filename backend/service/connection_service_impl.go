@@ -139,18 +139,20 @@ func (service *ConnectionServiceImpl) SendConnectionRequest(ctx context.Context,
 		CreatedAt: createdRequest.CreatedAt,
 		UpdatedAt: createdRequest.UpdatedAt,
 		Receiver: &web.UserShort{
-			Id:       receiver.Id,
-			Name:     receiver.Name,
-			Username: receiver.Username,
-			Headline: optionalStringPtr(receiver.Headline),
-			Photo:    optionalStringPtr(receiver.Photo),
+			Id:            receiver.Id,
+			Name:          receiver.Name,
+			Username:      receiver.Username,
+			Headline:      optionalStringPtr(receiver.Headline),
+			Photo:         optionalStringPtr(receiver.Photo),
+			PhotoVariants: helper.ImageVariantsOf(receiver.Photo),
 		},
 		Sender: &web.UserShort{
-			Id:       sender.Id,
-			Name:     sender.Name,
-			Username: sender.Username,
-			Headline: optionalStringPtr(sender.Headline),
-			Photo:    optionalStringPtr(sender.Photo),
+			Id:            sender.Id,
+			Name:          sender.Name,
+			Username:      sender.Username,
+			Headline:      optionalStringPtr(sender.Headline),
+			Photo:         optionalStringPtr(sender.Photo),
+			PhotoVariants: helper.ImageVariantsOf(sender.Photo),
 		},
 	}
 }
@@ -182,11 +184,12 @@ func (service *ConnectionServiceImpl) GetConnectionRequests(ctx context.Context,
 		// Add sender info if available
 		if request.Sender != nil {
 			requestResponse.Sender = &web.UserShort{
-				Id:       request.Sender.Id,
-				Name:     request.Sender.Name,
-				Username: request.Sender.Username,
-				Headline: optionalStringPtr(request.Sender.Headline),
-				Photo:    optionalStringPtr(request.Sender.Photo),
+				Id:            request.Sender.Id,
+				Name:          request.Sender.Name,
+				Username:      request.Sender.Username,
+				Headline:      optionalStringPtr(request.Sender.Headline),
+				Photo:         optionalStringPtr(request.Sender.Photo),
+				PhotoVariants: helper.ImageVariantsOf(request.Sender.Photo),
 			}
 		}
 
@@ -266,18 +269,20 @@ func (service *ConnectionServiceImpl) AcceptConnectionRequest(ctx context.Contex
 		CreatedAt: updatedRequest.CreatedAt,
 		UpdatedAt: updatedRequest.UpdatedAt,
 		Sender: &web.UserShort{
-			Id:       sender.Id,
-			Name:     sender.Name,
-			Username: sender.Username,
-			Headline: optionalStringPtr(sender.Headline),
-			Photo:    optionalStringPtr(sender.Photo),
+			Id:            sender.Id,
+			Name:          sender.Name,
+			Username:      sender.Username,
+			Headline:      optionalStringPtr(sender.Headline),
+			Photo:         optionalStringPtr(sender.Photo),
+			PhotoVariants: helper.ImageVariantsOf(sender.Photo),
 		},
 		Receiver: &web.UserShort{
-			Id:       receiver.Id,
-			Name:     receiver.Name,
-			Username: receiver.Username,
-			Headline: optionalStringPtr(receiver.Headline),
-			Photo:    optionalStringPtr(receiver.Photo),
+			Id:            receiver.Id,
+			Name:          receiver.Name,
+			Username:      receiver.Username,
+			Headline:      optionalStringPtr(receiver.Headline),
+			Photo:         optionalStringPtr(receiver.Photo),
+			PhotoVariants: helper.ImageVariantsOf(receiver.Photo),
 		},
 	}
 }
@@ -326,18 +331,20 @@ func (service *ConnectionServiceImpl) RejectConnectionRequest(ctx context.Contex
 		CreatedAt: updatedRequest.CreatedAt,
 		UpdatedAt: updatedRequest.UpdatedAt,
 		Sender: &web.UserShort{
-			Id:       sender.Id,
-			Name:     sender.Name,
-			Username: sender.Username,
-			Headline: optionalStringPtr(sender.Headline),
-			Photo:    optionalStringPtr(sender.Photo),
+			Id:            sender.Id,
+			Name:          sender.Name,
+			Username:      sender.Username,
+			Headline:      optionalStringPtr(sender.Headline),
+			Photo:         optionalStringPtr(sender.Photo),
+			PhotoVariants: helper.ImageVariantsOf(sender.Photo),
 		},
 		Receiver: &web.UserShort{
-			Id:       receiver.Id,
-			Name:     receiver.Name,
-			Username: receiver.Username,
-			Headline: optionalStringPtr(receiver.Headline),
-			Photo:    optionalStringPtr(receiver.Photo),
+			Id:            receiver.Id,
+			Name:          receiver.Name,
+			Username:      receiver.Username,
+			Headline:      optionalStringPtr(receiver.Headline),
+			Photo:         optionalStringPtr(receiver.Photo),
+			PhotoVariants: helper.ImageVariantsOf(receiver.Photo),
 		},
 	}
 }
@@ -369,15 +376,14 @@ func (service *ConnectionServiceImpl) GetConnections(ctx context.Context, userId
 
 		isConnected := service.ConnectionRepository.CheckConnectionExists(ctx, tx, userId, otherUser.Id)
 
-		fmt.Println("Is connected:", isConnected)
-
 		userShort := web.UserShort{
-			Id:          otherUser.Id,
-			Name:        otherUser.Name,
-			Username:    otherUser.Username,
-			Headline:    optionalStringPtr(otherUser.Headline),
-			Photo:       optionalStringPtr(otherUser.Photo),
-			IsConnected: isConnected,
+			Id:            otherUser.Id,
+			Name:          otherUser.Name,
+			Username:      otherUser.Username,
+			Headline:      optionalStringPtr(otherUser.Headline),
+			Photo:         optionalStringPtr(otherUser.Photo),
+			PhotoVariants: helper.ImageVariantsOf(otherUser.Photo),
+			IsConnected:   isConnected,
 		}
 
 		connectionResponse := web.ConnectionResponse{
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+	"evoconnect/backend/model/web"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DataExportService interface {
+	RequestExport(ctx context.Context, userId uuid.UUID) web.DataExportResponse
+	FindAll(ctx context.Context, userId uuid.UUID) []web.DataExportResponse
+	FindById(ctx context.Context, userId uuid.UUID, exportId uuid.UUID) web.DataExportResponse
+	FindDownload(ctx context.Context, userId uuid.UUID, exportId uuid.UUID) (string, string)
+	ProcessPendingExports(ctx context.Context) int
+	RunExportWorker(ctx context.Context, interval time.Duration)
+}
@@ -0,0 +1,464 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"evoconnect/backend/exception"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/domain"
+	"evoconnect/backend/model/web"
+	"evoconnect/backend/repository"
+	"evoconnect/backend/storage"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Exports still processing after this long belong to a worker that died
+	dataExportStaleAfter = 30 * time.Minute
+	dataExportListLimit  = 10
+	dataExportCleanBatch = 100
+
+	// Archives are kept in the file storage outside uploads/, which is served publicly,
+	// so any instance can serve an archive built by the worker of another
+	dataExportPrefix = "exports/"
+)
+
+type DataExportServiceImpl struct {
+	DataExportRepository repository.DataExportRepository
+	NotificationService  NotificationService
+	// How long an archive can be downloaded, and how long a user waits between exports
+	TTL      time.Duration
+	Cooldown time.Duration
+	DB       *sql.DB
+
+	// wake lets a new request start the worker without waiting for the next tick
+	wake   chan struct{}
+	Logger *slog.Logger
+}
+
+func NewDataExportService(
+	dataExportRepository repository.DataExportRepository,
+	notificationService NotificationService,
+	ttl time.Duration,
+	cooldown time.Duration,
+	db *sql.DB,
+	logger *slog.Logger,
+) DataExportService {
+	return &DataExportServiceImpl{
+		DataExportRepository: dataExportRepository,
+		NotificationService:  notificationService,
+		TTL:                  ttl,
+		Cooldown:             cooldown,
+		DB:                   db,
+		wake:                 make(chan struct{}, 1),
+		Logger:               logger,
+	}
+}
+
+// RequestExport queues a new export, the archive is built by the export worker
+func (service *DataExportServiceImpl) RequestExport(ctx context.Context, userId uuid.UUID) web.DataExportResponse {
+	export := service.queueExport(ctx, userId)
+
+	select {
+	case service.wake <- struct{}{}:
+	default:
+	}
+
+	return helper.ToDataExportResponse(export)
+}
+
+func (service *DataExportServiceImpl) queueExport(ctx context.Context, userId uuid.UUID) domain.DataExport {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	latest, err := service.DataExportRepository.FindLatestByUserId(ctx, tx, userId)
+	if err == nil {
+		switch {
+		case latest.Status == domain.DataExportStatusPending || latest.Status == domain.DataExportStatusProcessing:
+			panic(exception.NewBadRequestError("Your data export is already being prepared"))
+		case latest.Status != domain.DataExportStatusFailed && time.Since(latest.CreatedAt) < service.Cooldown:
+			availableAt := latest.CreatedAt.Add(service.Cooldown)
+			panic(exception.NewTooManyRequestsError(fmt.Sprintf("You can request a new data export after %s", availableAt.Format(time.RFC3339))))
+		}
+	}
+
+	return service.DataExportRepository.Save(ctx, tx, domain.DataExport{
+		UserId:    userId,
+		Status:    domain.DataExportStatusPending,
+		CreatedAt: time.Now(),
+	})
+}
+
+func (service *DataExportServiceImpl) FindAll(ctx context.Context, userId uuid.UUID) []web.DataExportResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	exports := service.DataExportRepository.FindByUserId(ctx, tx, userId, dataExportListLimit)
+
+	responses := []web.DataExportResponse{}
+	for _, export := range exports {
+		responses = append(responses, helper.ToDataExportResponse(export))
+	}
+
+	return responses
+}
+
+func (service *DataExportServiceImpl) FindById(ctx context.Context, userId uuid.UUID, exportId uuid.UUID) web.DataExportResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	return helper.ToDataExportResponse(service.findOwnedExport(ctx, tx, userId, exportId))
+}
+
+// FindDownload returns the storage key of the archive and the file name to download it as
+func (service *DataExportServiceImpl) FindDownload(ctx context.Context, userId uuid.UUID, exportId uuid.UUID) (string, string) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	export := service.findOwnedExport(ctx, tx, userId, exportId)
+	if !export.IsDownloadable() {
+		if export.Status == domain.DataExportStatusReady || export.Status == domain.DataExportStatusExpired {
+			panic(exception.NewBadRequestError("This download link has expired, please request a new export"))
+		}
+		panic(exception.NewBadRequestError("This data export is not ready yet"))
+	}
+
+	if _, err := helper.FileStorage().Stat(ctx, *export.FilePath); err != nil {
+		panic(exception.NewNotFoundError("Data export file not found"))
+	}
+
+	return *export.FilePath, fmt.Sprintf("evoconnect-data-%s.zip", export.CreatedAt.Format("2006-01-02"))
+}
+
+func (service *DataExportServiceImpl) findOwnedExport(ctx context.Context, tx *sql.Tx, userId uuid.UUID, exportId uuid.UUID) domain.DataExport {
+	export, err := service.DataExportRepository.FindById(ctx, tx, exportId)
+	if err != nil || export.UserId != userId {
+		panic(exception.NewNotFoundError("Data export not found"))
+	}
+	return export
+}
+
+// ProcessPendingExports builds every queued export and returns how many archives are ready
+func (service *DataExportServiceImpl) ProcessPendingExports(ctx context.Context) int {
+	built := 0
+
+	for ctx.Err() == nil {
+		tx, err := service.DB.Begin()
+		if err != nil {
+			service.Logger.ErrorContext(ctx, "data export failed to begin transaction", "error", err)
+			return built
+		}
+		export, err := service.DataExportRepository.ClaimPending(ctx, tx)
+		if err != nil {
+			tx.Rollback()
+			return built
+		}
+		if err := tx.Commit(); err != nil {
+			service.Logger.ErrorContext(ctx, "data export claim failed", "export_id", export.Id, "error", err)
+			return built
+		}
+
+		if service.processExport(ctx, export) {
+			built++
+		}
+	}
+
+	return built
+}
+
+// RunExportWorker builds queued exports and removes expired archives until ctx is cancelled
+func (service *DataExportServiceImpl) RunExportWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		service.requeueStaleExports(ctx)
+		if built := service.ProcessPendingExports(ctx); built > 0 {
+			service.Logger.InfoContext(ctx, "data exports ready", "count", built)
+		}
+		service.removeExpiredExports(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-service.wake:
+		}
+	}
+}
+
+// processExport builds one archive and tells the user how it went
+func (service *DataExportServiceImpl) processExport(ctx context.Context, export domain.DataExport) (ready bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			service.Logger.ErrorContext(ctx, "data export panicked", "export_id", export.Id, "panic", fmt.Sprint(r))
+			ready = false
+		}
+	}()
+
+	key, fileSize, err := service.buildArchive(ctx, export)
+	if err != nil {
+		service.Logger.ErrorContext(ctx, "data export build failed", "export_id", export.Id, "error", err)
+		service.finishExport(ctx, export, func(tx *sql.Tx) error {
+			return service.DataExportRepository.MarkFailed(ctx, tx, export.Id, "We could not prepare your data, please try again")
+		})
+		service.notify(ctx, export, domain.NotificationTypeDataExportFailed,
+			"Data export failed",
+			"We could not prepare your data export. Please request a new one.")
+		return false
+	}
+
+	expiresAt := time.Now().Add(service.TTL)
+	err = service.finishExport(ctx, export, func(tx *sql.Tx) error {
+		return service.DataExportRepository.MarkReady(ctx, tx, export.Id, key, fileSize, expiresAt)
+	})
+	if err != nil {
+		helper.FileStorage().Delete(ctx, key)
+		return false
+	}
+
+	service.notify(ctx, export, domain.NotificationTypeDataExportReady,
+		"Your data export is ready",
+		fmt.Sprintf("Your data is ready to download until %s.", expiresAt.Format("January 2, 2006 15:04")))
+	return true
+}
+
+func (service *DataExportServiceImpl) finishExport(ctx context.Context, export domain.DataExport, update func(tx *sql.Tx) error) error {
+	tx, err := service.DB.Begin()
+	if err == nil {
+		err = update(tx)
+		if err == nil {
+			err = tx.Commit()
+		} else {
+			tx.Rollback()
+		}
+	}
+	if err != nil {
+		service.Logger.ErrorContext(ctx, "data export update failed", "export_id", export.Id, "error", err)
+	}
+	return err
+}
+
+func (service *DataExportServiceImpl) notify(ctx context.Context, export domain.DataExport, notificationType domain.NotificationType, title string, message string) {
+	referenceType := "data_export"
+	service.NotificationService.Create(
+		ctx,
+		export.UserId,
+		string(domain.NotificationCategoryProfile),
+		string(notificationType),
+		title,
+		message,
+		&export.Id,
+		&referenceType,
+		nil,
+	)
+}
+
+// buildArchive writes one JSON file per section plus the uploaded media into a zip and stores it,
+// returning its key. Everything is read from a single snapshot so the files agree with each other.
+func (service *DataExportServiceImpl) buildArchive(ctx context.Context, export domain.DataExport) (string, int64, error) {
+	// The zip is assembled in a local temporary file, only the finished archive is stored
+	file, err := os.CreateTemp("", "evoconnect-export-*.zip")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+
+	tx, err := service.DB.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return "", 0, err
+	}
+	defer tx.Rollback()
+
+	for _, section := range repository.DataExportSections {
+		document, err := service.DataExportRepository.FindSection(ctx, tx, section, export.UserId)
+		if err != nil {
+			return "", 0, fmt.Errorf("section %s: %w", section, err)
+		}
+		if err := writeArchiveJSON(archive, section+".json", document); err != nil {
+			return "", 0, err
+		}
+	}
+
+	mediaPaths, err := service.DataExportRepository.FindMediaPaths(ctx, tx, export.UserId)
+	if err != nil {
+		return "", 0, fmt.Errorf("media: %w", err)
+	}
+	tx.Rollback()
+
+	media := []string{}
+	missingMedia := []string{}
+	for _, storedPath := range mediaPaths {
+		key, archivePath, ok := resolveUploadPath(storedPath)
+		if !ok {
+			missingMedia = append(missingMedia, storedPath)
+			continue
+		}
+		err := copyFileToArchive(ctx, archive, key, "media/"+archivePath)
+		if errors.Is(err, storage.ErrNotFound) {
+			missingMedia = append(missingMedia, storedPath)
+			continue
+		}
+		if err != nil {
+			return "", 0, err
+		}
+		media = append(media, "media/"+archivePath)
+	}
+
+	manifest, err := json.Marshal(map[string]interface{}{
+		"export_id":     export.Id,
+		"user_id":       export.UserId,
+		"generated_at":  time.Now(),
+		"sections":      repository.DataExportSections,
+		"media":         media,
+		"missing_media": missingMedia,
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	if err := writeArchiveJSON(archive, "manifest.json", manifest); err != nil {
+		return "", 0, err
+	}
+
+	if err := archive.Close(); err != nil {
+		return "", 0, err
+	}
+
+	size, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return "", 0, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", 0, err
+	}
+
+	key := dataExportKey(export)
+	if err := helper.FileStorage().Put(ctx, key, file, size, "application/zip"); err != nil {
+		return "", 0, err
+	}
+	return key, size, nil
+}
+
+// dataExportUserPrefix holds every archive of a user, removed with the account
+func dataExportUserPrefix(userId uuid.UUID) string {
+	return dataExportPrefix + userId.String() + "/"
+}
+
+func dataExportKey(export domain.DataExport) string {
+	return dataExportUserPrefix(export.UserId) + export.Id.String() + ".zip"
+}
+
+func (service *DataExportServiceImpl) requeueStaleExports(ctx context.Context) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		service.Logger.ErrorContext(ctx, "data export failed to begin transaction", "error", err)
+		return
+	}
+	requeued, err := service.DataExportRepository.RequeueStale(ctx, tx, time.Now().Add(-dataExportStaleAfter))
+	if err != nil {
+		tx.Rollback()
+		service.Logger.ErrorContext(ctx, "data export failed to requeue stale exports", "error", err)
+		return
+	}
+	if err := tx.Commit(); err == nil && requeued > 0 {
+		service.Logger.InfoContext(ctx, "stale data exports requeued", "count", requeued)
+	}
+}
+
+// removeExpiredExports deletes archives whose download link is over
+func (service *DataExportServiceImpl) removeExpiredExports(ctx context.Context) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		service.Logger.ErrorContext(ctx, "data export failed to begin transaction", "error", err)
+		return
+	}
+	defer tx.Rollback()
+
+	exports, err := service.DataExportRepository.FindExpired(ctx, tx, time.Now(), dataExportCleanBatch)
+	if err != nil {
+		service.Logger.ErrorContext(ctx, "data export failed to find expired exports", "error", err)
+		return
+	}
+
+	for _, export := range exports {
+		if export.FilePath != nil {
+			if err := helper.FileStorage().Delete(ctx, *export.FilePath); err != nil {
+				service.Logger.WarnContext(ctx, "data export failed to remove archive", "export_id", export.Id, "path", *export.FilePath, "error", err)
+				continue
+			}
+		}
+		if err := service.DataExportRepository.MarkExpired(ctx, tx, export.Id); err != nil {
+			service.Logger.ErrorContext(ctx, "data export failed to expire export", "export_id", export.Id, "error", err)
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		service.Logger.ErrorContext(ctx, "data export failed to expire exports", "error", err)
+	}
+}
+
+func writeArchiveJSON(archive *zip.Writer, name string, document []byte) error {
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, document, "", "  "); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	writer, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = pretty.WriteTo(writer)
+	return err
+}
+
+func copyFileToArchive(ctx context.Context, archive *zip.Writer, key string, archivePath string) error {
+	source, _, err := helper.OpenStoredFile(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	writer, err := archive.Create(archivePath)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(writer, source)
+	return err
+}
+
+// resolveUploadPath maps a stored upload path to its storage key and a path inside the archive.
+// Stored paths come with or without the uploads/ prefix; anything that would leave uploads/ is rejected.
+func resolveUploadPath(storedPath string) (string, string, bool) {
+	storedPath = strings.ReplaceAll(strings.TrimSpace(storedPath), "\\", "/")
+	if strings.Contains(storedPath, "://") {
+		return "", "", false
+	}
+
+	relative := strings.TrimPrefix(path.Clean("/"+storedPath), "/")
+	relative = strings.TrimPrefix(relative, "uploads/")
+	if relative == "" || relative == "uploads" {
+		return "", "", false
+	}
+
+	return "uploads/" + relative, relative, true
+}
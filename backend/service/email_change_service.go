@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+	"evoconnect/backend/model/web"
+
+	"github.com/google/uuid"
+)
+
+type EmailChangeService interface {
+	RequestChange(ctx context.Context, userId uuid.UUID, request web.ChangeEmailRequest) web.EmailChangeResponse
+	FindPending(ctx context.Context, userId uuid.UUID) web.EmailChangeResponse
+	CancelPending(ctx context.Context, userId uuid.UUID) web.MessageResponse
+	Confirm(ctx context.Context, request web.EmailChangeTokenRequest) web.MessageResponse
+	CancelByToken(ctx context.Context, request web.EmailChangeTokenRequest) web.MessageResponse
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/exception"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/web"
+	"evoconnect/backend/repository"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// How long the confirmation link sent to the new address stays valid
+const emailChangeLinkValidity = 24 * time.Hour
+
+type EmailChangeServiceImpl struct {
+	UserRepository        repository.UserRepository
+	UserSessionRepository repository.UserSessionRepository
+	APITokenRepository    repository.APITokenRepository
+	RateLimiter           RateLimiter
+	ClientURL             string
+	DB                    *sql.DB
+	Validate              *validator.Validate
+	Logger                *slog.Logger
+}
+
+func NewEmailChangeService(
+	userRepository repository.UserRepository,
+	userSessionRepository repository.UserSessionRepository,
+	apiTokenRepository repository.APITokenRepository,
+	rateLimiter RateLimiter,
+	clientURL string,
+	db *sql.DB,
+	validate *validator.Validate,
+	logger *slog.Logger,
+) EmailChangeService {
+	return &EmailChangeServiceImpl{
+		UserRepository:        userRepository,
+		UserSessionRepository: userSessionRepository,
+		APITokenRepository:    apiTokenRepository,
+		RateLimiter:           rateLimiter,
+		ClientURL:             clientURL,
+		DB:                    db,
+		Validate:              validate,
+		Logger:                logger,
+	}
+}
+
+// RequestChange sends a confirmation link to the new address and a cancel link to the current
+// one. The login email stays the same until the new address is confirmed.
+func (service *EmailChangeServiceImpl) RequestChange(ctx context.Context, userId uuid.UUID, request web.ChangeEmailRequest) web.EmailChangeResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	// Counts wrong passwords too, so the endpoint cannot be used to guess them
+	service.RateLimiter.Allow(ctx, RateLimitEmailChangeRequest, RateLimitKeyUser(userId))
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	user, err := service.UserRepository.FindById(ctx, tx, userId)
+	if err != nil {
+		panic(exception.NewNotFoundError("User not found"))
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password))
+	if err != nil {
+		panic(exception.NewUnauthorizedError("Current password is incorrect. Accounts created with a social login can set one through forgot password."))
+	}
+
+	if request.NewEmail == user.Email {
+		panic(exception.NewBadRequestError("This is already your email address"))
+	}
+	if _, err := service.UserRepository.FindByEmail(ctx, tx, request.NewEmail); err == nil {
+		panic(exception.NewBadRequestError("Email already registered"))
+	}
+
+	confirmToken := helper.GenerateSecureToken(32)
+	cancelToken := helper.GenerateSecureToken(32)
+	expires := time.Now().Add(emailChangeLinkValidity)
+
+	err = service.UserRepository.SaveEmailChange(ctx, tx, userId, request.NewEmail, helper.HashToken(confirmToken), helper.HashToken(cancelToken), expires)
+	helper.PanicIfError(err)
+
+	confirmLink := fmt.Sprintf("%s/confirm-email-change?token=%s", service.ClientURL, confirmToken)
+	confirmBody := fmt.Sprintf(`
+        <html>
+        <body>
+            <h1>Confirm your new email address</h1>
+            <p>Hello %s,</p>
+            <p>You asked to use this address for your EvoConnect account. <a href="%s">Click here</a> to confirm the change.</p>
+            <p>This link will expire in 24 hours. After confirming you will need to log in again.</p>
+            <p>If you did not request this change, please ignore this email.</p>
+            <p>Best regards,<br/>The EvoConnect Team</p>
+        </body>
+        </html>
+    `, user.Name, confirmLink)
+
+	// Without the confirmation email the request is useless, so it is rolled back
+	err = helper.EmailSender(request.NewEmail, "Confirm your new EvoConnect email address", confirmBody)
+	if err != nil {
+		panic(exception.NewBadRequestError("Failed to send confirmation email: " + err.Error()))
+	}
+
+	cancelLink := fmt.Sprintf("%s/cancel-email-change?token=%s", service.ClientURL, cancelToken)
+	cancelBody := fmt.Sprintf(`
+        <html>
+        <body>
+            <h1>Your email address is about to change</h1>
+            <p>Hello %s,</p>
+            <p>We received a request to change the email address of your EvoConnect account to <strong>%s</strong>.</p>
+            <p>If this was not you, <a href="%s">click here</a> to cancel the change and change your password.</p>
+            <p>Best regards,<br/>The EvoConnect Team</p>
+        </body>
+        </html>
+    `, user.Name, request.NewEmail, cancelLink)
+
+	if err := helper.EmailSender(user.Email, "Your EvoConnect email address is about to change", cancelBody); err != nil {
+		service.Logger.WarnContext(ctx, "failed to send email change notice", "user_id", userId, "error", err)
+	}
+
+	return web.EmailChangeResponse{
+		Message:      "Check your new email address for a confirmation link",
+		PendingEmail: request.NewEmail,
+		ExpiresAt:    expires,
+	}
+}
+
+func (service *EmailChangeServiceImpl) FindPending(ctx context.Context, userId uuid.UUID) web.EmailChangeResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	change, err := service.UserRepository.FindEmailChange(ctx, tx, userId)
+	if err != nil {
+		panic(exception.NewNotFoundError("No pending email change"))
+	}
+
+	return web.EmailChangeResponse{
+		Message:      "Waiting for confirmation of the new email address",
+		PendingEmail: change.PendingEmail,
+		ExpiresAt:    change.ExpiresAt,
+	}
+}
+
+func (service *EmailChangeServiceImpl) CancelPending(ctx context.Context, userId uuid.UUID) web.MessageResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	err = service.UserRepository.ClearEmailChange(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	return web.MessageResponse{
+		Message: "Email change cancelled",
+	}
+}
+
+// Confirm swaps in the new address and signs the user out everywhere, existing tokens were
+// issued to the old identity
+func (service *EmailChangeServiceImpl) Confirm(ctx context.Context, request web.EmailChangeTokenRequest) web.MessageResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	clientIP := helper.GetClientIP(ctx)
+	service.RateLimiter.Check(ctx, RateLimitEmailChangeToken, RateLimitKeyIP(clientIP))
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	change, err := service.UserRepository.FindEmailChangeByConfirmToken(ctx, tx, helper.HashToken(request.Token))
+	if err != nil {
+		service.RateLimiter.Record(ctx, RateLimitEmailChangeToken, RateLimitKeyIP(clientIP))
+		panic(exception.NewBadRequestError("Invalid or expired confirmation link"))
+	}
+
+	// Someone may have registered the address while the link was on its way
+	if _, err := service.UserRepository.FindByEmail(ctx, tx, change.PendingEmail); err == nil {
+		panic(exception.NewBadRequestError("Email already registered"))
+	}
+
+	err = service.UserRepository.ApplyEmailChange(ctx, tx, change.UserId)
+	helper.PanicIfError(err)
+
+	err = service.UserSessionRepository.RevokeAllByUserId(ctx, tx, change.UserId, nil, SessionRevokedEmailChange)
+	helper.PanicIfError(err)
+
+	// Personal access tokens were issued to the old identity too
+	err = service.APITokenRepository.RevokePersonalByUserId(ctx, tx, change.UserId)
+	helper.PanicIfError(err)
+
+	noticeBody := fmt.Sprintf(`
+        <html>
+        <body>
+            <h1>Your email address was changed</h1>
+            <p>Hello %s,</p>
+            <p>The email address of your EvoConnect account was changed to <strong>%s</strong>. This address will no longer receive emails about your account.</p>
+            <p>If you did not make this change, please contact our support team right away.</p>
+            <p>Best regards,<br/>The EvoConnect Team</p>
+        </body>
+        </html>
+    `, change.Name, change.PendingEmail)
+
+	if err := helper.EmailSender(change.Email, "Your EvoConnect email address was changed", noticeBody); err != nil {
+		service.Logger.WarnContext(ctx, "failed to send email changed notice", "user_id", change.UserId, "error", err)
+	}
+
+	return web.MessageResponse{
+		Message: "Email address changed. Please log in with your new email address.",
+	}
+}
+
+// CancelByToken is used from the link sent to the old address, without being logged in
+func (service *EmailChangeServiceImpl) CancelByToken(ctx context.Context, request web.EmailChangeTokenRequest) web.MessageResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	clientIP := helper.GetClientIP(ctx)
+	service.RateLimiter.Check(ctx, RateLimitEmailChangeToken, RateLimitKeyIP(clientIP))
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	change, err := service.UserRepository.FindEmailChangeByCancelToken(ctx, tx, helper.HashToken(request.Token))
+	if err != nil {
+		service.RateLimiter.Record(ctx, RateLimitEmailChangeToken, RateLimitKeyIP(clientIP))
+		panic(exception.NewBadRequestError("Invalid link or the email change was already completed"))
+	}
+
+	err = service.UserRepository.ClearEmailChange(ctx, tx, change.UserId)
+	helper.PanicIfError(err)
+
+	return web.MessageResponse{
+		Message: "Email change cancelled. If you did not request it, please change your password.",
+	}
+}
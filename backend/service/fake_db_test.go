@@ -0,0 +1,49 @@
+package service
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// fakeDriver hands out connections whose transactions do nothing, for services whose
+// repositories are replaced by in-memory fakes that ignore the *sql.Tx
+type fakeDriver struct{}
+
+type fakeConn struct{}
+
+type fakeTx struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) { return fakeConn{}, nil }
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("fake database does not run queries")
+}
+func (fakeConn) Close() error              { return nil }
+func (fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+var registerFakeDriver sync.Once
+
+func newFakeDB(t *testing.T) *sql.DB {
+	t.Helper()
+	registerFakeDriver.Do(func() { sql.Register("evoconnect-fake", fakeDriver{}) })
+
+	db, err := sql.Open("evoconnect-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// recoverPanic runs fn and returns what it panicked with, nil when it returned normally
+func recoverPanic(fn func()) (recovered interface{}) {
+	defer func() { recovered = recover() }()
+	fn()
+	return nil
+}
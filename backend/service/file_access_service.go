@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/model/domain"
+	"evoconnect/backend/model/web"
+)
+
+type FileAccessService interface {
+	// IssueURL records the access inside the caller's transaction and signs a URL for the file.
+	// The caller has already checked that the user may see the file.
+	IssueURL(ctx context.Context, tx *sql.Tx, access domain.FileAccessLog, filename string) web.SignedFileResponse
+}
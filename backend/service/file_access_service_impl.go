@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/domain"
+	"evoconnect/backend/model/web"
+	"evoconnect/backend/repository"
+	"time"
+)
+
+type FileAccessServiceImpl struct {
+	FileAccessLogRepository repository.FileAccessLogRepository
+}
+
+func NewFileAccessService(fileAccessLogRepository repository.FileAccessLogRepository) FileAccessService {
+	return &FileAccessServiceImpl{
+		FileAccessLogRepository: fileAccessLogRepository,
+	}
+}
+
+// IssueURL signs a URL showing viewed files in the browser and downloading the others
+func (service *FileAccessServiceImpl) IssueURL(ctx context.Context, tx *sql.Tx, access domain.FileAccessLog, filename string) web.SignedFileResponse {
+	access.CreatedAt = time.Now()
+	service.FileAccessLogRepository.Save(ctx, tx, access)
+
+	url, expiresAt := helper.SignFileURL(access.FileKey, filename, access.Action == domain.FileAccessView)
+	return web.SignedFileResponse{
+		Url:       url,
+		ExpiresAt: expiresAt,
+	}
+}
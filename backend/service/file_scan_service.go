@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/model/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FileScanService interface {
+	// Queue records an upload stored in quarantine inside the caller's transaction, call Wake once it commits
+	Queue(ctx context.Context, tx *sql.Tx, userId uuid.UUID, fileKey string, fileName string, kind string)
+	Wake()
+	FindStatus(ctx context.Context, tx *sql.Tx, fileKey string) domain.FileScanStatus
+	ProcessPendingScans(ctx context.Context) int
+	RunScanWorker(ctx context.Context, interval time.Duration)
+}
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"evoconnect/backend/exception"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/domain"
+	"evoconnect/backend/repository"
+	"evoconnect/backend/scanner"
+	"evoconnect/backend/storage"
+	"evoconnect/backend/utils"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Scans still running after this long belong to a worker that died
+	fileScanStaleAfter = 10 * time.Minute
+)
+
+type FileScanServiceImpl struct {
+	FileScanRepository  repository.FileScanRepository
+	NotificationService NotificationService
+	Scanner             scanner.Scanner
+	// Scans that keep failing give up after this many attempts, the file then stays in quarantine
+	MaxAttempts int
+	DB          *sql.DB
+
+	// wake lets a new upload start the worker without waiting for the next tick
+	wake   chan struct{}
+	Logger *slog.Logger
+}
+
+func NewFileScanService(
+	fileScanRepository repository.FileScanRepository,
+	notificationService NotificationService,
+	fileScanner scanner.Scanner,
+	maxAttempts int,
+	db *sql.DB,
+	logger *slog.Logger,
+) FileScanService {
+	return &FileScanServiceImpl{
+		FileScanRepository:  fileScanRepository,
+		NotificationService: notificationService,
+		Scanner:             fileScanner,
+		MaxAttempts:         maxAttempts,
+		DB:                  db,
+		wake:                make(chan struct{}, 1),
+		Logger:              logger,
+	}
+}
+
+func (service *FileScanServiceImpl) Queue(ctx context.Context, tx *sql.Tx, userId uuid.UUID, fileKey string, fileName string, kind string) {
+	service.FileScanRepository.Save(ctx, tx, domain.FileScan{
+		UserId:    userId,
+		FileKey:   fileKey,
+		FileName:  fileName,
+		Kind:      kind,
+		Status:    domain.FileScanStatusPending,
+		CreatedAt: time.Now(),
+	})
+}
+
+func (service *FileScanServiceImpl) Wake() {
+	select {
+	case service.wake <- struct{}{}:
+	default:
+	}
+}
+
+// FindStatus returns the scan status of a stored file. Files uploaded before scanning was added count as clean,
+// a failed lookup must not, so it panics instead.
+func (service *FileScanServiceImpl) FindStatus(ctx context.Context, tx *sql.Tx, fileKey string) domain.FileScanStatus {
+	scan, err := service.FileScanRepository.FindByFileKey(ctx, tx, fileKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.FileScanStatusClean
+	}
+	helper.PanicIfError(err)
+	return scan.Status
+}
+
+// ProcessPendingScans scans every queued file and returns how many were scanned. A file the scanner
+// fails on is requeued and skipped for the rest of the batch, it is tried again on the next run.
+func (service *FileScanServiceImpl) ProcessPendingScans(ctx context.Context) int {
+	scanned := 0
+	var failed []uuid.UUID
+
+	for ctx.Err() == nil {
+		tx, err := service.DB.Begin()
+		if err != nil {
+			service.Logger.ErrorContext(ctx, "file scan failed to begin transaction", "error", err)
+			return scanned
+		}
+		scan, err := service.FileScanRepository.ClaimPending(ctx, tx, failed)
+		if err != nil {
+			tx.Rollback()
+			return scanned
+		}
+		if err := tx.Commit(); err != nil {
+			service.Logger.ErrorContext(ctx, "file scan claim failed", "scan_id", scan.Id, "error", err)
+			return scanned
+		}
+
+		// One file clamd cannot read must not hold back the rest of the queue
+		if err := service.processScan(ctx, scan); err != nil {
+			service.retryScan(ctx, scan, err)
+			failed = append(failed, scan.Id)
+			continue
+		}
+		scanned++
+	}
+
+	return scanned
+}
+
+// RunScanWorker scans quarantined uploads until ctx is cancelled
+func (service *FileScanServiceImpl) RunScanWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		service.requeueStaleScans(ctx)
+		if scanned := service.ProcessPendingScans(ctx); scanned > 0 {
+			service.Logger.InfoContext(ctx, "uploads scanned", "count", scanned)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-service.wake:
+		}
+	}
+}
+
+// processScan scans one file and releases or deletes it. An error leaves the file in quarantine.
+func (service *FileScanServiceImpl) processScan(ctx context.Context, scan domain.FileScan) error {
+	object, _, err := helper.OpenQuarantinedFile(ctx, scan.FileKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		// Deleted by the uploader before it was scanned, or released by a worker that died before recording it
+		if _, statErr := helper.FileStorage().Stat(ctx, scan.FileKey); statErr == nil {
+			return service.finishScan(ctx, scan, func(tx *sql.Tx) error {
+				return service.FileScanRepository.MarkClean(ctx, tx, scan.Id)
+			})
+		}
+		service.Logger.InfoContext(ctx, "quarantined upload is gone", "scan_id", scan.Id, "key", scan.FileKey)
+		return service.finishScan(ctx, scan, func(tx *sql.Tx) error {
+			return service.FileScanRepository.MarkFailed(ctx, tx, scan.Id)
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	result, err := service.Scanner.Scan(ctx, object)
+	object.Close()
+	if err != nil {
+		return err
+	}
+
+	if result.Infected {
+		service.quarantineInfected(ctx, scan, result.Signature)
+		return nil
+	}
+
+	if err := helper.ReleaseQuarantinedFile(ctx, scan.FileKey); err != nil {
+		return err
+	}
+	return service.finishScan(ctx, scan, func(tx *sql.Tx) error {
+		return service.FileScanRepository.MarkClean(ctx, tx, scan.Id)
+	})
+}
+
+// quarantineInfected deletes an infected upload with the CV or messages pointing at it and tells the uploader
+func (service *FileScanServiceImpl) quarantineInfected(ctx context.Context, scan domain.FileScan, signature string) {
+	service.Logger.WarnContext(ctx, "infected upload deleted", "scan_id", scan.Id, "user_id", scan.UserId, "key", scan.FileKey, "signature", signature)
+
+	if err := helper.DeleteQuarantinedFile(ctx, scan.FileKey); err != nil {
+		service.Logger.ErrorContext(ctx, "file scan failed to delete infected upload", "scan_id", scan.Id, "error", err)
+	}
+
+	var messages []domain.Message
+	err := service.finishScan(ctx, scan, func(tx *sql.Tx) error {
+		if err := service.FileScanRepository.MarkInfected(ctx, tx, scan.Id, signature); err != nil {
+			return err
+		}
+		var err error
+		messages, err = service.FileScanRepository.DeleteFileReferences(ctx, tx, scan.FileKey)
+		return err
+	})
+	if err != nil {
+		return
+	}
+
+	for _, message := range messages {
+		utils.TriggerPusher(fmt.Sprintf("private-conversation-%s", message.ConversationId), "message-deleted", map[string]interface{}{
+			"message_id":      message.Id,
+			"conversation_id": message.ConversationId,
+		})
+	}
+
+	if scan.Kind == domain.FileScanKindCv {
+		service.notify(ctx, scan, domain.NotificationTypeFileInfected,
+			"Your CV was removed",
+			fmt.Sprintf("Your CV %q contains malware and was deleted. Please upload a clean copy, applications sent with it no longer have a CV.", scan.FileName))
+	} else {
+		service.notify(ctx, scan, domain.NotificationTypeFileInfected,
+			"Your file was removed",
+			fmt.Sprintf("The file %q you sent in a chat contains malware and was deleted.", scan.FileName))
+	}
+}
+
+// retryScan puts a scan back in the queue, or gives up once it failed too often
+func (service *FileScanServiceImpl) retryScan(ctx context.Context, scan domain.FileScan, scanErr error) {
+	service.Logger.ErrorContext(ctx, "file scan failed", "scan_id", scan.Id, "attempt", scan.Attempts, "error", scanErr)
+
+	if scan.Attempts < service.MaxAttempts {
+		service.finishScan(ctx, scan, func(tx *sql.Tx) error {
+			return service.FileScanRepository.Requeue(ctx, tx, scan.Id)
+		})
+		return
+	}
+
+	err := service.finishScan(ctx, scan, func(tx *sql.Tx) error {
+		return service.FileScanRepository.MarkFailed(ctx, tx, scan.Id)
+	})
+	if err == nil {
+		service.notify(ctx, scan, domain.NotificationTypeFileScanFailed,
+			"Your file could not be checked",
+			fmt.Sprintf("We could not check %q for malware, so it is not available. Please upload it again.", scan.FileName))
+	}
+}
+
+func (service *FileScanServiceImpl) finishScan(ctx context.Context, scan domain.FileScan, update func(tx *sql.Tx) error) error {
+	tx, err := service.DB.Begin()
+	if err == nil {
+		err = update(tx)
+		if err == nil {
+			err = tx.Commit()
+		} else {
+			tx.Rollback()
+		}
+	}
+	if err != nil {
+		service.Logger.ErrorContext(ctx, "file scan update failed", "scan_id", scan.Id, "error", err)
+	}
+	return err
+}
+
+func (service *FileScanServiceImpl) requeueStaleScans(ctx context.Context) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		service.Logger.ErrorContext(ctx, "file scan failed to begin transaction", "error", err)
+		return
+	}
+	requeued, err := service.FileScanRepository.RequeueStale(ctx, tx, time.Now().Add(-fileScanStaleAfter))
+	if err != nil {
+		tx.Rollback()
+		service.Logger.ErrorContext(ctx, "file scan failed to requeue stale scans", "error", err)
+		return
+	}
+	if err := tx.Commit(); err == nil && requeued > 0 {
+		service.Logger.InfoContext(ctx, "stale file scans requeued", "count", requeued)
+	}
+}
+
+func (service *FileScanServiceImpl) notify(ctx context.Context, scan domain.FileScan, notificationType domain.NotificationType, title string, message string) {
+	// The scan is already recorded, a failing notification must not send it back to the queue
+	defer func() {
+		if r := recover(); r != nil {
+			service.Logger.ErrorContext(ctx, "file scan notification failed", "scan_id", scan.Id, "panic", fmt.Sprint(r))
+		}
+	}()
+
+	category := domain.NotificationCategoryProfile
+	if scan.Kind == domain.FileScanKindCv {
+		category = domain.NotificationCategoryJob
+	}
+
+	referenceType := "file_scan"
+	service.NotificationService.Create(
+		ctx,
+		scan.UserId,
+		string(category),
+		string(notificationType),
+		title,
+		message,
+		&scan.Id,
+		&referenceType,
+		nil,
+	)
+}
+
+// panicIfNotScanned refuses access to a file until its malware scan is clean
+func panicIfNotScanned(status domain.FileScanStatus, name string) {
+	switch status {
+	case domain.FileScanStatusClean:
+	case domain.FileScanStatusPending, domain.FileScanStatusScanning:
+		panic(exception.NewBadRequestError(fmt.Sprintf("This %s is still being checked, please try again shortly", name)))
+	default:
+		panic(exception.NewNotFoundError(fmt.Sprintf("%s file not found", name)))
+	}
+}
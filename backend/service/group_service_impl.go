@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"log/slog"
 	"mime/multipart"
 	"time"
 
@@ -28,6 +29,7 @@ type GroupServiceImpl struct {
 	Validate                   *validator.Validate
 	GroupJoinRequestRepository repository.GroupJoinRequestRepository
 	BlockedMemberRepository    repository.GroupBlockedMemberRepository
+	Logger                     *slog.Logger
 }
 
 func NewGroupService(
@@ -40,7 +42,7 @@ func NewGroupService(
 	notificationService NotificationService,
 	groupJoinRequestRepository repository.GroupJoinRequestRepository,
 	blockedMemberRepository repository.GroupBlockedMemberRepository,
-	validate *validator.Validate) GroupService {
+	validate *validator.Validate, logger *slog.Logger) GroupService {
 	return &GroupServiceImpl{
 		DB:                         db,
 		GroupRepository:            groupRepository,
@@ -52,6 +54,7 @@ func NewGroupService(
 		Validate:                   validate,
 		BlockedMemberRepository:    blockedMemberRepository,
 		GroupJoinRequestRepository: groupJoinRequestRepository,
+		Logger:                     logger,
 	}
 }
 
@@ -167,7 +170,7 @@ func (service *GroupServiceImpl) Update(ctx context.Context, groupId, userId uui
 			err = helper.DeleteFile(*group.Image)
 			if err != nil {
 				// Just log the error but don't stop the process
-				fmt.Printf("Failed to delete old image: %v\n", err)
+				service.Logger.ErrorContext(ctx, "failed to delete old image", "error", err)
 			}
 		}
 
@@ -910,11 +913,12 @@ func (service *GroupServiceImpl) CreateInvitation(ctx context.Context, groupId,
 
 	// Add group details
 	response.Group = web.GroupBriefResponse{
-		Id:           group.Id,
-		Name:         group.Name,
-		Description:  group.Description,
-		Image:        group.Image,
-		PrivacyLevel: group.PrivacyLevel,
+		Id:            group.Id,
+		Name:          group.Name,
+		Description:   group.Description,
+		Image:         group.Image,
+		ImageVariants: helper.OptionalImageVariantsOf(group.Image),
+		PrivacyLevel:  group.PrivacyLevel,
 	}
 
 	// Count members
@@ -1032,11 +1036,12 @@ func (service *GroupServiceImpl) GetMyInvitations(ctx context.Context, userId uu
 		group, err := service.GroupRepository.FindById(ctx, tx, invitation.GroupId)
 		if err == nil {
 			response.Group = web.GroupBriefResponse{
-				Id:           group.Id,
-				Name:         group.Name,
-				Description:  group.Description,
-				Image:        group.Image,
-				PrivacyLevel: group.PrivacyLevel,
+				Id:            group.Id,
+				Name:          group.Name,
+				Description:   group.Description,
+				Image:         group.Image,
+				ImageVariants: helper.OptionalImageVariantsOf(group.Image),
+				PrivacyLevel:  group.PrivacyLevel,
 			}
 
 			// Count members
@@ -1407,7 +1412,7 @@ func (service *GroupServiceImpl) CreateJoinRequest(ctx context.Context, userId u
 			newCtx := context.Background()
 			newTx, err := service.DB.Begin()
 			if err != nil {
-				fmt.Printf("Error creating transaction in goroutine: %v\n", err)
+				service.Logger.ErrorContext(ctx, "failed to begin notification transaction", "error", err)
 				return
 			}
 			defer newTx.Commit()
@@ -1450,7 +1455,7 @@ func (service *GroupServiceImpl) FindJoinRequestsByGroupId(ctx context.Context,
 		// Get user info
 		user, err := service.UserRepository.FindById(ctx, tx, request.UserId)
 		if err != nil {
-			fmt.Printf("ERROR: Failed to get user info: %v\n", err)
+			service.Logger.ErrorContext(ctx, "failed to get user info", "error", err)
 			continue
 		}
 		request.User = &user
@@ -1458,7 +1463,7 @@ func (service *GroupServiceImpl) FindJoinRequestsByGroupId(ctx context.Context,
 		// Get group info
 		group, err := service.GroupRepository.FindById(ctx, tx, request.GroupId)
 		if err != nil {
-			fmt.Printf("ERROR: Failed to get group info: %v\n", err)
+			service.Logger.ErrorContext(ctx, "failed to get group info", "error", err)
 			continue
 		}
 		request.Group = &group
@@ -1525,7 +1530,7 @@ func (service *GroupServiceImpl) AcceptJoinRequest(ctx context.Context, requestI
 			newCtx := context.Background()
 			newTx, err := service.DB.Begin()
 			if err != nil {
-				fmt.Printf("Error creating transaction in goroutine: %v\n", err)
+				service.Logger.ErrorContext(ctx, "failed to begin notification transaction", "error", err)
 				return
 			}
 			defer newTx.Commit()
@@ -1586,7 +1591,7 @@ func (service *GroupServiceImpl) RejectJoinRequest(ctx context.Context, requestI
 			newCtx := context.Background()
 			newTx, err := service.DB.Begin()
 			if err != nil {
-				fmt.Printf("Error creating transaction in goroutine: %v\n", err)
+				service.Logger.ErrorContext(ctx, "failed to begin notification transaction", "error", err)
 				return
 			}
 			defer newTx.Commit()
@@ -1717,7 +1722,7 @@ func (service *GroupServiceImpl) FindMyJoinRequests(ctx context.Context, userId
 		// Dapatkan informasi grup dengan lengkap
 		group, err := service.GroupRepository.FindById(ctx, tx, request.GroupId)
 		if err != nil {
-			fmt.Printf("ERROR: Failed to get group info: %v\n", err)
+			service.Logger.ErrorContext(ctx, "failed to get group info", "error", err)
 			continue
 		}
 
@@ -1735,7 +1740,7 @@ func (service *GroupServiceImpl) FindMyJoinRequests(ctx context.Context, userId
 		// Dapatkan informasi user
 		user, err := service.UserRepository.FindById(ctx, tx, request.UserId)
 		if err != nil {
-			fmt.Printf("ERROR: Failed to get user info: %v\n", err)
+			service.Logger.ErrorContext(ctx, "failed to get user info", "error", err)
 			continue
 		}
 		request.User = &user
@@ -1753,6 +1758,7 @@ func (service *GroupServiceImpl) FindMyJoinRequests(ctx context.Context, userId
 			// Pastikan image diambil dengan benar
 			if group.Image != nil {
 				response.Group.Image = group.Image
+				response.Group.ImageVariants = helper.ImageVariantsOf(*group.Image)
 			}
 		}
 
@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+	"evoconnect/backend/model/web"
+)
+
+type HealthService interface {
+	Liveness() web.HealthResponse
+	// Readiness reports whether the instance should receive traffic
+	Readiness(ctx context.Context) (web.HealthResponse, bool)
+	// StartDraining fails readiness so load balancers stop routing here before shutdown
+	StartDraining()
+}
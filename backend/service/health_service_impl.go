@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/db/migrations"
+	"evoconnect/backend/model/web"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	HealthStatusOK       = "ok"
+	HealthStatusFailing  = "failing"
+	HealthStatusDraining = "draining"
+
+	healthCheckTimeout = 2 * time.Second
+)
+
+type HealthServiceImpl struct {
+	DB *sql.DB
+	// Built once at startup, so a probe only reads the version table instead of parsing the embedded SQL
+	Migrator *migrations.Migrator
+	// Set with ALLOW_PENDING_MIGRATIONS, a rolling deploy keeps serving while another job migrates
+	AllowPendingMigrations bool
+	Logger                 *slog.Logger
+	draining               atomic.Bool
+}
+
+func NewHealthService(db *sql.DB, migrator *migrations.Migrator, allowPendingMigrations bool, logger *slog.Logger) HealthService {
+	return &HealthServiceImpl{
+		DB:                     db,
+		Migrator:               migrator,
+		AllowPendingMigrations: allowPendingMigrations,
+		Logger:                 logger,
+	}
+}
+
+func (service *HealthServiceImpl) Liveness() web.HealthResponse {
+	return web.HealthResponse{Status: HealthStatusOK}
+}
+
+func (service *HealthServiceImpl) Readiness(ctx context.Context) (web.HealthResponse, bool) {
+	if service.draining.Load() {
+		return web.HealthResponse{Status: HealthStatusDraining}, false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	// The endpoint is public, the reason an instance is not ready only goes to the logs
+	if !service.checkDatabase(ctx) || !service.checkMigrations(ctx) {
+		return web.HealthResponse{Status: HealthStatusFailing}, false
+	}
+	return web.HealthResponse{Status: HealthStatusOK}, true
+}
+
+func (service *HealthServiceImpl) StartDraining() {
+	service.draining.Store(true)
+}
+
+func (service *HealthServiceImpl) checkDatabase(ctx context.Context) bool {
+	if err := service.DB.PingContext(ctx); err != nil {
+		service.Logger.WarnContext(ctx, "readiness database check failed", "error", err)
+		return false
+	}
+	return true
+}
+
+// checkMigrations keeps an instance out of rotation until the schema matches the code
+func (service *HealthServiceImpl) checkMigrations(ctx context.Context) bool {
+	pending, err := service.Migrator.PendingVersions(ctx)
+	if err != nil {
+		service.Logger.WarnContext(ctx, "readiness migration check failed", "error", err)
+		return false
+	}
+	if len(pending) > 0 && !service.AllowPendingMigrations {
+		service.Logger.WarnContext(ctx, "readiness failing, migrations are pending", "pending", pending)
+		return false
+	}
+	return true
+}
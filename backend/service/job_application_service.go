@@ -19,4 +19,5 @@ type JobApplicationService interface {
 	ReviewApplication(ctx context.Context, request web.ReviewJobApplicationRequest, jobApplicationId, reviewerId uuid.UUID) web.JobApplicationResponse
 	GetStats(ctx context.Context, companyId *uuid.UUID) web.JobApplicationStatsResponse
 	HasApplied(ctx context.Context, jobVacancyId, applicantId uuid.UUID) bool
+	ViewCv(ctx context.Context, jobApplicationId, viewerId uuid.UUID) web.SignedFileResponse
 }
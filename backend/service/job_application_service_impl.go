@@ -11,9 +11,7 @@ import (
 	"evoconnect/backend/repository"
 	"fmt"
 	"mime/multipart"
-	"os"
 	"path/filepath"
-	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -27,6 +25,8 @@ type JobApplicationServiceImpl struct {
 	UserRepository            repository.UserRepository
 	MemberCompanyReRepository repository.MemberCompanyRepository
 	NotificationService       NotificationService
+	FileScanService           FileScanService
+	FileAccessService         FileAccessService
 	DB                        *sql.DB
 	Validate                  *validator.Validate
 }
@@ -38,6 +38,8 @@ func NewJobApplicationService(
 	userRepository repository.UserRepository,
 	memberCompanyRepository repository.MemberCompanyRepository,
 	notificationService NotificationService,
+	fileScanService FileScanService,
+	fileAccessService FileAccessService,
 	DB *sql.DB,
 	validate *validator.Validate) JobApplicationService {
 	return &JobApplicationServiceImpl{
@@ -47,6 +49,8 @@ func NewJobApplicationService(
 		UserRepository:            userRepository,
 		MemberCompanyReRepository: memberCompanyRepository,
 		NotificationService:       notificationService,
+		FileScanService:           fileScanService,
+		FileAccessService:         fileAccessService,
 		DB:                        DB,
 		Validate:                  validate,
 	}
@@ -56,6 +60,11 @@ func (service *JobApplicationServiceImpl) Create(ctx context.Context, request we
 	err := service.Validate.Struct(request)
 	helper.PanicIfError(err)
 
+	// A new CV is scanned once the transaction below has committed
+	if request.CvFile != nil {
+		defer service.FileScanService.Wake()
+	}
+
 	tx, err := service.DB.Begin()
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
@@ -146,7 +155,6 @@ func (service *JobApplicationServiceImpl) Create(ctx context.Context, request we
 
 			// Validasi companyOwnerId tidak kosong
 			if companyOwnerId == uuid.Nil {
-				fmt.Printf("Warning: Company owner ID is nil for company %s\n", jobApplication.JobVacancy.Company.Name)
 				return
 			}
 
@@ -173,7 +181,6 @@ func (service *JobApplicationServiceImpl) Create(ctx context.Context, request we
 		go func() {
 			// Validasi CreatorId tidak kosong
 			if *jobApplication.JobVacancy.CreatorId == uuid.Nil {
-				fmt.Printf("Warning: Job vacancy creator ID is nil for vacancy %s\n", jobApplication.JobVacancy.Title)
 				return
 			}
 
@@ -201,6 +208,11 @@ func (service *JobApplicationServiceImpl) Update(ctx context.Context, request we
 	err := service.Validate.Struct(request)
 	helper.PanicIfError(err)
 
+	// A new CV is scanned once the transaction below has committed
+	if request.CvFile != nil {
+		defer service.FileScanService.Wake()
+	}
+
 	tx, err := service.DB.Begin()
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
@@ -513,16 +525,15 @@ func (service *JobApplicationServiceImpl) HasApplied(ctx context.Context, jobVac
 // Helper methods
 
 func (service *JobApplicationServiceImpl) handleCvUpload(ctx context.Context, tx *sql.Tx, file *multipart.FileHeader, userId uuid.UUID) string {
-	// Validate file
-	if file.Size > 5*1024*1024 { // 5MB limit
-		panic(exception.NewBadRequestError("CV file size must be less than 5MB"))
+	// Validate file, a CV must be a PDF of at most 5MB
+	src, err := file.Open()
+	if err != nil {
+		panic(exception.NewBadRequestError("Failed to open uploaded file"))
 	}
+	defer src.Close()
 
-	// Check file extension
-	ext := strings.ToLower(filepath.Ext(file.Filename))
-	if ext != ".pdf" && ext != ".doc" && ext != ".docx" {
-		panic(exception.NewBadRequestError("CV must be in PDF, DOC, or DOCX format"))
-	}
+	checked, err := helper.UploadPolicyCv.Check(src, file)
+	helper.PanicIfError(err)
 
 	// Check if user already has CV
 	userHasCv := service.UserCvStorageRepository.ExistsByUserId(ctx, tx, userId)
@@ -536,21 +547,16 @@ func (service *JobApplicationServiceImpl) handleCvUpload(ctx context.Context, tx
 		}
 	}
 
-	// Save file
-	src, err := file.Open()
-	if err != nil {
-		panic(exception.NewBadRequestError("Failed to open uploaded file"))
-	}
-	defer src.Close()
-
-	filePath := helper.SaveUploadedFile(src, "cv_storage", userId.String(), file.Filename)
+	// Save file to quarantine, it is released once scanned clean
+	filePath := helper.SaveQuarantinedFile(src, helper.DirCVStorage, userId.String(), checked.Extension)
+	service.FileScanService.Queue(ctx, tx, userId, filePath, checked.Filename, domain.FileScanKindCv)
 
 	// Update or create CV storage record
 	cvStorage := domain.UserCvStorage{
 		Id:               uuid.New(),
 		UserId:           userId,
 		CvFilePath:       filePath,
-		OriginalFilename: file.Filename,
+		OriginalFilename: checked.Filename,
 		FileSize:         file.Size,
 		UploadedAt:       time.Now(),
 		UpdatedAt:        time.Now(),
@@ -563,7 +569,7 @@ func (service *JobApplicationServiceImpl) handleCvUpload(ctx context.Context, tx
 		// Delete old file if exists and different from new one
 		if oldCvPath != "" && oldCvPath != filePath {
 			go func() {
-				os.Remove(oldCvPath)
+				helper.DeleteFile(oldCvPath)
 			}()
 		}
 	} else {
@@ -607,9 +613,10 @@ func (service *JobApplicationServiceImpl) toJobApplicationResponse(jobApplicatio
 
 		if jobApplication.JobVacancy.Company != nil {
 			response.JobVacancy.Company = &web.CompanyBriefResponse{
-				Id:   jobApplication.JobVacancy.Company.Id,
-				Name: jobApplication.JobVacancy.Company.Name,
-				Logo: &jobApplication.JobVacancy.Company.Logo,
+				Id:           jobApplication.JobVacancy.Company.Id,
+				Name:         jobApplication.JobVacancy.Company.Name,
+				Logo:         &jobApplication.JobVacancy.Company.Logo,
+				LogoVariants: helper.ImageVariantsOf(jobApplication.JobVacancy.Company.Logo),
 			}
 		}
 	}
@@ -652,8 +659,8 @@ func (service *JobApplicationServiceImpl) toContactInfoResponse(contactInfo doma
 	}
 }
 
-// Tambahkan sebagai fungsi baru
-func (service *JobApplicationServiceImpl) ViewCv(ctx context.Context, jobApplicationId, viewerId uuid.UUID) web.JobApplicationResponse {
+// ViewCv issues a link to the CV of an application for the applicant or the HR team of the company
+func (service *JobApplicationServiceImpl) ViewCv(ctx context.Context, jobApplicationId, viewerId uuid.UUID) web.SignedFileResponse {
 	tx, err := service.DB.Begin()
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
@@ -663,20 +670,50 @@ func (service *JobApplicationServiceImpl) ViewCv(ctx context.Context, jobApplica
 		panic(exception.NewNotFoundError("Job application not found"))
 	}
 
+	if jobApplication.ApplicantId != viewerId {
+		viewer, err := service.MemberCompanyReRepository.FindByUserAndCompany(ctx, tx, viewerId, jobApplication.JobVacancy.CompanyId)
+		if err != nil {
+			panic(exception.NewForbiddenError("You are not authorized to view this CV"))
+		}
+		if viewer.Role != entity.RoleHRD && viewer.Role != entity.RoleAdmin && viewer.Role != entity.RoleSuperAdmin {
+			panic(exception.NewForbiddenError("Only HR or company admin can view CVs"))
+		}
+	}
+
+	if jobApplication.CvFilePath == "" {
+		panic(exception.NewNotFoundError("This application has no CV"))
+	}
+	panicIfNotScanned(service.FileScanService.FindStatus(ctx, tx, jobApplication.CvFilePath), "CV")
+
+	filename := "CV" + filepath.Ext(jobApplication.CvFilePath)
+	if jobApplication.Applicant != nil && jobApplication.Applicant.Name != "" {
+		filename = jobApplication.Applicant.Name + " " + filename
+	}
+
+	signedFile := service.FileAccessService.IssueURL(ctx, tx, domain.FileAccessLog{
+		UserId:        viewerId,
+		FileKey:       jobApplication.CvFilePath,
+		Action:        domain.FileAccessView,
+		ReferenceType: domain.FileReferenceJobApplication,
+		ReferenceId:   jobApplication.Id,
+	}, filename)
+
 	// Kirim notifikasi ke pelamar
-	go func() {
-		service.NotificationService.Create(
-			context.Background(),
-			jobApplication.ApplicantId,
-			string(domain.NotificationCategoryJob),
-			"cv_viewed",
-			"CV Viewed",
-			"Your CV has been viewed by the HR team",
-			&jobApplication.Id,
-			nil,
-			&viewerId,
-		)
-	}()
+	if jobApplication.ApplicantId != viewerId {
+		go func() {
+			service.NotificationService.Create(
+				context.Background(),
+				jobApplication.ApplicantId,
+				string(domain.NotificationCategoryJob),
+				"cv_viewed",
+				"CV Viewed",
+				"Your CV has been viewed by the HR team",
+				&jobApplication.Id,
+				nil,
+				&viewerId,
+			)
+		}()
+	}
 
-	return service.toJobApplicationResponse(jobApplication)
+	return signedFile
 }
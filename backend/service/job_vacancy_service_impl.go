@@ -491,6 +491,7 @@ func (service *JobVacancyServiceImpl) GetPublicJobDetail(ctx context.Context, jo
 			Id:   jobVacancy.Company.Id.String(),
 			Name: jobVacancy.Company.Name,
 			Logo: &jobVacancy.Company.Logo,
+			LogoVariants: helper.ImageVariantsOf(jobVacancy.Company.Logo),
 		}
 	}
 
@@ -617,6 +618,7 @@ func (service *JobVacancyServiceImpl) toJobVacancyResponse(ctx context.Context,
 			Id:   jobVacancy.Company.Id.String(),
 			Name: jobVacancy.Company.Name,
 			Logo: &jobVacancy.Company.Logo,
+			LogoVariants: helper.ImageVariantsOf(jobVacancy.Company.Logo),
 		}
 	}
 
@@ -658,6 +660,7 @@ func toJobVacancyPublicResponse(jobVacancy domain.JobVacancy) web.JobVacancyPubl
 			Id:   jobVacancy.Company.Id.String(),
 			Name: jobVacancy.Company.Name,
 			Logo: &jobVacancy.Company.Logo,
+			LogoVariants: helper.ImageVariantsOf(jobVacancy.Company.Logo),
 		}
 	}
 
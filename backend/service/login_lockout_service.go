@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/model/domain"
+
+	"github.com/google/uuid"
+)
+
+type LoginLockoutService interface {
+	// CheckLocked panics with TooManyRequestsError while the account is locked
+	CheckLocked(ctx context.Context, ownerType domain.AccountType, ownerId uuid.UUID)
+	// RecordFailure counts a wrong password and locks the account once the threshold is reached
+	RecordFailure(ctx context.Context, ownerType domain.AccountType, ownerId uuid.UUID, email string, name string)
+	Reset(ctx context.Context, tx *sql.Tx, ownerType domain.AccountType, ownerId uuid.UUID)
+}
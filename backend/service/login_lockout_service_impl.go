@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/exception"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/domain"
+	"evoconnect/backend/repository"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Failures older than this no longer count towards a lockout
+const loginFailureWindow = time.Hour
+
+// The longest a lockout can get however often it repeats
+const maxLoginLockout = 24 * time.Hour
+
+type LoginLockoutServiceImpl struct {
+	LoginLockoutRepository repository.LoginLockoutRepository
+	// Failed logins within the failure window that lock the account
+	Threshold int
+	// Length of the first lockout
+	BaseDuration time.Duration
+	DB           *sql.DB
+	Logger       *slog.Logger
+}
+
+func NewLoginLockoutService(loginLockoutRepository repository.LoginLockoutRepository, threshold int, baseDuration time.Duration, db *sql.DB, logger *slog.Logger) LoginLockoutService {
+	return &LoginLockoutServiceImpl{
+		LoginLockoutRepository: loginLockoutRepository,
+		Threshold:              threshold,
+		BaseDuration:           baseDuration,
+		DB:                     db,
+		Logger:                 logger,
+	}
+}
+
+// loginLockoutDuration doubles with every lockout: 5, 10, 20 minutes and so on up to a day
+func loginLockoutDuration(baseDuration time.Duration, previousLockouts int) time.Duration {
+	duration := baseDuration
+	for i := 0; i < previousLockouts && duration < maxLoginLockout; i++ {
+		duration *= 2
+	}
+	if duration > maxLoginLockout {
+		duration = maxLoginLockout
+	}
+	return duration
+}
+
+func (service *LoginLockoutServiceImpl) CheckLocked(ctx context.Context, ownerType domain.AccountType, ownerId uuid.UUID) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	lockout, err := service.LoginLockoutRepository.FindByOwner(ctx, tx, ownerType, ownerId)
+	if err != nil || !lockout.IsLocked() {
+		return
+	}
+
+	panic(exception.NewTooManyRequestsErrorWithRetry(
+		fmt.Sprintf("This account is temporarily locked after too many failed logins. Try again after %s.", lockout.LockedUntil.Format(time.RFC3339)),
+		time.Until(*lockout.LockedUntil),
+	))
+}
+
+// RecordFailure runs in its own transaction because the login that failed is rolled back
+func (service *LoginLockoutServiceImpl) RecordFailure(ctx context.Context, ownerType domain.AccountType, ownerId uuid.UUID, email string, name string) {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		service.Logger.ErrorContext(ctx, "login lockout failed to begin transaction", "error", err)
+		return
+	}
+	defer tx.Rollback()
+
+	lockout, err := service.LoginLockoutRepository.RecordFailure(ctx, tx, ownerType, ownerId, time.Now().Add(-loginFailureWindow))
+	if err != nil {
+		service.Logger.ErrorContext(ctx, "login lockout failed to record failure", "owner_type", ownerType, "owner_id", ownerId, "error", err)
+		return
+	}
+
+	if lockout.FailedCount < service.Threshold {
+		tx.Commit()
+		return
+	}
+
+	lockedUntil := time.Now().Add(loginLockoutDuration(service.BaseDuration, lockout.LockoutCount))
+	if err := service.LoginLockoutRepository.Lock(ctx, tx, ownerType, ownerId, lockedUntil); err != nil {
+		service.Logger.ErrorContext(ctx, "login lockout failed to lock account", "owner_type", ownerType, "owner_id", ownerId, "error", err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		service.Logger.ErrorContext(ctx, "login lockout failed to lock account", "owner_type", ownerType, "owner_id", ownerId, "error", err)
+		return
+	}
+
+	go service.sendLoginLockoutEmail(email, name, lockout.FailedCount, helper.GetClientIP(ctx), lockedUntil)
+}
+
+func (service *LoginLockoutServiceImpl) Reset(ctx context.Context, tx *sql.Tx, ownerType domain.AccountType, ownerId uuid.UUID) {
+	err := service.LoginLockoutRepository.Delete(ctx, tx, ownerType, ownerId)
+	helper.PanicIfError(err)
+}
+
+func (service *LoginLockoutServiceImpl) sendLoginLockoutEmail(email string, name string, failedCount int, clientIP string, lockedUntil time.Time) {
+	emailBody := fmt.Sprintf(`
+        <html>
+        <body>
+            <h1>Your account was temporarily locked</h1>
+            <p>Hello %s,</p>
+            <p>We noticed %d failed login attempts on your EvoConnect account, the last one from IP address <strong>%s</strong>.</p>
+            <p>To protect your account, logins are blocked until <strong>%s</strong>.</p>
+            <p>If this was you, simply wait and try again. If it was not, we recommend resetting your password and enabling two-factor authentication.</p>
+            <p>Best regards,<br/>The EvoConnect Team</p>
+        </body>
+        </html>
+    `, name, failedCount, clientIP, lockedUntil.Format("January 2, 2006 15:04 MST"))
+
+	if err := helper.EmailSender(email, "Your EvoConnect account was temporarily locked", emailBody); err != nil {
+		service.Logger.Warn("failed to send login lockout email", "email", email, "error", err)
+	}
+}
@@ -550,6 +550,7 @@ func (service *memberCompanyServiceImpl) toMemberCompanyResponse(memberCompany e
 			Size:        memberCompany.Company.Size,
 			Type:        memberCompany.Company.Type,
 			Logo:        memberCompany.Company.Logo,
+			LogoVariants:        helper.ImageVariantsOf(memberCompany.Company.Logo),
 			Tagline:     memberCompany.Company.Tagline,
 			IsVerified:  memberCompany.Company.IsVerified,
 		}
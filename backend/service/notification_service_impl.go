@@ -9,6 +9,7 @@ import (
 	"evoconnect/backend/repository"
 	"evoconnect/backend/utils"
 	"fmt"
+	"log/slog"
 	"math/rand"
 	
 	"github.com/go-playground/validator/v10"
@@ -20,6 +21,7 @@ type NotificationServiceImpl struct {
 	UserRepository         repository.UserRepository
 	DB                     *sql.DB
 	Validate               *validator.Validate
+	Logger                 *slog.Logger
 }
 
 func NewNotificationService(
@@ -27,12 +29,14 @@ func NewNotificationService(
 	userRepository repository.UserRepository,
 	DB *sql.DB,
 	validate *validator.Validate,
+	logger *slog.Logger,
 ) NotificationService {
 	return &NotificationServiceImpl{
 		NotificationRepository: notificationRepository,
 		UserRepository:         userRepository,
 		DB:                     DB,
 		Validate:               validate,
+		Logger:                 logger,
 	}
 }
 
@@ -47,9 +51,6 @@ func (service *NotificationServiceImpl) Create(
 	referenceType *string,
 	actorId *uuid.UUID,
 ) uuid.UUID {
-	fmt.Printf("Creating notification: userId=%s, category=%s, type=%s\n",
-		userId, category, notificationType)
-
 	tx, err := service.DB.Begin()
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
@@ -71,7 +72,6 @@ func (service *NotificationServiceImpl) Create(
 
 		if err == nil {
 			// Notifikasi serupa sudah ada, gunakan yang sudah ada
-			fmt.Printf("Similar notification already exists with ID: %s\n", existingNotification.Id)
 			return existingNotification.Id
 		}
 	}
@@ -90,7 +90,8 @@ func (service *NotificationServiceImpl) Create(
 	}
 
 	notification = service.NotificationRepository.Save(ctx, tx, notification)
-	fmt.Printf("Notification saved with ID: %s\n", notification.Id)
+	helper.NotificationsCreatedTotal.WithLabelValues(category).Inc()
+	service.Logger.DebugContext(ctx, "notification created", "notification_id", notification.Id, "user_id", userId, "type", notificationType)
 
 	// Get actor details if actorId is provided
 	// if actorId != nil {
@@ -103,14 +104,11 @@ func (service *NotificationServiceImpl) Create(
 	// Trigger Pusher event
 	notificationResponse := service.toNotificationResponse(notification)
 	channelName := fmt.Sprintf("private-user-%s", userId)
-	fmt.Printf("Triggering Pusher: channel=%s, event=new-notification\n", channelName)
 
 	go func() {
-		err := utils.PusherClient.Trigger(channelName, "new-notification", notificationResponse)
+		err := utils.TriggerPusher(channelName, "new-notification", notificationResponse)
 		if err != nil {
-			fmt.Printf("Error triggering Pusher: %v\n", err)
-		} else {
-			fmt.Printf("Successfully triggered Pusher notification\n")
+			service.Logger.ErrorContext(ctx, "pusher trigger failed", "channel", channelName, "error", err)
 		}
 	}()
 
@@ -118,23 +116,15 @@ func (service *NotificationServiceImpl) Create(
 }
 
 func (service *NotificationServiceImpl) GetNotifications(ctx context.Context, userId uuid.UUID, category string, limit, offset int) web.NotificationListResponse {
-	fmt.Printf("DEBUG: Getting notifications for user: %s, category: %s, limit: %d, offset: %d\n",
-		userId, category, limit, offset)
-
 	tx, err := service.DB.Begin()
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
 
 	notifications := service.NotificationRepository.FindByUserId(ctx, tx, userId, category, limit, offset)
-	fmt.Printf("DEBUG: Found %d notifications from repository\n", len(notifications))
 
 	total := service.NotificationRepository.CountByUserId(ctx, tx, userId, category)
-	fmt.Printf("DEBUG: Total count: %d\n", total)
 	unreadCount := service.NotificationRepository.CountUnreadByUserId(ctx, tx, userId, category)
 
-	fmt.Printf("Found %d notifications, total: %d, unread: %d\n",
-		len(notifications), total, unreadCount)
-
 	var notificationResponses []web.NotificationResponse
 	for _, notification := range notifications {
 		notificationResponses = append(notificationResponses, service.toNotificationResponse(notification))
@@ -148,9 +138,6 @@ func (service *NotificationServiceImpl) GetNotifications(ctx context.Context, us
 }
 
 func (service *NotificationServiceImpl) MarkAsRead(ctx context.Context, userId uuid.UUID, request web.MarkNotificationReadRequest) int {
-	fmt.Printf("Marking notifications as read for user: %s, count: %d\n",
-		userId, len(request.NotificationIds))
-
 	err := service.Validate.Struct(request)
 	helper.PanicIfError(err)
 
@@ -162,20 +149,16 @@ func (service *NotificationServiceImpl) MarkAsRead(ctx context.Context, userId u
 	service.NotificationRepository.MarkAsRead(ctx, tx, userId, request.NotificationIds)
 
 	unreadCount := service.NotificationRepository.CountUnreadByUserId(ctx, tx, userId, "")
-	fmt.Printf("Remaining unread notifications: %d\n", unreadCount)
 
 	// Trigger Pusher event to update unread count
 	channelName := fmt.Sprintf("private-user-%s", userId)
-	fmt.Printf("Triggering Pusher: channel=%s, event=notifications-read\n", channelName)
 
 	go func() {
-		err := utils.PusherClient.Trigger(channelName, "notifications-read", map[string]interface{}{
+		err := utils.TriggerPusher(channelName, "notifications-read", map[string]interface{}{
 			"unread_count": unreadCount,
 		})
 		if err != nil {
-			fmt.Printf("Error triggering Pusher: %v\n", err)
-		} else {
-			fmt.Printf("Successfully triggered Pusher notification\n")
+			service.Logger.ErrorContext(ctx, "pusher trigger failed", "channel", channelName, "error", err)
 		}
 	}()
 
@@ -183,9 +166,6 @@ func (service *NotificationServiceImpl) MarkAsRead(ctx context.Context, userId u
 }
 
 func (service *NotificationServiceImpl) MarkAllAsRead(ctx context.Context, userId uuid.UUID, category string) int {
-	fmt.Printf("Marking all notifications as read for user: %s, category: %s\n",
-		userId, category)
-
 	tx, err := service.DB.Begin()
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
@@ -195,20 +175,16 @@ func (service *NotificationServiceImpl) MarkAllAsRead(ctx context.Context, userI
 
 	// Hitung jumlah notifikasi yang belum dibaca (semua kategori)
 	unreadCount := service.NotificationRepository.CountUnreadByUserId(ctx, tx, userId, "")
-	fmt.Printf("Remaining unread notifications: %d\n", unreadCount)
 
 	// Trigger Pusher event to update unread count
 	channelName := fmt.Sprintf("private-user-%s", userId)
-	fmt.Printf("Triggering Pusher: channel=%s, event=notifications-read\n", channelName)
 
 	go func() {
-		err := utils.PusherClient.Trigger(channelName, "notifications-read", map[string]interface{}{
+		err := utils.TriggerPusher(channelName, "notifications-read", map[string]interface{}{
 			"unread_count": unreadCount,
 		})
 		if err != nil {
-			fmt.Printf("Error triggering Pusher: %v\n", err)
-		} else {
-			fmt.Printf("Successfully triggered Pusher notification\n")
+			service.Logger.ErrorContext(ctx, "pusher trigger failed", "channel", channelName, "error", err)
 		}
 	}()
 
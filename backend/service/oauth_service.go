@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+	"evoconnect/backend/model/web"
+
+	"github.com/google/uuid"
+)
+
+type OAuthService interface {
+	ListProviders(ctx context.Context) []web.OAuthProviderResponse
+	Authorize(ctx context.Context, provider string) web.OAuthAuthorizeResponse
+	Login(ctx context.Context, provider string, request web.OAuthCallbackRequest) web.LoginResponse
+	LoginWithIDToken(ctx context.Context, provider string, idToken string) (web.LoginResponse, error)
+	AuthorizeConnect(ctx context.Context, userId uuid.UUID, provider string) web.OAuthAuthorizeResponse
+	Connect(ctx context.Context, userId uuid.UUID, provider string, request web.OAuthCallbackRequest) web.UserIdentityResponse
+	FindIdentities(ctx context.Context, userId uuid.UUID) []web.UserIdentityResponse
+	Disconnect(ctx context.Context, userId uuid.UUID, provider string) web.MessageResponse
+}
@@ -0,0 +1,378 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/exception"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/domain"
+	"evoconnect/backend/model/web"
+	"evoconnect/backend/repository"
+	"evoconnect/backend/utils"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// How long the user has to finish signing in at the provider
+const oauthStateTTL = 10 * time.Minute
+
+type OAuthServiceImpl struct {
+	Providers              map[string]utils.OAuthProvider
+	UserRepository         repository.UserRepository
+	UserSessionRepository  repository.UserSessionRepository
+	UserIdentityRepository repository.UserIdentityRepository
+	OAuthStateRepository   repository.OAuthStateRepository
+	AccountStatusService   AccountStatusService
+	TwoFactorService       TwoFactorService
+	RateLimiter            RateLimiter
+	SessionLifetimes       SessionLifetimes
+	DB                     *sql.DB
+	Validate               *validator.Validate
+	Logger                 *slog.Logger
+}
+
+func NewOAuthService(
+	providers map[string]utils.OAuthProvider,
+	userRepository repository.UserRepository,
+	userSessionRepository repository.UserSessionRepository,
+	userIdentityRepository repository.UserIdentityRepository,
+	oauthStateRepository repository.OAuthStateRepository,
+	accountStatusService AccountStatusService,
+	twoFactorService TwoFactorService,
+	rateLimiter RateLimiter,
+	sessionLifetimes SessionLifetimes,
+	db *sql.DB,
+	validate *validator.Validate,
+	logger *slog.Logger,
+) OAuthService {
+	return &OAuthServiceImpl{
+		Providers:              providers,
+		UserRepository:         userRepository,
+		UserSessionRepository:  userSessionRepository,
+		UserIdentityRepository: userIdentityRepository,
+		OAuthStateRepository:   oauthStateRepository,
+		AccountStatusService:   accountStatusService,
+		TwoFactorService:       twoFactorService,
+		RateLimiter:            rateLimiter,
+		SessionLifetimes:       sessionLifetimes,
+		DB:                     db,
+		Validate:               validate,
+		Logger:                 logger,
+	}
+}
+
+func (service *OAuthServiceImpl) ListProviders(ctx context.Context) []web.OAuthProviderResponse {
+	var responses []web.OAuthProviderResponse
+	for _, provider := range service.Providers {
+		responses = append(responses, web.OAuthProviderResponse{
+			Name:        provider.Name(),
+			DisplayName: provider.DisplayName(),
+		})
+	}
+
+	sort.Slice(responses, func(i, j int) bool { return responses[i].Name < responses[j].Name })
+	return responses
+}
+
+// Authorize starts a sign-in. The returned URL sends the browser to the provider, which
+// redirects back to the frontend with a code for Login.
+func (service *OAuthServiceImpl) Authorize(ctx context.Context, providerName string) web.OAuthAuthorizeResponse {
+	service.RateLimiter.Allow(ctx, RateLimitOAuthLogin, RateLimitKeyIP(helper.GetClientIP(ctx)))
+
+	return service.startAuthorization(ctx, service.getProvider(providerName), domain.OAuthPurposeLogin, nil)
+}
+
+// Login finishes a sign-in started by Authorize
+func (service *OAuthServiceImpl) Login(ctx context.Context, providerName string, request web.OAuthCallbackRequest) web.LoginResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	service.RateLimiter.Allow(ctx, RateLimitOAuthLogin, RateLimitKeyIP(helper.GetClientIP(ctx)))
+
+	provider := service.getProvider(providerName)
+	state := service.consumeState(ctx, provider, request.State, domain.OAuthPurposeLogin)
+
+	identity, err := provider.Exchange(ctx, request.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		service.Logger.WarnContext(ctx, "oauth login failed", "provider", provider.Name(), "error", err)
+		panic(exception.NewUnauthorizedError(fmt.Sprintf("Could not sign in with %s", provider.DisplayName())))
+	}
+
+	return service.completeLogin(ctx, provider, identity)
+}
+
+// LoginWithIDToken signs in with an ID token the frontend got straight from the provider,
+// as the Google sign-in button does. Invalid tokens are returned as an error.
+func (service *OAuthServiceImpl) LoginWithIDToken(ctx context.Context, providerName string, idToken string) (web.LoginResponse, error) {
+	service.RateLimiter.Allow(ctx, RateLimitOAuthLogin, RateLimitKeyIP(helper.GetClientIP(ctx)))
+
+	provider := service.getProvider(providerName)
+	verifier, ok := provider.(utils.IDTokenVerifier)
+	if !ok {
+		return web.LoginResponse{}, fmt.Errorf("%s does not support ID token sign-in", provider.DisplayName())
+	}
+
+	identity, err := verifier.VerifyIDToken(ctx, idToken, "")
+	if err != nil {
+		return web.LoginResponse{}, err
+	}
+
+	return service.completeLogin(ctx, provider, identity), nil
+}
+
+// AuthorizeConnect starts linking another provider to the signed-in user
+func (service *OAuthServiceImpl) AuthorizeConnect(ctx context.Context, userId uuid.UUID, providerName string) web.OAuthAuthorizeResponse {
+	return service.startAuthorization(ctx, service.getProvider(providerName), domain.OAuthPurposeConnect, &userId)
+}
+
+// Connect finishes linking a provider started by AuthorizeConnect
+func (service *OAuthServiceImpl) Connect(ctx context.Context, userId uuid.UUID, providerName string, request web.OAuthCallbackRequest) web.UserIdentityResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	provider := service.getProvider(providerName)
+	state := service.consumeState(ctx, provider, request.State, domain.OAuthPurposeConnect)
+	if state.UserId == nil || *state.UserId != userId {
+		panic(exception.NewBadRequestError("Invalid or expired authorization request"))
+	}
+
+	identity, err := provider.Exchange(ctx, request.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		service.Logger.WarnContext(ctx, "oauth connect failed", "provider", provider.Name(), "error", err)
+		panic(exception.NewBadRequestError(fmt.Sprintf("Could not connect %s", provider.DisplayName())))
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	existing, err := service.UserIdentityRepository.FindByProviderSubject(ctx, tx, provider.Name(), identity.Subject)
+	if err == nil {
+		if existing.UserId != userId {
+			panic(exception.NewBadRequestError(fmt.Sprintf("This %s account is already connected to another user", provider.DisplayName())))
+		}
+		return helper.ToUserIdentityResponse(existing, provider.DisplayName())
+	}
+
+	_, err = service.UserIdentityRepository.FindByUserIdAndProvider(ctx, tx, userId, provider.Name())
+	if err == nil {
+		panic(exception.NewBadRequestError(fmt.Sprintf("A different %s account is already connected, disconnect it first", provider.DisplayName())))
+	}
+
+	saved, err := service.UserIdentityRepository.Save(ctx, tx, domain.UserIdentity{
+		UserId:   userId,
+		Provider: provider.Name(),
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	})
+	helper.PanicIfError(err)
+
+	return helper.ToUserIdentityResponse(saved, provider.DisplayName())
+}
+
+func (service *OAuthServiceImpl) FindIdentities(ctx context.Context, userId uuid.UUID) []web.UserIdentityResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	identities, err := service.UserIdentityRepository.FindByUserId(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	responses := []web.UserIdentityResponse{}
+	for _, identity := range identities {
+		displayName := identity.Provider
+		if provider, ok := service.Providers[identity.Provider]; ok {
+			displayName = provider.DisplayName()
+		}
+		responses = append(responses, helper.ToUserIdentityResponse(identity, displayName))
+	}
+
+	return responses
+}
+
+// Disconnect unlinks a provider. Users without a known password can still get back in with
+// the forgot-password flow, so the last identity may be removed as well.
+func (service *OAuthServiceImpl) Disconnect(ctx context.Context, userId uuid.UUID, providerName string) web.MessageResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	err = service.UserIdentityRepository.Delete(ctx, tx, userId, strings.ToLower(providerName))
+	if err != nil {
+		panic(exception.NewNotFoundError("Provider is not connected"))
+	}
+
+	return web.MessageResponse{Message: "Provider disconnected"}
+}
+
+func (service *OAuthServiceImpl) getProvider(name string) utils.OAuthProvider {
+	provider, ok := service.Providers[strings.ToLower(name)]
+	if !ok {
+		panic(exception.NewNotFoundError("Unknown sign-in provider"))
+	}
+	return provider
+}
+
+func (service *OAuthServiceImpl) startAuthorization(ctx context.Context, provider utils.OAuthProvider, purpose string, userId *uuid.UUID) web.OAuthAuthorizeResponse {
+	state := helper.GenerateSecureToken(32)
+	nonce := helper.GenerateSecureToken(16)
+	codeVerifier := helper.GenerateSecureToken(48)
+
+	authorizationUrl, err := provider.AuthCodeURL(ctx, state, nonce, codeVerifier)
+	if err != nil {
+		service.Logger.WarnContext(ctx, "oauth authorize failed", "provider", provider.Name(), "error", err)
+		panic(exception.NewInternalServerError(fmt.Sprintf("%s sign-in is currently unavailable", provider.DisplayName())))
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	err = service.OAuthStateRepository.DeleteExpired(ctx, tx)
+	helper.PanicIfError(err)
+
+	expiresAt := time.Now().Add(oauthStateTTL)
+	_, err = service.OAuthStateRepository.Save(ctx, tx, domain.OAuthState{
+		StateHash:    helper.HashToken(state),
+		Provider:     provider.Name(),
+		Purpose:      purpose,
+		UserId:       userId,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    expiresAt,
+	})
+	helper.PanicIfError(err)
+
+	return web.OAuthAuthorizeResponse{
+		AuthorizationUrl: authorizationUrl,
+		State:            state,
+		ExpiresAt:        expiresAt,
+	}
+}
+
+// consumeState commits on its own, so a state is used up even when the code exchange fails
+func (service *OAuthServiceImpl) consumeState(ctx context.Context, provider utils.OAuthProvider, rawState string, purpose string) domain.OAuthState {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	state, err := service.OAuthStateRepository.Consume(ctx, tx, helper.HashToken(rawState), provider.Name())
+	if err != nil || state.Purpose != purpose {
+		panic(exception.NewBadRequestError("Invalid or expired authorization request"))
+	}
+
+	return state
+}
+
+// completeLogin finds the user for a provider identity. Unknown identities are linked to an
+// existing account only when the provider vouches for the email address, otherwise anyone
+// could sign up at a provider with someone else's address and take over their account.
+func (service *OAuthServiceImpl) completeLogin(ctx context.Context, provider utils.OAuthProvider, identity utils.OAuthIdentity) web.LoginResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	var user domain.User
+	isNewUser := false
+
+	linked, err := service.UserIdentityRepository.FindByProviderSubject(ctx, tx, provider.Name(), identity.Subject)
+	if err == nil {
+		user, err = service.UserRepository.FindById(ctx, tx, linked.UserId)
+		if err != nil {
+			panic(exception.NewNotFoundError("User not found"))
+		}
+	} else {
+		if identity.Email == "" {
+			panic(exception.NewBadRequestError(fmt.Sprintf("%s did not share an email address", provider.DisplayName())))
+		}
+
+		user, err = service.UserRepository.FindByEmail(ctx, tx, identity.Email)
+		if err == nil {
+			if !identity.EmailVerified {
+				panic(exception.NewBadRequestError(fmt.Sprintf("An account with this email already exists. Sign in and connect %s from your settings.", provider.DisplayName())))
+			}
+		} else {
+			user = service.createUser(ctx, tx, identity)
+			isNewUser = true
+		}
+
+		linked, err = service.UserIdentityRepository.Save(ctx, tx, domain.UserIdentity{
+			UserId:   user.Id,
+			Provider: provider.Name(),
+			Subject:  identity.Subject,
+			Email:    identity.Email,
+		})
+		helper.PanicIfError(err)
+	}
+
+	err = service.UserIdentityRepository.UpdateLastLogin(ctx, tx, linked.Id, identity.Email)
+	helper.PanicIfError(err)
+
+	if !isNewUser {
+		// Banned or suspended users cannot sign in through a provider either
+		if err := service.AccountStatusService.CheckAccountStatus(ctx, user.Id); err != nil {
+			panic(err)
+		}
+
+		// Fill in what the profile is missing, but never overwrite what the user chose
+		if (user.Name == "" && identity.Name != "") || (user.Photo == "" && identity.Picture != "") {
+			if user.Name == "" {
+				user.Name = identity.Name
+			}
+			if user.Photo == "" {
+				user.Photo = identity.Picture
+			}
+			user.UpdatedAt = time.Now()
+			user = service.UserRepository.Update(ctx, tx, user)
+		}
+
+		// Provider sign-in still requires the second factor when it is enabled
+		if service.TwoFactorService.IsEnabled(ctx, domain.TwoFactorOwnerUser, user.Id) {
+			return web.LoginResponse{
+				MfaRequired: true,
+				MfaToken:    generateUserMFAPendingToken(user.Id),
+			}
+		}
+	}
+
+	tokens, deletionCancelled := openUserSession(ctx, tx, service.UserRepository, service.UserSessionRepository, service.SessionLifetimes, user)
+
+	return web.LoginResponse{
+		Token:             tokens.Token,
+		RefreshToken:      tokens.RefreshToken,
+		ExpiresAt:         &tokens.ExpiresAt,
+		DeletionCancelled: deletionCancelled,
+		User:              &user,
+	}
+}
+
+func (service *OAuthServiceImpl) createUser(ctx context.Context, tx *sql.Tx, identity utils.OAuthIdentity) domain.User {
+	name := identity.Name
+	if name == "" {
+		name = strings.Split(identity.Email, "@")[0]
+	}
+
+	// The account gets a random password, the user can set one with forgot-password
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(helper.GenerateSecureToken(24)), bcrypt.DefaultCost)
+	helper.PanicIfError(err)
+
+	now := time.Now()
+	return service.UserRepository.Save(ctx, tx, domain.User{
+		Id:         uuid.New(),
+		Name:       name,
+		Email:      identity.Email,
+		Username:   generateUsernameFromName(name),
+		Password:   string(hashedPassword),
+		IsVerified: identity.EmailVerified,
+		Photo:      identity.Picture,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+}
@@ -0,0 +1,594 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"evoconnect/backend/config"
+	"evoconnect/backend/exception"
+	"evoconnect/backend/model/domain"
+	"evoconnect/backend/model/web"
+	"evoconnect/backend/repository"
+	"evoconnect/backend/utils"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+const (
+	fakeIssuerClientID     = "evoconnect-test"
+	fakeIssuerClientSecret = "evoconnect-test-secret"
+	fakeIssuerKeyID        = "fake-issuer-key"
+)
+
+// fakeAuthorization is what the fake issuer remembers about a code until it is redeemed
+type fakeAuthorization struct {
+	redirectURI   string
+	codeChallenge string
+	nonce         string
+	claims        jwt.MapClaims
+}
+
+// fakeIssuer is a minimal OpenID Connect provider with discovery, JWKS, authorization and
+// token endpoints. The authorization endpoint signs in whoever is set as the next user.
+type fakeIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu             sync.Mutex
+	nextUser       jwt.MapClaims
+	authorizations map[string]fakeAuthorization
+
+	// Lets a test tamper with the ID token before it is signed, or sign it with another key
+	editIDToken func(claims jwt.MapClaims)
+	signingKey  *rsa.PrivateKey
+	// Issuer named by the discovery document, the server URL when empty
+	publishedIssuer string
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuer := &fakeIssuer{key: key, authorizations: map[string]fakeAuthorization{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", issuer.discovery)
+	mux.HandleFunc("/jwks", issuer.jwks)
+	mux.HandleFunc("/authorize", issuer.authorize)
+	mux.HandleFunc("/token", issuer.token)
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+func (issuer *fakeIssuer) discovery(writer http.ResponseWriter, request *http.Request) {
+	published := issuer.publishedIssuer
+	if published == "" {
+		published = issuer.server.URL
+	}
+	writeJSON(writer, http.StatusOK, map[string]interface{}{
+		"issuer":                 published,
+		"authorization_endpoint": issuer.server.URL + "/authorize",
+		"token_endpoint":         issuer.server.URL + "/token",
+		"jwks_uri":               issuer.server.URL + "/jwks",
+	})
+}
+
+func (issuer *fakeIssuer) jwks(writer http.ResponseWriter, request *http.Request) {
+	writeJSON(writer, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": fakeIssuerKeyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(issuer.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(issuer.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (issuer *fakeIssuer) authorize(writer http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+	if query.Get("client_id") != fakeIssuerClientID || query.Get("response_type") != "code" ||
+		query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		http.Error(writer, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+
+	issuer.mu.Lock()
+	code := uuid.NewString()
+	issuer.authorizations[code] = fakeAuthorization{
+		redirectURI:   query.Get("redirect_uri"),
+		codeChallenge: query.Get("code_challenge"),
+		nonce:         query.Get("nonce"),
+		claims:        issuer.nextUser,
+	}
+	issuer.mu.Unlock()
+
+	redirect, _ := url.Parse(query.Get("redirect_uri"))
+	redirect.RawQuery = url.Values{"code": {code}, "state": {query.Get("state")}}.Encode()
+	http.Redirect(writer, request, redirect.String(), http.StatusFound)
+}
+
+func (issuer *fakeIssuer) token(writer http.ResponseWriter, request *http.Request) {
+	if err := request.ParseForm(); err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	clientID, clientSecret, ok := request.BasicAuth()
+	if !ok {
+		clientID, clientSecret = request.PostForm.Get("client_id"), request.PostForm.Get("client_secret")
+	}
+	if clientID != fakeIssuerClientID || clientSecret != fakeIssuerClientSecret {
+		writeJSON(writer, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	// Codes can be redeemed once, with the verifier matching the challenge
+	issuer.mu.Lock()
+	authorization, ok := issuer.authorizations[request.PostForm.Get("code")]
+	delete(issuer.authorizations, request.PostForm.Get("code"))
+	issuer.mu.Unlock()
+
+	verifierHash := sha256.Sum256([]byte(request.PostForm.Get("code_verifier")))
+	if !ok || request.PostForm.Get("grant_type") != "authorization_code" ||
+		request.PostForm.Get("redirect_uri") != authorization.redirectURI ||
+		base64.RawURLEncoding.EncodeToString(verifierHash[:]) != authorization.codeChallenge {
+		writeJSON(writer, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":   issuer.server.URL,
+		"aud":   fakeIssuerClientID,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(5 * time.Minute).Unix(),
+		"nonce": authorization.nonce,
+	}
+	for name, value := range authorization.claims {
+		claims[name] = value
+	}
+	if issuer.editIDToken != nil {
+		issuer.editIDToken(claims)
+	}
+
+	signingKey := issuer.key
+	if issuer.signingKey != nil {
+		signingKey = issuer.signingKey
+	}
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	idToken.Header["kid"] = fakeIssuerKeyID
+	signed, err := idToken.SignedString(signingKey)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(writer, http.StatusOK, map[string]interface{}{
+		"access_token": "fake-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     signed,
+	})
+}
+
+func writeJSON(writer http.ResponseWriter, status int, body interface{}) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	json.NewEncoder(writer).Encode(body)
+}
+
+// signIn plays the browser: it follows the authorization URL to the issuer as the given user
+// and returns the callback the frontend would post back
+func (issuer *fakeIssuer) signIn(t *testing.T, authorizationUrl string, user jwt.MapClaims) web.OAuthCallbackRequest {
+	t.Helper()
+	issuer.mu.Lock()
+	issuer.nextUser = user
+	issuer.mu.Unlock()
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	response, err := client.Get(authorizationUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusFound {
+		body, _ := io.ReadAll(response.Body)
+		t.Fatalf("authorization failed: %d %s", response.StatusCode, body)
+	}
+
+	callback, err := url.Parse(response.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return web.OAuthCallbackRequest{Code: callback.Query().Get("code"), State: callback.Query().Get("state")}
+}
+
+type memoryUserRepository struct {
+	repository.UserRepository
+
+	mu    sync.Mutex
+	users map[uuid.UUID]domain.User
+}
+
+func (fake *memoryUserRepository) Save(ctx context.Context, tx *sql.Tx, user domain.User) domain.User {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	fake.users[user.Id] = user
+	return user
+}
+
+func (fake *memoryUserRepository) Update(ctx context.Context, tx *sql.Tx, user domain.User) domain.User {
+	return fake.Save(ctx, tx, user)
+}
+
+func (fake *memoryUserRepository) FindById(ctx context.Context, tx *sql.Tx, userId uuid.UUID) (domain.User, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	user, ok := fake.users[userId]
+	if !ok {
+		return user, errors.New("user not found")
+	}
+	return user, nil
+}
+
+func (fake *memoryUserRepository) FindByEmail(ctx context.Context, tx *sql.Tx, email string) (domain.User, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	for _, user := range fake.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return domain.User{}, errors.New("user not found")
+}
+
+func (fake *memoryUserRepository) CancelDeletion(ctx context.Context, tx *sql.Tx, userId uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+type memoryIdentityRepository struct {
+	repository.UserIdentityRepository
+
+	mu         sync.Mutex
+	identities []domain.UserIdentity
+}
+
+func (fake *memoryIdentityRepository) Save(ctx context.Context, tx *sql.Tx, identity domain.UserIdentity) (domain.UserIdentity, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	identity.Id = uuid.New()
+	identity.CreatedAt = time.Now()
+	fake.identities = append(fake.identities, identity)
+	return identity, nil
+}
+
+func (fake *memoryIdentityRepository) find(match func(domain.UserIdentity) bool) (domain.UserIdentity, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	for _, identity := range fake.identities {
+		if match(identity) {
+			return identity, nil
+		}
+	}
+	return domain.UserIdentity{}, errors.New("identity not found")
+}
+
+func (fake *memoryIdentityRepository) FindByProviderSubject(ctx context.Context, tx *sql.Tx, provider string, subject string) (domain.UserIdentity, error) {
+	return fake.find(func(identity domain.UserIdentity) bool {
+		return identity.Provider == provider && identity.Subject == subject
+	})
+}
+
+func (fake *memoryIdentityRepository) FindByUserIdAndProvider(ctx context.Context, tx *sql.Tx, userId uuid.UUID, provider string) (domain.UserIdentity, error) {
+	return fake.find(func(identity domain.UserIdentity) bool {
+		return identity.UserId == userId && identity.Provider == provider
+	})
+}
+
+func (fake *memoryIdentityRepository) UpdateLastLogin(ctx context.Context, tx *sql.Tx, identityId uuid.UUID, email string) error {
+	return nil
+}
+
+// memoryOAuthStateRepository consumes a state at most once, like the DELETE ... RETURNING it stands in for
+type memoryOAuthStateRepository struct {
+	repository.OAuthStateRepository
+
+	mu     sync.Mutex
+	states map[string]domain.OAuthState
+}
+
+func (fake *memoryOAuthStateRepository) Save(ctx context.Context, tx *sql.Tx, state domain.OAuthState) (domain.OAuthState, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	state.Id = uuid.New()
+	fake.states[state.StateHash] = state
+	return state, nil
+}
+
+func (fake *memoryOAuthStateRepository) Consume(ctx context.Context, tx *sql.Tx, stateHash string, provider string) (domain.OAuthState, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	state, ok := fake.states[stateHash]
+	if !ok || state.Provider != provider || time.Now().After(state.ExpiresAt) {
+		return domain.OAuthState{}, errors.New("state not found")
+	}
+	delete(fake.states, stateHash)
+	return state, nil
+}
+
+func (fake *memoryOAuthStateRepository) DeleteExpired(ctx context.Context, tx *sql.Tx) error {
+	return nil
+}
+
+type fakeTwoFactorService struct {
+	TwoFactorService
+}
+
+func (fakeTwoFactorService) IsEnabled(ctx context.Context, ownerType domain.TwoFactorOwnerType, ownerId uuid.UUID) bool {
+	return false
+}
+
+// newOAuthTestService returns an OAuth service with a single "fake" provider backed by a fake issuer
+func newOAuthTestService(t *testing.T) (*OAuthServiceImpl, *fakeIssuer) {
+	t.Helper()
+	if err := utils.InitJWT(config.JWTConfig{Secret: "oauth-test-secret-0123456789abcdef"}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	issuer := newFakeIssuer(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	service := &OAuthServiceImpl{
+		Providers: map[string]utils.OAuthProvider{
+			"fake": utils.NewOIDCProvider(utils.OAuthProviderConfig{
+				Name:         "fake",
+				DisplayName:  "Fake",
+				Type:         utils.OAuthProviderTypeOIDC,
+				ClientID:     fakeIssuerClientID,
+				ClientSecret: fakeIssuerClientSecret,
+				Issuer:       issuer.server.URL,
+				Scopes:       []string{"openid", "email", "profile"},
+				RedirectURL:  "http://localhost:3000/oauth/callback/fake",
+			}),
+		},
+		UserRepository:         &memoryUserRepository{users: map[uuid.UUID]domain.User{}},
+		UserSessionRepository:  newFakeSessionRepository(),
+		UserIdentityRepository: &memoryIdentityRepository{},
+		OAuthStateRepository:   &memoryOAuthStateRepository{states: map[string]domain.OAuthState{}},
+		AccountStatusService:   fakeAccountStatusService{},
+		TwoFactorService:       fakeTwoFactorService{},
+		RateLimiter:            NewRateLimiter(repository.NewMemoryRateLimitStore(), logger),
+		SessionLifetimes:       SessionLifetimes{AccessToken: 15 * time.Minute, RefreshToken: 30 * 24 * time.Hour},
+		DB:                     newFakeDB(t),
+		Validate:               validator.New(),
+		Logger:                 logger,
+	}
+	return service, issuer
+}
+
+func oauthTestUsers(service *OAuthServiceImpl) map[uuid.UUID]domain.User {
+	return service.UserRepository.(*memoryUserRepository).users
+}
+
+func oauthTestIdentities(service *OAuthServiceImpl) []domain.UserIdentity {
+	return service.UserIdentityRepository.(*memoryIdentityRepository).identities
+}
+
+func oauthLogin(service *OAuthServiceImpl, callback web.OAuthCallbackRequest) (response web.LoginResponse, failure interface{}) {
+	failure = recoverPanic(func() {
+		response = service.Login(context.Background(), "fake", callback)
+	})
+	return response, failure
+}
+
+func oauthConnect(service *OAuthServiceImpl, userId uuid.UUID, callback web.OAuthCallbackRequest) (response web.UserIdentityResponse, failure interface{}) {
+	failure = recoverPanic(func() {
+		response = service.Connect(context.Background(), userId, "fake", callback)
+	})
+	return response, failure
+}
+
+func fakeUser(subject string, email string, emailVerified bool) jwt.MapClaims {
+	return jwt.MapClaims{"sub": subject, "email": email, "email_verified": emailVerified, "name": "Fake User"}
+}
+
+func TestOAuthLoginCreatesAccount(t *testing.T) {
+	service, issuer := newOAuthTestService(t)
+	ctx := context.Background()
+
+	authorization := service.Authorize(ctx, "fake")
+	response, failure := oauthLogin(service, issuer.signIn(t, authorization.AuthorizationUrl, fakeUser("subject-1", "new@example.com", true)))
+	if failure != nil {
+		t.Fatalf("login failed: %v", failure)
+	}
+	if response.Token == "" || response.RefreshToken == "" || response.User == nil {
+		t.Fatalf("login did not open a session: %+v", response)
+	}
+	if !response.User.IsVerified || response.User.Email != "new@example.com" {
+		t.Errorf("created user = %+v", response.User)
+	}
+
+	identities := oauthTestIdentities(service)
+	if len(identities) != 1 || identities[0].UserId != response.User.Id || identities[0].Subject != "subject-1" {
+		t.Fatalf("identities = %+v", identities)
+	}
+
+	// Signing in again finds the linked account
+	authorization = service.Authorize(ctx, "fake")
+	again, failure := oauthLogin(service, issuer.signIn(t, authorization.AuthorizationUrl, fakeUser("subject-1", "new@example.com", true)))
+	if failure != nil {
+		t.Fatalf("second login failed: %v", failure)
+	}
+	if again.User.Id != response.User.Id || len(oauthTestUsers(service)) != 1 {
+		t.Errorf("second login signed in as %s, want %s", again.User.Id, response.User.Id)
+	}
+}
+
+func TestOAuthLoginRejectsInvalidState(t *testing.T) {
+	service, issuer := newOAuthTestService(t)
+	ctx := context.Background()
+
+	authorization := service.Authorize(ctx, "fake")
+	callback := issuer.signIn(t, authorization.AuthorizationUrl, fakeUser("subject-1", "user@example.com", true))
+	if _, failure := oauthLogin(service, callback); failure != nil {
+		t.Fatalf("login failed: %v", failure)
+	}
+
+	connectState := service.AuthorizeConnect(ctx, uuid.New(), "fake")
+	tests := []struct {
+		name  string
+		state string
+	}{
+		{"reused state", callback.State},
+		{"unknown state", "not-a-state"},
+		{"state of a connect request", connectState.State},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, failure := oauthLogin(service, web.OAuthCallbackRequest{Code: "any-code", State: test.state})
+			if _, ok := failure.(exception.BadRequestError); !ok {
+				t.Errorf("login = %v, want a bad request", failure)
+			}
+		})
+	}
+}
+
+func TestOAuthLoginRejectsInvalidIDToken(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		editIDToken func(jwt.MapClaims)
+		signingKey  *rsa.PrivateKey
+	}{
+		{name: "nonce mismatch", editIDToken: func(claims jwt.MapClaims) { claims["nonce"] = "replayed-nonce" }},
+		{name: "missing nonce", editIDToken: func(claims jwt.MapClaims) { delete(claims, "nonce") }},
+		{name: "other audience", editIDToken: func(claims jwt.MapClaims) { claims["aud"] = "another-client" }},
+		{name: "other issuer", editIDToken: func(claims jwt.MapClaims) { claims["iss"] = "https://issuer.example.com" }},
+		{name: "expired", editIDToken: func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Minute).Unix() }},
+		{name: "unknown signing key", signingKey: otherKey},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, issuer := newOAuthTestService(t)
+			issuer.editIDToken = test.editIDToken
+			issuer.signingKey = test.signingKey
+
+			authorization := service.Authorize(context.Background(), "fake")
+			_, failure := oauthLogin(service, issuer.signIn(t, authorization.AuthorizationUrl, fakeUser("subject-1", "user@example.com", true)))
+			if _, ok := failure.(exception.UnauthorizedError); !ok {
+				t.Fatalf("login = %v, want an unauthorized error", failure)
+			}
+			if len(oauthTestUsers(service)) != 0 || len(oauthTestIdentities(service)) != 0 {
+				t.Errorf("rejected sign-in created an account")
+			}
+		})
+	}
+}
+
+func TestOAuthAuthorizeRejectsDiscoveryOfOtherIssuer(t *testing.T) {
+	service, issuer := newOAuthTestService(t)
+	issuer.publishedIssuer = "https://issuer.example.com"
+
+	failure := recoverPanic(func() { service.Authorize(context.Background(), "fake") })
+	if failure == nil {
+		t.Fatal("Authorize() trusted a discovery document naming another issuer")
+	}
+}
+
+func TestOAuthLoginLinksExistingAccount(t *testing.T) {
+	tests := []struct {
+		name          string
+		emailVerified bool
+		wantLinked    bool
+	}{
+		{"verified email", true, true},
+		{"unverified email", false, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service, issuer := newOAuthTestService(t)
+			existing := domain.User{Id: uuid.New(), Name: "Existing User", Email: "existing@example.com", IsVerified: true}
+			oauthTestUsers(service)[existing.Id] = existing
+
+			authorization := service.Authorize(context.Background(), "fake")
+			response, failure := oauthLogin(service, issuer.signIn(t, authorization.AuthorizationUrl, fakeUser("subject-1", "existing@example.com", test.emailVerified)))
+
+			if !test.wantLinked {
+				if _, ok := failure.(exception.BadRequestError); !ok {
+					t.Fatalf("login = %v, want a bad request", failure)
+				}
+				if len(oauthTestIdentities(service)) != 0 {
+					t.Errorf("unverified email was linked: %+v", oauthTestIdentities(service))
+				}
+				return
+			}
+
+			if failure != nil {
+				t.Fatalf("login failed: %v", failure)
+			}
+			if response.User.Id != existing.Id || len(oauthTestUsers(service)) != 1 {
+				t.Errorf("signed in as %s, want the existing account %s", response.User.Id, existing.Id)
+			}
+			if identities := oauthTestIdentities(service); len(identities) != 1 || identities[0].UserId != existing.Id {
+				t.Errorf("identities = %+v", identities)
+			}
+		})
+	}
+}
+
+func TestOAuthConnect(t *testing.T) {
+	service, issuer := newOAuthTestService(t)
+	ctx := context.Background()
+	owner, other := uuid.New(), uuid.New()
+
+	authorization := service.AuthorizeConnect(ctx, owner, "fake")
+	response, failure := oauthConnect(service, owner, issuer.signIn(t, authorization.AuthorizationUrl, fakeUser("subject-1", "owner@example.com", true)))
+	if failure != nil {
+		t.Fatalf("connect failed: %v", failure)
+	}
+	if response.Provider != "fake" {
+		t.Errorf("response = %+v", response)
+	}
+	if identities := oauthTestIdentities(service); len(identities) != 1 || identities[0].UserId != owner {
+		t.Fatalf("identities = %+v", identities)
+	}
+
+	// A connect request started by one user cannot be finished by another
+	authorization = service.AuthorizeConnect(ctx, owner, "fake")
+	callback := issuer.signIn(t, authorization.AuthorizationUrl, fakeUser("subject-2", "owner@example.com", true))
+	if _, failure := oauthConnect(service, other, callback); failure == nil {
+		t.Error("connect finished with another user's state")
+	}
+
+	// The same provider account cannot be linked to a second user
+	authorization = service.AuthorizeConnect(ctx, other, "fake")
+	callback = issuer.signIn(t, authorization.AuthorizationUrl, fakeUser("subject-1", "owner@example.com", true))
+	if _, failure := oauthConnect(service, other, callback); failure == nil {
+		t.Error("provider account linked to a second user")
+	}
+	if identities := oauthTestIdentities(service); len(identities) != 1 {
+		t.Errorf("identities = %+v", identities)
+	}
+}
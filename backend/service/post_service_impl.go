@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"log/slog"
 	"mime/multipart"
 	"sort"
 	"time"
@@ -30,6 +31,7 @@ type PostServiceImpl struct {
 
 	GroupService          GroupService
 	PendingPostRepository repository.PendingPostRepository
+	Logger                *slog.Logger
 }
 
 type ExtendedPost struct {
@@ -49,7 +51,7 @@ func NewPostService(
 	notificationService NotificationService,
 	groupService GroupService,
 	pendingPostRepository repository.PendingPostRepository,
-	db *sql.DB, validate *validator.Validate) PostService {
+	db *sql.DB, validate *validator.Validate, logger *slog.Logger) PostService {
 	return &PostServiceImpl{
 		UserRepository:        userRepository,
 		PostRepository:        postRepository,
@@ -62,6 +64,7 @@ func NewPostService(
 		GroupService:          groupService,
 		PendingPostRepository: pendingPostRepository,
 		Validate:              validate,
+		Logger:                logger,
 	}
 }
 
@@ -86,6 +89,7 @@ func (service *PostServiceImpl) Create(ctx context.Context, userId uuid.UUID, re
 		file.Close()
 
 		if err != nil {
+			helper.PanicIfUploadRejected(err)
 			panic(exception.NewInternalServerError("Failed to upload image: " + err.Error()))
 		}
 
@@ -124,8 +128,7 @@ func (service *PostServiceImpl) Create(ctx context.Context, userId uuid.UUID, re
 			userName := user.Name
 			postId := post.Id
 
-			// Debug: cetak jumlah koneksi yang ditemukan
-			fmt.Printf("Sending notifications to %d connections for user %s\n", len(connections), userId)
+			service.Logger.DebugContext(ctx, "notifying connections of new post", "post_id", postId, "connections", len(connections))
 
 			go func() {
 				// Kirim notifikasi ke setiap koneksi
@@ -141,9 +144,6 @@ func (service *PostServiceImpl) Create(ctx context.Context, userId uuid.UUID, re
 						continue
 					}
 
-					// Debug: cetak ID user yang akan menerima notifikasi
-					fmt.Printf("Sending notification to user %s\n", connectedUserId)
-
 					refType := "post"
 					service.NotificationService.Create(
 						context.Background(),
@@ -211,6 +211,7 @@ func (service *PostServiceImpl) Update(ctx context.Context, postId uuid.UUID, us
 		file.Close()
 
 		if err != nil {
+			helper.PanicIfUploadRejected(err)
 			panic(exception.NewInternalServerError("Failed to upload image: " + err.Error()))
 		}
 
@@ -251,9 +252,7 @@ func (service *PostServiceImpl) cleanupUnusedImages(oldImages, newImages []strin
 			err := helper.DeleteFile(oldImg)
 			if err != nil {
 				// Just log the error, don't fail the process
-				fmt.Printf("Error deleting unused image %s: %v\n", oldImg, err)
-			} else {
-				fmt.Printf("Successfully deleted unused image: %s\n", oldImg)
+				service.Logger.Warn("failed to delete unused image", "path", oldImg, "error", err)
 			}
 		}
 	}
@@ -350,7 +349,6 @@ func (service *PostServiceImpl) FindAll(ctx context.Context, limit, offset int,
 		post.LikesCount = service.PostRepository.GetLikesCount(ctx, tx, post.Id)
 		post.IsReported = service.PostRepository.IsReported(ctx, tx, post.Id, currentUserId)	
 		
-
 		// Perbaikan: Gunakan IsConnected alih-alih CheckConnectionExists
 		// dan pastikan parameter diberikan dengan benar
 		if post.User != nil && post.UserId != currentUserId {
@@ -537,8 +535,6 @@ func (service *PostServiceImpl) CreateGroupPost(ctx context.Context, groupId uui
 		panic(exception.NewInternalServerError("Failed to get group info: " + err.Error()))
 	}
 
-	fmt.Printf("DEBUG: Group %s has post_approval: %v\n", group.Id, group.PostApproval)
-
 	// Process uploads
 	var imagePaths []string
 	for _, fileHeader := range files {
@@ -552,7 +548,6 @@ func (service *PostServiceImpl) CreateGroupPost(ctx context.Context, groupId uui
 
 	// Check if post approval is enabled and user is not admin/moderator/creator
 	isAdmin := (role == "admin" || role == "moderator" || group.CreatorId == userId)
-	fmt.Printf("DEBUG: User %s is admin/moderator/creator: %v\n", userId, isAdmin)
 
 	// Create post
 	postId := uuid.New()
@@ -561,7 +556,6 @@ func (service *PostServiceImpl) CreateGroupPost(ctx context.Context, groupId uui
 	// Set status based on group post approval setting and user role
 	if group.PostApproval && !isAdmin {
 		postStatus = "pending"
-		fmt.Printf("DEBUG: Post requires approval. Setting status to pending.\n")
 	} else {
 		postStatus = "approved"
 	}
@@ -588,8 +582,6 @@ func (service *PostServiceImpl) CreateGroupPost(ctx context.Context, groupId uui
 	)
 	helper.PanicIfError(err)
 
-	fmt.Printf("DEBUG: Post saved with ID: %s and GroupId: %s with status: %s\n", postId, groupId, postStatus)
-
 	// Get the created post
 	post := domain.Post{
 		Id:         postId,
@@ -635,7 +627,7 @@ func (service *PostServiceImpl) CreateGroupPost(ctx context.Context, groupId uui
 		membersSQL := `SELECT user_id FROM group_members WHERE group_id = $1 AND is_active = true`
 		memberRows, err := tx.QueryContext(ctx, membersSQL, groupId)
 		if err != nil {
-			fmt.Printf("ERROR: Failed to get group members: %v\n", err)
+			service.Logger.ErrorContext(ctx, "failed to get group members", "error", err)
 		} else {
 			defer memberRows.Close()
 
@@ -647,8 +639,7 @@ func (service *PostServiceImpl) CreateGroupPost(ctx context.Context, groupId uui
 				}
 			}
 
-			// Debug: cetak jumlah anggota grup
-			fmt.Printf("Sending notifications to %d group members for post %s\n", len(memberIds), post.Id)
+			service.Logger.DebugContext(ctx, "notifying group members of new post", "post_id", post.Id, "members", len(memberIds))
 
 			// Salin data untuk goroutine
 			finalMemberIds := memberIds
@@ -658,7 +649,7 @@ func (service *PostServiceImpl) CreateGroupPost(ctx context.Context, groupId uui
 				newCtx := context.Background()
 				newTx, err := service.DB.Begin()
 				if err != nil {
-					fmt.Printf("Error creating transaction in goroutine: %v\n", err)
+					service.Logger.ErrorContext(ctx, "failed to begin group post notification transaction", "error", err)
 					return
 				}
 				defer newTx.Commit() // Commit transaction di goroutine
@@ -687,7 +678,7 @@ func (service *PostServiceImpl) CreateGroupPost(ctx context.Context, groupId uui
 func (service *PostServiceImpl) FindByGroupId(ctx context.Context, groupId uuid.UUID, userId uuid.UUID, limit, offset int) []web.PostResponse {
 	tx, err := service.DB.Begin()
 	if err != nil {
-		fmt.Printf("ERROR: Failed to begin transaction: %v\n", err)
+		service.Logger.ErrorContext(ctx, "failed to begin transaction", "error", err)
 		return []web.PostResponse{}
 	}
 	defer helper.CommitOrRollback(tx)
@@ -702,8 +693,6 @@ func (service *PostServiceImpl) FindByGroupId(ctx context.Context, groupId uuid.
 
 	// Kode yang sudah ada...
 
-	fmt.Printf("DEBUG: Finding posts for group %s by user %s\n", groupId, userId)
-
 	// Check if group exists
 	groupSQL := `SELECT id, name, description, privacy_level, creator_id, post_approval
                 FROM groups
@@ -723,13 +712,10 @@ func (service *PostServiceImpl) FindByGroupId(ctx context.Context, groupId uuid.
 		if err == sql.ErrNoRows {
 			panic(exception.NewNotFoundError("Group not found"))
 		}
-		fmt.Printf("ERROR: Failed to get group info: %v\n", err)
+		service.Logger.ErrorContext(ctx, "failed to get group info", "error", err)
 		return []web.PostResponse{}
 	}
 
-	fmt.Printf("DEBUG: Group %s found with privacy_level: %s, post_approval: %v\n",
-		group.Id, group.PrivacyLevel, group.PostApproval)
-
 	// For private groups, verify the user is a member
 	if group.PrivacyLevel == "private" {
 		memberSQL := `SELECT COUNT(*) FROM group_members 
@@ -749,8 +735,7 @@ func (service *PostServiceImpl) FindByGroupId(ctx context.Context, groupId uuid.
 	if err == nil && (role == "admin" || role == "moderator" || group.CreatorId == userId) {
 		isAdmin = true
 	}
-
-	fmt.Printf("DEBUG: User %s is admin/moderator/creator: %v\n", userId, isAdmin)
+	service.Logger.DebugContext(ctx, "listing group posts", "group_id", groupId, "is_admin", isAdmin)
 
 	// Get posts for the group - only approved posts or user's own pending posts
 	var postsSQL string
@@ -763,11 +748,9 @@ func (service *PostServiceImpl) FindByGroupId(ctx context.Context, groupId uuid.
                 ORDER BY p.created_at DESC
                 LIMIT $2 OFFSET $3`
 
-	fmt.Printf("DEBUG SQL: %s with params: %s, %d, %d\n", postsSQL, groupId, limit, offset)
-
 	rows, err = tx.QueryContext(ctx, postsSQL, groupId, limit, offset)
 	if err != nil {
-		fmt.Printf("ERROR: Failed to query posts: %v\n", err)
+		service.Logger.ErrorContext(ctx, "failed to query posts", "error", err)
 		return []web.PostResponse{}
 	}
 	defer rows.Close()
@@ -798,7 +781,7 @@ func (service *PostServiceImpl) FindByGroupId(ctx context.Context, groupId uuid.
 			&status,
 		)
 		if err != nil {
-			fmt.Printf("ERROR: Failed to scan post: %v\n", err)
+			service.Logger.ErrorContext(ctx, "failed to scan post", "error", err)
 			continue
 		}
 
@@ -809,7 +792,7 @@ func (service *PostServiceImpl) FindByGroupId(ctx context.Context, groupId uuid.
 		if imagesBytes != nil {
 			err = json.Unmarshal(imagesBytes, &post.Images)
 			if err != nil {
-				fmt.Printf("ERROR: Failed to unmarshal images: %v\n", err)
+				service.Logger.ErrorContext(ctx, "failed to unmarshal images", "error", err)
 				post.Images = []string{}
 			}
 		}
@@ -817,13 +800,11 @@ func (service *PostServiceImpl) FindByGroupId(ctx context.Context, groupId uuid.
 		postsWithStatus = append(postsWithStatus, post)
 	}
 
-	fmt.Printf("DEBUG: Found %d posts for group %s\n", len(postsWithStatus), groupId)
-
 	// Get pinned posts information
 	pinnedPostsSQL := `SELECT post_id, pinned_at FROM group_pinned_posts WHERE group_id = $1`
 	pinnedRows, err := tx.QueryContext(ctx, pinnedPostsSQL, groupId)
 	if err != nil {
-		fmt.Printf("ERROR: Failed to query pinned posts: %v\n", err)
+		service.Logger.ErrorContext(ctx, "failed to query pinned posts", "error", err)
 	} else {
 		defer pinnedRows.Close()
 
@@ -877,7 +858,7 @@ func (service *PostServiceImpl) FindByGroupId(ctx context.Context, groupId uuid.
 			&user.Headline,
 		)
 		if err != nil {
-			fmt.Printf("ERROR: Failed to get user info: %v\n", err)
+			service.Logger.ErrorContext(ctx, "failed to get user info", "error", err)
 			continue
 		}
 
@@ -909,6 +890,7 @@ func (service *PostServiceImpl) FindByGroupId(ctx context.Context, groupId uuid.
 			UserId:        post.UserId,
 			Content:       post.Content,
 			Images:        post.Images,
+			ImageVariants: helper.ImageVariantsOfAll(post.Images),
 			LikesCount:    likesCount,
 			CommentsCount: commentsCount,
 			Visibility:    post.Visibility,
@@ -930,6 +912,7 @@ func (service *PostServiceImpl) FindByGroupId(ctx context.Context, groupId uuid.
 		if user.Photo != "" {
 			photo := user.Photo
 			response.User.Photo = &photo
+			response.User.PhotoVariants = helper.ImageVariantsOf(photo)
 		}
 
 		if user.Headline != "" {
@@ -954,7 +937,6 @@ func (service *PostServiceImpl) FindByGroupId(ctx context.Context, groupId uuid.
 		responses = append(responses, response)
 	}
 
-	fmt.Printf("DEBUG: Returning %d post responses\n", len(responses))
 	return responses
 }
 
@@ -963,8 +945,6 @@ func (service *PostServiceImpl) ApprovePost(ctx context.Context, postId uuid.UUI
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
 
-	fmt.Printf("DEBUG: Approving post %s by user %s\n", postId, userId)
-
 	// Get the post directly from database instead of using repository
 	postSQL := `SELECT id, user_id, content, images, visibility, created_at, updated_at, group_id, COALESCE(status, 'pending') as status
 				FROM posts
@@ -1001,13 +981,11 @@ func (service *PostServiceImpl) ApprovePost(ctx context.Context, postId uuid.UUI
 	if imagesBytes != nil {
 		err = json.Unmarshal(imagesBytes, &post.Images)
 		if err != nil {
-			fmt.Printf("ERROR: Failed to unmarshal images: %v\n", err)
+			service.Logger.ErrorContext(ctx, "failed to unmarshal images", "error", err)
 			post.Images = []string{}
 		}
 	}
 
-	fmt.Printf("DEBUG: Found post with ID: %s, GroupId: %v, Status: %s\n", post.Id, post.GroupId, post.Status)
-
 	// Check if post belongs to a group
 	if post.GroupId == nil {
 		panic(exception.NewBadRequestError("post is not a group post"))
@@ -1028,8 +1006,6 @@ func (service *PostServiceImpl) ApprovePost(ctx context.Context, postId uuid.UUI
 	_, err = tx.ExecContext(ctx, updateSQL, postId)
 	helper.PanicIfError(err)
 
-	fmt.Printf("DEBUG: Updated post status to approved\n")
-
 	// Get updated post
 	post, err = service.PostRepository.FindById(ctx, tx, postId)
 	if err != nil {
@@ -1046,7 +1022,7 @@ func (service *PostServiceImpl) ApprovePost(ctx context.Context, postId uuid.UUI
 		groupSQL := `SELECT name FROM groups WHERE id = $1`
 		err = tx.QueryRowContext(ctx, groupSQL, *post.GroupId).Scan(&groupName)
 		if err != nil {
-			fmt.Printf("WARNING: Failed to get group name: %v\n", err)
+			service.Logger.WarnContext(ctx, "failed to get group name", "error", err)
 		}
 	}
 
@@ -1080,8 +1056,6 @@ func (service *PostServiceImpl) RejectPost(ctx context.Context, postId uuid.UUID
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
 
-	fmt.Printf("DEBUG: Rejecting post %s by user %s\n", postId, userId)
-
 	// Get the post directly from database instead of using repository
 	postSQL := `SELECT id, user_id, content, images, visibility, created_at, updated_at, group_id, COALESCE(status, 'pending') as status
 				FROM posts
@@ -1118,13 +1092,11 @@ func (service *PostServiceImpl) RejectPost(ctx context.Context, postId uuid.UUID
 	if imagesBytes != nil {
 		err = json.Unmarshal(imagesBytes, &post.Images)
 		if err != nil {
-			fmt.Printf("ERROR: Failed to unmarshal images: %v\n", err)
+			service.Logger.ErrorContext(ctx, "failed to unmarshal images", "error", err)
 			post.Images = []string{}
 		}
 	}
 
-	fmt.Printf("DEBUG: Found post with ID: %s, GroupId: %v, Status: %s\n", post.Id, post.GroupId, post.Status)
-
 	// Check if post belongs to a group
 	if post.GroupId == nil {
 		panic(exception.NewBadRequestError("post is not a group post"))
@@ -1146,7 +1118,7 @@ func (service *PostServiceImpl) RejectPost(ctx context.Context, postId uuid.UUID
 		groupSQL := `SELECT name FROM groups WHERE id = $1`
 		err = tx.QueryRowContext(ctx, groupSQL, *post.GroupId).Scan(&groupName)
 		if err != nil {
-			fmt.Printf("WARNING: Failed to get group name: %v\n", err)
+			service.Logger.WarnContext(ctx, "failed to get group name", "error", err)
 		}
 	}
 
@@ -1157,9 +1129,7 @@ func (service *PostServiceImpl) RejectPost(ctx context.Context, postId uuid.UUID
 	for _, imagePath := range post.Images {
 		err := helper.DeleteFile(imagePath)
 		if err != nil {
-			fmt.Printf("WARNING: Failed to delete image %s: %v\n", imagePath, err)
-		} else {
-			fmt.Printf("DEBUG: Deleted image: %s\n", imagePath)
+			service.Logger.WarnContext(ctx, "failed to delete post image", "path", imagePath, "error", err)
 		}
 	}
 
@@ -1167,18 +1137,14 @@ func (service *PostServiceImpl) RejectPost(ctx context.Context, postId uuid.UUID
 	deleteLikesSQL := `DELETE FROM post_likes WHERE post_id = $1`
 	_, err = tx.ExecContext(ctx, deleteLikesSQL, postId)
 	if err != nil {
-		fmt.Printf("WARNING: Failed to delete post likes: %v\n", err)
-	} else {
-		fmt.Printf("DEBUG: Deleted likes for post %s\n", postId)
+		service.Logger.WarnContext(ctx, "failed to delete post likes", "error", err)
 	}
 
 	// Delete post comments
 	deleteCommentsSQL := `DELETE FROM comments WHERE post_id = $1`
 	_, err = tx.ExecContext(ctx, deleteCommentsSQL, postId)
 	if err != nil {
-		fmt.Printf("WARNING: Failed to delete post comments: %v\n", err)
-	} else {
-		fmt.Printf("DEBUG: Deleted comments for post %s\n", postId)
+		service.Logger.WarnContext(ctx, "failed to delete post comments", "error", err)
 	}
 
 	// Delete the post
@@ -1187,7 +1153,6 @@ func (service *PostServiceImpl) RejectPost(ctx context.Context, postId uuid.UUID
 	if err != nil {
 		panic(exception.NewInternalServerError("Failed to delete post: " + err.Error()))
 	}
-	fmt.Printf("DEBUG: Deleted post %s\n", postId)
 
 	// Notify post author after transaction is committed
 	if service.NotificationService != nil {
@@ -1211,13 +1176,11 @@ func (service *PostServiceImpl) RejectPost(ctx context.Context, postId uuid.UUID
 func (service *PostServiceImpl) FindPendingPostsByGroupId(ctx context.Context, groupId uuid.UUID, userId uuid.UUID, limit, offset int) []web.PendingPostResponse {
 	tx, err := service.DB.Begin()
 	if err != nil {
-		fmt.Printf("ERROR: Failed to begin transaction: %v\n", err)
+		service.Logger.ErrorContext(ctx, "failed to begin transaction", "error", err)
 		return []web.PendingPostResponse{}
 	}
 	defer helper.CommitOrRollback(tx)
 
-	fmt.Printf("DEBUG: Finding pending posts for group %s by user %s\n", groupId, userId)
-
 	// Check if user has permission to view pending posts
 	isAdmin := false
 	if service.GroupService != nil {
@@ -1231,10 +1194,7 @@ func (service *PostServiceImpl) FindPendingPostsByGroupId(ctx context.Context, g
 		}
 	}
 
-	fmt.Printf("DEBUG: User is admin/moderator: %v\n", isAdmin)
-
 	if !isAdmin {
-		fmt.Printf("DEBUG: User is not admin/moderator, returning empty list\n")
 		return []web.PendingPostResponse{} // Return empty list instead of panic
 	}
 
@@ -1246,7 +1206,7 @@ func (service *PostServiceImpl) FindPendingPostsByGroupId(ctx context.Context, g
 	)`
 	err = tx.QueryRowContext(ctx, checkColumnSQL).Scan(&hasStatusColumn)
 	if err != nil {
-		fmt.Printf("ERROR: Failed to check if status column exists: %v\n", err)
+		service.Logger.ErrorContext(ctx, "failed to check if status column exists", "error", err)
 		return []web.PendingPostResponse{}
 	}
 
@@ -1260,15 +1220,13 @@ func (service *PostServiceImpl) FindPendingPostsByGroupId(ctx context.Context, g
 				LIMIT $2 OFFSET $3`
 	} else {
 		// Fallback if status column doesn't exist yet
-		fmt.Printf("WARNING: Status column doesn't exist in posts table\n")
+		service.Logger.WarnContext(ctx, "posts table has no status column")
 		return []web.PendingPostResponse{}
 	}
 
-	fmt.Printf("DEBUG: Executing SQL: %s with groupId: %s, limit: %d, offset: %d\n", SQL, groupId, limit, offset)
-
 	rows, err := tx.QueryContext(ctx, SQL, groupId, limit, offset)
 	if err != nil {
-		fmt.Printf("ERROR: Failed to query pending posts: %v\n", err)
+		service.Logger.ErrorContext(ctx, "failed to query pending posts", "error", err)
 		return []web.PendingPostResponse{}
 	}
 	defer rows.Close()
@@ -1292,7 +1250,7 @@ func (service *PostServiceImpl) FindPendingPostsByGroupId(ctx context.Context, g
 			&status,
 		)
 		if err != nil {
-			fmt.Printf("ERROR: Failed to scan post: %v\n", err)
+			service.Logger.ErrorContext(ctx, "failed to scan post", "error", err)
 			continue
 		}
 
@@ -1303,7 +1261,7 @@ func (service *PostServiceImpl) FindPendingPostsByGroupId(ctx context.Context, g
 		if imagesBytes != nil {
 			err = json.Unmarshal(imagesBytes, &post.Images)
 			if err != nil {
-				fmt.Printf("ERROR: Failed to unmarshal images: %v\n", err)
+				service.Logger.ErrorContext(ctx, "failed to unmarshal images", "error", err)
 				post.Images = []string{}
 			}
 		}
@@ -1311,7 +1269,7 @@ func (service *PostServiceImpl) FindPendingPostsByGroupId(ctx context.Context, g
 		// Get user info in a separate transaction to avoid connection issues
 		userTx, err := service.DB.Begin()
 		if err != nil {
-			fmt.Printf("ERROR: Failed to begin user transaction: %v\n", err)
+			service.Logger.ErrorContext(ctx, "failed to begin user transaction", "error", err)
 			continue
 		}
 
@@ -1328,7 +1286,7 @@ func (service *PostServiceImpl) FindPendingPostsByGroupId(ctx context.Context, g
 		)
 
 		if err != nil {
-			fmt.Printf("ERROR: Failed to get user info: %v\n", err)
+			service.Logger.ErrorContext(ctx, "failed to get user info", "error", err)
 			userTx.Rollback()
 			continue
 		}
@@ -1342,6 +1300,7 @@ func (service *PostServiceImpl) FindPendingPostsByGroupId(ctx context.Context, g
 			UserId:        post.UserId,
 			Content:       post.Content,
 			Images:        post.Images,
+			ImageVariants: helper.ImageVariantsOfAll(post.Images),
 			LikesCount:    0, // Simplified for now
 			CommentsCount: 0, // Simplified for now
 			Visibility:    post.Visibility,
@@ -1361,6 +1320,7 @@ func (service *PostServiceImpl) FindPendingPostsByGroupId(ctx context.Context, g
 		if user.Photo != "" {
 			photo := user.Photo
 			postResponse.User.Photo = &photo
+			postResponse.User.PhotoVariants = helper.ImageVariantsOf(photo)
 		}
 
 		if user.Headline != "" {
@@ -1382,7 +1342,6 @@ func (service *PostServiceImpl) FindPendingPostsByGroupId(ctx context.Context, g
 		pendingPosts = append(pendingPosts, pendingResponse)
 	}
 
-	fmt.Printf("DEBUG: Returning %d pending post responses\n", len(pendingPosts))
 	return pendingPosts
 }
 
@@ -1505,7 +1464,7 @@ func (service *PostServiceImpl) FindPendingPostsByUserId(ctx context.Context, us
 
 	rows, err := db.QueryContext(ctx, SQL, userId, limit, offset)
 	if err != nil {
-		fmt.Printf("ERROR: Failed to query posts: %v\n", err)
+		service.Logger.ErrorContext(ctx, "failed to query posts", "error", err)
 		return []web.PendingPostResponse{}
 	}
 	defer rows.Close()
@@ -1528,7 +1487,7 @@ func (service *PostServiceImpl) FindPendingPostsByUserId(ctx context.Context, us
 		)
 
 		if err != nil {
-			fmt.Printf("ERROR: Failed to scan post: %v\n", err)
+			service.Logger.ErrorContext(ctx, "failed to scan post", "error", err)
 			continue
 		}
 
@@ -1609,6 +1568,7 @@ func (service *PostServiceImpl) FindPendingPostsByUserId(ctx context.Context, us
 			if post.User.Photo != "" {
 				photo := post.User.Photo
 				postResponse.User.Photo = &photo
+				postResponse.User.PhotoVariants = helper.ImageVariantsOf(photo)
 			}
 
 			if post.User.Headline != "" {
@@ -1633,6 +1593,7 @@ func (service *PostServiceImpl) FindPendingPostsByUserId(ctx context.Context, us
 
 			if post.Group.Image != nil && *post.Group.Image != "" {
 				postResponse.Group.Image = post.Group.Image // Langsung gunakan pointer yang sudah ada
+				postResponse.Group.ImageVariants = helper.ImageVariantsOf(*post.Group.Image)
 			}
 		}
 
@@ -1656,7 +1617,7 @@ func (service *PostServiceImpl) FindPendingPostsByUserIdAndGroupId(ctx context.C
 
 	rows, err := db.QueryContext(ctx, SQL, userId, groupId, limit, offset)
 	if err != nil {
-		fmt.Printf("ERROR: Failed to query posts: %v\n", err)
+		service.Logger.ErrorContext(ctx, "failed to query posts", "error", err)
 		return []web.PendingPostResponse{}
 	}
 	defer rows.Close()
@@ -1679,7 +1640,7 @@ func (service *PostServiceImpl) FindPendingPostsByUserIdAndGroupId(ctx context.C
 		)
 
 		if err != nil {
-			fmt.Printf("ERROR: Failed to scan post: %v\n", err)
+			service.Logger.ErrorContext(ctx, "failed to scan post", "error", err)
 			continue
 		}
 
@@ -1758,6 +1719,7 @@ func (service *PostServiceImpl) FindPendingPostsByUserIdAndGroupId(ctx context.C
 			if post.User.Photo != "" {
 				photo := post.User.Photo
 				postResponse.User.Photo = &photo
+				postResponse.User.PhotoVariants = helper.ImageVariantsOf(photo)
 			}
 
 			if post.User.Headline != "" {
@@ -1782,6 +1744,7 @@ func (service *PostServiceImpl) FindPendingPostsByUserIdAndGroupId(ctx context.C
 
 			if post.Group.Image != nil && *post.Group.Image != "" {
 				postResponse.Group.Image = post.Group.Image // Langsung gunakan pointer yang sudah ada
+				postResponse.Group.ImageVariants = helper.ImageVariantsOf(*post.Group.Image)
 			}
 		}
 
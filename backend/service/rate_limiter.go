@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimitRule allows Limit hits per key inside a sliding Window
+type RateLimitRule struct {
+	Name    string
+	Limit   int
+	Window  time.Duration
+	Message string
+}
+
+type RateLimitStatus struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+type RateLimiter interface {
+	// Allow counts one request and panics with TooManyRequestsError once the limit is used up
+	Allow(ctx context.Context, rule RateLimitRule, key string)
+	// Check panics with TooManyRequestsError when the limit is used up, without counting
+	Check(ctx context.Context, rule RateLimitRule, key string)
+	Status(ctx context.Context, rule RateLimitRule, key string) RateLimitStatus
+	// Record counts one hit, typically a failed attempt guarded by Check
+	Record(ctx context.Context, rule RateLimitRule, key string)
+	Reset(ctx context.Context, rule RateLimitRule, key string)
+}
+
+var (
+	RateLimitLogin = RateLimitRule{
+		Name: "login", Limit: 30, Window: 15 * time.Minute,
+		Message: "Too many login attempts. Please try again later.",
+	}
+	RateLimitLoginFailure = RateLimitRule{
+		Name: "login_failure", Limit: 10, Window: 15 * time.Minute,
+		Message: "Too many failed login attempts. Please try again later.",
+	}
+	RateLimitOAuthLogin = RateLimitRule{
+		Name: "oauth_login", Limit: 30, Window: 15 * time.Minute,
+		Message: "Too many sign-in attempts. Please try again later.",
+	}
+	RateLimitAdminLogin = RateLimitRule{
+		Name: "admin_login", Limit: 10, Window: 15 * time.Minute,
+		Message: "Too many login attempts. Please try again later.",
+	}
+	RateLimitAdminLoginFailure = RateLimitRule{
+		Name: "admin_login_failure", Limit: 5, Window: 15 * time.Minute,
+		Message: "Too many failed login attempts. Please try again later.",
+	}
+	RateLimitRegister = RateLimitRule{
+		Name: "register", Limit: 10, Window: time.Hour,
+		Message: "Too many registrations from this address. Please try again later.",
+	}
+	RateLimitRefreshToken = RateLimitRule{
+		Name: "refresh_token", Limit: 60, Window: 15 * time.Minute,
+		Message: "Too many token refreshes. Please try again later.",
+	}
+	RateLimitSendVerificationEmail = RateLimitRule{
+		Name: "send_verification_email", Limit: 3, Window: 15 * time.Minute,
+		Message: "Too many verification email requests. Please try again later.",
+	}
+	RateLimitVerifyEmail = RateLimitRule{
+		Name: "email_verification", Limit: 5, Window: 5 * time.Minute,
+		Message: "Too many verification attempts. Please try again later.",
+	}
+	RateLimitForgotPassword = RateLimitRule{
+		Name: "password_reset_request", Limit: 3, Window: 15 * time.Minute,
+		Message: "Too many password reset requests. Please try again later.",
+	}
+	RateLimitResetPassword = RateLimitRule{
+		Name: "password_reset", Limit: 5, Window: 5 * time.Minute,
+		Message: "Too many reset attempts. Please try again later.",
+	}
+	RateLimitTwoFactorVerify = RateLimitRule{
+		Name: "two_factor_verify", Limit: 5, Window: 5 * time.Minute,
+		Message: "Too many invalid authentication codes, please try again later",
+	}
+	RateLimitSendMagicLink = RateLimitRule{
+		Name: "send_magic_link", Limit: 3, Window: 15 * time.Minute,
+		Message: "Too many sign-in link requests. Please try again later.",
+	}
+	RateLimitMagicLinkLogin = RateLimitRule{
+		Name: "magic_link_login", Limit: 5, Window: 5 * time.Minute,
+		Message: "Too many invalid sign-in links. Please try again later.",
+	}
+	RateLimitChangePassword = RateLimitRule{
+		Name: "change_password", Limit: 10, Window: 15 * time.Minute,
+		Message: "Too many password change attempts. Please try again later.",
+	}
+	RateLimitEmailChangeRequest = RateLimitRule{
+		Name: "email_change_request", Limit: 5, Window: time.Hour,
+		Message: "Too many email change requests. Please try again later.",
+	}
+	RateLimitEmailChangeToken = RateLimitRule{
+		Name: "email_change_token", Limit: 10, Window: 15 * time.Minute,
+		Message: "Too many invalid email change links. Please try again later.",
+	}
+)
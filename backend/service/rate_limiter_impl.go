@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"evoconnect/backend/exception"
+	"evoconnect/backend/model/domain"
+	"evoconnect/backend/repository"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RateLimiterImpl struct {
+	Store  repository.RateLimitStore
+	Logger *slog.Logger
+}
+
+func NewRateLimiter(store repository.RateLimitStore, logger *slog.Logger) RateLimiter {
+	return &RateLimiterImpl{
+		Store:  store,
+		Logger: logger,
+	}
+}
+
+func RateLimitKeyIP(ip string) string {
+	return "ip:" + ip
+}
+
+func RateLimitKeyEmail(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func RateLimitKeyUser(userId uuid.UUID) string {
+	return "user:" + userId.String()
+}
+
+// RateLimitKeyTwoFactorOwner limits code guesses per account, whatever address they come from
+func RateLimitKeyTwoFactorOwner(ownerType domain.TwoFactorOwnerType, ownerId uuid.UUID) string {
+	return string(ownerType) + ":" + ownerId.String()
+}
+
+func (limiter *RateLimiterImpl) Allow(ctx context.Context, rule RateLimitRule, key string) {
+	limiter.Check(ctx, rule, key)
+	limiter.Record(ctx, rule, key)
+}
+
+func (limiter *RateLimiterImpl) Check(ctx context.Context, rule RateLimitRule, key string) {
+	status := limiter.Status(ctx, rule, key)
+	if !status.Allowed {
+		panic(exception.NewTooManyRequestsErrorWithRetry(rule.Message, status.RetryAfter))
+	}
+}
+
+// Status fails open: when the store is unreachable requests are let through rather than locking everyone out
+func (limiter *RateLimiterImpl) Status(ctx context.Context, rule RateLimitRule, key string) RateLimitStatus {
+	now := time.Now()
+	hits, err := limiter.Store.Hits(ctx, bucketKey(rule, key), now.Add(-rule.Window))
+	if err != nil {
+		limiter.Logger.ErrorContext(ctx, "rate limiter failed to read hits", "bucket", bucketKey(rule, key), "error", err)
+		return RateLimitStatus{Allowed: true, Remaining: rule.Limit}
+	}
+
+	if len(hits) < rule.Limit {
+		return RateLimitStatus{Allowed: true, Remaining: rule.Limit - len(hits)}
+	}
+
+	// A slot frees up when the hit that pushed the key over the limit leaves the window
+	retryAfter := hits[len(hits)-rule.Limit].Add(rule.Window).Sub(now)
+	if retryAfter < time.Second {
+		retryAfter = time.Second
+	}
+	return RateLimitStatus{Allowed: false, RetryAfter: retryAfter}
+}
+
+func (limiter *RateLimiterImpl) Record(ctx context.Context, rule RateLimitRule, key string) {
+	if err := limiter.Store.Add(ctx, bucketKey(rule, key), time.Now(), rule.Window); err != nil {
+		limiter.Logger.ErrorContext(ctx, "rate limiter failed to record hit", "bucket", bucketKey(rule, key), "error", err)
+	}
+}
+
+func (limiter *RateLimiterImpl) Reset(ctx context.Context, rule RateLimitRule, key string) {
+	if err := limiter.Store.Reset(ctx, bucketKey(rule, key)); err != nil {
+		limiter.Logger.ErrorContext(ctx, "rate limiter failed to reset", "bucket", bucketKey(rule, key), "error", err)
+	}
+}
+
+func bucketKey(rule RateLimitRule, key string) string {
+	return fmt.Sprintf("%s:%s", rule.Name, key)
+}
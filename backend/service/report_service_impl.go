@@ -8,6 +8,7 @@ import (
 	"evoconnect/backend/model/web"
 	"evoconnect/backend/repository"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -34,7 +35,9 @@ type reportServiceImpl struct {
 	companyPostCommentRepository repository.CompanyPostCommentRepository
 	jobVacancyRepository         repository.JobVacancyRepository
 	notificationService          NotificationService
+	auditLogService              AuditLogService
 	db                           *sql.DB
+	Logger                       *slog.Logger
 }
 
 func NewReportService(
@@ -50,7 +53,9 @@ func NewReportService(
 	companyPostCommentRepo repository.CompanyPostCommentRepository,
 	jobVacancyRepo repository.JobVacancyRepository,
 	notificationService NotificationService,
+	auditLogService AuditLogService,
 	db *sql.DB,
+	logger *slog.Logger,
 ) ReportService {
 	return &reportServiceImpl{
 		reportRepository:             reportRepo,
@@ -65,7 +70,9 @@ func NewReportService(
 		companyPostCommentRepository: companyPostCommentRepo,
 		jobVacancyRepository:         jobVacancyRepo,
 		notificationService:          notificationService,
+		auditLogService:              auditLogService,
 		db:                           db,
+		Logger:                       logger,
 	}
 }
 
@@ -194,7 +201,7 @@ func (s *reportServiceImpl) FindAll(ctx context.Context, page, limit int, target
 		reportResponse, err := s.enrichReportResponse(ctx, report)
 		if err != nil {
 			// Log error tapi tetap lanjutkan
-			fmt.Printf("Error enriching report data: %v\n", err)
+			s.Logger.ErrorContext(ctx, "failed to enrich report", "report_id", report.ID, "error", err)
 
 			// Tambahkan response dasar jika gagal mendapatkan info tambahan
 			reportResponse = web.ReportResponse{
@@ -698,8 +705,16 @@ func (s *reportServiceImpl) TakeAction(ctx context.Context, id string, request w
 	}
 	defer tx.Rollback()
 
+	previousStatus := report.Status
+
+	// Keadaan target sebelum aksi, untuk audit log
+	targetBefore, err := s.reportRepository.FindTargetSnapshot(ctx, tx, report.TargetType, report.TargetID)
+	if err != nil {
+		return web.AdminActionResponse{}, err
+	}
+
 	// Update status report
-	report, err = s.reportRepository.UpdateStatus(ctx, id, request.Status)
+	report, err = s.reportRepository.UpdateStatus(ctx, tx, id, request.Status)
 	if err != nil {
 		return web.AdminActionResponse{}, err
 	}
@@ -1158,6 +1173,32 @@ func (s *reportServiceImpl) TakeAction(ctx context.Context, id string, request w
 		}
 	}
 
+	// Keadaan target setelah aksi, null jika target sudah dihapus (grup yang di-ban)
+	targetAfter, err := s.reportRepository.FindTargetSnapshot(ctx, tx, report.TargetType, report.TargetID)
+	if err != nil {
+		return web.AdminActionResponse{}, err
+	}
+
+	// Catat di audit log dalam transaksi yang sama
+	err = s.auditLogService.Record(ctx, tx, domain.AuditActionReportAction, report.TargetType, report.TargetID,
+		map[string]interface{}{
+			"report_id":     report.ID,
+			"report_status": previousStatus,
+			"target":        targetBefore,
+		},
+		map[string]interface{}{
+			"report_id":       report.ID,
+			"report_status":   report.Status,
+			"action":          request.Action,
+			"reason":          request.Reason,
+			"suspended_until": suspendedUntil,
+			"target":          targetAfter,
+		},
+	)
+	if err != nil {
+		return web.AdminActionResponse{}, err
+	}
+
 	// Commit transaksi
 	if err := tx.Commit(); err != nil {
 		return web.AdminActionResponse{}, err
@@ -10,4 +10,5 @@ import (
 
 type SearchService interface {
     Search(ctx context.Context, query string, searchType string, limit int, offset int, currentUserId uuid.UUID) web.SearchResponse
+    Reindex(ctx context.Context) []string
 }
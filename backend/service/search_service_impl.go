@@ -7,11 +7,14 @@ import (
 	"evoconnect/backend/model/domain"
 	"evoconnect/backend/model/web"
 	"evoconnect/backend/repository"
-	"fmt"
+	"log/slog"
 
 	"github.com/google/uuid"
 )
 
+// Tables read by Search, in the order they are reindexed
+var searchTables = []string{"users", "posts", "blogs", "groups", "companies", "company_posts", "job_vacancies"}
+
 type SearchServiceImpl struct {
 	DB                         *sql.DB
 	UserRepository             repository.UserRepository
@@ -24,6 +27,7 @@ type SearchServiceImpl struct {
 	CompanyPostRepository      repository.CompanyPostRepository
 	JobVacancyRepository       repository.JobVacancyRepository
 	CompanyFollowerRepository  repository.CompanyFollowerRepository
+	Logger                     *slog.Logger
 }
 
 func NewSearchService(
@@ -38,6 +42,7 @@ func NewSearchService(
 	companyPostRepository repository.CompanyPostRepository,
 	jobVacancyRepository repository.JobVacancyRepository,
 	companyFollowerRepository repository.CompanyFollowerRepository,
+	logger *slog.Logger,
 ) SearchService {
 	return &SearchServiceImpl{
 		DB:                         db,
@@ -51,16 +56,16 @@ func NewSearchService(
 		CompanyPostRepository:      companyPostRepository,
 		JobVacancyRepository:       jobVacancyRepository,
 		CompanyFollowerRepository:  companyFollowerRepository,
+		Logger:                     logger,
 	}
 }
 
 func (service *SearchServiceImpl) Search(ctx context.Context, query string, searchType string, limit int, offset int, currentUserId uuid.UUID) web.SearchResponse {
 	response := web.SearchResponse{}
-	fmt.Printf("Search service called with query: '%s', type: '%s', limit: %d, offset: %d\n", query, searchType, limit, offset)
+	service.Logger.DebugContext(ctx, "search", "query", query, "type", searchType, "limit", limit, "offset", offset)
 
 	// Jika tidak ada query, kembalikan response kosong
 	if query == "" {
-		fmt.Println("Empty query, returning empty response")
 		return response
 	}
 
@@ -71,53 +76,39 @@ func (service *SearchServiceImpl) Search(ctx context.Context, query string, sear
 
 	// Existing searches...
 	if searchType == "all" || searchType == "user" {
-		fmt.Println("Searching users...")
 		users := service.searchUsers(ctx, query, limit, offset, currentUserId)
 		response.Users = users
-		fmt.Printf("Found %d users\n", len(users))
 	}
 
 	if searchType == "all" || searchType == "post" {
-		fmt.Println("Searching posts...")
 		posts := service.searchPosts(ctx, query, limit, offset, currentUserId)
 		response.Posts = posts
-		fmt.Printf("Found %d posts\n", len(posts))
 	}
 
 	if searchType == "all" || searchType == "blog" {
-		fmt.Println("Searching blogs...")
 		blogs := service.searchBlogs(ctx, query, limit, offset, currentUserId)
 		response.Blogs = blogs
-		fmt.Printf("Found %d blogs\n", len(blogs))
 	}
 
 	if searchType == "all" || searchType == "group" {
-		fmt.Println("Searching groups...")
 		groups := service.searchGroups(ctx, query, limit, offset, currentUserId)
 		response.Groups = groups
-		fmt.Printf("Found %d groups\n", len(groups))
 	}
 
 	// NEW SEARCHES - TAMBAH INI
 	if searchType == "all" || searchType == "company" {
-		fmt.Println("Searching companies...")
 		companies := service.searchCompanies(ctx, query, limit, offset, currentUserId)
 		response.Companies = companies
-		fmt.Printf("Found %d companies\n", len(companies))
 	}
 
 	if searchType == "all" || searchType == "company_post" {
-		fmt.Println("Searching company posts...")
 		companyPosts := service.searchCompanyPosts(ctx, query, limit, offset, currentUserId)
 		response.CompanyPosts = companyPosts
-		fmt.Printf("Found %d company posts\n", len(companyPosts))
 	}
 
 	if searchType == "all" || searchType == "job_vacancy" {
-		fmt.Println("Searching job vacancies...")
 		jobVacancies := service.searchJobVacancies(ctx, query, limit, offset, currentUserId)
 		response.JobVacancies = jobVacancies
-		fmt.Printf("Found %d job vacancies\n", len(jobVacancies))
 	}
 
 	return response
@@ -126,22 +117,16 @@ func (service *SearchServiceImpl) Search(ctx context.Context, query string, sear
 func (service *SearchServiceImpl) searchUsers(ctx context.Context, query string, limit int, offset int, currentUserId uuid.UUID) []web.UserSearchResult {
 	tx, err := service.DB.Begin()
 	if err != nil {
-		fmt.Printf("Error starting transaction for user search: %v\n", err)
+		service.Logger.ErrorContext(ctx, "user search failed", "error", err)
 		return []web.UserSearchResult{}
 	}
 
-	fmt.Printf("Searching users with query: '%s'\n", query)
 	users := service.UserRepository.Search(ctx, tx, query, limit, offset, currentUserId) // Tambahkan currentUserId
-	fmt.Printf("User repository returned %d users\n", len(users))
 
 	var results []web.UserSearchResult
 	for _, user := range users {
-		// Log user IDs untuk debugging
-		fmt.Printf("DEBUG: Checking connection between currentUserId=%s and userId=%s\n",
-			currentUserId.String(), user.Id.String())
 
 		isConnected := service.ConnectionRepository.CheckConnectionExists(ctx, tx, currentUserId, user.Id)
-		fmt.Printf("DEBUG: isConnected=%v\n", isConnected)
 
 		// Check for connection request status
 		var isConnectedRequest string = "none"
@@ -149,24 +134,18 @@ func (service *SearchServiceImpl) searchUsers(ctx context.Context, query string,
 		// Jika sudah terhubung, set status ke "accepted"
 		if isConnected {
 			isConnectedRequest = "accepted"
-			fmt.Printf("DEBUG: Users are connected, setting isConnectedRequest to 'accepted'\n")
 		} else {
 			// Jika belum terhubung, cek apakah ada permintaan koneksi
-			fmt.Printf("DEBUG: Checking connection request from currentUser to user\n")
 			request, err := service.ConnectionRepository.FindConnectionRequestBySenderIdAndReceiverId(ctx, tx, currentUserId, user.Id)
 			if err == nil {
 				// Request ditemukan, set status
 				isConnectedRequest = string(request.Status)
-				fmt.Printf("DEBUG: Found request from currentUser to user with status: %s\n", isConnectedRequest)
 			} else {
 				// Cek apakah ada permintaan dari user ini ke current user
-				fmt.Printf("DEBUG: Checking connection request from user to currentUser\n")
 				request, err = service.ConnectionRepository.FindConnectionRequestBySenderIdAndReceiverId(ctx, tx, user.Id, currentUserId)
 				if err == nil {
 					isConnectedRequest = string(request.Status)
-					fmt.Printf("DEBUG: Found request from user to currentUser with status: %s\n", isConnectedRequest)
 				} else {
-					fmt.Printf("DEBUG: No connection request found in either direction\n")
 				}
 			}
 		}
@@ -181,13 +160,11 @@ func (service *SearchServiceImpl) searchUsers(ctx context.Context, query string,
 			IsConnectedRequest: isConnectedRequest,
 		}
 		results = append(results, result)
-		fmt.Printf("Added user to results: %s (%s), isConnected: %v, isConnectedRequest: %s\n",
-			user.Name, user.Username, isConnected, isConnectedRequest)
 	}
 
 	err = tx.Commit()
 	if err != nil {
-		fmt.Printf("Error committing transaction for user search: %v\n", err)
+		service.Logger.ErrorContext(ctx, "user search failed", "error", err)
 		tx.Rollback()
 	}
 	return results
@@ -196,18 +173,15 @@ func (service *SearchServiceImpl) searchUsers(ctx context.Context, query string,
 func (service *SearchServiceImpl) searchPosts(ctx context.Context, query string, limit int, offset int, currentUserId uuid.UUID) []web.PostSearchResult {
 	tx, err := service.DB.Begin()
 	if err != nil {
-		fmt.Printf("Error starting transaction for post search: %v\n", err)
+		service.Logger.ErrorContext(ctx, "post search failed", "error", err)
 		return []web.PostSearchResult{}
 	}
 
-	fmt.Printf("Searching posts with query: '%s'\n", query)
 	posts := service.PostRepository.Search(ctx, tx, query, limit, offset)
-	fmt.Printf("Post repository returned %d posts\n", len(posts))
 
 	var results []web.PostSearchResult
 	for _, post := range posts {
 		if post.User == nil {
-			fmt.Println("Skipping post with nil user")
 			continue
 		}
 
@@ -228,12 +202,11 @@ func (service *SearchServiceImpl) searchPosts(ctx context.Context, query string,
 			User:      userResult,
 		}
 		results = append(results, result)
-		fmt.Printf("Added post to results: %s by %s\n", post.Id, post.User.Name)
 	}
 
 	err = tx.Commit()
 	if err != nil {
-		fmt.Printf("Error committing transaction for post search: %v\n", err)
+		service.Logger.ErrorContext(ctx, "post search failed", "error", err)
 		tx.Rollback()
 	}
 	return results
@@ -242,7 +215,7 @@ func (service *SearchServiceImpl) searchPosts(ctx context.Context, query string,
 func (service *SearchServiceImpl) searchBlogs(ctx context.Context, query string, limit int, offset int, currentUserId uuid.UUID) []web.BlogSearchResult {
 	tx, err := service.DB.Begin()
 	if err != nil {
-		fmt.Printf("Error starting transaction for blog search: %v\n", err)
+		service.Logger.ErrorContext(ctx, "blog search failed", "error", err)
 		return []web.BlogSearchResult{}
 	}
 	defer helper.CommitOrRollback(tx)
@@ -287,13 +260,11 @@ func (service *SearchServiceImpl) searchBlogs(ctx context.Context, query string,
 func (service *SearchServiceImpl) searchGroups(ctx context.Context, query string, limit int, offset int, currentUserId uuid.UUID) []web.GroupSearchResult {
 	tx, err := service.DB.Begin()
 	if err != nil {
-		fmt.Printf("Error starting transaction for group search: %v\n", err)
+		service.Logger.ErrorContext(ctx, "group search failed", "error", err)
 		return []web.GroupSearchResult{}
 	}
 	defer helper.CommitOrRollback(tx)
 
-	fmt.Printf("Searching groups with query: '%s'\n", query)
-
 	// Ubah ini untuk menggunakan SQL yang menampilkan semua grup
 	SQL := `SELECT id, name, description, rule, creator_id, image, privacy_level, invite_policy, created_at, updated_at
             FROM groups
@@ -302,11 +273,10 @@ func (service *SearchServiceImpl) searchGroups(ctx context.Context, query string
             LIMIT $2 OFFSET $3`
 
 	searchPattern := "%" + query + "%"
-	fmt.Printf("Executing direct group search SQL with pattern: %s\n", searchPattern)
 
 	rows, err := tx.QueryContext(ctx, SQL, searchPattern, limit, offset)
 	if err != nil {
-		fmt.Printf("Error executing group search: %v\n", err)
+		service.Logger.ErrorContext(ctx, "group search failed", "error", err)
 		return []web.GroupSearchResult{}
 	}
 	defer rows.Close()
@@ -328,15 +298,13 @@ func (service *SearchServiceImpl) searchGroups(ctx context.Context, query string
 			&group.UpdatedAt,
 		)
 		if err != nil {
-			fmt.Printf("Error scanning group: %v\n", err)
+			service.Logger.ErrorContext(ctx, "group search: scanning row failed", "error", err)
 			continue
 		}
 		group.Image = imagePtr
 		groups = append(groups, group)
 	}
 
-	fmt.Printf("Direct SQL query returned %d groups\n", len(groups))
-
 	var results []web.GroupSearchResult
 	for _, group := range groups {
 		memberCount := service.GroupRepository.CountMembers(ctx, tx, group.Id)
@@ -379,7 +347,7 @@ func (service *SearchServiceImpl) searchGroups(ctx context.Context, query string
 func (service *SearchServiceImpl) searchCompanies(ctx context.Context, query string, limit int, offset int, currentUserId uuid.UUID) []web.CompanySearchResult {
 	tx, err := service.DB.Begin()
 	if err != nil {
-		fmt.Printf("Error starting transaction for company search: %v\n", err)
+		service.Logger.ErrorContext(ctx, "company search failed", "error", err)
 		return []web.CompanySearchResult{}
 	}
 	defer helper.CommitOrRollback(tx)
@@ -394,7 +362,7 @@ func (service *SearchServiceImpl) searchCompanies(ctx context.Context, query str
 	searchPattern := "%" + query + "%"
 	rows, err := tx.QueryContext(ctx, SQL, searchPattern, limit, offset)
 	if err != nil {
-		fmt.Printf("Error executing company search: %v\n", err)
+		service.Logger.ErrorContext(ctx, "company search failed", "error", err)
 		return []web.CompanySearchResult{}
 	}
 	defer rows.Close()
@@ -430,7 +398,7 @@ func (service *SearchServiceImpl) searchCompanies(ctx context.Context, query str
 func (service *SearchServiceImpl) searchCompanyPosts(ctx context.Context, query string, limit int, offset int, currentUserId uuid.UUID) []web.CompanyPostSearchResult {
 	tx, err := service.DB.Begin()
 	if err != nil {
-		fmt.Printf("Error starting transaction for company post search: %v\n", err)
+		service.Logger.ErrorContext(ctx, "company post search failed", "error", err)
 		return []web.CompanyPostSearchResult{}
 	}
 	defer helper.CommitOrRollback(tx)
@@ -450,7 +418,7 @@ func (service *SearchServiceImpl) searchCompanyPosts(ctx context.Context, query
 	searchPattern := "%" + query + "%"
 	rows, err := tx.QueryContext(ctx, SQL, searchPattern, limit, offset)
 	if err != nil {
-		fmt.Printf("Error executing company post search: %v\n", err)
+		service.Logger.ErrorContext(ctx, "company post search failed", "error", err)
 		return []web.CompanyPostSearchResult{}
 	}
 	defer rows.Close()
@@ -491,7 +459,7 @@ func (service *SearchServiceImpl) searchCompanyPosts(ctx context.Context, query
 func (service *SearchServiceImpl) searchJobVacancies(ctx context.Context, query string, limit int, offset int, currentUserId uuid.UUID) []web.JobVacancySearchResult {
 	tx, err := service.DB.Begin()
 	if err != nil {
-		fmt.Printf("Error starting transaction for job vacancy search: %v\n", err)
+		service.Logger.ErrorContext(ctx, "job vacancy search failed", "error", err)
 		return []web.JobVacancySearchResult{}
 	}
 	defer helper.CommitOrRollback(tx)
@@ -512,7 +480,7 @@ func (service *SearchServiceImpl) searchJobVacancies(ctx context.Context, query
 	searchPattern := "%" + query + "%"
 	rows, err := tx.QueryContext(ctx, SQL, searchPattern, limit, offset)
 	if err != nil {
-		fmt.Printf("Error executing job vacancy search: %v\n", err)
+		service.Logger.ErrorContext(ctx, "job vacancy search failed", "error", err)
 		return []web.JobVacancySearchResult{}
 	}
 	defer rows.Close()
@@ -558,3 +526,22 @@ func (service *SearchServiceImpl) searchJobVacancies(ctx context.Context, query
 
 	return results
 }
+
+// Reindex rebuilds the indexes of the searched tables without locking out writes and refreshes
+// their planner statistics, for after bulk imports or when searches turn slow from index bloat.
+// It returns the tables that were processed.
+func (service *SearchServiceImpl) Reindex(ctx context.Context) []string {
+	reindexed := make([]string, 0, len(searchTables))
+	for _, table := range searchTables {
+		// REINDEX CONCURRENTLY refuses to run inside a transaction
+		_, err := service.DB.ExecContext(ctx, "REINDEX TABLE CONCURRENTLY "+table)
+		helper.PanicIfError(err)
+
+		_, err = service.DB.ExecContext(ctx, "ANALYZE "+table)
+		helper.PanicIfError(err)
+
+		service.Logger.InfoContext(ctx, "search table reindexed", "table", table)
+		reindexed = append(reindexed, table)
+	}
+	return reindexed
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"context"
+	"evoconnect/backend/model/domain"
+	"evoconnect/backend/model/web"
+
+	"github.com/google/uuid"
+)
+
+type TwoFactorService interface {
+	GetStatus(ctx context.Context, ownerType domain.TwoFactorOwnerType, ownerId uuid.UUID) web.TwoFactorStatusResponse
+	Setup(ctx context.Context, ownerType domain.TwoFactorOwnerType, ownerId uuid.UUID, accountName string) web.TwoFactorSetupResponse
+	Enable(ctx context.Context, ownerType domain.TwoFactorOwnerType, ownerId uuid.UUID, request web.TwoFactorCodeRequest) web.TwoFactorRecoveryCodesResponse
+	Disable(ctx context.Context, ownerType domain.TwoFactorOwnerType, ownerId uuid.UUID, request web.TwoFactorCodeRequest) web.MessageResponse
+	RegenerateRecoveryCodes(ctx context.Context, ownerType domain.TwoFactorOwnerType, ownerId uuid.UUID, request web.TwoFactorCodeRequest) web.TwoFactorRecoveryCodesResponse
+	IsEnabled(ctx context.Context, ownerType domain.TwoFactorOwnerType, ownerId uuid.UUID) bool
+	VerifyCode(ctx context.Context, ownerType domain.TwoFactorOwnerType, ownerId uuid.UUID, code string) error
+	IsAdminTwoFactorRequired(ctx context.Context) bool
+	SetAdminTwoFactorRequired(ctx context.Context, request web.TwoFactorPolicyRequest) web.TwoFactorPolicyResponse
+}
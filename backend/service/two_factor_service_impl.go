@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"evoconnect/backend/exception"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/domain"
+	"evoconnect/backend/model/web"
+	"evoconnect/backend/repository"
+	"evoconnect/backend/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+const (
+	twoFactorIssuer            = "EvoConnect"
+	twoFactorRecoveryCodeCount = 10
+	mfaPendingTokenTTL         = 5 * time.Minute
+)
+
+type TwoFactorServiceImpl struct {
+	TwoFactorRepository     repository.TwoFactorRepository
+	SystemSettingRepository repository.SystemSettingRepository
+	RateLimiter             RateLimiter
+	AuditLogService         AuditLogService
+	DB                      *sql.DB
+	Validate                *validator.Validate
+}
+
+func NewTwoFactorService(
+	twoFactorRepository repository.TwoFactorRepository,
+	systemSettingRepository repository.SystemSettingRepository,
+	rateLimiter RateLimiter,
+	auditLogService AuditLogService,
+	db *sql.DB,
+	validate *validator.Validate,
+) TwoFactorService {
+	return &TwoFactorServiceImpl{
+		TwoFactorRepository:     twoFactorRepository,
+		SystemSettingRepository: systemSettingRepository,
+		RateLimiter:             rateLimiter,
+		AuditLogService:         auditLogService,
+		DB:                      db,
+		Validate:                validate,
+	}
+}
+
+func (service *TwoFactorServiceImpl) GetStatus(ctx context.Context, ownerType domain.TwoFactorOwnerType, ownerId uuid.UUID) web.TwoFactorStatusResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	credential, err := service.TwoFactorRepository.FindByOwner(ctx, tx, ownerType, ownerId)
+	if err != nil || !credential.IsEnabled() {
+		return web.TwoFactorStatusResponse{Enabled: false}
+	}
+
+	remaining, err := service.TwoFactorRepository.CountUnusedRecoveryCodes(ctx, tx, credential.Id)
+	helper.PanicIfError(err)
+
+	return web.TwoFactorStatusResponse{
+		Enabled:                true,
+		EnabledAt:              credential.EnabledAt,
+		RecoveryCodesRemaining: remaining,
+	}
+}
+
+// Setup generates a new secret that only becomes active after Enable confirms a code
+func (service *TwoFactorServiceImpl) Setup(ctx context.Context, ownerType domain.TwoFactorOwnerType, ownerId uuid.UUID, accountName string) web.TwoFactorSetupResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	existing, err := service.TwoFactorRepository.FindByOwner(ctx, tx, ownerType, ownerId)
+	if err == nil && existing.IsEnabled() {
+		panic(exception.NewBadRequestError("Two-factor authentication is already enabled, disable it first"))
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	helper.PanicIfError(err)
+
+	service.TwoFactorRepository.Upsert(ctx, tx, domain.TwoFactorCredential{
+		Id:        uuid.New(),
+		OwnerType: ownerType,
+		OwnerId:   ownerId,
+		Secret:    secret,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+
+	return web.TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(twoFactorIssuer, accountName, secret),
+	}
+}
+
+func (service *TwoFactorServiceImpl) Enable(ctx context.Context, ownerType domain.TwoFactorOwnerType, ownerId uuid.UUID, request web.TwoFactorCodeRequest) web.TwoFactorRecoveryCodesResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	credential, err := service.TwoFactorRepository.FindByOwner(ctx, tx, ownerType, ownerId)
+	if err != nil {
+		panic(exception.NewBadRequestError("Two-factor setup has not been started"))
+	}
+
+	if credential.IsEnabled() {
+		panic(exception.NewBadRequestError("Two-factor authentication is already enabled"))
+	}
+
+	step, ok := utils.ValidateTOTP(credential.Secret, request.Code, time.Now())
+	if !ok {
+		panic(exception.NewBadRequestError("Invalid authentication code"))
+	}
+
+	err = service.TwoFactorRepository.Enable(ctx, tx, credential.Id)
+	helper.PanicIfError(err)
+
+	err = service.TwoFactorRepository.UpdateLastUsedStep(ctx, tx, credential.Id, step)
+	helper.PanicIfError(err)
+
+	return web.TwoFactorRecoveryCodesResponse{
+		RecoveryCodes: service.replaceRecoveryCodes(ctx, tx, credential.Id),
+	}
+}
+
+func (service *TwoFactorServiceImpl) Disable(ctx context.Context, ownerType domain.TwoFactorOwnerType, ownerId uuid.UUID, request web.TwoFactorCodeRequest) web.MessageResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	if ownerType == domain.TwoFactorOwnerAdmin && service.IsAdminTwoFactorRequired(ctx) {
+		panic(exception.NewForbiddenError("Two-factor authentication is mandatory for admin accounts"))
+	}
+
+	if err := service.VerifyCode(ctx, ownerType, ownerId, request.Code); err != nil {
+		panic(exception.NewBadRequestError(err.Error()))
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	credential, err := service.TwoFactorRepository.FindByOwner(ctx, tx, ownerType, ownerId)
+	helper.PanicIfError(err)
+
+	err = service.TwoFactorRepository.Delete(ctx, tx, credential.Id)
+	helper.PanicIfError(err)
+
+	return web.MessageResponse{
+		Message: "Two-factor authentication disabled",
+	}
+}
+
+func (service *TwoFactorServiceImpl) RegenerateRecoveryCodes(ctx context.Context, ownerType domain.TwoFactorOwnerType, ownerId uuid.UUID, request web.TwoFactorCodeRequest) web.TwoFactorRecoveryCodesResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	if err := service.VerifyCode(ctx, ownerType, ownerId, request.Code); err != nil {
+		panic(exception.NewBadRequestError(err.Error()))
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	credential, err := service.TwoFactorRepository.FindByOwner(ctx, tx, ownerType, ownerId)
+	helper.PanicIfError(err)
+
+	return web.TwoFactorRecoveryCodesResponse{
+		RecoveryCodes: service.replaceRecoveryCodes(ctx, tx, credential.Id),
+	}
+}
+
+func (service *TwoFactorServiceImpl) IsEnabled(ctx context.Context, ownerType domain.TwoFactorOwnerType, ownerId uuid.UUID) bool {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	credential, err := service.TwoFactorRepository.FindByOwner(ctx, tx, ownerType, ownerId)
+	return err == nil && credential.IsEnabled()
+}
+
+// VerifyCode accepts either a current TOTP code or an unused recovery code. Accepted
+// TOTP steps are remembered so the same code cannot be replayed.
+func (service *TwoFactorServiceImpl) VerifyCode(ctx context.Context, ownerType domain.TwoFactorOwnerType, ownerId uuid.UUID, code string) error {
+	ipKey := RateLimitKeyIP(helper.GetClientIP(ctx))
+	ownerKey := RateLimitKeyTwoFactorOwner(ownerType, ownerId)
+
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	// Limited per address and per account, rotating addresses does not buy more guesses
+	for _, key := range []string{ipKey, ownerKey} {
+		if !service.RateLimiter.Status(ctx, RateLimitTwoFactorVerify, key).Allowed {
+			return errors.New(RateLimitTwoFactorVerify.Message)
+		}
+	}
+
+	credential, err := service.TwoFactorRepository.FindByOwner(ctx, tx, ownerType, ownerId)
+	if err != nil || !credential.IsEnabled() {
+		return errors.New("two-factor authentication is not enabled")
+	}
+
+	step, ok := utils.ValidateTOTP(credential.Secret, code, time.Now())
+	if ok && step > credential.LastUsedStep {
+		err = service.TwoFactorRepository.UpdateLastUsedStep(ctx, tx, credential.Id, step)
+		if !errors.Is(err, repository.ErrTwoFactorStepUsed) {
+			return err
+		}
+		// Accepted by a concurrent request in the meantime, a code cannot be replayed
+	}
+
+	if !ok {
+		used, err := service.TwoFactorRepository.UseRecoveryCode(ctx, tx, credential.Id, helper.HashToken(normalizeRecoveryCode(code)))
+		if err != nil {
+			return err
+		}
+		if used {
+			return nil
+		}
+	}
+
+	service.RateLimiter.Record(ctx, RateLimitTwoFactorVerify, ipKey)
+	service.RateLimiter.Record(ctx, RateLimitTwoFactorVerify, ownerKey)
+	return errors.New("invalid authentication code")
+}
+
+func (service *TwoFactorServiceImpl) IsAdminTwoFactorRequired(ctx context.Context) bool {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	value, err := service.SystemSettingRepository.Get(ctx, tx, domain.SettingAdminTwoFactorRequired)
+	if err != nil {
+		return false
+	}
+
+	required, _ := strconv.ParseBool(value)
+	return required
+}
+
+func (service *TwoFactorServiceImpl) SetAdminTwoFactorRequired(ctx context.Context, request web.TwoFactorPolicyRequest) web.TwoFactorPolicyResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	previous, _ := service.SystemSettingRepository.Get(ctx, tx, domain.SettingAdminTwoFactorRequired)
+	value := strconv.FormatBool(*request.AdminTwoFactorRequired)
+
+	err = service.SystemSettingRepository.Set(ctx, tx, domain.SettingAdminTwoFactorRequired, value)
+	helper.PanicIfError(err)
+
+	err = service.AuditLogService.Record(ctx, tx, domain.AuditActionSettingUpdate, "system_setting", domain.SettingAdminTwoFactorRequired,
+		map[string]string{"value": previous},
+		map[string]string{"value": value},
+	)
+	helper.PanicIfError(err)
+
+	return web.TwoFactorPolicyResponse{
+		AdminTwoFactorRequired: *request.AdminTwoFactorRequired,
+	}
+}
+
+// replaceRecoveryCodes stores hashes of new recovery codes and returns the plain codes
+func (service *TwoFactorServiceImpl) replaceRecoveryCodes(ctx context.Context, tx *sql.Tx, credentialId uuid.UUID) []string {
+	codes := make([]string, 0, twoFactorRecoveryCodeCount)
+	hashes := make([]string, 0, twoFactorRecoveryCodeCount)
+
+	for i := 0; i < twoFactorRecoveryCodeCount; i++ {
+		raw := strings.ToLower(helper.GenerateSecureToken(6))
+		raw = strings.NewReplacer("-", "x", "_", "y").Replace(raw)
+		code := raw[:4] + "-" + raw[4:8]
+
+		codes = append(codes, code)
+		hashes = append(hashes, helper.HashToken(normalizeRecoveryCode(code)))
+	}
+
+	err := service.TwoFactorRepository.ReplaceRecoveryCodes(ctx, tx, credentialId, hashes)
+	helper.PanicIfError(err)
+
+	return codes
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"evoconnect/backend/model/domain"
+	"evoconnect/backend/repository"
+	"evoconnect/backend/utils"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeTwoFactorRepository keeps one credential in memory, with the same forward-only update as
+// UpdateLastUsedStep. staleReads makes lookups return the credential as it was enrolled, like a
+// request that read it before a concurrent request accepted the same code.
+type fakeTwoFactorRepository struct {
+	repository.TwoFactorRepository
+
+	mu         sync.Mutex
+	enrolled   domain.TwoFactorCredential
+	credential domain.TwoFactorCredential
+	staleReads bool
+}
+
+func (fake *fakeTwoFactorRepository) FindByOwner(ctx context.Context, tx *sql.Tx, ownerType domain.TwoFactorOwnerType, ownerId uuid.UUID) (domain.TwoFactorCredential, error) {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if ownerType != fake.credential.OwnerType || ownerId != fake.credential.OwnerId {
+		return domain.TwoFactorCredential{}, errors.New("credential not found")
+	}
+	if fake.staleReads {
+		return fake.enrolled, nil
+	}
+	return fake.credential, nil
+}
+
+func (fake *fakeTwoFactorRepository) UpdateLastUsedStep(ctx context.Context, tx *sql.Tx, credentialId uuid.UUID, step int64) error {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.credential.LastUsedStep >= step {
+		return repository.ErrTwoFactorStepUsed
+	}
+	fake.credential.LastUsedStep = step
+	return nil
+}
+
+func (fake *fakeTwoFactorRepository) UseRecoveryCode(ctx context.Context, tx *sql.Tx, credentialId uuid.UUID, codeHash string) (bool, error) {
+	return false, nil
+}
+
+func newTwoFactorTestService(t *testing.T) (*TwoFactorServiceImpl, *fakeTwoFactorRepository) {
+	t.Helper()
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enabledAt := time.Now()
+	credential := domain.TwoFactorCredential{
+		Id:        uuid.New(),
+		OwnerType: domain.TwoFactorOwnerUser,
+		OwnerId:   uuid.New(),
+		Secret:    secret,
+		EnabledAt: &enabledAt,
+	}
+	credentials := &fakeTwoFactorRepository{enrolled: credential, credential: credential}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	service := &TwoFactorServiceImpl{
+		TwoFactorRepository: credentials,
+		RateLimiter:         NewRateLimiter(repository.NewMemoryRateLimitStore(), logger),
+		DB:                  newFakeDB(t),
+	}
+	return service, credentials
+}
+
+func currentTOTPCode(t *testing.T, secret string) string {
+	t.Helper()
+	code, err := utils.GenerateTOTPCode(secret, utils.TOTPStep(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+func fromIP(ip string) context.Context {
+	return context.WithValue(context.Background(), "client_ip", ip)
+}
+
+func TestVerifyCodeRejectsConcurrentReplay(t *testing.T) {
+	service, credentials := newTwoFactorTestService(t)
+	credential := credentials.credential
+	code := currentTOTPCode(t, credential.Secret)
+
+	// Both requests read the credential before either recorded the step
+	credentials.staleReads = true
+
+	if err := service.VerifyCode(fromIP("192.0.2.1"), credential.OwnerType, credential.OwnerId, code); err != nil {
+		t.Fatalf("first VerifyCode() error = %v", err)
+	}
+	err := service.VerifyCode(fromIP("192.0.2.1"), credential.OwnerType, credential.OwnerId, code)
+	if err == nil || err.Error() != "invalid authentication code" {
+		t.Fatalf("replayed VerifyCode() error = %v, want the code rejected", err)
+	}
+}
+
+func TestVerifyCodeLimitsGuessesPerAccount(t *testing.T) {
+	service, credentials := newTwoFactorTestService(t)
+	credential := credentials.credential
+
+	for i := 0; i < RateLimitTwoFactorVerify.Limit; i++ {
+		ctx := fromIP("192.0.2." + string(rune('1'+i)))
+		if err := service.VerifyCode(ctx, credential.OwnerType, credential.OwnerId, "000000x"); err == nil {
+			t.Fatal("an invalid code was accepted")
+		}
+	}
+
+	// A fresh address does not reset the budget of the account
+	err := service.VerifyCode(fromIP("198.51.100.1"), credential.OwnerType, credential.OwnerId, currentTOTPCode(t, credential.Secret))
+	if err == nil || err.Error() != RateLimitTwoFactorVerify.Message {
+		t.Fatalf("VerifyCode() error = %v, want the rate limit", err)
+	}
+}
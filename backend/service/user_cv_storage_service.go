@@ -13,4 +13,5 @@ type UserCvStorageService interface {
 	GetUserCv(ctx context.Context, userId uuid.UUID) web.UserCvStorageResponse
 	DeleteCv(ctx context.Context, userId uuid.UUID)
 	HasCv(ctx context.Context, userId uuid.UUID) bool
+	DownloadCv(ctx context.Context, userId uuid.UUID, requesterId uuid.UUID) web.SignedFileResponse
 }
@@ -9,9 +9,6 @@ import (
 	"evoconnect/backend/model/web"
 	"evoconnect/backend/repository"
 	"mime/multipart"
-	"os"
-	"path/filepath"
-	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -21,6 +18,8 @@ import (
 type UserCvStorageServiceImpl struct {
 	UserCvStorageRepository repository.UserCvStorageRepository
 	UserRepository          repository.UserRepository
+	FileScanService         FileScanService
+	FileAccessService       FileAccessService
 	DB                      *sql.DB
 	Validate                *validator.Validate
 }
@@ -28,11 +27,15 @@ type UserCvStorageServiceImpl struct {
 func NewUserCvStorageService(
 	userCvStorageRepository repository.UserCvStorageRepository,
 	userRepository repository.UserRepository,
+	fileScanService FileScanService,
+	fileAccessService FileAccessService,
 	DB *sql.DB,
 	validate *validator.Validate) UserCvStorageService {
 	return &UserCvStorageServiceImpl{
 		UserCvStorageRepository: userCvStorageRepository,
 		UserRepository:          userRepository,
+		FileScanService:         fileScanService,
+		FileAccessService:       fileAccessService,
 		DB:                      DB,
 		Validate:                validate,
 	}
@@ -43,6 +46,9 @@ func (service *UserCvStorageServiceImpl) UploadCv(ctx context.Context, file *mul
 		panic(exception.NewBadRequestError("CV file is required"))
 	}
 
+	// Deferred calls run in reverse, so the scan worker is woken after the commit below
+	defer service.FileScanService.Wake()
+
 	tx, err := service.DB.Begin()
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
@@ -57,16 +63,15 @@ func (service *UserCvStorageServiceImpl) UploadCv(ctx context.Context, file *mul
 	// 	panic(exception.NewForbiddenError("Only job seekers can upload CV"))
 	// }
 
-	// Validate file
-	if file.Size > 5*1024*1024 { // 5MB limit
-		panic(exception.NewBadRequestError("CV file size must be less than 5MB"))
+	// Validate file, a CV must be a PDF of at most 5MB
+	src, err := file.Open()
+	if err != nil {
+		panic(exception.NewBadRequestError("Failed to open uploaded file"))
 	}
+	defer src.Close()
 
-	// Check file extension
-	ext := strings.ToLower(filepath.Ext(file.Filename))
-	if ext != ".pdf" && ext != ".doc" && ext != ".docx" {
-		panic(exception.NewBadRequestError("CV must be in PDF, DOC, or DOCX format"))
-	}
+	checked, err := helper.UploadPolicyCv.Check(src, file)
+	helper.PanicIfError(err)
 
 	// Check if user already has CV
 	userHasCv := service.UserCvStorageRepository.ExistsByUserId(ctx, tx, userId)
@@ -80,21 +85,16 @@ func (service *UserCvStorageServiceImpl) UploadCv(ctx context.Context, file *mul
 		}
 	}
 
-	// Save file
-	src, err := file.Open()
-	if err != nil {
-		panic(exception.NewBadRequestError("Failed to open uploaded file"))
-	}
-	defer src.Close()
-
-	filePath := helper.SaveUploadedFile(src, "cv_storage", userId.String(), file.Filename)
+	// Save file, it stays in quarantine until the malware scan is clean
+	filePath := helper.SaveQuarantinedFile(src, helper.DirCVStorage, userId.String(), checked.Extension)
+	service.FileScanService.Queue(ctx, tx, userId, filePath, checked.Filename, domain.FileScanKindCv)
 
 	// Update or create CV storage record
 	cvStorage := domain.UserCvStorage{
 		Id:               uuid.New(),
 		UserId:           userId,
 		CvFilePath:       filePath,
-		OriginalFilename: file.Filename,
+		OriginalFilename: checked.Filename,
 		FileSize:         file.Size,
 		UploadedAt:       time.Now(),
 		UpdatedAt:        time.Now(),
@@ -107,7 +107,7 @@ func (service *UserCvStorageServiceImpl) UploadCv(ctx context.Context, file *mul
 		// Delete old file if exists and different from new one
 		if oldCvPath != "" && oldCvPath != filePath {
 			go func() {
-				os.Remove(oldCvPath)
+				helper.DeleteFile(oldCvPath)
 			}()
 		}
 	} else {
@@ -116,10 +116,11 @@ func (service *UserCvStorageServiceImpl) UploadCv(ctx context.Context, file *mul
 	}
 
 	return web.UploadCvResponse{
-		Message:  "CV uploaded successfully",
-		CvPath:   filePath,
-		Filename: file.Filename,
-		FileSize: file.Size,
+		Message:    "CV uploaded successfully",
+		CvPath:     filePath,
+		Filename:   file.Filename,
+		FileSize:   file.Size,
+		ScanStatus: string(domain.FileScanStatusPending),
 	}
 }
 
@@ -141,6 +142,7 @@ func (service *UserCvStorageServiceImpl) GetUserCv(ctx context.Context, userId u
 		FileSize:         cvStorage.FileSize,
 		UploadedAt:       cvStorage.UploadedAt,
 		UpdatedAt:        cvStorage.UpdatedAt,
+		ScanStatus:       string(service.FileScanService.FindStatus(ctx, tx, cvStorage.CvFilePath)),
 	}
 }
 
@@ -161,7 +163,7 @@ func (service *UserCvStorageServiceImpl) DeleteCv(ctx context.Context, userId uu
 
 	// Delete file asynchronously
 	go func() {
-		os.Remove(cvStorage.CvFilePath)
+		helper.DeleteFile(cvStorage.CvFilePath)
 	}()
 }
 
@@ -172,3 +174,24 @@ func (service *UserCvStorageServiceImpl) HasCv(ctx context.Context, userId uuid.
 
 	return service.UserCvStorageRepository.ExistsByUserId(ctx, tx, userId)
 }
+
+// DownloadCv issues a download link for the stored CV of userId and records who asked for it
+func (service *UserCvStorageServiceImpl) DownloadCv(ctx context.Context, userId uuid.UUID, requesterId uuid.UUID) web.SignedFileResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	cvStorage, err := service.UserCvStorageRepository.FindByUserId(ctx, tx, userId)
+	if err != nil {
+		panic(exception.NewNotFoundError("CV not found"))
+	}
+	panicIfNotScanned(service.FileScanService.FindStatus(ctx, tx, cvStorage.CvFilePath), "CV")
+
+	return service.FileAccessService.IssueURL(ctx, tx, domain.FileAccessLog{
+		UserId:        requesterId,
+		FileKey:       cvStorage.CvFilePath,
+		Action:        domain.FileAccessDownload,
+		ReferenceType: domain.FileReferenceCv,
+		ReferenceId:   cvStorage.Id,
+	}, cvStorage.OriginalFilename)
+}
@@ -200,7 +200,7 @@ func (service *UserServiceImpl) UploadPhotoProfile(ctx context.Context, userId u
 		helper.PanicIfError(err)
 		defer image.Close()
 
-		uploadResult, err = helper.UploadImage(image, file, helper.DirUsers, userId.String(), "photo-profile")
+		uploadResult, err = helper.UploadImageWithPolicy(helper.UploadPolicyAvatar, image, file, helper.DirUsers, userId.String(), "photo-profile")
 		helper.PanicIfError(err)
 	}
 
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+	"evoconnect/backend/model/web"
+
+	"github.com/google/uuid"
+)
+
+type UserSessionService interface {
+	ValidateSession(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID) error
+	FindActiveSessions(ctx context.Context, userId uuid.UUID, currentSessionId uuid.UUID) []web.UserSessionResponse
+	RevokeSession(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID) web.MessageResponse
+	RevokeOtherSessions(ctx context.Context, userId uuid.UUID, currentSessionId uuid.UUID) web.MessageResponse
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"evoconnect/backend/exception"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/model/domain"
+	"evoconnect/backend/model/web"
+	"evoconnect/backend/repository"
+	"evoconnect/backend/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session revocation reasons stored in user_sessions.revoked_reason
+const (
+	SessionRevokedLogout         = "logout"
+	SessionRevokedByUser         = "revoked_by_user"
+	SessionRevokedTokenReuse     = "refresh_token_reuse"
+	SessionRevokedDeletion       = "account_deletion"
+	SessionRevokedEmailChange    = "email_change"
+	SessionRevokedPasswordChange = "password_change"
+)
+
+// Last seen is only written when it is older than this, to avoid a write per request
+const sessionTouchInterval = time.Minute
+
+// SessionLifetimes are how long access tokens and refresh tokens live. A session is extended
+// to the refresh token lifetime whenever its refresh token is rotated.
+type SessionLifetimes struct {
+	AccessToken  time.Duration
+	RefreshToken time.Duration
+}
+
+// issueUserSession opens a new session for the user inside the caller's transaction and
+// returns its first access token and refresh token
+func issueUserSession(ctx context.Context, tx *sql.Tx, sessionRepository repository.UserSessionRepository, lifetimes SessionLifetimes, user domain.User) web.TokenResponse {
+	now := time.Now()
+	userAgent := helper.GetUserAgent(ctx)
+
+	session := sessionRepository.Save(ctx, tx, domain.UserSession{
+		Id:         uuid.New(),
+		UserId:     user.Id,
+		Device:     helper.DescribeDevice(userAgent),
+		UserAgent:  userAgent,
+		IpAddress:  helper.GetClientIP(ctx),
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(lifetimes.RefreshToken),
+		CreatedAt:  now,
+	})
+
+	return issueSessionTokens(ctx, tx, sessionRepository, lifetimes, user, session.Id)
+}
+
+// issueSessionTokens signs an access token for the session and stores a fresh refresh token
+func issueSessionTokens(ctx context.Context, tx *sql.Tx, sessionRepository repository.UserSessionRepository, lifetimes SessionLifetimes, user domain.User, sessionId uuid.UUID) web.TokenResponse {
+	now := time.Now()
+	refreshToken := helper.GenerateSecureToken(32)
+
+	sessionRepository.SaveRefreshToken(ctx, tx, domain.UserRefreshToken{
+		Id:        uuid.New(),
+		SessionId: sessionId,
+		TokenHash: helper.HashToken(refreshToken),
+		ExpiresAt: now.Add(lifetimes.RefreshToken),
+		CreatedAt: now,
+	})
+
+	expiresAt := now.Add(lifetimes.AccessToken)
+	accessToken, err := utils.GenerateUserAccessToken(user.Id.String(), user.Email, sessionId.String(), lifetimes.AccessToken)
+	helper.PanicIfError(err)
+
+	return web.TokenResponse{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    expiresAt,
+	}
+}
+
+type UserSessionServiceImpl struct {
+	UserSessionRepository repository.UserSessionRepository
+	UserRepository        repository.UserRepository
+	DB                    *sql.DB
+}
+
+func NewUserSessionService(userSessionRepository repository.UserSessionRepository, userRepository repository.UserRepository, db *sql.DB) UserSessionService {
+	return &UserSessionServiceImpl{
+		UserSessionRepository: userSessionRepository,
+		UserRepository:        userRepository,
+		DB:                    db,
+	}
+}
+
+// ValidateSession is used by the auth middleware to reject access tokens whose session
+// was revoked or has expired
+func (service *UserSessionServiceImpl) ValidateSession(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID) error {
+	tx, err := service.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer helper.CommitOrRollback(tx)
+
+	session, err := service.UserSessionRepository.FindById(ctx, tx, sessionId)
+	if err != nil {
+		return err
+	}
+
+	if session.UserId != userId {
+		return errors.New("session does not belong to this user")
+	}
+
+	if session.RevokedAt != nil {
+		return errors.New("session has been revoked")
+	}
+
+	if !session.IsActive() {
+		return errors.New("session has expired")
+	}
+
+	if time.Since(session.LastSeenAt) > sessionTouchInterval {
+		return service.UserSessionRepository.Touch(ctx, tx, sessionId, helper.GetClientIP(ctx))
+	}
+
+	return nil
+}
+
+func (service *UserSessionServiceImpl) FindActiveSessions(ctx context.Context, userId uuid.UUID, currentSessionId uuid.UUID) []web.UserSessionResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	sessions := service.UserSessionRepository.FindActiveByUserId(ctx, tx, userId)
+
+	responses := []web.UserSessionResponse{}
+	for _, session := range sessions {
+		responses = append(responses, web.UserSessionResponse{
+			Id:         session.Id,
+			Device:     session.Device,
+			IpAddress:  session.IpAddress,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+			CreatedAt:  session.CreatedAt,
+			IsCurrent:  session.Id == currentSessionId,
+		})
+	}
+
+	return responses
+}
+
+func (service *UserSessionServiceImpl) RevokeSession(ctx context.Context, userId uuid.UUID, sessionId uuid.UUID) web.MessageResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	session, err := service.UserSessionRepository.FindById(ctx, tx, sessionId)
+	if err != nil || session.UserId != userId {
+		panic(exception.NewNotFoundError("Session not found"))
+	}
+
+	err = service.UserSessionRepository.Revoke(ctx, tx, sessionId, SessionRevokedByUser)
+	helper.PanicIfError(err)
+
+	return web.MessageResponse{
+		Message: "Session revoked",
+	}
+}
+
+func (service *UserSessionServiceImpl) RevokeOtherSessions(ctx context.Context, userId uuid.UUID, currentSessionId uuid.UUID) web.MessageResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	err = service.UserSessionRepository.RevokeAllByUserId(ctx, tx, userId, &currentSessionId, SessionRevokedByUser)
+	helper.PanicIfError(err)
+
+	// A sign-in link that was sent but not used would open a new session
+	err = service.UserRepository.ClearMagicLinkToken(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	return web.MessageResponse{
+		Message: "All other sessions revoked",
+	}
+}
@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// Temporary files of unfinished writes, skipped when listing
+const localTempPattern = ".upload-*.tmp"
+
+// LocalStorage keeps objects as files under Root, the key being the path relative to it.
+// Only suitable for a single instance, or with Root on a shared volume.
+type LocalStorage struct {
+	Root string
+}
+
+func NewLocalStorage(root string) *LocalStorage {
+	if root == "" {
+		root = "."
+	}
+	return &LocalStorage{Root: root}
+}
+
+func (storage *LocalStorage) filePath(key string) (string, error) {
+	key, err := NormalizeKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(storage.Root, filepath.FromSlash(key)), nil
+}
+
+// Put writes to a temporary file first, a reader never sees a half written upload
+func (storage *LocalStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	filePath, err := storage.filePath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(filePath), localTempPattern)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := io.Copy(temp, body); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(temp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), filePath)
+}
+
+func (storage *LocalStorage) Open(ctx context.Context, key string) (io.ReadSeekCloser, ObjectInfo, error) {
+	info, err := storage.Stat(ctx, key)
+	if err != nil {
+		return nil, info, err
+	}
+
+	filePath, _ := storage.filePath(key)
+	file, err := os.Open(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, info, ErrNotFound
+	}
+	return file, info, err
+}
+
+func (storage *LocalStorage) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	filePath, err := storage.filePath(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	fileInfo, err := os.Stat(filePath)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && fileInfo.IsDir()) {
+		return ObjectInfo{}, ErrNotFound
+	}
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	key, _ = NormalizeKey(key)
+	return ObjectInfo{
+		Key:         key,
+		Size:        fileInfo.Size(),
+		ContentType: mime.TypeByExtension(path.Ext(key)),
+		ModTime:     fileInfo.ModTime(),
+	}, nil
+}
+
+func (storage *LocalStorage) Delete(ctx context.Context, key string) error {
+	filePath, err := storage.filePath(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (storage *LocalStorage) DeletePrefix(ctx context.Context, prefix string) error {
+	dirPath, err := storage.filePath(prefix)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dirPath)
+}
+
+func (storage *LocalStorage) List(ctx context.Context, prefix string, visit func(ObjectInfo) error) error {
+	dirPath, err := storage.filePath(prefix)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(dirPath); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	return filepath.WalkDir(dirPath, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		if matched, _ := filepath.Match(localTempPattern, entry.Name()); matched {
+			return nil
+		}
+
+		relative, err := filepath.Rel(storage.Root, filePath)
+		if err != nil {
+			return err
+		}
+		fileInfo, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		key := filepath.ToSlash(relative)
+		return visit(ObjectInfo{
+			Key:         key,
+			Size:        fileInfo.Size(),
+			ContentType: mime.TypeByExtension(path.Ext(key)),
+			ModTime:     fileInfo.ModTime(),
+		})
+	})
+}
@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"context"
+	"evoconnect/backend/config"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Storage keeps objects in a bucket of Amazon S3 or any S3 compatible service such as MinIO
+type S3Storage struct {
+	Client *minio.Client
+	Bucket string
+}
+
+// NewS3Storage connects to the endpoint and checks that the bucket exists, so a wrong
+// bucket or wrong credentials stop the server at startup instead of failing every upload
+func NewS3Storage(ctx context.Context, s3Config config.S3Config) (*S3Storage, error) {
+	client, err := minio.New(s3Config.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(s3Config.AccessKeyID, s3Config.SecretAccessKey, ""),
+		Secure: s3Config.UseSSL,
+		Region: s3Config.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create S3 client: %w", err)
+	}
+
+	exists, err := client.BucketExists(ctx, s3Config.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("check S3 bucket %s: %w", s3Config.Bucket, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("S3 bucket %s does not exist", s3Config.Bucket)
+	}
+
+	return &S3Storage{Client: client, Bucket: s3Config.Bucket}, nil
+}
+
+func (storage *S3Storage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	key, err := NormalizeKey(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = storage.Client.PutObject(ctx, storage.Bucket, key, body, size, minio.PutObjectOptions{ContentType: contentType})
+	return err
+}
+
+func (storage *S3Storage) Open(ctx context.Context, key string) (io.ReadSeekCloser, ObjectInfo, error) {
+	key, err := NormalizeKey(key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	object, err := storage.Client.GetObject(ctx, storage.Bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, ObjectInfo{}, s3Error(err)
+	}
+
+	// GetObject is lazy, Stat makes the request and reports a missing key
+	stat, err := object.Stat()
+	if err != nil {
+		object.Close()
+		return nil, ObjectInfo{}, s3Error(err)
+	}
+	return object, toObjectInfo(stat), nil
+}
+
+func (storage *S3Storage) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	key, err := NormalizeKey(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	stat, err := storage.Client.StatObject(ctx, storage.Bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return ObjectInfo{}, s3Error(err)
+	}
+	return toObjectInfo(stat), nil
+}
+
+func (storage *S3Storage) Delete(ctx context.Context, key string) error {
+	key, err := NormalizeKey(key)
+	if err != nil {
+		return err
+	}
+
+	// S3 deletes are idempotent, a missing key is not an error
+	return storage.Client.RemoveObject(ctx, storage.Bucket, key, minio.RemoveObjectOptions{})
+}
+
+func (storage *S3Storage) DeletePrefix(ctx context.Context, prefix string) error {
+	prefix, err := NormalizeKey(prefix)
+	if err != nil {
+		return err
+	}
+
+	objects := storage.Client.ListObjects(ctx, storage.Bucket, minio.ListObjectsOptions{Prefix: prefix + "/", Recursive: true})
+	// The channel is drained so the removal goroutine can finish
+	var firstErr error
+	for removeErr := range storage.Client.RemoveObjects(ctx, storage.Bucket, objects, minio.RemoveObjectsOptions{}) {
+		if firstErr == nil {
+			firstErr = fmt.Errorf("delete %s: %w", removeErr.ObjectName, removeErr.Err)
+		}
+	}
+	return firstErr
+}
+
+func (storage *S3Storage) List(ctx context.Context, prefix string, visit func(ObjectInfo) error) error {
+	prefix, err := NormalizeKey(prefix)
+	if err != nil {
+		return err
+	}
+
+	// Cancelling stops the listing goroutine when visit returns early
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for object := range storage.Client.ListObjects(ctx, storage.Bucket, minio.ListObjectsOptions{Prefix: prefix + "/", Recursive: true}) {
+		if object.Err != nil {
+			return s3Error(object.Err)
+		}
+		if err := visit(toObjectInfo(object)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toObjectInfo(object minio.ObjectInfo) ObjectInfo {
+	return ObjectInfo{
+		Key:         object.Key,
+		Size:        object.Size,
+		ContentType: object.ContentType,
+		ModTime:     object.LastModified,
+	}
+}
+
+func s3Error(err error) error {
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return ErrNotFound
+	}
+	return err
+}
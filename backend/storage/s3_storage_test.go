@@ -0,0 +1,306 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"evoconnect/backend/config"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 is a MinIO stand-in keeping objects in memory. It implements the calls S3Storage makes:
+// HEAD bucket, PUT, GET and HEAD object, DELETE, ListObjectsV2 and multi-object delete.
+type fakeS3 struct {
+	bucket  string
+	mu      sync.Mutex
+	objects map[string]fakeS3Object
+}
+
+type fakeS3Object struct {
+	body        []byte
+	contentType string
+	modTime     time.Time
+}
+
+func newFakeS3(t *testing.T, bucket string) (*fakeS3, config.S3Config) {
+	fake := &fakeS3{bucket: bucket, objects: map[string]fakeS3Object{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	return fake, config.S3Config{
+		Endpoint:        strings.TrimPrefix(server.URL, "http://"),
+		Region:          "us-east-1",
+		Bucket:          bucket,
+		AccessKeyID:     "minioadmin",
+		SecretAccessKey: "minioadmin",
+		UseSSL:          false,
+	}
+}
+
+func (fake *fakeS3) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(request.URL.Path, "/"), "/")
+	if bucket != fake.bucket {
+		fake.writeError(writer, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	query := request.URL.Query()
+	switch {
+	case key == "" && request.Method == http.MethodHead:
+		writer.WriteHeader(http.StatusOK)
+	case key == "" && request.Method == http.MethodGet && query.Get("list-type") == "2":
+		fake.list(writer, query.Get("prefix"))
+	case key == "" && request.Method == http.MethodPost && query.Has("delete"):
+		fake.deleteObjects(writer, request)
+	case request.Method == http.MethodPut:
+		fake.put(writer, request, key)
+	case request.Method == http.MethodGet || request.Method == http.MethodHead:
+		object, ok := fake.objects[key]
+		if !ok {
+			fake.writeError(writer, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		sum := md5.Sum(object.body)
+		writer.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+		writer.Header().Set("Content-Type", object.contentType)
+		http.ServeContent(writer, request, key, object.modTime, bytes.NewReader(object.body))
+	case request.Method == http.MethodDelete:
+		delete(fake.objects, key)
+		writer.WriteHeader(http.StatusNoContent)
+	default:
+		fake.writeError(writer, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+func (fake *fakeS3) put(writer http.ResponseWriter, request *http.Request, key string) {
+	var body []byte
+	var err error
+	// Without TLS the client signs every chunk and sends the body aws-chunked encoded
+	if strings.HasPrefix(request.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		body, err = decodeAwsChunked(request.Body)
+	} else {
+		body, err = io.ReadAll(request.Body)
+	}
+	if err != nil {
+		fake.writeError(writer, http.StatusBadRequest, "IncompleteBody")
+		return
+	}
+
+	fake.objects[key] = fakeS3Object{
+		body:        body,
+		contentType: request.Header.Get("Content-Type"),
+		modTime:     time.Now().UTC().Truncate(time.Second),
+	}
+	sum := md5.Sum(body)
+	writer.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+	writer.WriteHeader(http.StatusOK)
+}
+
+func (fake *fakeS3) list(writer http.ResponseWriter, prefix string) {
+	type content struct {
+		Key          string
+		LastModified string
+		ETag         string
+		Size         int64
+		StorageClass string
+	}
+	result := struct {
+		XMLName     xml.Name `xml:"ListBucketResult"`
+		Name        string
+		Prefix      string
+		KeyCount    int
+		MaxKeys     int
+		IsTruncated bool
+		Contents    []content
+	}{Name: fake.bucket, Prefix: prefix, MaxKeys: 1000}
+
+	for key, object := range fake.objects {
+		if strings.HasPrefix(key, prefix) {
+			result.Contents = append(result.Contents, content{
+				Key:          key,
+				LastModified: object.modTime.Format(time.RFC3339),
+				ETag:         `"etag"`,
+				Size:         int64(len(object.body)),
+				StorageClass: "STANDARD",
+			})
+		}
+	}
+	sort.Slice(result.Contents, func(i, j int) bool { return result.Contents[i].Key < result.Contents[j].Key })
+	result.KeyCount = len(result.Contents)
+
+	writer.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(writer).Encode(result)
+}
+
+func (fake *fakeS3) deleteObjects(writer http.ResponseWriter, request *http.Request) {
+	var deleteRequest struct {
+		Objects []struct {
+			Key string
+		} `xml:"Object"`
+	}
+	if err := xml.NewDecoder(request.Body).Decode(&deleteRequest); err != nil {
+		fake.writeError(writer, http.StatusBadRequest, "MalformedXML")
+		return
+	}
+	for _, object := range deleteRequest.Objects {
+		delete(fake.objects, object.Key)
+	}
+
+	writer.Header().Set("Content-Type", "application/xml")
+	fmt.Fprint(writer, `<?xml version="1.0" encoding="UTF-8"?><DeleteResult></DeleteResult>`)
+}
+
+func (fake *fakeS3) writeError(writer http.ResponseWriter, status int, code string) {
+	writer.Header().Set("Content-Type", "application/xml")
+	writer.WriteHeader(status)
+	fmt.Fprintf(writer, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>%s</Code><Message>%s</Message></Error>`, code, code)
+}
+
+// decodeAwsChunked reads a body of "<hex size>;chunk-signature=<sig>\r\n<data>\r\n" chunks
+func decodeAwsChunked(body io.Reader) ([]byte, error) {
+	reader := bufio.NewReader(body)
+	var decoded bytes.Buffer
+	for {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		sizeHex, _, _ := strings.Cut(strings.TrimSpace(header), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return decoded.Bytes(), nil
+		}
+		if _, err := io.CopyN(&decoded, reader, size); err != nil {
+			return nil, err
+		}
+		if _, err := reader.Discard(2); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func TestNewS3StorageRequiresBucket(t *testing.T) {
+	_, s3Config := newFakeS3(t, "evoconnect-uploads")
+	s3Config.Bucket = "missing-bucket"
+
+	if _, err := NewS3Storage(context.Background(), s3Config); err == nil {
+		t.Fatal("expected an error for a bucket that does not exist")
+	}
+}
+
+func TestS3Storage(t *testing.T) {
+	ctx := context.Background()
+	fake, s3Config := newFakeS3(t, "evoconnect-uploads")
+
+	s3Storage, err := NewS3Storage(ctx, s3Config)
+	if err != nil {
+		t.Fatalf("NewS3Storage: %v", err)
+	}
+
+	content := []byte("%PDF-1.7 resume")
+	if err := s3Storage.Put(ctx, "/uploads/cv_storage/user-1/cv.pdf", bytes.NewReader(content), int64(len(content)), "application/pdf"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, ok := fake.objects["uploads/cv_storage/user-1/cv.pdf"]; !ok {
+		t.Fatalf("object not stored under its normalized key, have %v", fake.objects)
+	}
+	if err := s3Storage.Put(ctx, "uploads/cv_storage/user-1/old.pdf", strings.NewReader("old"), 3, "application/pdf"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	info, err := s3Storage.Stat(ctx, "uploads/cv_storage/user-1/cv.pdf")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.Size != int64(len(content)) || info.ContentType != "application/pdf" {
+		t.Errorf("Stat = %+v", info)
+	}
+
+	object, _, err := s3Storage.Open(ctx, "uploads/cv_storage/user-1/cv.pdf")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	// Serving with range requests seeks within the object
+	if _, err := object.Seek(5, io.SeekStart); err != nil {
+		t.Fatalf("Seek: %v", err)
+	}
+	rest, err := io.ReadAll(object)
+	object.Close()
+	if err != nil || string(rest) != string(content[5:]) {
+		t.Errorf("read after seek = %q, %v", rest, err)
+	}
+
+	var listed []string
+	err = s3Storage.List(ctx, "uploads/cv_storage", func(info ObjectInfo) error {
+		listed = append(listed, info.Key)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if strings.Join(listed, ",") != "uploads/cv_storage/user-1/cv.pdf,uploads/cv_storage/user-1/old.pdf" {
+		t.Errorf("List = %v", listed)
+	}
+
+	if err := s3Storage.Delete(ctx, "uploads/cv_storage/user-1/old.pdf"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := s3Storage.Delete(ctx, "uploads/cv_storage/user-1/old.pdf"); err != nil {
+		t.Errorf("Delete of a missing object: %v", err)
+	}
+	if _, err := s3Storage.Stat(ctx, "uploads/cv_storage/user-1/old.pdf"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Stat after Delete = %v, want ErrNotFound", err)
+	}
+	if _, _, err := s3Storage.Open(ctx, "uploads/cv_storage/user-1/old.pdf"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open after Delete = %v, want ErrNotFound", err)
+	}
+
+	if err := s3Storage.DeletePrefix(ctx, "uploads/cv_storage/user-1"); err != nil {
+		t.Fatalf("DeletePrefix: %v", err)
+	}
+	if len(fake.objects) != 0 {
+		t.Errorf("objects left after DeletePrefix: %v", fake.objects)
+	}
+}
+
+func TestCopyLocalToS3(t *testing.T) {
+	ctx := context.Background()
+	fake, s3Config := newFakeS3(t, "evoconnect-uploads")
+
+	s3Storage, err := NewS3Storage(ctx, s3Config)
+	if err != nil {
+		t.Fatalf("NewS3Storage: %v", err)
+	}
+	localStorage := NewLocalStorage(t.TempDir())
+	if err := localStorage.Put(ctx, "uploads/posts/a/image.png", strings.NewReader("png"), 3, "image/png"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	if err := Copy(ctx, localStorage, s3Storage, "uploads/posts/a/image.png"); err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+
+	object, ok := fake.objects["uploads/posts/a/image.png"]
+	if !ok || string(object.body) != "png" || object.contentType != "image/png" {
+		t.Errorf("copied object = %+v, %v", object, ok)
+	}
+}
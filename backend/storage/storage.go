@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"evoconnect/backend/config"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned when no object is stored under a key
+var ErrNotFound = errors.New("object not found")
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// Storage keeps uploaded files under object keys. Keys are slash separated and include the
+// public "uploads/" prefix, so a key is also the path clients request it from.
+type Storage interface {
+	// Put stores body under key, replacing any existing object. size may be -1 when unknown.
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Open returns the object content, seekable so it can be served with range requests
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, ObjectInfo, error)
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+	// Delete succeeds when the object does not exist
+	Delete(ctx context.Context, key string) error
+	// DeletePrefix removes every object under a directory-like prefix ending in a slash
+	DeletePrefix(ctx context.Context, prefix string) error
+	// List calls visit for every object under prefix, stopping at the first error
+	List(ctx context.Context, prefix string, visit func(ObjectInfo) error) error
+}
+
+// New returns the storage backend selected by the configuration
+func New(ctx context.Context, storageConfig config.StorageConfig) (Storage, error) {
+	switch storageConfig.Driver {
+	case config.StorageDriverLocal:
+		return NewLocalStorage(storageConfig.LocalRoot), nil
+	case config.StorageDriverS3:
+		return NewS3Storage(ctx, storageConfig.S3)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", storageConfig.Driver)
+	}
+}
+
+// NormalizeKey turns a stored file reference, such as "/uploads/posts/x.jpg" or a Windows
+// style path saved by older versions, into a key. URLs and keys that would escape the
+// storage root are rejected.
+func NormalizeKey(reference string) (string, error) {
+	key := strings.ReplaceAll(strings.TrimSpace(reference), "\\", "/")
+	if key == "" || strings.Contains(key, "://") {
+		return "", fmt.Errorf("%q is not a storage key", reference)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == ".." {
+			return "", fmt.Errorf("%q is not a storage key", reference)
+		}
+	}
+
+	key = strings.TrimPrefix(path.Clean("/"+key), "/")
+	if key == "" {
+		return "", fmt.Errorf("%q is not a storage key", reference)
+	}
+	return key, nil
+}
+
+// Copy transfers one object between backends, used when moving uploads to another backend
+func Copy(ctx context.Context, from Storage, to Storage, key string) error {
+	object, info, err := from.Open(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer object.Close()
+
+	return to.Put(ctx, key, object, info.Size, info.ContentType)
+}
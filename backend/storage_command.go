@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"evoconnect/backend/config"
+	"evoconnect/backend/storage"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+const storageUsage = "usage: evoconnect storage copy [-from-root DIR] [-prefix PREFIX] [-dry-run]"
+
+// runStorageCommand moves uploads kept on local disk into the configured storage, used when
+// switching STORAGE_DRIVER to s3. Objects that already exist with the same size are skipped,
+// so an interrupted copy can simply be run again.
+func runStorageCommand(ctx context.Context, cfg config.Config, db *sql.DB, logger *slog.Logger, args []string) int {
+	if len(args) == 0 || args[0] != "copy" {
+		return usageError(storageUsage)
+	}
+
+	flags := flag.NewFlagSet("storage copy", flag.ContinueOnError)
+	fromRoot := flags.String("from-root", ".", "directory holding the local uploads/ directory")
+	prefix := flags.String("prefix", "uploads", "copy only keys under this prefix")
+	dryRun := flags.Bool("dry-run", false, "list what would be copied without writing anything")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 {
+		return usageError(storageUsage)
+	}
+
+	if cfg.Storage.Driver == config.StorageDriverLocal && sameDirectory(*fromRoot, cfg.Storage.LocalRoot) {
+		fmt.Fprintln(os.Stderr, "storage: source and destination are the same directory")
+		return 1
+	}
+
+	destination, err := storage.New(ctx, cfg.Storage)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "storage:", err)
+		return 1
+	}
+	source := storage.NewLocalStorage(*fromRoot)
+
+	copied, skipped, failed := 0, 0, 0
+	err = source.List(ctx, *prefix, func(info storage.ObjectInfo) error {
+		existing, err := destination.Stat(ctx, info.Key)
+		if err == nil && existing.Size == info.Size {
+			skipped++
+			return nil
+		}
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+
+		if *dryRun {
+			fmt.Println("would copy", info.Key)
+			copied++
+			return nil
+		}
+		if err := storage.Copy(ctx, source, destination, info.Key); err != nil {
+			logger.Error("failed to copy object", "key", info.Key, "error", err)
+			failed++
+			return nil
+		}
+		copied++
+		return nil
+	})
+
+	fmt.Printf("%d copied, %d skipped, %d failed\n", copied, skipped, failed)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "storage:", err)
+		return 1
+	}
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+func sameDirectory(a string, b string) bool {
+	absoluteA, errA := filepath.Abs(a)
+	absoluteB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absoluteA == absoluteB
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"evoconnect/backend/config"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+)
+
+const userUsage = "usage: evoconnect user suspend [-days N] USER | unsuspend USER | verify USER"
+
+func runUserCommand(ctx context.Context, cfg config.Config, db *sql.DB, logger *slog.Logger, args []string) int {
+	if len(args) == 0 {
+		return usageError(userUsage)
+	}
+	action := args[0]
+
+	flags := flag.NewFlagSet("user "+action, flag.ContinueOnError)
+	days := 0
+	if action == "suspend" {
+		flags.IntVar(&days, "days", 0, "length of the suspension, 0 keeps it until it is lifted")
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 || days < 0 {
+		return usageError(userUsage)
+	}
+
+	application, err := newApplication(ctx, cfg, db, logger)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "user:", err)
+		return 1
+	}
+
+	ctx = operatorContext(ctx)
+	userId, err := application.accountStatusService.FindUserId(ctx, flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "user:", err)
+		return 1
+	}
+
+	switch action {
+	case "suspend":
+		var until *time.Time
+		if days > 0 {
+			suspendedUntil := time.Now().AddDate(0, 0, days)
+			until = &suspendedUntil
+		}
+		err = application.accountStatusService.Suspend(ctx, userId, until)
+	case "unsuspend":
+		err = application.accountStatusService.Unsuspend(ctx, userId)
+	case "verify":
+		err = application.accountStatusService.MarkVerified(ctx, userId)
+	default:
+		return usageError(userUsage)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "user %s: %v\n", action, err)
+		return 1
+	}
+
+	fmt.Printf("user %s: done for %s\n", action, userId)
+	return 0
+}
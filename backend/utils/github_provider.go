@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+// GitHubProvider signs users in with GitHub, which speaks plain OAuth2 rather than OpenID Connect,
+// so the identity is read from its REST API instead of an ID token.
+type GitHubProvider struct {
+	Config OAuthProviderConfig
+}
+
+func NewGitHubProvider(config OAuthProviderConfig) *GitHubProvider {
+	return &GitHubProvider{Config: config}
+}
+
+func (provider *GitHubProvider) Name() string {
+	return provider.Config.Name
+}
+
+func (provider *GitHubProvider) DisplayName() string {
+	return provider.Config.DisplayName
+}
+
+func (provider *GitHubProvider) AuthCodeURL(ctx context.Context, state string, nonce string, codeVerifier string) (string, error) {
+	return provider.oauthConfig().AuthCodeURL(state, oauth2.S256ChallengeOption(codeVerifier)), nil
+}
+
+func (provider *GitHubProvider) Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (OAuthIdentity, error) {
+	token, err := provider.oauthConfig().Exchange(context.WithValue(ctx, oauth2.HTTPClient, oauthHTTPClient), code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return OAuthIdentity{}, fmt.Errorf("code exchange failed: %w", err)
+	}
+
+	var user struct {
+		ID        int64  `json:"id"`
+		Login     string `json:"login"`
+		Name      string `json:"name"`
+		AvatarURL string `json:"avatar_url"`
+	}
+	if err := getJSON(ctx, provider.Config.APIURL+"/user", token.AccessToken, &user); err != nil {
+		return OAuthIdentity{}, fmt.Errorf("fetching GitHub user failed: %w", err)
+	}
+	if user.ID == 0 {
+		return OAuthIdentity{}, fmt.Errorf("GitHub user has no id")
+	}
+
+	// The profile email is optional and unverified, the emails endpoint tells us which one is
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := getJSON(ctx, provider.Config.APIURL+"/user/emails", token.AccessToken, &emails); err != nil {
+		return OAuthIdentity{}, fmt.Errorf("fetching GitHub emails failed: %w", err)
+	}
+
+	identity := OAuthIdentity{
+		Provider: provider.Config.Name,
+		Subject:  strconv.FormatInt(user.ID, 10),
+		Name:     user.Name,
+		Picture:  user.AvatarURL,
+	}
+	if identity.Name == "" {
+		identity.Name = user.Login
+	}
+	for _, email := range emails {
+		if email.Primary {
+			identity.Email = strings.ToLower(strings.TrimSpace(email.Email))
+			identity.EmailVerified = email.Verified
+			break
+		}
+	}
+
+	return identity, nil
+}
+
+func (provider *GitHubProvider) oauthConfig() *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     provider.Config.ClientID,
+		ClientSecret: provider.Config.ClientSecret,
+		RedirectURL:  provider.Config.RedirectURL,
+		Scopes:       provider.Config.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  provider.Config.AuthURL,
+			TokenURL: provider.Config.TokenURL,
+		},
+	}
+}
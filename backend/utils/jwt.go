@@ -1,29 +1,87 @@
 package utils
 
 import (
+	"evoconnect/backend/config"
+	"evoconnect/backend/helper"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var jwtSecret []byte
+var (
+	userKeyring  *JWTKeyring
+	adminKeyring *JWTKeyring
+)
+
+// InitJWT loads the user and admin keyrings - harus dipanggil di main.go. The shared secret is
+// used when a keyring is not configured and to accept tokens signed before key ids existed.
+// Placeholder secrets are only allowed in debug mode.
+func InitJWT(cfg config.JWTConfig, allowPlaceholder bool) error {
+	secret := cfg.Secret
+	if secret == "" && allowPlaceholder && cfg.UserKeys == "" {
+		secret = jwtPlaceholderSecrets[0]
+	}
+	adminSecret := cfg.AdminSecret
+	if adminSecret == "" {
+		adminSecret = secret
+	}
+
+	for _, setting := range [][2]string{{"JWT_SECRET_KEY", secret}, {"ADMIN_JWT_SECRET_KEY", adminSecret}} {
+		if !isJWTPlaceholderSecret(setting[1]) {
+			continue
+		}
+		if !allowPlaceholder {
+			return fmt.Errorf("%s is set to a placeholder value, configure a real secret", setting[0])
+		}
+		slog.Warn("JWT secret is a placeholder value, tokens can be forged. Only use this in debug mode.", "setting", setting[0])
+	}
+
+	legacySecret := ""
+	if cfg.AcceptLegacyTokens {
+		legacySecret = secret
+	}
 
-// InitJWT initializes the JWT secret - harus dipanggil di main.go
-func InitJWT(secret string) {
-	if secret == "" {
-		panic("JWT secret cannot be empty")
+	var err error
+	userKeyring, err = LoadJWTKeyring(JWTAudienceUser, cfg.UserKeys, secret, legacySecret)
+	if err != nil {
+		return err
 	}
-	jwtSecret = []byte(secret)
-	fmt.Printf("JWT Secret initialized successfully\n")
+	adminKeyring, err = LoadJWTKeyring(JWTAudienceAdmin, cfg.AdminKeys, adminSecret, legacySecret)
+	if err != nil {
+		return err
+	}
+
+	// helper cannot import utils, the token helpers used by controllers get the parsers here
+	helper.SetTokenParsers(
+		func(tokenString string) (string, error) {
+			claims, err := ValidateUserToken(tokenString)
+			if err != nil {
+				return "", err
+			}
+			return claims.ID, nil
+		},
+		func(tokenString string) (string, error) {
+			claims, err := ValidateAdminToken(tokenString)
+			if err != nil {
+				return "", err
+			}
+			return claims.ID, nil
+		},
+	)
+
+	slog.Info("JWT keyrings initialized", "user_key_id", userKeyring.ActiveKeyID(), "admin_key_id", adminKeyring.ActiveKeyID())
+	return nil
 }
 
 // UserClaims represents the claims for user tokens
 type UserClaims struct {
-	ID    string `json:"user_id"`
-	Email string `json:"email"`
-	Role  string `json:"role"`
+	ID        string `json:"user_id"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -35,25 +93,48 @@ type AdminClaims struct {
 	jwt.RegisteredClaims
 }
 
+// Purposes of the short-lived tokens issued between the password and 2FA steps
+const (
+	MFAPurposePending    = "mfa_pending"
+	MFAPurposeEnrollment = "mfa_enrollment"
+)
+
+// MFAPendingClaims identify a user or admin who passed the password step but still
+// has to provide a second factor. They cannot be used as access tokens.
+type MFAPendingClaims struct {
+	SubjectType string `json:"subject_type"`
+	Purpose     string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+// errJWTNotInitialized is returned until InitJWT has loaded the keyrings
+var errJWTNotInitialized = fmt.Errorf("JWT keyrings not initialized")
+
 // GenerateUserToken creates a JWT token for users
 func GenerateUserToken(userID, email string, duration time.Duration) (string, error) {
-	if len(jwtSecret) == 0 {
-		return "", fmt.Errorf("JWT secret not initialized")
+	return GenerateUserAccessToken(userID, email, "", duration)
+}
+
+// GenerateUserAccessToken creates a short-lived JWT bound to a server-side session
+func GenerateUserAccessToken(userID, email, sessionID string, duration time.Duration) (string, error) {
+	if userKeyring == nil {
+		return "", errJWTNotInitialized
 	}
 
 	claims := UserClaims{
-		ID:    userID,
-		Email: email,
-		Role:  "user",
+		ID:        userID,
+		Email:     email,
+		Role:      "user",
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{JWTAudienceUser},
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Issuer:    "evoconnect",
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString(jwtSecret)
+	signedToken, err := userKeyring.Sign(claims)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign user token: %v", err)
 	}
@@ -61,25 +142,25 @@ func GenerateUserToken(userID, email string, duration time.Duration) (string, er
 	return signedToken, nil
 }
 
-// GenerateAdminToken creates a JWT token for admins
-func GenerateAdminToken(adminID, email string, duration time.Duration) (string, error) {
-	if len(jwtSecret) == 0 {
-		return "", fmt.Errorf("JWT secret not initialized")
+// GenerateAdminToken creates a JWT token for admins carrying their panel role
+func GenerateAdminToken(adminID, email, role string, duration time.Duration) (string, error) {
+	if adminKeyring == nil {
+		return "", errJWTNotInitialized
 	}
 
 	claims := AdminClaims{
 		ID:    adminID,
 		Email: email,
-		Role:  "admin",
+		Role:  role,
 		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{JWTAudienceAdmin},
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Issuer:    "evoconnect",
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString(jwtSecret)
+	signedToken, err := adminKeyring.Sign(claims)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign admin token: %v", err)
 	}
@@ -89,18 +170,11 @@ func GenerateAdminToken(adminID, email string, duration time.Duration) (string,
 
 // ValidateUserToken validates a user JWT token
 func ValidateUserToken(tokenString string) (*UserClaims, error) {
-	if len(jwtSecret) == 0 {
-		return nil, fmt.Errorf("JWT secret not initialized")
+	if userKeyring == nil {
+		return nil, errJWTNotInitialized
 	}
 
-	token, err := jwt.ParseWithClaims(tokenString, &UserClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// Validate the signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return jwtSecret, nil
-	})
-
+	token, err := userKeyring.Parse(tokenString, &UserClaims{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse user token: %v", err)
 	}
@@ -109,8 +183,9 @@ func ValidateUserToken(tokenString string) (*UserClaims, error) {
 		return nil, fmt.Errorf("invalid user token")
 	}
 
+	// Tokens from before key ids carry no audience, newer ones must be meant for users
 	claims, ok := token.Claims.(*UserClaims)
-	if !ok {
+	if !ok || claims.ID == "" || !claims.VerifyAudience(JWTAudienceUser, false) {
 		return nil, fmt.Errorf("invalid user token claims")
 	}
 
@@ -119,18 +194,11 @@ func ValidateUserToken(tokenString string) (*UserClaims, error) {
 
 // ValidateAdminToken validates an admin JWT token
 func ValidateAdminToken(tokenString string) (*AdminClaims, error) {
-	if len(jwtSecret) == 0 {
-		return nil, fmt.Errorf("JWT secret not initialized")
+	if adminKeyring == nil {
+		return nil, errJWTNotInitialized
 	}
 
-	token, err := jwt.ParseWithClaims(tokenString, &AdminClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// Validate the signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return jwtSecret, nil
-	})
-
+	token, err := adminKeyring.Parse(tokenString, &AdminClaims{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse admin token: %v", err)
 	}
@@ -140,32 +208,93 @@ func ValidateAdminToken(tokenString string) (*AdminClaims, error) {
 	}
 
 	claims, ok := token.Claims.(*AdminClaims)
-	if !ok {
+	if !ok || claims.ID == "" || !claims.VerifyAudience(JWTAudienceAdmin, false) {
 		return nil, fmt.Errorf("invalid admin token claims")
 	}
 
 	return claims, nil
 }
 
+// mfaKeyring signs pending tokens with the keyring of the account type they belong to
+func mfaKeyring(subjectType string) *JWTKeyring {
+	if subjectType == JWTAudienceAdmin {
+		return adminKeyring
+	}
+	return userKeyring
+}
+
+// GenerateMFAPendingToken creates the token returned by the first login step
+func GenerateMFAPendingToken(subjectID, subjectType, purpose string, duration time.Duration) (string, error) {
+	keyring := mfaKeyring(subjectType)
+	if keyring == nil {
+		return "", errJWTNotInitialized
+	}
+
+	claims := MFAPendingClaims{
+		SubjectType: subjectType,
+		Purpose:     purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subjectID,
+			Audience:  jwt.ClaimStrings{purpose},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    "evoconnect",
+		},
+	}
+
+	signedToken, err := keyring.Sign(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign mfa token: %v", err)
+	}
+
+	return signedToken, nil
+}
+
+// ValidateMFAPendingToken validates a token created by GenerateMFAPendingToken
+func ValidateMFAPendingToken(tokenString, subjectType string) (*MFAPendingClaims, error) {
+	keyring := mfaKeyring(subjectType)
+	if keyring == nil {
+		return nil, errJWTNotInitialized
+	}
+
+	token, err := keyring.Parse(tokenString, &MFAPendingClaims{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse mfa token: %v", err)
+	}
+
+	claims, ok := token.Claims.(*MFAPendingClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid mfa token")
+	}
+
+	if claims.Purpose != MFAPurposePending && claims.Purpose != MFAPurposeEnrollment {
+		return nil, fmt.Errorf("invalid mfa token purpose")
+	}
+
+	if claims.SubjectType != subjectType {
+		return nil, fmt.Errorf("mfa token was not issued for this login")
+	}
+
+	return claims, nil
+}
+
 // Legacy functions for backward compatibility
 func GenerateToken(userID, email, role string, duration time.Duration) (string, error) {
 	if role == "admin" {
-		return GenerateAdminToken(userID, email, duration)
+		return GenerateAdminToken(userID, email, role, duration)
 	}
 	return GenerateUserToken(userID, email, duration)
 }
 
 func ValidateToken(tokenString string) (*jwt.MapClaims, error) {
-	if len(jwtSecret) == 0 {
-		return nil, fmt.Errorf("JWT secret not initialized")
+	if userKeyring == nil || adminKeyring == nil {
+		return nil, errJWTNotInitialized
 	}
 
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return jwtSecret, nil
-	})
+	token, err := userKeyring.Parse(tokenString, jwt.MapClaims{})
+	if err != nil {
+		token, err = adminKeyring.Parse(tokenString, jwt.MapClaims{})
+	}
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %v", err)
@@ -184,10 +313,6 @@ func ValidateToken(tokenString string) (*jwt.MapClaims, error) {
 }
 
 func HashPassword(password string) (string, error) {
-	if len(jwtSecret) == 0 {
-		return "", fmt.Errorf("JWT secret not initialized")
-	}
-
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		return "", fmt.Errorf("failed to hash password: %v", err)
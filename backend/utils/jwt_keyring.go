@@ -0,0 +1,300 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Audiences get their own keyring, so a leaked admin key cannot mint user tokens and the other way round
+const (
+	JWTAudienceUser  = "user"
+	JWTAudienceAdmin = "admin"
+)
+
+// Supported signing algorithms
+const (
+	JWTAlgorithmHS256 = "HS256"
+	JWTAlgorithmRS256 = "RS256"
+	JWTAlgorithmEdDSA = "EdDSA"
+)
+
+const jwtMinHMACKeyLength = 32
+
+// Example values from the README, .env.example and old fallbacks. They are public, so tokens
+// signed with them can be forged by anyone.
+var jwtPlaceholderSecrets = []string{
+	"your-super-secret-jwt-key-at-least-32-characters-long",
+	"your_jwt_secret_key_here",
+	"your_admin_jwt_secret_key_here",
+	"your-secret-key",
+}
+
+// JWTKey is one signing key. Keys without a private half can only verify.
+type JWTKey struct {
+	ID         string
+	Algorithm  string
+	signingKey interface{}
+	verifyKey  interface{}
+}
+
+func (key *JWTKey) method() jwt.SigningMethod {
+	return jwt.GetSigningMethod(key.Algorithm)
+}
+
+// JWTKeyring signs with its active key and verifies with any key it holds. Rotating means
+// putting a new key first and keeping the old ones until the tokens they signed have expired.
+type JWTKeyring struct {
+	Audience string
+	active   *JWTKey
+	keys     map[string]*JWTKey
+	// legacy verifies tokens without a kid header, signed before keyrings existed
+	legacy *JWTKey
+}
+
+func (keyring *JWTKeyring) ActiveKeyID() string {
+	return keyring.active.ID
+}
+
+// Sign signs the claims with the active key and names it in the kid header
+func (keyring *JWTKeyring) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(keyring.active.method(), claims)
+	token.Header["kid"] = keyring.active.ID
+	return token.SignedString(keyring.active.signingKey)
+}
+
+// Parse verifies the token with the key named by its kid. The algorithm must be the one the
+// key was configured with, so an RSA public key can never be used as an HMAC secret.
+func (keyring *JWTKeyring) Parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		var key *JWTKey
+		if kid, ok := token.Header["kid"].(string); ok {
+			key = keyring.keys[kid]
+			if key == nil {
+				return nil, fmt.Errorf("unknown signing key %q", kid)
+			}
+		} else if keyring.legacy != nil {
+			key = keyring.legacy
+		} else {
+			return nil, errors.New("token has no key id")
+		}
+
+		if token.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return key.verifyKey, nil
+	})
+}
+
+// LoadJWTKeyring parses keys, the JWT_<AUDIENCE>_KEYS setting, a comma separated list of
+// kid=ALG:material entries. The first entry signs new tokens. Material is file:<path>, env:<VAR>,
+// base64:<data> or, for HS256, the secret itself; RS256 and EdDSA take PEM private keys, or
+// public keys to verify only.
+//
+// Without keys the ring falls back to one HS256 key derived from fallbackSecret.
+func LoadJWTKeyring(audience string, keys string, fallbackSecret string, legacySecret string) (*JWTKeyring, error) {
+	keyring := &JWTKeyring{
+		Audience: audience,
+		keys:     make(map[string]*JWTKey),
+	}
+
+	envName := "JWT_" + strings.ToUpper(audience) + "_KEYS"
+	specs := splitList(keys)
+
+	if len(specs) == 0 {
+		if fallbackSecret == "" {
+			return nil, fmt.Errorf("%s or JWT_SECRET_KEY must be set", envName)
+		}
+		key := deriveJWTKey(audience, fallbackSecret)
+		keyring.active = key
+		keyring.keys[key.ID] = key
+	}
+
+	for i, spec := range specs {
+		key, err := parseJWTKeySpec(spec)
+		if err != nil {
+			return nil, fmt.Errorf("%s entry %d: %w", envName, i+1, err)
+		}
+		if _, exists := keyring.keys[key.ID]; exists {
+			return nil, fmt.Errorf("%s: duplicate key id %q", envName, key.ID)
+		}
+		if i == 0 {
+			if key.signingKey == nil {
+				return nil, fmt.Errorf("%s: the first key signs new tokens and needs a private key", envName)
+			}
+			keyring.active = key
+		}
+		keyring.keys[key.ID] = key
+	}
+
+	// Moving from JWT_SECRET_KEY to explicit keys must not log everyone out, so the derived key
+	// keeps verifying as long as the secret is configured
+	if len(specs) > 0 && fallbackSecret != "" {
+		derived := deriveJWTKey(audience, fallbackSecret)
+		if _, exists := keyring.keys[derived.ID]; !exists {
+			derived.signingKey = nil
+			keyring.keys[derived.ID] = derived
+		}
+	}
+
+	if legacySecret != "" {
+		keyring.legacy = &JWTKey{
+			ID:        "legacy",
+			Algorithm: JWTAlgorithmHS256,
+			verifyKey: []byte(legacySecret),
+		}
+	}
+
+	return keyring, nil
+}
+
+// deriveJWTKey gives every audience a different key from the one shared secret
+func deriveJWTKey(audience string, secret string) *JWTKey {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("evoconnect-jwt-" + audience))
+	derived := mac.Sum(nil)
+
+	// The id is a fingerprint of the key, so changing the secret also changes the kid
+	fingerprint := sha256.Sum256(derived)
+
+	return &JWTKey{
+		ID:         audience + "-" + hex.EncodeToString(fingerprint[:4]),
+		Algorithm:  JWTAlgorithmHS256,
+		signingKey: derived,
+		verifyKey:  derived,
+	}
+}
+
+func parseJWTKeySpec(spec string) (*JWTKey, error) {
+	kid, rest, ok := strings.Cut(spec, "=")
+	if !ok || kid == "" {
+		return nil, errors.New("expected kid=ALG:material")
+	}
+	algorithm, source, ok := strings.Cut(rest, ":")
+	if !ok || source == "" {
+		return nil, errors.New("expected kid=ALG:material")
+	}
+
+	material, err := readJWTKeyMaterial(source)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &JWTKey{ID: kid, Algorithm: algorithm}
+
+	switch algorithm {
+	case JWTAlgorithmHS256:
+		secret := []byte(strings.TrimSpace(string(material)))
+		if len(secret) < jwtMinHMACKeyLength {
+			return nil, fmt.Errorf("HS256 key %q must be at least %d bytes", kid, jwtMinHMACKeyLength)
+		}
+		if isJWTPlaceholderSecret(string(secret)) {
+			return nil, fmt.Errorf("HS256 key %q is a placeholder value", kid)
+		}
+		key.signingKey = secret
+		key.verifyKey = secret
+
+	case JWTAlgorithmRS256, JWTAlgorithmEdDSA:
+		block, _ := pem.Decode(material)
+		if block == nil {
+			return nil, fmt.Errorf("key %q is not PEM encoded", kid)
+		}
+		if err := key.setPEMKey(block); err != nil {
+			return nil, fmt.Errorf("key %q: %w", kid, err)
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", algorithm)
+	}
+
+	return key, nil
+}
+
+func (key *JWTKey) setPEMKey(block *pem.Block) error {
+	var parsed interface{}
+	var err error
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch parsedKey := parsed.(type) {
+	case *rsa.PrivateKey:
+		if key.Algorithm != JWTAlgorithmRS256 {
+			return errors.New("RSA key used with " + key.Algorithm)
+		}
+		key.signingKey = parsedKey
+		key.verifyKey = &parsedKey.PublicKey
+	case *rsa.PublicKey:
+		if key.Algorithm != JWTAlgorithmRS256 {
+			return errors.New("RSA key used with " + key.Algorithm)
+		}
+		key.verifyKey = parsedKey
+	case ed25519.PrivateKey:
+		if key.Algorithm != JWTAlgorithmEdDSA {
+			return errors.New("Ed25519 key used with " + key.Algorithm)
+		}
+		key.signingKey = parsedKey
+		key.verifyKey = parsedKey.Public()
+	case ed25519.PublicKey:
+		if key.Algorithm != JWTAlgorithmEdDSA {
+			return errors.New("Ed25519 key used with " + key.Algorithm)
+		}
+		key.verifyKey = parsedKey
+	default:
+		return fmt.Errorf("unsupported key type %T", parsed)
+	}
+
+	return nil
+}
+
+func readJWTKeyMaterial(source string) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(source, "file:"):
+		return os.ReadFile(strings.TrimPrefix(source, "file:"))
+	case strings.HasPrefix(source, "env:"):
+		name := strings.TrimPrefix(source, "env:")
+		value := os.Getenv(name)
+		if value == "" {
+			return nil, fmt.Errorf("environment variable %s is empty", name)
+		}
+		// PEM keys in .env files usually have their newlines escaped
+		return []byte(strings.ReplaceAll(value, `\n`, "\n")), nil
+	case strings.HasPrefix(source, "base64:"):
+		return base64.StdEncoding.DecodeString(strings.TrimPrefix(source, "base64:"))
+	default:
+		return []byte(source), nil
+	}
+}
+
+func isJWTPlaceholderSecret(secret string) bool {
+	for _, placeholder := range jwtPlaceholderSecrets {
+		if secret == placeholder {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,189 @@
+package utils
+
+import (
+	"context"
+	"evoconnect/backend/config"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OAuthIdentity is what a provider tells us about the person who signed in
+type OAuthIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Picture       string
+}
+
+// OAuthProvider runs the authorization code flow against one identity provider
+type OAuthProvider interface {
+	Name() string
+	DisplayName() string
+	AuthCodeURL(ctx context.Context, state string, nonce string, codeVerifier string) (string, error)
+	Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (OAuthIdentity, error)
+}
+
+// IDTokenVerifier is implemented by OIDC providers, it checks an ID token obtained by the frontend
+type IDTokenVerifier interface {
+	VerifyIDToken(ctx context.Context, rawIDToken string, nonce string) (OAuthIdentity, error)
+}
+
+// OAuthProviderConfig is a provider with the defaults of well-known providers filled in
+type OAuthProviderConfig struct {
+	Name         string
+	DisplayName  string
+	Type         string
+	ClientID     string
+	ClientSecret string
+	Issuer       string
+	AuthURL      string
+	TokenURL     string
+	APIURL       string
+	Scopes       []string
+	RedirectURL  string
+}
+
+const (
+	OAuthProviderTypeOIDC   = config.OAuthProviderTypeOIDC
+	OAuthProviderTypeGitHub = config.OAuthProviderTypeGitHub
+)
+
+// Defaults for well-known providers, anything else needs an issuer
+var knownOAuthProviders = map[string]OAuthProviderConfig{
+	"google": {
+		DisplayName: "Google",
+		Type:        OAuthProviderTypeOIDC,
+		Issuer:      "https://accounts.google.com",
+		Scopes:      []string{"openid", "email", "profile"},
+	},
+	"linkedin": {
+		DisplayName: "LinkedIn",
+		Type:        OAuthProviderTypeOIDC,
+		Issuer:      "https://www.linkedin.com/oauth",
+		Scopes:      []string{"openid", "email", "profile"},
+	},
+	"microsoft": {
+		DisplayName: "Microsoft",
+		Type:        OAuthProviderTypeOIDC,
+		Issuer:      "https://login.microsoftonline.com/common/v2.0",
+		Scopes:      []string{"openid", "email", "profile"},
+	},
+	"github": {
+		DisplayName: "GitHub",
+		Type:        OAuthProviderTypeGitHub,
+		AuthURL:     "https://github.com/login/oauth/authorize",
+		TokenURL:    "https://github.com/login/oauth/access_token",
+		APIURL:      "https://api.github.com",
+		Scopes:      []string{"read:user", "user:email"},
+	},
+}
+
+// oauthHTTPClient is shared by discovery, JWKS, token and user info calls
+var oauthHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// Client id the Google sign-in button has always used when GOOGLE_CLIENT_ID is not set
+const defaultGoogleClientID = "630548216793-u72hegqjlqli4petjg5lsgkrp8fn0foc.apps.googleusercontent.com"
+
+// LoadOAuthProviders builds the configured providers on top of the defaults of well-known ones.
+// Google is always enabled because the existing Google sign-in button posts its ID token to
+// /api/auth/google.
+func LoadOAuthProviders(cfg config.OAuthConfig, clientURL string) (map[string]OAuthProvider, error) {
+	providers := make(map[string]OAuthProvider)
+
+	settings := map[string]config.OAuthProviderConfig{"google": {}}
+	for name, setting := range cfg.Providers {
+		settings[strings.ToLower(name)] = setting
+	}
+
+	// The frontend receives the code on this page and posts it back to the API
+	redirectBase := cfg.RedirectBaseURL
+	if redirectBase == "" {
+		redirectBase = clientURL + "/oauth/callback"
+	}
+
+	for name, setting := range settings {
+		providerConfig, err := buildOAuthProviderConfig(name, setting, redirectBase)
+		if err != nil {
+			return nil, fmt.Errorf("OAuth provider %s: %w", name, err)
+		}
+
+		switch providerConfig.Type {
+		case OAuthProviderTypeGitHub:
+			providers[name] = NewGitHubProvider(providerConfig)
+		default:
+			providers[name] = NewOIDCProvider(providerConfig)
+		}
+	}
+
+	return providers, nil
+}
+
+func buildOAuthProviderConfig(name string, setting config.OAuthProviderConfig, redirectBase string) (OAuthProviderConfig, error) {
+	providerConfig := knownOAuthProviders[name]
+	providerConfig.Name = name
+	providerConfig.DisplayName = firstNonEmpty(setting.DisplayName, providerConfig.DisplayName)
+	providerConfig.Type = firstNonEmpty(setting.Type, providerConfig.Type)
+	providerConfig.ClientID = setting.ClientID
+	providerConfig.ClientSecret = setting.ClientSecret
+	providerConfig.Issuer = strings.TrimSuffix(firstNonEmpty(setting.Issuer, providerConfig.Issuer), "/")
+	providerConfig.AuthURL = firstNonEmpty(setting.AuthURL, providerConfig.AuthURL)
+	providerConfig.TokenURL = firstNonEmpty(setting.TokenURL, providerConfig.TokenURL)
+	providerConfig.APIURL = strings.TrimSuffix(firstNonEmpty(setting.APIURL, providerConfig.APIURL), "/")
+	if len(setting.Scopes) > 0 {
+		providerConfig.Scopes = setting.Scopes
+	}
+
+	if name == "google" && providerConfig.ClientID == "" {
+		providerConfig.ClientID = defaultGoogleClientID
+	}
+
+	if providerConfig.DisplayName == "" {
+		providerConfig.DisplayName = name
+	}
+	if providerConfig.Type == "" {
+		providerConfig.Type = OAuthProviderTypeOIDC
+	}
+	if len(providerConfig.Scopes) == 0 && providerConfig.Type == OAuthProviderTypeOIDC {
+		providerConfig.Scopes = []string{"openid", "email", "profile"}
+	}
+
+	providerConfig.RedirectURL = firstNonEmpty(setting.RedirectURL, strings.TrimSuffix(redirectBase, "/")+"/"+name)
+
+	if providerConfig.ClientID == "" {
+		return providerConfig, fmt.Errorf("client id is not set")
+	}
+	if providerConfig.Type == OAuthProviderTypeOIDC && providerConfig.Issuer == "" {
+		return providerConfig, fmt.Errorf("issuer is not set")
+	}
+	if providerConfig.Type == OAuthProviderTypeGitHub && (providerConfig.AuthURL == "" || providerConfig.TokenURL == "" || providerConfig.APIURL == "") {
+		return providerConfig, fmt.Errorf("auth, token and API URLs are required")
+	}
+	if providerConfig.Type != OAuthProviderTypeOIDC && providerConfig.Type != OAuthProviderTypeGitHub {
+		return providerConfig, fmt.Errorf("unknown provider type %q", providerConfig.Type)
+	}
+
+	return providerConfig, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,414 @@
+package utils
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"golang.org/x/oauth2"
+)
+
+// How long discovery documents and key sets are trusted before they are fetched again
+const (
+	oidcDiscoveryTTL = time.Hour
+	oidcJWKSTTL      = time.Hour
+	// Unknown key ids trigger a refetch, but not more often than this
+	oidcJWKSMinRefresh = time.Minute
+)
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// OIDCProvider signs users in with any OpenID Connect identity provider. Endpoints and signing
+// keys come from the issuer's discovery document, so a local fake IdP works the same as Google.
+type OIDCProvider struct {
+	Config OAuthProviderConfig
+
+	mu              sync.Mutex
+	discovery       *oidcDiscovery
+	discoveryExpiry time.Time
+	keys            map[string]crypto.PublicKey
+	keysExpiry      time.Time
+	keysFetchedAt   time.Time
+}
+
+type oidcClaims struct {
+	Nonce         string          `json:"nonce"`
+	Email         string          `json:"email"`
+	EmailVerified json.RawMessage `json:"email_verified"`
+	Name          string          `json:"name"`
+	GivenName     string          `json:"given_name"`
+	FamilyName    string          `json:"family_name"`
+	Picture       string          `json:"picture"`
+	Azp           string          `json:"azp"`
+	Tid           string          `json:"tid"`
+	// Microsoft accounts without a mailbox only carry the sign-in name
+	PreferredUsername string `json:"preferred_username"`
+	jwt.RegisteredClaims
+}
+
+func NewOIDCProvider(config OAuthProviderConfig) *OIDCProvider {
+	return &OIDCProvider{Config: config}
+}
+
+func (provider *OIDCProvider) Name() string {
+	return provider.Config.Name
+}
+
+func (provider *OIDCProvider) DisplayName() string {
+	return provider.Config.DisplayName
+}
+
+func (provider *OIDCProvider) AuthCodeURL(ctx context.Context, state string, nonce string, codeVerifier string) (string, error) {
+	config, err := provider.oauthConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return config.AuthCodeURL(state,
+		oauth2.SetAuthURLParam("nonce", nonce),
+		oauth2.S256ChallengeOption(codeVerifier),
+	), nil
+}
+
+func (provider *OIDCProvider) Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (OAuthIdentity, error) {
+	config, err := provider.oauthConfig(ctx)
+	if err != nil {
+		return OAuthIdentity{}, err
+	}
+
+	token, err := config.Exchange(context.WithValue(ctx, oauth2.HTTPClient, oauthHTTPClient), code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return OAuthIdentity{}, fmt.Errorf("code exchange failed: %w", err)
+	}
+
+	rawIDToken, _ := token.Extra("id_token").(string)
+	if rawIDToken == "" {
+		return OAuthIdentity{}, errors.New("provider did not return an id_token")
+	}
+
+	identity, err := provider.VerifyIDToken(ctx, rawIDToken, nonce)
+	if err != nil {
+		return OAuthIdentity{}, err
+	}
+
+	// Some providers keep the email out of the ID token, ask the userinfo endpoint for it
+	if identity.Email == "" {
+		if err := provider.fillFromUserinfo(ctx, token, &identity); err != nil {
+			return OAuthIdentity{}, err
+		}
+	}
+
+	return identity, nil
+}
+
+// VerifyIDToken checks signature, issuer, audience, expiry and (when given) nonce of an ID token
+func (provider *OIDCProvider) VerifyIDToken(ctx context.Context, rawIDToken string, nonce string) (OAuthIdentity, error) {
+	discovery, err := provider.getDiscovery(ctx)
+	if err != nil {
+		return OAuthIdentity{}, err
+	}
+
+	claims := &oidcClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}))
+	_, err = parser.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return provider.getKey(ctx, discovery, kid)
+	})
+	if err != nil {
+		return OAuthIdentity{}, fmt.Errorf("invalid id_token: %w", err)
+	}
+
+	// Multi-tenant issuers like Microsoft's common endpoint use a {tenantid} placeholder
+	expectedIssuer := strings.ReplaceAll(discovery.Issuer, "{tenantid}", claims.Tid)
+	if strings.TrimSuffix(claims.Issuer, "/") != strings.TrimSuffix(expectedIssuer, "/") {
+		return OAuthIdentity{}, fmt.Errorf("invalid id_token: unexpected issuer %q", claims.Issuer)
+	}
+	if !claims.VerifyAudience(provider.Config.ClientID, true) {
+		return OAuthIdentity{}, errors.New("invalid id_token: audience mismatch")
+	}
+	if len(claims.Audience) > 1 && claims.Azp != provider.Config.ClientID {
+		return OAuthIdentity{}, errors.New("invalid id_token: authorized party mismatch")
+	}
+	if claims.ExpiresAt == nil {
+		return OAuthIdentity{}, errors.New("invalid id_token: missing exp")
+	}
+	if nonce != "" && claims.Nonce != nonce {
+		return OAuthIdentity{}, errors.New("invalid id_token: nonce mismatch")
+	}
+	if claims.Subject == "" {
+		return OAuthIdentity{}, errors.New("invalid id_token: missing sub")
+	}
+
+	name := claims.Name
+	if name == "" {
+		name = strings.TrimSpace(claims.GivenName + " " + claims.FamilyName)
+	}
+
+	return OAuthIdentity{
+		Provider:      provider.Config.Name,
+		Subject:       claims.Subject,
+		Email:         strings.ToLower(strings.TrimSpace(claims.Email)),
+		EmailVerified: parseEmailVerified(claims.EmailVerified),
+		Name:          name,
+		Picture:       claims.Picture,
+	}, nil
+}
+
+func (provider *OIDCProvider) fillFromUserinfo(ctx context.Context, token *oauth2.Token, identity *OAuthIdentity) error {
+	discovery, err := provider.getDiscovery(ctx)
+	if err != nil {
+		return err
+	}
+	if discovery.UserinfoEndpoint == "" {
+		return nil
+	}
+
+	var info oidcClaims
+	if err := getJSON(ctx, discovery.UserinfoEndpoint, token.AccessToken, &info); err != nil {
+		return fmt.Errorf("userinfo request failed: %w", err)
+	}
+
+	// The userinfo response must describe the same person as the ID token
+	if info.Subject != identity.Subject {
+		return errors.New("userinfo subject does not match id_token")
+	}
+
+	identity.Email = strings.ToLower(strings.TrimSpace(info.Email))
+	identity.EmailVerified = parseEmailVerified(info.EmailVerified)
+	if identity.Name == "" {
+		identity.Name = info.Name
+	}
+	if identity.Picture == "" {
+		identity.Picture = info.Picture
+	}
+	return nil
+}
+
+func (provider *OIDCProvider) oauthConfig(ctx context.Context) (*oauth2.Config, error) {
+	discovery, err := provider.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Config{
+		ClientID:     provider.Config.ClientID,
+		ClientSecret: provider.Config.ClientSecret,
+		RedirectURL:  provider.Config.RedirectURL,
+		Scopes:       provider.Config.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  discovery.AuthorizationEndpoint,
+			TokenURL: discovery.TokenEndpoint,
+		},
+	}, nil
+}
+
+func (provider *OIDCProvider) getDiscovery(ctx context.Context) (*oidcDiscovery, error) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	if provider.discovery != nil && time.Now().Before(provider.discoveryExpiry) {
+		return provider.discovery, nil
+	}
+
+	var discovery oidcDiscovery
+	if err := getJSON(ctx, provider.Config.Issuer+"/.well-known/openid-configuration", "", &discovery); err != nil {
+		if provider.discovery != nil {
+			// Keep using the last good document while the IdP is unreachable
+			return provider.discovery, nil
+		}
+		return nil, fmt.Errorf("discovery failed for %s: %w", provider.Config.Name, err)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, fmt.Errorf("discovery document for %s is incomplete", provider.Config.Name)
+	}
+	// OIDC Discovery requires the document to name exactly the issuer it was fetched for
+	if !discoveredIssuerMatches(provider.Config.Issuer, discovery.Issuer) {
+		return nil, fmt.Errorf("discovery document for %s names issuer %q instead of %q", provider.Config.Name, discovery.Issuer, provider.Config.Issuer)
+	}
+	if provider.Config.AuthURL != "" {
+		discovery.AuthorizationEndpoint = provider.Config.AuthURL
+	}
+	if provider.Config.TokenURL != "" {
+		discovery.TokenEndpoint = provider.Config.TokenURL
+	}
+
+	provider.discovery = &discovery
+	provider.discoveryExpiry = time.Now().Add(oidcDiscoveryTTL)
+	return provider.discovery, nil
+}
+
+func (provider *OIDCProvider) getKey(ctx context.Context, discovery *oidcDiscovery, kid string) (crypto.PublicKey, error) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+
+	now := time.Now()
+	if provider.keys != nil && now.Before(provider.keysExpiry) {
+		if key, ok := provider.lookupKey(kid); ok {
+			return key, nil
+		}
+		// The IdP may have rotated its keys, refetch unless we just did
+		if now.Sub(provider.keysFetchedAt) < oidcJWKSMinRefresh {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := getJSON(ctx, discovery.JWKSURI, "", &set); err != nil {
+		return nil, fmt.Errorf("fetching signing keys failed: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	provider.keys = keys
+	provider.keysFetchedAt = now
+	provider.keysExpiry = now.Add(oidcJWKSTTL)
+
+	if key, ok := provider.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// lookupKey accepts a missing kid only when the set holds a single key
+func (provider *OIDCProvider) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if key, ok := provider.keys[kid]; ok {
+		return key, true
+	}
+	if kid == "" && len(provider.keys) == 1 {
+		for _, key := range provider.keys {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+func (jwk jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("EC key is not on its curve")
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
+
+// parseEmailVerified copes with providers that send the flag as a string
+func parseEmailVerified(raw json.RawMessage) bool {
+	value := strings.Trim(string(raw), `"`)
+	return strings.EqualFold(value, "true")
+}
+
+func getJSON(ctx context.Context, url string, accessToken string, target interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		request.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+
+	response, err := oauthHTTPClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status %d", url, response.StatusCode)
+	}
+
+	return json.NewDecoder(response.Body).Decode(target)
+}
+
+// discoveredIssuerMatches compares the issuer of a discovery document with the configured one. The only
+// accepted difference is Microsoft's multi-tenant endpoint, whose document for .../common/v2.0 names
+// .../{tenantid}/v2.0, the tenant is then checked on every ID token.
+func discoveredIssuerMatches(configured string, discovered string) bool {
+	if discovered == configured {
+		return true
+	}
+	for _, tenant := range []string{"/common/", "/organizations/", "/consumers/"} {
+		if strings.Contains(configured, tenant) && strings.Replace(configured, tenant, "/{tenantid}/", 1) == discovered {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"bufio"
+	_ "embed"
+	"evoconnect/backend/exception"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+//go:embed data/common_passwords.txt
+var bundledCommonPasswords string
+
+// bcrypt ignores everything after 72 bytes, a longer password would only look stronger
+const passwordMaxBytes = 72
+
+// Name and email parts shorter than this are too common to reject, "al" would block half the dictionary
+const passwordMinPersonalPart = 3
+
+// PasswordPolicy decides which new passwords are accepted
+type PasswordPolicy struct {
+	MinLength int
+	// Blocklist holds lowercased common and breached passwords
+	Blocklist map[string]struct{}
+}
+
+// LoadPasswordPolicy builds the policy from PASSWORD_MIN_LENGTH and PASSWORD_BLOCKLIST_FILE.
+// Without a blocklist file the bundled list of common passwords is used.
+func LoadPasswordPolicy(minLength int, blocklistFile string) (*PasswordPolicy, error) {
+	policy := &PasswordPolicy{
+		MinLength: minLength,
+		Blocklist: make(map[string]struct{}),
+	}
+	if policy.MinLength < 1 || policy.MinLength > passwordMaxBytes {
+		return nil, fmt.Errorf("PASSWORD_MIN_LENGTH must be between 1 and %d", passwordMaxBytes)
+	}
+
+	var source io.Reader = strings.NewReader(bundledCommonPasswords)
+	if blocklistFile != "" {
+		file, err := os.Open(blocklistFile)
+		if err != nil {
+			return nil, fmt.Errorf("opening password blocklist: %w", err)
+		}
+		defer file.Close()
+		source = file
+	}
+
+	scanner := bufio.NewScanner(source)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		policy.Blocklist[strings.ToLower(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading password blocklist: %w", err)
+	}
+
+	return policy, nil
+}
+
+// Check lists every rule the password breaks, so the user can fix them all at once
+func (policy *PasswordPolicy) Check(password string, name string, email string) []exception.PasswordViolation {
+	var violations []exception.PasswordViolation
+
+	if utf8.RuneCountInString(password) < policy.MinLength {
+		violations = append(violations, exception.PasswordViolation{
+			Code:    exception.PasswordTooShort,
+			Message: fmt.Sprintf("Password must be at least %d characters long", policy.MinLength),
+		})
+	}
+	if len(password) > passwordMaxBytes {
+		violations = append(violations, exception.PasswordViolation{
+			Code:    exception.PasswordTooLong,
+			Message: fmt.Sprintf("Password must be at most %d bytes long", passwordMaxBytes),
+		})
+	}
+
+	lowered := strings.ToLower(password)
+	if containsPersonalInfo(lowered, name, email) {
+		violations = append(violations, exception.PasswordViolation{
+			Code:    exception.PasswordContainsPersonalInfo,
+			Message: "Password must not contain your name or email address",
+		})
+	}
+	if _, listed := policy.Blocklist[lowered]; listed {
+		violations = append(violations, exception.PasswordViolation{
+			Code:    exception.PasswordCommon,
+			Message: "This password is too common or has appeared in a data breach",
+		})
+	}
+
+	return violations
+}
+
+// Validate panics with a PasswordPolicyError when the password breaks any rule
+func (policy *PasswordPolicy) Validate(password string, name string, email string) {
+	if violations := policy.Check(password, name, email); len(violations) > 0 {
+		panic(exception.NewPasswordPolicyError(violations))
+	}
+}
+
+func containsPersonalInfo(password string, name string, email string) bool {
+	parts := strings.Fields(strings.ToLower(name))
+	if len(parts) > 1 {
+		parts = append(parts, strings.Join(parts, ""))
+	}
+
+	email = strings.ToLower(email)
+	if localPart, _, ok := strings.Cut(email, "@"); ok {
+		parts = append(parts, localPart)
+	}
+
+	for _, part := range parts {
+		if utf8.RuneCountInString(part) >= passwordMinPersonalPart && strings.Contains(password, part) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"evoconnect/backend/config"
 	"evoconnect/backend/helper"
 
 	"github.com/pusher/pusher-http-go/v5"
@@ -8,12 +9,21 @@ import (
 
 var PusherClient *pusher.Client
 
-func InitPusherClient() {
+func InitPusherClient(pusherConfig config.PusherConfig) {
 	PusherClient = &pusher.Client{
-		AppID:   helper.GetEnv("PUSHER_APP_ID", "your-app-id"),
-		Key:     helper.GetEnv("PUSHER_KEY", "your-key"),
-		Secret:  helper.GetEnv("PUSHER_SECRET", "your-secret"),
-		Cluster: helper.GetEnv("PUSHER_CLUSTER", "your-cluster"),
+		AppID:   pusherConfig.AppID,
+		Key:     pusherConfig.Key,
+		Secret:  pusherConfig.Secret,
+		Cluster: pusherConfig.Cluster,
 		Secure:  true,
 	}
 }
+
+// TriggerPusher sends an event through the shared client and counts failed deliveries
+func TriggerPusher(channel string, event string, data interface{}) error {
+	err := PusherClient.Trigger(channel, event, data)
+	if err != nil {
+		helper.PusherTriggerFailuresTotal.WithLabelValues(event).Inc()
+	}
+	return err
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"context"
 	"database/sql"
+	"evoconnect/backend/helper"
 	"evoconnect/backend/model/domain"
 	"evoconnect/backend/model/web"
 	"evoconnect/backend/repository"
@@ -13,11 +14,12 @@ import (
 func ToUserShortWithConnection(ctx context.Context, tx *sql.Tx, repo repository.ConnectionRepository, currentUserId uuid.UUID, user domain.User) web.UserShort {
 	isConnected := repo.IsConnected(ctx, tx, currentUserId, user.Id)
 	return web.UserShort{
-		Id:          user.Id,
-		Name:        user.Name,
-		Username:    user.Username,
-		Photo:       optionalStringPtr(user.Photo),
-		IsConnected: isConnected,
+		Id:            user.Id,
+		Name:          user.Name,
+		Username:      user.Username,
+		Photo:         optionalStringPtr(user.Photo),
+		PhotoVariants: helper.ImageVariantsOf(user.Photo),
+		IsConnected:   isConnected,
 	}
 }
 
@@ -0,0 +1,605 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"evoconnect/backend/app"
+	"evoconnect/backend/config"
+	"evoconnect/backend/controller"
+	"evoconnect/backend/db/migrations"
+	"evoconnect/backend/helper"
+	"evoconnect/backend/repository"
+	"evoconnect/backend/scanner"
+	"evoconnect/backend/service"
+	"evoconnect/backend/storage"
+	"evoconnect/backend/utils"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/julienschmidt/httprouter"
+)
+
+// application is the wiring shared by the server and the administrative commands,
+// so a command runs the same services, with the same rules, as the API
+type application struct {
+	router                 *httprouter.Router
+	healthService          service.HealthService
+	accountStatusService   service.AccountStatusService
+	accountDeletionService service.AccountDeletionService
+	dataExportService      service.DataExportService
+	fileScanService        service.FileScanService
+	adminManagementService service.AdminManagementService
+	searchService          service.SearchService
+}
+
+func newApplication(ctx context.Context, cfg config.Config, db *sql.DB, logger *slog.Logger) (*application, error) {
+	helper.InitTimezone("Asia/Jakarta")
+	validate := validator.New()
+	utils.InitPusherClient(cfg.Pusher)
+	helper.InitEmail(cfg.Email)
+
+	// Uploads go to local disk or an S3 compatible bucket
+	fileStorage, err := storage.New(ctx, cfg.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("initialize storage: %w", err)
+	}
+	helper.InitStorage(fileStorage)
+
+	// CVs and chat files are only downloaded through links signed with this key
+	signingKey := []byte(cfg.Storage.SigningKey)
+	if len(signingKey) == 0 {
+		signingKey = make([]byte, 32)
+		if _, err := rand.Read(signingKey); err != nil {
+			return nil, fmt.Errorf("generate file URL signing key: %w", err)
+		}
+		logger.Warn("FILE_URL_SIGNING_KEY is not set, using a random key: file links stop working on restart and across instances")
+	}
+	helper.InitFileURLSigner(signingKey, time.Duration(cfg.Storage.SignedURLTTLSeconds)*time.Second)
+
+	// CVs and chat files are checked for malware before they are served
+	fileScanner, err := scanner.New(cfg.Scanner)
+	if err != nil {
+		return nil, fmt.Errorf("initialize malware scanner: %w", err)
+	}
+
+	// Initialize JWT keyrings; placeholder secrets are refused unless DEBUG_MODE is on
+	jwtSecret := cfg.JWT.Secret
+	if err := utils.InitJWT(cfg.JWT, cfg.Debug); err != nil {
+		return nil, fmt.Errorf("initialize JWT: %w", err)
+	}
+
+	// Password policy with the bundled or configured list of common passwords
+	passwordPolicy, err := utils.LoadPasswordPolicy(cfg.Auth.PasswordMinLength, cfg.Auth.PasswordBlocklistFile)
+	if err != nil {
+		return nil, fmt.Errorf("load password policy: %w", err)
+	}
+
+	// Sign-in providers, Google is always enabled
+	oauthProviders, err := utils.LoadOAuthProviders(cfg.OAuth, cfg.ClientURL)
+	if err != nil {
+		return nil, fmt.Errorf("load OAuth providers: %w", err)
+	}
+
+	sessionLifetimes := service.SessionLifetimes{
+		AccessToken:  time.Duration(cfg.Auth.AccessTokenMinutes) * time.Minute,
+		RefreshToken: time.Duration(cfg.Auth.RefreshTokenDays) * 24 * time.Hour,
+	}
+
+	// ===== Repositories =====
+	// User-related repositories
+	userRepository := repository.NewUserRepository()
+	connectionRepository := repository.NewConnectionRepository(db)
+	profileViewRepository := repository.NewProfileViewRepository()
+	userSessionRepository := repository.NewUserSessionRepository()
+	twoFactorRepository := repository.NewTwoFactorRepository()
+	systemSettingRepository := repository.NewSystemSettingRepository()
+	accountPurgeRepository := repository.NewAccountPurgeRepository()
+	dataExportRepository := repository.NewDataExportRepository()
+	loginLockoutRepository := repository.NewLoginLockoutRepository()
+	userIdentityRepository := repository.NewUserIdentityRepository()
+	oauthStateRepository := repository.NewOAuthStateRepository()
+	apiTokenRepository := repository.NewAPITokenRepository()
+
+	// Rate limit hits live in Postgres so every instance shares them, memory suits a single instance
+	var rateLimitStore repository.RateLimitStore
+	if cfg.RateLimit.Store == config.RateLimitStoreMemory {
+		rateLimitStore = repository.NewMemoryRateLimitStore()
+	} else {
+		rateLimitStore = repository.NewPostgresRateLimitStore(db)
+	}
+
+	// Content-related repositories
+	blogRepository := repository.NewBlogRepository(db)
+	commentBlogRepository := repository.NewCommentBlogRepository()
+
+	// Post repository
+	postRepository := repository.NewPostRepository()
+
+	// Comment repository
+	commentRepository := repository.NewCommentRepository()
+
+	// Professional info repositories
+	educationRepository := repository.NewEducationRepository()
+	experienceRepository := repository.NewExperienceRepository()
+
+	// Group-related repositories
+	groupRepository := repository.NewGroupRepository()
+	groupMemberRepository := repository.NewGroupMemberRepository()
+	groupInvitationRepository := repository.NewGroupInvitationRepository()
+
+	pendingPostRepository := repository.NewPendingPostRepository()
+	groupJoinRequestRepository := repository.NewGroupJoinRequestRepository()
+
+	// Chat repository
+	chatRepository := repository.NewChatRepository()
+
+	// Report repository
+	reportRepository := repository.NewReportRepository(db)
+
+	// Notification repository
+	notificationRepository := repository.NewNotificationRepository()
+
+	// Admin notification repository
+	adminNotificationRepository := repository.NewAdminNotificationRepository()
+
+	// Notification service (moved up)
+	// Admin repository
+	adminRepository := repository.NewAdminRepository()
+	adminInvitationRepository := repository.NewAdminInvitationRepository()
+
+	// Company-related repositories
+	companyRepository := repository.NewCompanyRepository()
+	companySubmissionRepository := repository.NewCompanySubmissionRepository()
+	companyEditRequestRepository := repository.NewCompanyEditRequestRepository()
+	memberCompanyRepository := repository.NewMemberCompanyRepository()
+	companyJoinRequestRepository := repository.NewCompanyJoinRequestRepository()
+	companyPostRepository := repository.NewCompanyPostRepository()
+	companyPostCommentRepository := repository.NewCompanyPostCommentRepository()
+
+	// Add company follower repository
+	companyFollowerRepository := repository.NewCompanyFollowerRepository()
+
+	// Job-related repositories
+	jobVacancyRepository := repository.NewJobVacancyRepository()
+	jobApplicationRepository := repository.NewJobApplicationRepository()
+	userCvStorageRepository := repository.NewUserCvStorageRepository()
+	fileScanRepository := repository.NewFileScanRepository()
+
+	savedJobRepository := repository.NewSavedJobRepository()
+
+	// ===== Services =====
+	// Notification service (moved up because it's used by many other services)
+	notificationService := service.NewNotificationService(
+		notificationRepository,
+		userRepository,
+		db,
+		validate,
+		logger,
+	)
+
+	// Malware scans of quarantined uploads, shared by the CV and chat services
+	fileScanService := service.NewFileScanService(fileScanRepository, notificationService, fileScanner, cfg.Scanner.MaxAttempts, db, logger)
+
+	// Signed download links for private files, every issued link is logged
+	fileAccessLogRepository := repository.NewFileAccessLogRepository()
+	fileAccessService := service.NewFileAccessService(fileAccessLogRepository)
+
+	// Audit log service, shared by every admin mutation
+	auditLogRepository := repository.NewAuditLogRepository()
+	auditLogService := service.NewAuditLogService(auditLogRepository, db)
+
+	// pinned post repository
+	groupPinnedPostRepository := repository.NewGroupPinnedPostRepository()
+	groupBlockedMemberRepository := repository.NewGroupBlockedMemberRepository()
+
+	// adminRepository := repository.NewAdminRepository()
+
+	// ===== Services =====
+	profileViewService := service.NewProfileViewService(db, profileViewRepository, userRepository, notificationService)
+	connectionService := service.NewConnectionService(connectionRepository, userRepository, notificationService, db, groupInvitationRepository, validate)
+	userService := service.NewUserService(userRepository, connectionRepository, profileViewService, db, validate)
+	rateLimiter := service.NewRateLimiter(rateLimitStore, logger)
+	loginLockoutService := service.NewLoginLockoutService(loginLockoutRepository, cfg.Auth.LoginLockoutThreshold, time.Duration(cfg.Auth.LoginLockoutBaseMinutes)*time.Minute, db, logger)
+	accountStatusService := service.NewAccountStatusService(userRepository, auditLogService, db)
+	userSessionService := service.NewUserSessionService(userSessionRepository, userRepository, db)
+	accountDeletionService := service.NewAccountDeletionService(userRepository, userSessionRepository, accountPurgeRepository, time.Duration(cfg.Accounts.DeletionGraceDays)*24*time.Hour, db, validate, logger)
+	dataExportService := service.NewDataExportService(dataExportRepository, notificationService, time.Duration(cfg.DataExport.TTLHours)*time.Hour, time.Duration(cfg.DataExport.CooldownHours)*time.Hour, db, logger)
+	twoFactorService := service.NewTwoFactorService(twoFactorRepository, systemSettingRepository, rateLimiter, auditLogService, db, validate)
+	oauthService := service.NewOAuthService(oauthProviders, userRepository, userSessionRepository, userIdentityRepository, oauthStateRepository, accountStatusService, twoFactorService, rateLimiter, sessionLifetimes, db, validate, logger)
+	emailChangeService := service.NewEmailChangeService(userRepository, userSessionRepository, apiTokenRepository, rateLimiter, cfg.ClientURL, db, validate, logger)
+	apiTokenService := service.NewAPITokenService(apiTokenRepository, memberCompanyRepository, jobVacancyRepository, jobApplicationRepository, db, validate)
+	authService := service.NewAuthService(userRepository, userSessionRepository, accountStatusService, twoFactorService, rateLimiter, loginLockoutService, oauthService, passwordPolicy, sessionLifetimes, cfg.ClientURL, db, validate, jwtSecret, logger)
+
+	// Content-related services
+	blogService := service.NewBlogService(
+		blogRepository,
+		userRepository,
+		connectionRepository,
+		notificationService,
+	)
+
+	commentBlogService := service.NewCommentBlogService(
+		commentBlogRepository,
+		blogRepository,
+		userRepository,
+		notificationService,
+		db,
+		validate,
+	)
+
+	// Pindahkan inisialisasi groupService sebelum postService
+	// Group service
+	groupService := service.NewGroupService(
+		db,
+		groupRepository,
+		groupMemberRepository,
+		groupInvitationRepository,
+		userRepository,
+		connectionRepository,
+		notificationService,
+		groupJoinRequestRepository,
+		groupBlockedMemberRepository, // Tambahkan parameter baru ini
+		validate,
+		logger,
+	)
+
+	// Post service
+	postService := service.NewPostService(
+		userRepository,
+		postRepository,
+		commentRepository,
+		connectionRepository,
+		groupRepository,
+		groupMemberRepository,
+		notificationService,
+		groupService, // Sekarang groupService sudah diinisialisasi
+		pendingPostRepository,
+		db,
+		validate,
+		logger,
+	)
+
+	// Comment service
+	commentService := service.NewCommentService(
+		commentRepository,
+		postRepository,
+		userRepository,
+		notificationService,
+		db,
+		validate,
+	)
+
+	// pinned post service
+	groupPinnedPostService := service.NewGroupPinnedPostService(
+		groupPinnedPostRepository,
+		postRepository,
+		groupRepository,
+		groupMemberRepository,
+		userRepository,
+		db,
+		validate,
+	)
+
+	// Professional info services
+	educationService := service.NewEducationService(educationRepository, userRepository, db, validate)
+	experienceService := service.NewExperienceService(experienceRepository, userRepository, db, validate)
+
+	// Chat service
+	chatService := service.NewChatService(chatRepository, userRepository, fileScanService, fileAccessService, db, validate, logger)
+
+	// Report service
+	reportService := service.NewReportService(
+		reportRepository,
+		userRepository,
+		postRepository,
+		commentRepository,
+		blogRepository,
+		commentBlogRepository,
+		groupRepository,
+		companyRepository,
+		companyPostRepository,
+		companyPostCommentRepository,
+		jobVacancyRepository,
+		notificationService,
+		auditLogService,
+		db,
+		logger,
+	)
+
+	// Search service
+	searchService := service.NewSearchService(
+		db,
+		userRepository,
+		postRepository,
+		blogRepository,
+		groupRepository,
+		connectionRepository,
+		groupJoinRequestRepository,
+		companyRepository,         // TAMBAH INI
+		companyPostRepository,     // TAMBAH INI
+		jobVacancyRepository,      // TAMBAH INI
+		companyFollowerRepository, // TAMBAH INI
+		logger,
+	)
+
+	adminNotificationService := service.NewAdminNotificationService(
+		adminNotificationRepository,
+		db,
+	)
+
+	// Admin auth service
+	adminAuthService := service.NewAdminAuthService(adminRepository, adminInvitationRepository, twoFactorService, rateLimiter, loginLockoutService, passwordPolicy, db, validate)
+	adminManagementService := service.NewAdminManagementService(adminRepository, adminInvitationRepository, auditLogService, passwordPolicy, cfg.ClientURL, db, validate)
+
+	// Member company service
+	memberCompanyService := service.NewMemberCompanyService(
+		memberCompanyRepository,
+		userRepository,
+		companyRepository,
+		notificationService,
+		db,
+		validate,
+	)
+
+	// Company submission service
+	companySubmissionService := service.NewCompanySubmissionService(
+		companySubmissionRepository,
+		companyRepository,
+		userRepository,
+		memberCompanyRepository,
+		adminRepository,
+		notificationService,
+		auditLogService,
+		db,
+		validate,
+		logger,
+	)
+
+	// Company follower service
+	companyFollowerService := service.NewCompanyFollowerService(
+		companyFollowerRepository,
+		companyRepository,
+		userRepository,
+		notificationService,
+		db,
+		validate,
+	)
+
+	// Company management service (updated with follower repository)
+	companyManagementService := service.NewCompanyManagementService(
+		companyRepository,
+		companyEditRequestRepository,
+		companyJoinRequestRepository,
+		memberCompanyRepository,
+		companyFollowerRepository, // Add this parameter
+		userRepository,
+		adminRepository,
+		notificationService,
+		reportRepository,
+		auditLogService,
+		db,
+		validate,
+		logger,
+	)
+
+	companyJoinRequestService := service.NewCompanyJoinRequestService(
+		db,
+		companyJoinRequestRepository,
+		companyRepository,
+		userRepository,
+		memberCompanyRepository,
+		notificationService,
+		validate,
+		logger,
+	)
+
+	companyPostService := service.NewCompanyPostService(
+		db,
+		companyPostRepository,
+		memberCompanyRepository,
+		companyRepository,
+		userRepository,
+		companyFollowerRepository,
+		notificationService,
+		validate,
+		logger,
+	)
+
+	companyPostCommentService := service.NewCompanyPostCommentService(
+		db,
+		companyPostCommentRepository,
+		companyPostRepository,
+		memberCompanyRepository,
+		userRepository,
+		notificationService,
+		validate,
+		logger,
+	)
+
+	jobVacancyService := service.NewJobVacancyService(
+		jobVacancyRepository,
+		companyRepository,
+		userRepository,
+		savedJobRepository,
+		jobApplicationRepository,
+		companyFollowerRepository, // Tambah ini
+		notificationService,       // Tambah ini
+		db,
+		validate,
+	)
+	jobApplicationService := service.NewJobApplicationService(
+		jobApplicationRepository,
+		userCvStorageRepository,
+		jobVacancyRepository,
+		userRepository,
+		memberCompanyRepository,
+		notificationService,
+		fileScanService,
+		fileAccessService,
+		db,
+		validate,
+	)
+
+	userCvStorageService := service.NewUserCvStorageService(userCvStorageRepository, userRepository, fileScanService, fileAccessService, db, validate)
+
+	savedJobService := service.NewSavedJobService(
+		savedJobRepository,
+		jobVacancyRepository,
+		db,
+		validate,
+	)
+
+	// ===== Controllers =====
+	// User-related controllers
+	userController := controller.NewUserController(
+		userService,
+		profileViewService,
+		notificationService,
+	)
+	connectionController := controller.NewConnectionController(connectionService)
+	profileViewController := controller.NewProfileViewController(profileViewService)
+	authController := controller.NewAuthController(authService)
+	userSessionController := controller.NewUserSessionController(userSessionService)
+	twoFactorController := controller.NewTwoFactorController(twoFactorService)
+	accountDeletionController := controller.NewAccountDeletionController(accountDeletionService)
+	dataExportController := controller.NewDataExportController(dataExportService)
+	oauthController := controller.NewOAuthController(oauthService)
+	apiTokenController := controller.NewAPITokenController(apiTokenService)
+	emailChangeController := controller.NewEmailChangeController(emailChangeService)
+
+	// Content-related controllers
+	blogController := controller.NewBlogController(blogService)
+	postController := controller.NewPostController(postService)
+	commentController := controller.NewCommentController(commentService)
+	commentBlogController := controller.NewCommentBlogController(commentBlogService)
+
+	// Professional info controllers
+	educationController := controller.NewEducationController(educationService)
+	experienceController := controller.NewExperienceController(experienceService)
+
+	// Group controller
+	groupController := controller.NewGroupController(groupService, postService)
+
+	// Chat controller
+	chatController := controller.NewChatController(chatService)
+
+	// Report controller
+	reportController := controller.NewReportController(reportService)
+
+	// Notification controller
+	notificationController := controller.NewNotificationController(notificationService)
+
+	// Search controller
+	searchController := controller.NewSearchController(searchService)
+
+	adminAuthController := controller.NewAdminAuthController(adminAuthService)
+	adminManagementController := controller.NewAdminManagementController(adminManagementService)
+	auditLogController := controller.NewAuditLogController(auditLogService)
+
+	// Liveness and readiness probes
+	migrator, err := migrations.NewMigrator(db, cfg.Migrations.Table, logger)
+	if err != nil {
+		return nil, fmt.Errorf("load migrations: %w", err)
+	}
+	healthService := service.NewHealthService(db, migrator, cfg.Migrations.AllowPending, logger)
+	healthController := controller.NewHealthController(healthService)
+
+	// admin report
+	adminReportController := controller.NewAdminReportController(reportService)
+
+	// pinned post controller
+	groupPinnedPostController := controller.NewGroupPinnedPostController(groupPinnedPostService)
+
+	// admin notification controller
+	adminNotificationController := controller.NewAdminNotificationController(adminNotificationService)
+	// Company submission controller
+	companySubmissionController := controller.NewCompanySubmissionController(companySubmissionService)
+
+	companyManagementController := controller.NewCompanyManagementController(companyManagementService)
+	adminCompanyEditController := controller.NewAdminCompanyEditController(companyManagementService)
+
+	// Member company controller
+	memberCompanyController := controller.NewMemberCompanyController(memberCompanyService)
+
+	companyJoinRequestController := controller.NewCompanyJoinRequestController(companyJoinRequestService)
+
+	companyPostController := controller.NewCompanyPostController(companyPostService)
+
+	companyPostCommentController := controller.NewCompanyPostCommentController(companyPostCommentService)
+
+	// Add company follower controller
+	companyFollowerController := controller.NewCompanyFollowerController(companyFollowerService)
+
+	jobVacancyController := controller.NewJobVacancyController(jobVacancyService)
+	jobApplicationController := controller.NewJobApplicationController(jobApplicationService)
+	userCvStorageController := controller.NewUserCvStorageController(userCvStorageService)
+
+	// Add to the controllers section:
+	savedJobController := controller.NewSavedJobController(savedJobService)
+
+	// ===== Router =====
+	// The middleware chain around it is added by the serve command
+	router := app.NewRouter(
+		authController,
+		userController,
+		blogController,
+		postController,
+		commentController,
+		educationController,
+		experienceController,
+		commentBlogController,
+		connectionController,
+		reportController,
+		groupController,
+		chatController,
+		profileViewController,
+		notificationController,
+		searchController,
+		adminAuthController,
+		adminReportController,
+		groupPinnedPostController,
+		adminNotificationController,
+		companySubmissionController,
+		companyManagementController,
+		adminCompanyEditController,
+		memberCompanyController,
+		companyJoinRequestController,
+		companyPostController,
+		companyPostCommentController,
+		companyFollowerController,
+		jobVacancyController,
+		jobApplicationController,
+		userCvStorageController,
+		savedJobController,
+		userSessionController,
+		twoFactorController,
+		accountDeletionController,
+		dataExportController,
+		oauthController,
+		apiTokenController,
+		emailChangeController,
+		adminManagementController,
+		auditLogController,
+		healthController,
+		accountStatusService,
+		userSessionService,
+		apiTokenService,
+		adminAuthService,
+		rateLimiter,
+		cfg.Metrics.Token,
+	)
+
+	return &application{
+		router:                 router,
+		healthService:          healthService,
+		accountStatusService:   accountStatusService,
+		accountDeletionService: accountDeletionService,
+		dataExportService:      dataExportService,
+		fileScanService:        fileScanService,
+		adminManagementService: adminManagementService,
+		searchService:          searchService,
+	}, nil
+}
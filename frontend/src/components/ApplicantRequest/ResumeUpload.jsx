@@ -114,7 +114,7 @@ const ResumeUpload = ({ userData, onBack, onNext, onResumeChange, isSubmitting,
                                                     </label>
                                                     <input
                                                         type="file"
-                                                        accept=".doc,.docx,.pdf"
+                                                        accept=".pdf"
                                                         onChange={handleFileChange}
                                                         className="hidden"
                                                         id="resume-update"
@@ -133,11 +133,11 @@ const ResumeUpload = ({ userData, onBack, onNext, onResumeChange, isSubmitting,
                                 ) : (
                                     <>
                                         <p className="text-sm font-medium text-gray-700 mb-1">Upload resume</p>
-                                        <p className="text-xs text-gray-500 mb-3">DOC, DOCX, PDF</p>
+                                        <p className="text-xs text-gray-500 mb-3">PDF</p>
 
                                         <input
                                             type="file"
-                                            accept=".doc,.docx,.pdf"
+                                            accept=".pdf"
                                             onChange={handleFileChange}
                                             className="hidden"
                                             id="resume-upload"
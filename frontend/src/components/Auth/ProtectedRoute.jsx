@@ -1,6 +1,7 @@
 import axios from 'axios';
 import React, { useState, useEffect } from 'react';
 import { Navigate, useLocation, useNavigate } from 'react-router-dom';
+import { clearSession } from '../../utils/session';
 // import Cookies from 'js-cookie';
 
 
@@ -37,7 +38,7 @@ const ProtectedRoute = ({ children }) => {
           setAuthState({ loading: false, verified: true });
         }
       } catch (error) {
-        localStorage.removeItem('token');
+        clearSession();
         navigate('/login', { state: { from: location }, replace: true });
       }
     };
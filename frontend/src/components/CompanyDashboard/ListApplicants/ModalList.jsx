@@ -164,6 +164,34 @@ const handleReject = async () => {
     }
 };
 
+// CVs are only served through short-lived signed links, ask for one when the CV is opened
+const handleViewCv = async () => {
+    if (!applicant || !applicant.id) return;
+
+    // Opened before the request, a window opened after it would be blocked as a popup
+    const cvWindow = window.open("", "_blank");
+    try {
+        const userToken = localStorage.getItem("token");
+        const response = await fetch(
+            `${apiUrl}/api/job-app/${applicant.id}/cv`,
+            {
+                headers: { 'Authorization': `Bearer ${userToken}` }
+            }
+        );
+
+        const data = await response.json();
+        if (!response.ok || !data?.data?.url) {
+            throw new Error(typeof data?.data === "string" ? data.data : "Failed to open CV");
+        }
+        cvWindow.opener = null;
+        cvWindow.location.href = `${apiUrl}${data.data.url}`;
+    } catch (error) {
+        cvWindow?.close();
+        console.error("Failed to open CV:", error);
+        alert(error.message || "Failed to open CV. Please try again.");
+    }
+};
+
 
     if (!applicant) return null;
     
@@ -286,14 +314,13 @@ const handleReject = async () => {
                                             <p className="font-medium truncate">{cvFilePath.split('/').pop()}</p>
                                             <p className="text-sm text-gray-500">PDF Document</p>
                                         </div>
-                                        <a
-                                            href={`${apiUrl}/${cvFilePath}`}
-                                            target="_blank"
-                                            rel="noopener noreferrer"
+                                        <button
+                                            type="button"
+                                            onClick={handleViewCv}
                                             className="text-blue-600 hover:text-blue-800 p-1 rounded-md hover:bg-blue-50 transition-colors"
                                         >
                                             <ArrowDownToLine className="h-5 w-5" />
-                                        </a>
+                                        </button>
                                     </div>
                                 </div>
                             )}
@@ -9,6 +9,7 @@ import MessageDropdown from "./Navbar/MessageDropdown";
 import NotificationDropdown from "./Navbar/NotificationDropdown";
 import UserDropdown from "./Navbar/UserDropdown";
 import Other from "./Navbar/Other";
+import { logout } from "../utils/session";
 
 const Navbar = () => {
   const apiUrl =
@@ -70,9 +71,8 @@ const Navbar = () => {
     };
   }, []);
 
-  const handleLogout = () => {
-    localStorage.removeItem("token");
-    localStorage.removeItem("user");
+  const handleLogout = async () => {
+    await logout();
     window.location.href = "/login";
   };
 
@@ -4,6 +4,10 @@ import { RouterProvider } from "react-router-dom";
 import "./index.css";
 import router from "./routes.jsx";
 import NotificationSound from "./components/NotificationSound";
+import { installSessionRefresh } from "./utils/session";
+
+// Access tokens expire after minutes, requests refresh them on 401
+installSessionRefresh();
 
 createRoot(document.getElementById("root")).render(
   <StrictMode>
@@ -7,6 +7,7 @@ import googleIcon from "../../assets/img/google-icon.jpg";
 import Alert from "../../components/Auth/alert";
 import "../../assets/css/style.css";
 import { GoogleOAuthProvider, GoogleLogin } from "@react-oauth/google";
+import { saveSession } from "../../utils/session";
 
 function Login() {
   const apiUrl = import.meta.env.VITE_APP_BACKEND_URL || "http://localhost:3000";
@@ -79,8 +80,8 @@ function Login() {
         throw new Error("No token received from server");
       }
 
-      // Store JWT token
-      localStorage.setItem("token", response.data.data.token);
+      // Store JWT token and the refresh token that renews it
+      saveSession(response.data.data);
 
       // Clear any stored name/email info from localStorage
       localStorage.removeItem("register_name");
@@ -139,7 +140,7 @@ function Login() {
     );
 
     // Simpan token dan data user
-    localStorage.setItem("token", response.data.data.token);
+    saveSession(response.data.data);
 
     setAlertInfo({
       show: true,
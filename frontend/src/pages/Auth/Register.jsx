@@ -6,6 +6,7 @@ import googleIcon from '../../assets/img/google-icon.jpg';
 import Alert from '../../components/Auth/alert';
 import '../../assets/css/style.css';
 import { GoogleOAuthProvider, GoogleLogin } from '@react-oauth/google';
+import { saveSession } from '../../utils/session';
 import Cookies from 'js-cookie';
 
 function Register() {
@@ -38,8 +39,8 @@ function Register() {
         throw new Error("No token received from server");
       }
 
-      // Store JWT token
-      localStorage.setItem("token", response.data.data.token);
+      // Store JWT token and the refresh token that renews it
+      saveSession(response.data.data);
 
       // Clear any stored name/email info from localStorage
       localStorage.removeItem("register_name");
@@ -136,8 +137,7 @@ function Register() {
     // In your Register component's handleSubmit:
     try {
       const response = await axios.post(apiUrl + '/api/auth/register', formData);
-      const token = response.data.data.token;
-      localStorage.setItem('token', token);
+      saveSession(response.data.data);
 
       localStorage.removeItem('register_name');
       localStorage.removeItem('register_email');
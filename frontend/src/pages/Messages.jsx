@@ -15,6 +15,39 @@ import {
   X,
 } from "lucide-react";
 
+// Images and audio are loaded through a signed link issued per message, so the scan check
+// and the file access log apply to them just like to document downloads
+const MessageMedia = ({ apiUrl, token, messageId, children }) => {
+  const [src, setSrc] = useState(null);
+  const [failed, setFailed] = useState(false);
+
+  useEffect(() => {
+    let cancelled = false;
+    axios
+      .get(`${apiUrl}/api/messages/${messageId}/file`, {
+        headers: { Authorization: `Bearer ${token}` },
+      })
+      .then((response) => {
+        if (!cancelled) setSrc(`${apiUrl}${response.data.data.url}`);
+      })
+      .catch((error) => {
+        console.error("Failed to load file:", error);
+        if (!cancelled) setFailed(true);
+      });
+    return () => {
+      cancelled = true;
+    };
+  }, [apiUrl, token, messageId]);
+
+  if (failed) {
+    return <p className="italic text-sm text-gray-500">File unavailable</p>;
+  }
+  if (!src) {
+    return <p className="text-sm text-gray-500">Loading...</p>;
+  }
+  return children(src);
+};
+
 export const Messages = () => {
   const apiUrl =
     import.meta.env.VITE_APP_BACKEND_URL || "http://localhost:3000";
@@ -284,6 +317,23 @@ export const Messages = () => {
     }
   };
 
+  // Documents are downloaded through a fresh signed link, issued when the user opens them
+  const openMessageFile = async (messageId) => {
+    const fileWindow = window.open("", "_blank");
+    try {
+      const response = await axios.get(
+        `${apiUrl}/api/messages/${messageId}/file`,
+        { headers: { Authorization: `Bearer ${token}` } }
+      );
+      fileWindow.opener = null;
+      fileWindow.location.href = `${apiUrl}${response.data.data.url}`;
+    } catch (error) {
+      fileWindow?.close();
+      console.error("Failed to open file:", error);
+      alert(error.response?.data?.data || "Failed to open file");
+    }
+  };
+
   const fetchConversations = async () => {
     setLoading(true);
     try {
@@ -1450,11 +1500,19 @@ export const Messages = () => {
                                 ) : message.message_type === "image" ? (
                                   // Image message
                                   <div>
-                                    <img
-                                      src={`${apiUrl}/${message.file_path}`}
-                                      alt="Image"
-                                      className="max-w-full rounded"
-                                    />
+                                    <MessageMedia
+                                      apiUrl={apiUrl}
+                                      token={token}
+                                      messageId={message.id}
+                                    >
+                                      {(src) => (
+                                        <img
+                                          src={src}
+                                          alt="Image"
+                                          className="max-w-full rounded"
+                                        />
+                                      )}
+                                    </MessageMedia>
                                     {message.content && (
                                       <p className="mt-1 text-sm">
                                         {message.content}
@@ -1465,26 +1523,33 @@ export const Messages = () => {
                                   // Document message
                                   <div className="flex items-center gap-2">
                                     <FileText size={24} />
-                                    <a
-                                      href={`${apiUrl}/${message.file_path}`}
-                                      target="_blank"
-                                      rel="noopener noreferrer"
+                                    <button
+                                      type="button"
+                                      onClick={() => openMessageFile(message.id)}
                                       className="text-blue-600 underline"
                                     >
                                       {message.file_name || "Document"}
-                                    </a>
+                                    </button>
                                   </div>
                                 ) : message.message_type === "audio" ? (
                                   // Audio message
                                   <div>
-                                    <audio
-                                      controls
-                                      src={`${apiUrl}/${message.file_path}`}
-                                      className="max-w-full"
+                                    <MessageMedia
+                                      apiUrl={apiUrl}
+                                      token={token}
+                                      messageId={message.id}
                                     >
-                                      Your browser does not support audio
-                                      playback.
-                                    </audio>
+                                      {(src) => (
+                                        <audio
+                                          controls
+                                          src={src}
+                                          className="max-w-full"
+                                        >
+                                          Your browser does not support audio
+                                          playback.
+                                        </audio>
+                                      )}
+                                    </MessageMedia>
                                   </div>
                                 ) : null}
 
@@ -0,0 +1,146 @@
+import axios from "axios";
+
+const apiUrl = import.meta.env.VITE_APP_BACKEND_URL || "http://localhost:3000";
+
+// Access tokens live for minutes, the refresh token keeps the session going. Every
+// request still reads the access token from localStorage, a refresh only replaces it.
+const TOKEN_KEY = "token";
+const REFRESH_TOKEN_KEY = "refresh_token";
+
+const originalFetch = window.fetch.bind(window);
+
+// Stores the token pair of a login, register or refresh response
+export const saveSession = (data) => {
+  if (data?.token) {
+    localStorage.setItem(TOKEN_KEY, data.token);
+  }
+  if (data?.refresh_token) {
+    localStorage.setItem(REFRESH_TOKEN_KEY, data.refresh_token);
+  }
+};
+
+export const clearSession = () => {
+  localStorage.removeItem(TOKEN_KEY);
+  localStorage.removeItem(REFRESH_TOKEN_KEY);
+  localStorage.removeItem("user");
+};
+
+// Revokes the session on the server before forgetting it locally
+export const logout = async () => {
+  const refreshToken = localStorage.getItem(REFRESH_TOKEN_KEY);
+  if (refreshToken) {
+    try {
+      await originalFetch(`${apiUrl}/api/auth/logout`, {
+        method: "POST",
+        headers: { "Content-Type": "application/json" },
+        body: JSON.stringify({ refresh_token: refreshToken }),
+      });
+    } catch (error) {
+      console.error("Failed to revoke session:", error);
+    }
+  }
+  clearSession();
+};
+
+let pendingRefresh = null;
+
+// Exchanges the refresh token for a new pair and returns the new access token, or null when
+// the session is gone. Concurrent callers share one request, a refresh token only works once.
+export const refreshSession = () => {
+  if (pendingRefresh) {
+    return pendingRefresh;
+  }
+
+  const refreshToken = localStorage.getItem(REFRESH_TOKEN_KEY);
+  if (!refreshToken) {
+    return Promise.resolve(null);
+  }
+
+  pendingRefresh = originalFetch(`${apiUrl}/api/auth/refresh`, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify({ refresh_token: refreshToken }),
+  })
+    .then(async (response) => {
+      if (!response.ok) {
+        clearSession();
+        return null;
+      }
+      const data = await response.json();
+      saveSession(data.data);
+      return data.data?.token || null;
+    })
+    .catch((error) => {
+      // A network error does not end the session, the next 401 tries again
+      console.error("Failed to refresh session:", error);
+      return null;
+    })
+    .finally(() => {
+      pendingRefresh = null;
+    });
+
+  return pendingRefresh;
+};
+
+const bearerOf = (authorization) =>
+  typeof authorization === "string" && authorization.startsWith("Bearer ")
+    ? authorization.slice("Bearer ".length)
+    : null;
+
+// Only requests made with the user's own access token are retried, not admin or auth calls
+const shouldRefresh = (url, authorization) => {
+  const token = bearerOf(authorization);
+  if (!token || !String(url).startsWith(apiUrl) || String(url).includes("/api/auth/")) {
+    return false;
+  }
+  return Boolean(localStorage.getItem(REFRESH_TOKEN_KEY));
+};
+
+// Retries a request that failed with 401 once with a refreshed access token. Installed once at
+// startup for axios and fetch, so the pages keep reading the token from localStorage as before.
+export const installSessionRefresh = () => {
+  axios.interceptors.response.use(undefined, async (error) => {
+    const config = error.config;
+    const authorization = config?.headers?.get
+      ? config.headers.get("Authorization")
+      : config?.headers?.Authorization;
+
+    if (
+      error.response?.status !== 401 ||
+      config._sessionRetried ||
+      !shouldRefresh(axios.getUri(config), authorization)
+    ) {
+      return Promise.reject(error);
+    }
+
+    const token = await refreshSession();
+    if (!token) {
+      return Promise.reject(error);
+    }
+
+    config._sessionRetried = true;
+    config.headers.Authorization = `Bearer ${token}`;
+    return axios(config);
+  });
+
+  window.fetch = async (input, init = {}) => {
+    const response = await originalFetch(input, init);
+    if (response.status !== 401) {
+      return response;
+    }
+
+    const url = input instanceof Request ? input.url : input;
+    const headers = new Headers(init.headers || (input instanceof Request ? input.headers : undefined));
+    if (!shouldRefresh(url, headers.get("Authorization"))) {
+      return response;
+    }
+
+    const token = await refreshSession();
+    if (!token) {
+      return response;
+    }
+
+    headers.set("Authorization", `Bearer ${token}`);
+    return originalFetch(input, { ...init, headers });
+  };
+};